@@ -87,6 +87,16 @@ true
 {{- end -}}
 {{- end }}
 
+{{/*
+isWebhookEnabled checks if the validating webhooks of the manager should be deployed.
+Webhooks require cluster scoped resources and can be disabled for clusters that can't run them.
+*/}}
+{{- define "fybrik.isWebhookEnabled" -}}
+{{- if and .Values.clusterScoped .Values.manager.webhooks.enabled -}}
+true
+{{- end -}}
+{{- end }}
+
 {{/*
 isRazeeConfigurationEnabled checks if razee configuration is enabled
 */}}
@@ -163,3 +173,36 @@ passed as parameter to the function.
 {{- $dir := toString (first .) -}}
 {{- printf "%s/%s" (include "fybrik.getDataDir" .) $dir }}
 {{- end }}
+
+{{/*
+Print the additional policy managers as a comma separated list of name=URL pairs.
+*/}}
+{{- define "fybrik.additionalPolicyManagers" -}}
+{{- $managers := list -}}
+{{- range .Values.coordinator.additionalPolicyManagers -}}
+{{- $managers = append $managers (printf "%s=%s" .name .connectorURL) -}}
+{{- end -}}
+{{- join "," $managers -}}
+{{- end }}
+
+{{/*
+Print the named policy managers as a comma separated list of name=URL pairs.
+*/}}
+{{- define "fybrik.namedPolicyManagers" -}}
+{{- $managers := list -}}
+{{- range .Values.coordinator.namedPolicyManagers -}}
+{{- $managers = append $managers (printf "%s=%s" .name .connectorURL) -}}
+{{- end -}}
+{{- join "," $managers -}}
+{{- end }}
+
+{{/*
+Print the module preferences as a comma separated list of action=module or action=key=value items.
+*/}}
+{{- define "fybrik.modulePreferences" -}}
+{{- $preferences := list -}}
+{{- range .Values.manager.modulePreferences -}}
+{{- $preferences = append $preferences (printf "%s=%s" .action (.module | default .label)) -}}
+{{- end -}}
+{{- join "," $preferences -}}
+{{- end }}
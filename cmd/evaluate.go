@@ -0,0 +1,114 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	pmclient "fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// evaluateOptions holds the flags of the evaluate command
+var evaluateOptions struct {
+	actionType         string
+	destination        string
+	processingLocation string
+	metadataFile       string
+	credentials        string
+	policyManagerName  string
+	policyManagerURL   string
+}
+
+// evaluateCmd asks the policy manager connector for the governance actions of an operation on an asset
+var evaluateCmd = &cobra.Command{
+	Use:   "evaluate DATASET_ID",
+	Short: "Evaluate the governance policies of an operation on an asset",
+	Long: `Evaluate the governance policies of an operation on an asset without deploying an application.
+The policy manager connector is asked for its decisions, and the returned actions are printed as JSON.
+The asset metadata that the policies depend on, e.g., its tags and columns, may be given as a JSON file.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		actionType := taxonomy.DataFlow(evaluateOptions.actionType)
+		switch actionType {
+		case taxonomy.ReadFlow, taxonomy.WriteFlow, taxonomy.DeleteFlow, taxonomy.CopyFlow:
+		default:
+			return errors.Errorf("unknown action type %q, expected one of read, write, delete or copy", actionType)
+		}
+		if evaluateOptions.policyManagerURL == "" {
+			return errors.New("the URL of the policy manager connector must be provided")
+		}
+		metadata, err := readResourceMetadata(evaluateOptions.metadataFile)
+		if err != nil {
+			return err
+		}
+		policyManager, err := pmclient.NewPolicyManager(evaluateOptions.policyManagerName, evaluateOptions.policyManagerURL)
+		if err != nil {
+			return errors.Wrap(err, "could not create the policy manager client")
+		}
+		defer policyManager.Close()
+
+		request := &policymanager.GetPolicyDecisionsRequest{
+			Action: policymanager.RequestAction{
+				ActionType:         actionType,
+				Destination:        evaluateOptions.destination,
+				ProcessingLocation: taxonomy.ProcessingLocation(evaluateOptions.processingLocation),
+			},
+			Resource: policymanager.Resource{ID: taxonomy.AssetID(args[0]), Metadata: metadata},
+			Time:     time.Now().UTC().Format(time.RFC3339),
+		}
+		response, err := policyManager.GetPoliciesDecisions(cmd.Context(), request, evaluateOptions.credentials)
+		if err != nil {
+			return errors.Wrap(err, "the policy manager connector failed to evaluate the policies")
+		}
+		output, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	},
+}
+
+// readResourceMetadata reads the asset metadata sent to the policy manager, if a file is given
+func readResourceMetadata(fileName string) (*datacatalog.ResourceMetadata, error) {
+	if fileName == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the asset metadata")
+	}
+	metadata := &datacatalog.ResourceMetadata{}
+	if err := json.Unmarshal(content, metadata); err != nil {
+		return nil, errors.Wrapf(err, "could not parse the asset metadata %s", fileName)
+	}
+	return metadata, nil
+}
+
+func init() {
+	flags := evaluateCmd.Flags()
+	flags.StringVar(&evaluateOptions.actionType, "action-type", string(taxonomy.ReadFlow),
+		"type of the operation on the asset: read, write, delete or copy")
+	flags.StringVar(&evaluateOptions.destination, "destination", "", "destination of the operation, e.g., the geography of a copy")
+	flags.StringVar(&evaluateOptions.processingLocation, "processing-location", "", "location in which the data is processed")
+	flags.StringVar(&evaluateOptions.metadataFile, "metadata", "", "JSON file with the asset metadata, e.g., its tags and columns")
+	flags.StringVar(&evaluateOptions.credentials, "credentials", "", "credentials path sent to the policy manager connector")
+	flags.StringVar(&evaluateOptions.policyManagerName, "policy-manager-name", os.Getenv(environment.MainPolicyManagerNameKey),
+		"name of the policy manager connector")
+	flags.StringVar(&evaluateOptions.policyManagerURL, "policy-manager-url", os.Getenv(environment.MainPolicyManagerConnectorURLKey),
+		"URL of the policy manager connector, e.g., http://opa-connector:8080 or grpc://opa-connector:50051")
+	rootCmd.AddCommand(evaluateCmd)
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// newMockPolicyManagerServer serves the decisions of the mock policy manager via the OpenAPI of the connectors
+func newMockPolicyManagerServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := &policymanager.GetPolicyDecisionsRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response, err := (&mockup.MockPolicyManager{}).GetPoliciesDecisions(r.Context(), request, r.Header.Get("X-Request-Cred"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runEvaluate runs the evaluate command with the given arguments, starting from the default flag values
+func runEvaluate(args ...string) (string, error) {
+	evaluateCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		_ = flag.Value.Set(flag.DefValue)
+	})
+	output := &bytes.Buffer{}
+	rootCmd.SetOut(output)
+	rootCmd.SetArgs(append([]string{"evaluate"}, args...))
+	err := rootCmd.Execute()
+	return output.String(), err
+}
+
+// decodeDecisions parses the decisions printed by the evaluate command
+func decodeDecisions(g *gomega.WithT, output string) *policymanager.GetPolicyDecisionsResponse {
+	response := &policymanager.GetPolicyDecisionsResponse{}
+	g.Expect(json.Unmarshal([]byte(output), response)).To(gomega.Succeed())
+	return response
+}
+
+func TestEvaluate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := newMockPolicyManagerServer(t)
+
+	// the actions returned for the asset are printed
+	output, err := runEvaluate("s3/redact-dataset", "--policy-manager-url", server.URL)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	response := decodeDecisions(g, output)
+	g.Expect(response.DecisionID).NotTo(gomega.BeEmpty())
+	g.Expect(response.Result).To(gomega.HaveLen(1))
+	g.Expect(response.Result[0].Action).To(gomega.Equal(taxonomy.NewRedactAction("SSN")))
+
+	// the destination is sent to the policy manager
+	output, err = runEvaluate("s3/allow-theshire", "--action-type", "copy", "--destination", "neverland",
+		"--processing-location", "neverland", "--policy-manager-url", server.URL)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	response = decodeDecisions(g, output)
+	g.Expect(response.Result).To(gomega.HaveLen(1))
+	g.Expect(response.Result[0].Action).To(gomega.Equal(taxonomy.NewDenyAction("destination not permitted", "allow-theshire-destination")))
+
+	output, err = runEvaluate("s3/allow-theshire", "--action-type", "copy", "--destination", "theshire",
+		"--policy-manager-url", server.URL)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(decodeDecisions(g, output).Result).To(gomega.BeEmpty())
+}
+
+func TestEvaluateWithMetadata(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := newMockPolicyManagerServer(t)
+
+	// the policies of the mock policy manager deny the access to assets tagged as restricted
+	metadataFile := filepath.Join(t.TempDir(), "metadata.json")
+	g.Expect(os.WriteFile(metadataFile, []byte(`{"tags": {"`+mockup.RestrictedTag+`": true}}`), 0o600)).To(gomega.Succeed())
+	output, err := runEvaluate("s3/allow-dataset", "--metadata", metadataFile, "--policy-manager-url", server.URL)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	response := decodeDecisions(g, output)
+	g.Expect(response.Result).To(gomega.HaveLen(1))
+	g.Expect(response.Result[0].Action.Name).To(gomega.Equal(taxonomy.DenyActionName))
+}
+
+func TestEvaluateInvalidArguments(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := newMockPolicyManagerServer(t)
+
+	_, err := runEvaluate("s3/allow-dataset", "--action-type", "update", "--policy-manager-url", server.URL)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("unknown action type")))
+
+	_, err = runEvaluate("s3/allow-dataset", "--policy-manager-url", "")
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("the URL of the policy manager connector must be provided")))
+
+	_, err = runEvaluate("s3/allow-dataset", "--metadata", filepath.Join(t.TempDir(), "missing.json"),
+		"--policy-manager-url", server.URL)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("could not read the asset metadata")))
+}
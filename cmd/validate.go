@@ -0,0 +1,63 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/validate"
+)
+
+var taxonomyFile string
+
+// validateCmd groups the commands that validate fybrik resources against the taxonomy
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate resources against the taxonomy",
+}
+
+// validatePolicyDecisionsCmd validates the actions of a policy manager response
+var validatePolicyDecisionsCmd = &cobra.Command{
+	Use:   "policy-decisions FILE",
+	Short: "Validate the actions of a policy manager response",
+	Long: `Validate the actions of a policy manager response, given as a JSON file, against the actions defined by the taxonomy.
+Unknown action names and missing or invalid action properties are reported.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		content, err := os.ReadFile(args[0])
+		if err != nil {
+			return errors.Wrap(err, "could not read the policy manager response")
+		}
+		response := &policymanager.GetPolicyDecisionsResponse{}
+		if err := json.Unmarshal(content, response); err != nil {
+			return errors.Wrapf(err, "could not parse the policy manager response %s", args[0])
+		}
+		allErrs, err := validate.ValidatePolicyDecisionActions(response, taxonomyFile)
+		if err != nil {
+			return err
+		}
+		for _, validationErr := range allErrs {
+			fmt.Fprintln(cmd.OutOrStdout(), validationErr.Error())
+		}
+		if len(allErrs) > 0 {
+			return errors.Errorf("found %d errors in the actions of %s", len(allErrs), args[0])
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "all actions are valid")
+		return nil
+	},
+}
+
+func init() {
+	validatePolicyDecisionsCmd.Flags().StringVar(&taxonomyFile, "taxonomy", "taxonomy.json", "taxonomy file that defines the actions")
+	validateCmd.AddCommand(validatePolicyDecisionsCmd)
+	rootCmd.AddCommand(validateCmd)
+}
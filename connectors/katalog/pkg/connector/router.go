@@ -3,7 +3,11 @@
 
 package connector
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
 
 // NewRouter returns a new router.
 func NewRouter(handler *Handler) *gin.Engine {
@@ -12,5 +16,7 @@ func NewRouter(handler *Handler) *gin.Engine {
 	router.POST("/createAsset", handler.createAsset)
 	router.DELETE("/deleteAsset", handler.deleteAsset)
 	router.PATCH("/updateAsset", handler.updateAsset)
+	// the health endpoint is used by the manager to check that the connector is reachable
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
 	return router
 }
@@ -6,6 +6,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -61,47 +62,75 @@ func (r *ConnectorController) GetPoliciesDecisions(c *gin.Context) {
 		return
 	}
 	logging.LogStructure("GetPoliciesDecisions object received:", request, &r.Log, zerolog.DebugLevel, false, false)
+	response, httpCode, err := r.evaluate(&request)
+	if err != nil {
+		r.reportError(c, httpCode, err.Error())
+		return
+	}
+	r.Log.Info().Msg(
+		"Sending response from opa connector with created asset ID: " + string(request.Resource.ID))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPoliciesDecisionsBatch evaluates the requests of several assets, and responds with their decisions in the same order
+func (r *ConnectorController) GetPoliciesDecisionsBatch(c *gin.Context) {
+	var requests []policymanager.GetPolicyDecisionsRequest
+	if err := c.ShouldBindJSON(&requests); err != nil {
+		r.reportError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	logging.LogStructure("GetPoliciesDecisionsBatch object received:", requests, &r.Log, zerolog.DebugLevel, false, false)
+	responses := make([]*policymanager.GetPolicyDecisionsResponse, len(requests))
+	for i := range requests {
+		response, httpCode, err := r.evaluate(&requests[i])
+		if err != nil {
+			r.reportError(c, httpCode, err.Error())
+			return
+		}
+		responses[i] = response
+	}
+	r.Log.Info().Msgf("Sending response from opa connector with the decisions of %d assets", len(responses))
+
+	c.JSON(http.StatusOK, responses)
+}
+
+// evaluate sends a request to OPA, and returns its decisions or the HTTP status code of the failure
+func (r *ConnectorController) evaluate(request *policymanager.GetPolicyDecisionsRequest) (
+	*policymanager.GetPolicyDecisionsResponse, int, error) {
 	// Add "input" hierarchy
-	inputStruct := map[string]interface{}{"input": &request}
+	inputStruct := map[string]interface{}{"input": request}
 	// Marshal request as JSON
 	requestBody, err := json.Marshal(&inputStruct)
 	if err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	// Send request to OPA
 	endpoint := fmt.Sprintf("%s/%s", strings.TrimRight(r.OpaServerURL, "/"), strings.TrimLeft(policyEndpoint, "/"))
 	responseFromOPA, err := r.OpaClient.Post(endpoint, "application/json", bytes.NewBuffer(requestBody))
 	if err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Read response from OPA
 	defer responseFromOPA.Body.Close()
 	responseFromOPABody, err := io.ReadAll(responseFromOPA.Body)
 	if err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Handle errors from OPA
 	if responseFromOPA.StatusCode != http.StatusOK {
 		// TODO: better error handling for OPA errors
-		r.reportError(c, responseFromOPA.StatusCode, string(responseFromOPABody))
-		return
+		return nil, responseFromOPA.StatusCode, errors.New(string(responseFromOPABody))
 	}
 
 	// Unmarshal as GetPolicyDecisionsResponse for the sake of validation
 	var response policymanager.GetPolicyDecisionsResponse
 	if err := json.Unmarshal(responseFromOPABody, &response); err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
-	r.Log.Info().Msg(
-		"Sending response from opa connector with created asset ID: " + string(request.Resource.ID))
-
-	c.JSON(http.StatusOK, response)
+	return &response, http.StatusOK, nil
 }
 
 func (r *ConnectorController) reportError(c *gin.Context, httpCode int, errorMessage string) {
@@ -21,7 +21,8 @@ import (
 	"fybrik.io/fybrik/pkg/serde"
 )
 
-func TestGetPoliciesDecisions(t *testing.T) {
+// policyFixture returns a request to the connector, the request that the connector sends to OPA and the response of OPA
+func policyFixture() (policymanager.GetPolicyDecisionsRequest, map[string]interface{}, *policymanager.GetPolicyDecisionsResponse) {
 	resMetadata := datacatalog.ResourceMetadata{
 		Name: "assetName",
 		Columns: []datacatalog.ResourceColumn{
@@ -76,6 +77,11 @@ func TestGetPoliciesDecisions(t *testing.T) {
 			},
 		},
 	}
+	return request, expectedOpaRequest, mockedOpaResponse
+}
+
+func TestGetPoliciesDecisions(t *testing.T) {
+	request, expectedOpaRequest, mockedOpaResponse := policyFixture()
 	opaMock := createMockServer(t, "opa", &expectedOpaRequest, mockedOpaResponse)
 	defer opaMock.Close()
 
@@ -103,6 +109,32 @@ func TestGetPoliciesDecisions(t *testing.T) {
 	})
 }
 
+func TestGetPoliciesDecisionsBatch(t *testing.T) {
+	request, expectedOpaRequest, mockedOpaResponse := policyFixture()
+	opaMock := createMockServer(t, "opa", &expectedOpaRequest, mockedOpaResponse)
+	defer opaMock.Close()
+
+	controller, err := NewConnectorController(opaMock.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	requestBytes := mustAsJSON(t, []policymanager.GetPolicyDecisionsRequest{request, request})
+	c.Request = httptest.NewRequest(http.MethodPost, "http://localhost/", bytes.NewBuffer(requestBytes))
+
+	// every request of the batch is evaluated by OPA
+	controller.GetPoliciesDecisionsBatch(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var responses []policymanager.GetPolicyDecisionsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &responses))
+	assert.Len(t, responses, 2)
+	for i := range responses {
+		assert.Equal(t, mockedOpaResponse.DecisionID, responses[i].DecisionID)
+	}
+}
+
 func createMockServer(t *testing.T, name string, expectedRequest, mockedResponse interface{}) *httptest.Server {
 	expectedRequestBytes := mustAsJSON(t, expectedRequest)
 	responseBytes := mustAsJSON(t, mockedResponse)
@@ -33,6 +33,9 @@ var (
 func NewRouter(controller *ConnectorController) *gin.Engine {
 	router := gin.Default()
 	router.POST("/getPoliciesDecisions", controller.GetPoliciesDecisions)
+	router.POST("/getPoliciesDecisionsBatch", controller.GetPoliciesDecisionsBatch)
+	// the health endpoint is used by the manager to check that the connector is reachable
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
 	return router
 }
 
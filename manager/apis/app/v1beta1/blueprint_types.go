@@ -4,6 +4,7 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"fybrik.io/fybrik/pkg/model/taxonomy"
@@ -24,6 +25,10 @@ type AssetContext struct {
 	// +optional
 	Transformations []taxonomy.Action `json:"transformations,omitempty"`
 
+	// Pushdown holds the parts of the transformations that the module pushes down to the data source
+	// +optional
+	Pushdown *PushdownHints `json:"pushdown,omitempty"`
+
 	// Capability of the module
 	// +required
 	Capability taxonomy.Capability `json:"capability"`
@@ -66,6 +71,12 @@ type BlueprintModule struct {
 	// as well as module status in the future.
 	// +optional
 	AssetIDs []string `json:"assetIds,omitempty"`
+
+	// DependsOn lists the instance names of the modules whose endpoints this module reads, e.g., the module serving
+	// the data that this module transforms. The module is deployed after them, while the modules that do not depend
+	// on each other are deployed concurrently.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // BlueprintSpec defines the desired state of Blueprint, which defines the components of the workload's data path
@@ -89,6 +100,11 @@ type BlueprintSpec struct {
 	// ApplicationContext is a context of the origin FybrikApplication (labels, properties, etc.)
 	// +optional
 	Application *ApplicationDetails `json:"application,omitempty"`
+
+	// ModuleResources are the compute resources requested by and limited for the modules of the blueprint.
+	// They are passed to the module charts as the resources value.
+	// +optional
+	ModuleResources *corev1.ResourceRequirements `json:"moduleResources,omitempty"`
 }
 
 // BlueprintStatus defines the observed state of Blueprint
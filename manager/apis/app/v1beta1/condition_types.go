@@ -5,6 +5,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ConditionType represents a condition type
@@ -12,10 +13,13 @@ type ConditionType string
 
 // Constants defining condition types
 const (
-	ErrorCondition ConditionType = "Error"
-	DenyCondition  ConditionType = "Deny"
-	ReadyCondition ConditionType = "Ready"
-	ValidCondition ConditionType = "Valid"
+	ErrorCondition         ConditionType = "Error"
+	DenyCondition          ConditionType = "Deny"
+	ReadyCondition         ConditionType = "Ready"
+	ValidCondition         ConditionType = "Valid"
+	WarningCondition       ConditionType = "Warning"
+	AssetNotFoundCondition ConditionType = "AssetNotFound"
+	DryRunCondition        ConditionType = "DryRun"
 )
 
 // Condition describes the state of a FybrikApplication at a certain point.
@@ -34,3 +38,39 @@ type Condition struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
+
+// TransitionType represents a step of the FybrikApplication reconcile
+type TransitionType string
+
+// Constants defining transition types
+const (
+	// PolicyEvaluatedTransition means that the governance actions of the assets have been evaluated
+	PolicyEvaluatedTransition TransitionType = "PolicyEvaluated"
+	// ModuleSelectedTransition means that the modules of the data paths have been selected
+	ModuleSelectedTransition TransitionType = "ModuleSelected"
+	// PlotterCreatedTransition means that the Plotter has been created or updated
+	PlotterCreatedTransition TransitionType = "PlotterCreated"
+	// PlotterStagedTransition means that the Plotter spec has been staged for approval
+	PlotterStagedTransition TransitionType = "PlotterStaged"
+	// ReadyTransition means that the application has become ready
+	ReadyTransition TransitionType = "Ready"
+	// AccessRevokedTransition means that the Plotter has been deleted since the access to some assets has been revoked
+	AccessRevokedTransition TransitionType = "AccessRevoked"
+)
+
+// MaxTransitionHistory is the maximal number of transitions kept in the status of a FybrikApplication
+const MaxTransitionHistory = 20
+
+// Transition records a step of the FybrikApplication reconcile
+type Transition struct {
+	// Type of the transition
+	Type TransitionType `json:"type"`
+	// Time at which the transition has occurred
+	Time metav1.Time `json:"time"`
+	// Message contains the details of the transition
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the version of the resource for which the transition has occurred
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
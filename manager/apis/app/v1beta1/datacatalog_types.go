@@ -18,4 +18,25 @@ type DataStore struct {
 	// Format represents data format (e.g. parquet) as received from catalog connectors
 	// +optional
 	Format taxonomy.DataFormat `json:"format,omitempty"`
+	// Schema is the expected schema of the data written to the asset.
+	// It is set in the strict schema mode only, and modules reject data that does not match it.
+	// +optional
+	Schema *AssetSchema `json:"schema,omitempty"`
+}
+
+// AssetSchema describes the columns of a tabular asset
+type AssetSchema struct {
+	// Columns are the names of the asset columns, in order
+	// +required
+	Columns []string `json:"columns"`
+}
+
+// ColumnSchema describes a column of the data served to the application
+type ColumnSchema struct {
+	// Name of the column
+	// +required
+	Name string `json:"name"`
+	// Data type of the column as defined by the data catalog, e.g., string, integer or timestamp
+	// +optional
+	Type string `json:"type,omitempty"`
 }
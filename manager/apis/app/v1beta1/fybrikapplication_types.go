@@ -4,6 +4,10 @@
 package v1beta1
 
 import (
+	"net"
+	"sort"
+	"strconv"
+
 	"github.com/c2h5oh/datasize"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,11 +45,38 @@ type DataRequirements struct {
 	// +optional
 	Interface *taxonomy.Interface `json:"interface,omitempty"`
 
+	// Consumers are the workloads that consume the asset, each with its own interface.
+	// A data path is constructed for every consumer, and the governance policies are evaluated per consumer.
+	// Interface is ignored if consumers are specified.
+	// +optional
+	Consumers []DataConsumer `json:"consumers,omitempty"`
+
+	// ExpectedColumns are the columns that the data user expects to read. The expected columns that the governance actions
+	// make unavailable, e.g., the columns removed by a projection or redacted, are listed in a warning condition of the asset.
+	// +optional
+	ExpectedColumns []string `json:"expectedColumns,omitempty"`
+
 	// FlowParams include the requirements for particular data flows
 	// +optional
 	FlowParams FlowRequirements `json:"flowParams,omitempty"`
 }
 
+// DataConsumer is a workload that consumes an asset with its own interface
+type DataConsumer struct {
+	// Name identifies the consumer in the endpoints of the asset state
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Interface indicates the protocol and format expected by the consumer
+	// +required
+	Interface taxonomy.Interface `json:"interface"`
+
+	// ClusterName is the cluster in which the consumer runs, the cluster of the workload selector if not specified
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+}
+
 // DataContext indicates data set being processed by the workload
 // and includes information about the data format and technologies used to access the data.
 type DataContext struct {
@@ -65,6 +96,16 @@ type DataContext struct {
 	// Requirements from the system
 	// +required
 	Requirements DataRequirements `json:"requirements"`
+
+	// ModuleHint is the name of a FybrikModule that must be used for the dataset, e.g., for debugging and testing.
+	// It narrows the modules considered by Fybrik to the given one, governance policies are still enforced.
+	// +optional
+	ModuleHint string `json:"moduleHint,omitempty"`
+
+	// Version pins the version of the dataset in the data catalog, e.g., to read a specific snapshot of the data.
+	// The governance policies of the pinned version are enforced. The latest version is used if not specified.
+	// +optional
+	Version string `json:"version,omitempty"`
 }
 
 // FybrikApplicationSpec defines data flows needed by the application, the purpose and other contextual information about the application.
@@ -90,6 +131,35 @@ type FybrikApplicationSpec struct {
 	// and the protocol used to access it and the format expected.
 	// +required
 	Data []DataContext `json:"data"`
+
+	// DryRun indicates that policy evaluation and module selection are performed without deploying anything.
+	// The computed Plotter spec is stored in a ConfigMap instead of creating a Plotter resource.
+	// The assets whose data path has been computed report the DryRun condition rather than the Ready condition.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// RequireApproval indicates that the generated Plotter is reviewed before it is deployed, e.g., in a GitOps flow.
+	// The Plotter spec is staged in a ConfigMap, and the Plotter resource is created only after the application
+	// is annotated with app.fybrik.io/approved-plotter set to the digest of the staged spec.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// StrictSchema indicates that the data written to registered assets has to match the asset schema in the catalog.
+	// The expected schema is recorded in the Plotter, and modules reject the data that does not match it.
+	// +optional
+	StrictSchema bool `json:"strictSchema,omitempty"`
+
+	// ModulesNamespace is the namespace where the modules of the application are deployed, e.g., a namespace of the team
+	// for isolation. The modules namespace configured for Fybrik is used if not specified.
+	// The Fybrik manager must be permitted to deploy modules in this namespace.
+	// +optional
+	ModulesNamespace string `json:"modulesNamespace,omitempty"`
+
+	// ModuleResources are the compute resources requested by and limited for the data-plane modules deployed for the application,
+	// e.g., to bound the resource usage of a large read. They are passed to the module charts as the resources value,
+	// overriding the defaults of the charts.
+	// +optional
+	ModuleResources *corev1.ResourceRequirements `json:"moduleResources,omitempty"`
 }
 
 // ResourceReference contains resource identifier(name, namespace, kind)
@@ -125,14 +195,29 @@ type DatasetDetails struct {
 	// Persistent storage (not to be removed after FybrikApplication is deleted)
 	// +optional
 	Persistent bool `json:"persistent,omitempty"`
+
+	// Staging storage written by a transactional module. The data is committed to the dataset storage once the write completes,
+	// or discarded if the write fails.
+	// +optional
+	Staging *DataStore `json:"staging,omitempty"`
 }
 
 // AssetState defines the observed state of an asset
 type AssetState struct {
-	// Conditions indicate the asset state (Ready, Deny, Error)
+	// Conditions indicate the asset state (Ready, Deny, Error, Warning, AssetNotFound, DryRun)
 	// +optional
 	Conditions []Condition `json:"conditions,omitempty"`
 
+	// AppliedActions are the governance actions applied to the data served to the application, e.g., the redacted columns.
+	// The actions are listed once, in the order in which they are applied, even if the asset is served to several consumers.
+	// +optional
+	AppliedActions []taxonomy.Action `json:"appliedActions,omitempty"`
+
+	// Schema lists the columns of the data served to the application, after the governance actions are applied,
+	// e.g., without the columns removed by a projection. It is derived from the columns defined by the data catalog.
+	// +optional
+	Schema []ColumnSchema `json:"schema,omitempty"`
+
 	// CatalogedAsset provides a new asset identifier after being registered in the enterprise catalog
 	// +optional
 	CatalogedAsset string `json:"catalogedAsset,omitempty"`
@@ -140,6 +225,107 @@ type AssetState struct {
 	// Endpoint provides the endpoint spec from which the asset will be served to the application
 	// +optional
 	Endpoint taxonomy.Connection `json:"endpoint,omitempty"`
+
+	// Endpoints provides the connection details of every protocol from which the asset is served to the application.
+	// The keys are connection types of the taxonomy, e.g., fybrik-arrow-flight or s3, and include the protocol of Endpoint.
+	// +optional
+	Endpoints map[taxonomy.ConnectionType]taxonomy.Connection `json:"endpoints,omitempty"`
+
+	// Services identifies the in-cluster services of the endpoints, by the same keys as Endpoints.
+	// Only endpoints with a hostname of a service in the modules namespace are included.
+	// +optional
+	Services map[taxonomy.ConnectionType]ServiceEndpoint `json:"services,omitempty"`
+
+	// Formats lists the data formats in which the asset is served by every protocol, by the same keys as Endpoints.
+	// Only endpoints whose data format is advertised by the module are included.
+	// +optional
+	Formats map[taxonomy.ConnectionType][]taxonomy.DataFormat `json:"formats,omitempty"`
+
+	// ConsumerEndpoints provides the endpoint from which the asset is served to every consumer, by the consumer name.
+	// Endpoint is the endpoint of the first consumer.
+	// +optional
+	ConsumerEndpoints map[string]taxonomy.Connection `json:"consumerEndpoints,omitempty"`
+
+	// DecisionIDs are the identifiers of the policy manager decisions that have been evaluated for the asset
+	// +optional
+	DecisionIDs []string `json:"decisionIDs,omitempty"`
+
+	// PolicyReevaluation is the time at which the policy decisions of the asset are evaluated again,
+	// since an action starts or stops to apply at this time according to its validity window,
+	// or since the decisions are evaluated periodically
+	// +optional
+	PolicyReevaluation *metav1.Time `json:"policyReevaluation,omitempty"`
+}
+
+// GetEndpoint returns the connection details of the endpoint serving the asset with the given protocol
+func (s *AssetState) GetEndpoint(protocol taxonomy.ConnectionType) (map[string]interface{}, bool) {
+	connection, found := s.Endpoints[protocol]
+	if !found {
+		if s.Endpoint.Name != protocol {
+			return nil, false
+		}
+		connection = s.Endpoint
+	}
+	details, ok := connection.AdditionalProperties.Items[string(protocol)].(map[string]interface{})
+	return details, ok
+}
+
+// SelectEndpoint returns the protocol and the connection details of an endpoint serving the asset in the given format.
+// The protocol of Endpoint is preferred, the other protocols are considered in the order of their names.
+func (s *AssetState) SelectEndpoint(format taxonomy.DataFormat) (taxonomy.ConnectionType, map[string]interface{}, bool) {
+	protocols := make([]taxonomy.ConnectionType, 0, len(s.Formats))
+	for protocol := range s.Formats {
+		protocols = append(protocols, protocol)
+	}
+	sort.Slice(protocols, func(i, j int) bool {
+		if (protocols[i] == s.Endpoint.Name) != (protocols[j] == s.Endpoint.Name) {
+			return protocols[i] == s.Endpoint.Name
+		}
+		return protocols[i] < protocols[j]
+	})
+	for _, protocol := range protocols {
+		for _, served := range s.Formats[protocol] {
+			if served != format {
+				continue
+			}
+			if details, found := s.GetEndpoint(protocol); found {
+				return protocol, details, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// GetService returns the in-cluster service of the endpoint serving the asset with the given protocol
+func (s *AssetState) GetService(protocol taxonomy.ConnectionType) (*ServiceEndpoint, bool) {
+	service, found := s.Services[protocol]
+	if !found {
+		return nil, false
+	}
+	return &service, true
+}
+
+// ServiceEndpoint identifies a kubernetes service serving an asset
+type ServiceEndpoint struct {
+	// Name of the service
+	Name string `json:"name"`
+
+	// Namespace of the service
+	Namespace string `json:"namespace"`
+
+	// Port of the service
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// Hostname returns the in-cluster DNS name of the service
+func (s *ServiceEndpoint) Hostname() string {
+	return s.Name + "." + s.Namespace
+}
+
+// Address returns the in-cluster host and port of the service
+func (s *ServiceEndpoint) Address() string {
+	return net.JoinHostPort(s.Hostname(), strconv.Itoa(int(s.Port)))
 }
 
 // FybrikApplicationStatus defines the observed state of FybrikApplication.
@@ -152,6 +338,14 @@ type FybrikApplicationStatus struct {
 	// +optional
 	ErrorMessage string `json:"errorMessage,omitempty"`
 
+	// ReadyAssets is the number of assets that are ready to be used
+	// +optional
+	ReadyAssets int `json:"readyAssets,omitempty"`
+
+	// DeniedAssets is the number of assets to which the access has been denied
+	// +optional
+	DeniedAssets int `json:"deniedAssets,omitempty"`
+
 	// AssetStates provides a status per asset
 	// +optional
 	AssetStates map[string]AssetState `json:"assetStates,omitempty"`
@@ -173,12 +367,34 @@ type FybrikApplicationStatus struct {
 	// +optional
 	Generated *ResourceReference `json:"generated,omitempty"`
 
+	// DryRunResult identifies the ConfigMap holding the Plotter spec computed in the dry-run mode
+	// +optional
+	DryRunResult *ResourceReference `json:"dryRunResult,omitempty"`
+
+	// StagedPlotter identifies the ConfigMap holding the Plotter spec that waits for approval
+	// +optional
+	StagedPlotter *ResourceReference `json:"stagedPlotter,omitempty"`
+
+	// StagedPlotterDigest is the digest of the staged Plotter spec, which approves it when set as the approval annotation
+	// +optional
+	StagedPlotterDigest string `json:"stagedPlotterDigest,omitempty"`
+
+	// PolicyManager is the named policy manager, selected by the app.fybrik.io/policy-manager annotation,
+	// that has evaluated the governance actions. It is empty if they are evaluated by the default policy managers.
+	// +optional
+	PolicyManager string `json:"policyManager,omitempty"`
+
 	// ProvisionedStorage maps a dataset (identified by AssetID) to the new provisioned bucket.
 	// It allows FybrikApplication controller to manage buckets in case the spec has been modified, an error has occurred,
 	// or a delete event has been received.
 	// ProvisionedStorage has the information required to register the dataset once the owned plotter resource is ready
 	// +optional
 	ProvisionedStorage map[string]DatasetDetails `json:"provisionedStorage,omitempty"`
+
+	// History lists the latest transitions of the reconcile, from the oldest to the newest.
+	// At most MaxTransitionHistory transitions are kept.
+	// +optional
+	History []Transition `json:"history,omitempty"`
 }
 
 // FybrikApplication provides information about the application whose data is being operated on,
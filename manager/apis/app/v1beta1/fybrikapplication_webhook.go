@@ -4,27 +4,88 @@
 package v1beta1
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/validate"
 )
 
 var taxonomyFilePath = environment.GetDataDir() + "/taxonomy/fybrik_application.json"
 
+// Annotations identifying the user that has created the FybrikApplication.
+// The groups are separated by commas.
+const (
+	RequesterAnnotation       = "app.fybrik.io/requester"
+	RequesterGroupsAnnotation = "app.fybrik.io/requester-groups"
+)
+
 func (r *FybrikApplication) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&RequesterDefaulter{}).
 		Complete()
 }
 
+// +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/mutate-app-fybrik-io-v1beta1-fybrikapplication,mutating=true,failurePolicy=fail,groups=app.fybrik.io,resources=fybrikapplications,versions=v1beta1,name=mfybrikapplication.kb.io
+
+// RequesterDefaulter records the user that has created a FybrikApplication in its annotations.
+// The user is taken from the admission request on creation and preserved on updates, so it can not be altered.
+type RequesterDefaulter struct{}
+
+var _ admission.CustomDefaulter = &RequesterDefaulter{}
+
+// Default implements admission.CustomDefaulter so a webhook will be registered for the type
+func (d *RequesterDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	application, ok := obj.(*FybrikApplication)
+	if !ok {
+		return fmt.Errorf("expected a FybrikApplication but got a %T", obj)
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	requester := req.UserInfo.Username
+	groups := strings.Join(req.UserInfo.Groups, ",")
+	if req.Operation == admissionv1.Update {
+		old := &FybrikApplication{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return err
+		}
+		requester = old.Annotations[RequesterAnnotation]
+		groups = old.Annotations[RequesterGroupsAnnotation]
+	}
+	annotations := application.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	delete(annotations, RequesterAnnotation)
+	delete(annotations, RequesterGroupsAnnotation)
+	if requester != "" {
+		annotations[RequesterAnnotation] = requester
+	}
+	if groups != "" {
+		annotations[RequesterGroupsAnnotation] = groups
+	}
+	application.SetAnnotations(annotations)
+	return nil
+}
+
 // +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/validate-app-fybrik-io-v1beta1-fybrikapplication,mutating=false,failurePolicy=fail,groups=app.fybrik.io,resources=fybrikapplications,versions=v1beta1,name=vfybrikapplication.kb.io
 
 var _ webhook.Validator = &FybrikApplication{}
@@ -59,6 +120,10 @@ func (r *FybrikApplication) ValidateFybrikApplication(taxonomyFile string) error
 	if err != nil {
 		return err
 	}
+	// Validate the consistency of the requested data flows
+	allErrs = append(allErrs, r.validateDataContexts()...)
+	allErrs = append(allErrs, r.validateModulesNamespace()...)
+	allErrs = append(allErrs, r.validateModuleResources()...)
 
 	// Return any error
 	if len(allErrs) == 0 {
@@ -69,3 +134,112 @@ func (r *FybrikApplication) ValidateFybrikApplication(taxonomyFile string) error
 		schema.GroupKind{Group: "app.fybrik.io", Kind: "FybrikApplication"},
 		r.Name, allErrs)
 }
+
+// validateModulesNamespace checks that the modules namespace, if specified, is a valid namespace name
+func (r *FybrikApplication) validateModulesNamespace() []*field.Error {
+	namespace := r.Spec.ModulesNamespace
+	if namespace == "" {
+		return nil
+	}
+	var allErrs []*field.Error
+	for _, msg := range validation.IsDNS1123Label(namespace) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "modulesNamespace"), namespace, msg))
+	}
+	return allErrs
+}
+
+// validateModuleResources checks that the resources requested for the modules do not exceed their limits
+func (r *FybrikApplication) validateModuleResources() []*field.Error {
+	resources := r.Spec.ModuleResources
+	if resources == nil {
+		return nil
+	}
+	names := []string{}
+	for name := range resources.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	var allErrs []*field.Error
+	path := field.NewPath("spec", "moduleResources", "requests")
+	for _, name := range names {
+		request := resources.Requests[corev1.ResourceName(name)]
+		if limit, found := resources.Limits[corev1.ResourceName(name)]; found && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Key(name), request.String(),
+				"must be less than or equal to the limit "+limit.String()))
+		}
+	}
+	return allErrs
+}
+
+// validateDataContexts rejects data contexts that can not be fulfilled regardless of the taxonomy,
+// e.g., an empty dataset ID or flow parameters that are not relevant to the requested flow
+func (r *FybrikApplication) validateDataContexts() []*field.Error {
+	var allErrs []*field.Error
+	type flowKey struct {
+		dataSetID string
+		flow      taxonomy.DataFlow
+	}
+	seen := map[flowKey]int{}
+	dataPath := field.NewPath("spec", "data")
+	for i := range r.Spec.Data {
+		dataCtx := &r.Spec.Data[i]
+		path := dataPath.Index(i)
+		flow := dataCtx.Flow
+		if flow == "" {
+			flow = taxonomy.ReadFlow
+		}
+		if strings.TrimSpace(dataCtx.DataSetID) == "" {
+			allErrs = append(allErrs, field.Required(path.Child("dataSetID"), "must not be empty"))
+		} else {
+			key := flowKey{dataSetID: dataCtx.DataSetID, flow: flow}
+			if first, found := seen[key]; found {
+				allErrs = append(allErrs, field.Duplicate(path.Child("dataSetID"), dataCtx.DataSetID+
+					" is already requested for the "+string(flow)+" flow by "+dataPath.Index(first).String()))
+			} else {
+				seen[key] = i
+			}
+		}
+		flowParams := &dataCtx.Requirements.FlowParams
+		flowParamsPath := path.Child("requirements", "flowParams")
+		if flowParams.IsNewDataSet && flow != taxonomy.WriteFlow {
+			allErrs = append(allErrs, field.Forbidden(flowParamsPath.Child("isNewDataSet"),
+				"new datasets can be created by the write flow only, the requested flow is "+string(flow)))
+		}
+		if flowParams.ResourceMetadata != nil && !flowParams.IsNewDataSet {
+			allErrs = append(allErrs, field.Forbidden(flowParamsPath.Child("metadata"),
+				"metadata can be provided for new datasets only, the metadata of existing datasets is retrieved from the catalog"))
+		}
+		if flowParams.Catalog != "" && (flow == taxonomy.ReadFlow || flow == taxonomy.DeleteFlow) {
+			allErrs = append(allErrs, field.Forbidden(flowParamsPath.Child("catalog"),
+				"datasets are registered in a catalog by the copy and write flows only, the requested flow is "+string(flow)))
+		}
+		if dataCtx.ModuleHint != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(dataCtx.ModuleHint) {
+				allErrs = append(allErrs, field.Invalid(path.Child("moduleHint"), dataCtx.ModuleHint, msg))
+			}
+		}
+		allErrs = append(allErrs, validateConsumers(dataCtx.Requirements.Consumers, flow, path.Child("requirements", "consumers"))...)
+	}
+	return allErrs
+}
+
+// validateConsumers checks that the consumers of a dataset have unique names, and that the dataset is read by them
+func validateConsumers(consumers []DataConsumer, flow taxonomy.DataFlow, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(consumers) > 0 && flow != taxonomy.ReadFlow {
+		allErrs = append(allErrs, field.Forbidden(path, "consumers can be specified for the read flow only, the requested flow is "+string(flow)))
+	}
+	names := map[string]bool{}
+	for i := range consumers {
+		namePath := path.Index(i).Child("name")
+		switch {
+		case consumers[i].Name == "":
+			allErrs = append(allErrs, field.Required(namePath, "must not be empty"))
+		case names[consumers[i].Name]:
+			allErrs = append(allErrs, field.Duplicate(namePath, consumers[i].Name))
+		default:
+			names[consumers[i].Name] = true
+		}
+	}
+	return allErrs
+}
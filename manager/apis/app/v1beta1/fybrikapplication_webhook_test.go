@@ -4,12 +4,24 @@
 package v1beta1
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/yaml"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 )
 
 func TestValidApplicationWithBaseTaxonomy(t *testing.T) {
@@ -97,3 +109,160 @@ func TestInvalidInterfaceWithEnhancedTaxonomy(t *testing.T) {
 	validateErr := (*fybrikApp).ValidateFybrikApplication(taxonomyFile)
 	assert.NotNil(t, validateErr, "Invalid interface error should be found")
 }
+
+func TestInvalidDataContexts(t *testing.T) {
+	t.Parallel()
+
+	filename := "../../../testdata/unittests/fybrikapplication-validForBase.yaml"
+	applicationYaml, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+		return
+	}
+
+	validApp := &FybrikApplication{}
+	err = yaml.Unmarshal(applicationYaml, validApp)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+		return
+	}
+	taxonomyFile := "../../../testdata/unittests/basetaxonomy/fybrik_application.json"
+	tests := []struct {
+		name     string
+		mutate   func(dataCtx *DataContext)
+		expected string
+	}{
+		{"empty dataset ID", func(dataCtx *DataContext) { dataCtx.DataSetID = " " },
+			"spec.data[0].dataSetID: Required value: must not be empty"},
+		{"new dataset is read", func(dataCtx *DataContext) { dataCtx.Requirements.FlowParams.IsNewDataSet = true },
+			"spec.data[0].requirements.flowParams.isNewDataSet: Forbidden: new datasets can be created by the write flow only"},
+		{"metadata of an existing dataset", func(dataCtx *DataContext) {
+			dataCtx.Flow = taxonomy.WriteFlow
+			dataCtx.Requirements.FlowParams.ResourceMetadata = &datacatalog.ResourceMetadata{Name: "new-data"}
+		}, "spec.data[0].requirements.flowParams.metadata: Forbidden"},
+		{"catalog in the read flow", func(dataCtx *DataContext) { dataCtx.Requirements.FlowParams.Catalog = "fybrik-notebook-sample" },
+			"spec.data[0].requirements.flowParams.catalog: Forbidden"},
+		{"invalid module hint", func(dataCtx *DataContext) { dataCtx.ModuleHint = "Arrow_Flight" },
+			"spec.data[0].moduleHint: Invalid value: \"Arrow_Flight\""},
+		{"duplicate consumers", func(dataCtx *DataContext) {
+			consumer := DataConsumer{Name: "notebook", Interface: taxonomy.Interface{Protocol: "fybrik-arrow-flight"}}
+			dataCtx.Requirements.Consumers = []DataConsumer{consumer, consumer}
+		}, "spec.data[0].requirements.consumers[1].name: Duplicate value: \"notebook\""},
+		{"consumers of the write flow", func(dataCtx *DataContext) {
+			dataCtx.Flow = taxonomy.WriteFlow
+			dataCtx.Requirements.Consumers = []DataConsumer{{Name: "job", Interface: taxonomy.Interface{Protocol: "s3"}}}
+		}, "spec.data[0].requirements.consumers: Forbidden"},
+	}
+	for _, test := range tests {
+		fybrikApp := validApp.DeepCopy()
+		test.mutate(&fybrikApp.Spec.Data[0])
+		validateErr := fybrikApp.ValidateFybrikApplication(taxonomyFile)
+		assert.NotNil(t, validateErr, "%s: an error should be found", test.name)
+		if validateErr != nil {
+			assert.Contains(t, validateErr.Error(), test.expected, test.name)
+		}
+	}
+
+	// the same dataset can be used by different flows, but can not be requested twice for the same flow
+	fybrikApp := validApp.DeepCopy()
+	fybrikApp.Spec.Data = append(fybrikApp.Spec.Data, fybrikApp.Spec.Data[0])
+	fybrikApp.Spec.Data[1].Flow = taxonomy.CopyFlow
+	assert.Nil(t, fybrikApp.ValidateFybrikApplication(taxonomyFile), "No error should be found")
+	fybrikApp.Spec.Data[1].Flow = taxonomy.ReadFlow
+	validateErr := fybrikApp.ValidateFybrikApplication(taxonomyFile)
+	assert.NotNil(t, validateErr, "Duplicate data context error should be found")
+	if validateErr != nil {
+		assert.Contains(t, validateErr.Error(), "spec.data[1].dataSetID: Duplicate value")
+	}
+}
+
+func TestInvalidModulesNamespace(t *testing.T) {
+	t.Parallel()
+
+	filename := "../../../testdata/unittests/fybrikapplication-validForBase.yaml"
+	applicationYaml, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+		return
+	}
+
+	fybrikApp := &FybrikApplication{}
+	err = yaml.Unmarshal(applicationYaml, fybrikApp)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+		return
+	}
+	taxonomyFile := "../../../testdata/unittests/basetaxonomy/fybrik_application.json"
+	fybrikApp.Spec.ModulesNamespace = "team-a"
+	assert.Nil(t, fybrikApp.ValidateFybrikApplication(taxonomyFile), "No error should be found")
+	fybrikApp.Spec.ModulesNamespace = "Team_A"
+	validateErr := fybrikApp.ValidateFybrikApplication(taxonomyFile)
+	assert.NotNil(t, validateErr, "Invalid namespace error should be found")
+	if validateErr != nil {
+		assert.Contains(t, validateErr.Error(), "spec.modulesNamespace: Invalid value: \"Team_A\"")
+	}
+}
+
+func TestInvalidModuleResources(t *testing.T) {
+	t.Parallel()
+
+	filename := "../../../testdata/unittests/fybrikapplication-validForBase.yaml"
+	applicationYaml, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+		return
+	}
+
+	fybrikApp := &FybrikApplication{}
+	err = yaml.Unmarshal(applicationYaml, fybrikApp)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+		return
+	}
+	taxonomyFile := "../../../testdata/unittests/basetaxonomy/fybrik_application.json"
+	fybrikApp.Spec.ModuleResources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	assert.Nil(t, fybrikApp.ValidateFybrikApplication(taxonomyFile), "No error should be found")
+	fybrikApp.Spec.ModuleResources.Requests[corev1.ResourceMemory] = resource.MustParse("2Gi")
+	validateErr := fybrikApp.ValidateFybrikApplication(taxonomyFile)
+	assert.NotNil(t, validateErr, "Invalid resources error should be found")
+	if validateErr != nil {
+		assert.Contains(t, validateErr.Error(), "spec.moduleResources.requests[memory]: Invalid value: \"2Gi\"")
+	}
+}
+
+func TestRequesterDefaulter(t *testing.T) {
+	t.Parallel()
+
+	defaulter := &RequesterDefaulter{}
+	userInfo := authenticationv1.UserInfo{Username: "alice", Groups: []string{"analysts", "system:authenticated"}}
+	created := &FybrikApplication{ObjectMeta: metav1.ObjectMeta{
+		Name:        "app",
+		Annotations: map[string]string{RequesterAnnotation: "admin", "app": "notebook"},
+	}}
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		UserInfo:  userInfo,
+	}})
+	assert.Nil(t, defaulter.Default(ctx, created))
+	assert.Equal(t, map[string]string{
+		RequesterAnnotation:       "alice",
+		RequesterGroupsAnnotation: "analysts,system:authenticated",
+		"app":                     "notebook",
+	}, created.Annotations, "the requester should be taken from the admission request")
+
+	// the requester is preserved when another user updates the application
+	oldObject, err := json.Marshal(created)
+	assert.Nil(t, err)
+	updated := created.DeepCopy()
+	updated.Annotations[RequesterGroupsAnnotation] = "admin"
+	ctx = admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		UserInfo:  authenticationv1.UserInfo{Username: "bob", Groups: []string{"admin"}},
+		OldObject: runtime.RawExtension{Raw: oldObject},
+	}})
+	assert.Nil(t, defaulter.Default(ctx, updated))
+	assert.Equal(t, created.Annotations, updated.Annotations, "the requester should not be changed")
+}
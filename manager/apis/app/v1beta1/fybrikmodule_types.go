@@ -105,12 +105,80 @@ type ModuleCapability struct {
 	// Plugins enable the module to add libraries to perform actions rather than implementing them by itself
 	// +optional
 	Plugins []Plugin `json:"plugins,omitempty"`
+
+	// Transactional indicates that the module writes a new asset to a staging location by a Kubernetes job.
+	// The staged data is committed to the location of the asset once the jobs of the module have run to completion,
+	// or discarded if the module fails, so that a partial write is never visible. Applies to the write capability only.
+	// +optional
+	Transactional bool `json:"transactional,omitempty"`
+}
+
+// SupportsAction returns true if the capability applies the given governance action.
+// The action returned by the policy manager is matched by name with the actions declared by the capability,
+// so that a module may support any action defined by the taxonomy, e.g., a custom TokenizeAction.
+func (c *ModuleCapability) SupportsAction(action *taxonomy.Action) bool {
+	for i := range c.Actions {
+		if c.Actions[i].Name == action.Name {
+			return c.Actions[i].Supports(action)
+		}
+	}
+	return false
+}
+
+// PushesDown returns true if the capability pushes the given governance action down to the data source
+func (c *ModuleCapability) PushesDown(action *taxonomy.Action) bool {
+	for i := range c.Actions {
+		if c.Actions[i].Name == action.Name {
+			return c.Actions[i].Pushdown
+		}
+	}
+	return false
 }
 
 type ModuleSupportedAction struct {
 	// Unique name of an action supported by the module
 	// +required
 	Name taxonomy.ActionName `json:"name"`
+
+	// Strategies of the RedactAction supported by the module, i.e., how the redacted values are replaced by column type.
+	// Only the masked-string strategy is supported if not specified.
+	// +optional
+	RedactionStrategies []taxonomy.RedactionStrategy `json:"redactionStrategies,omitempty"`
+
+	// Pushdown indicates that the module pushes a ProjectionAction or a FilterAction down to the data source,
+	// i.e., reads only the allowed columns or the selected rows rather than removing the others from the data it fetched.
+	// Modules that push the actions down are preferred, and receive the columns and predicates as pushdown hints.
+	// +optional
+	Pushdown bool `json:"pushdown,omitempty"`
+}
+
+// Supports returns true if the module supports the properties of a governance action of the same name,
+// i.e., all strategies applied by a RedactAction
+func (a *ModuleSupportedAction) Supports(action *taxonomy.Action) bool {
+	if action.Name != taxonomy.RedactActionName {
+		return true
+	}
+	redact := taxonomy.RedactAction{}
+	if err := taxonomy.DecodeActionProperties(action, &redact); err != nil {
+		return false
+	}
+	strategies := a.RedactionStrategies
+	if len(strategies) == 0 {
+		strategies = []taxonomy.RedactionStrategy{taxonomy.RedactMaskedString}
+	}
+	for _, strategy := range redact.UsedStrategies() {
+		found := false
+		for _, supported := range strategies {
+			if supported == strategy {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // ResourceStatusIndicator is used to determine the status of an orchestrated resource
@@ -173,6 +241,43 @@ type FybrikModuleSpec struct {
 	// StatusIndicators allow to check status of a non-standard resource that can not be computed by helm/kstatus
 	// +optional
 	StatusIndicators []ResourceStatusIndicator `json:"statusIndicators,omitempty"`
+
+	// Placement restricts the geographies and clusters in which the module can be deployed.
+	// If not specified, the module can be deployed in any cluster.
+	// +optional
+	Placement *ModulePlacement `json:"placement,omitempty"`
+}
+
+// ModulePlacement declares where a module is allowed to run
+type ModulePlacement struct {
+	// Geographies (cluster regions) in which the module can run. An empty list means any geography.
+	// +optional
+	Geographies []string `json:"geographies,omitempty"`
+
+	// Clusters in which the module can run. An empty list means any cluster.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// Allows returns true if the module can run in the given cluster located in the given region
+func (p *ModulePlacement) Allows(cluster, region string) bool {
+	if p == nil {
+		return true
+	}
+	return contains(p.Geographies, region) && contains(p.Clusters, cluster)
+}
+
+// contains returns true if the list is empty or includes the value
+func contains(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
 
 // ChartSpec specifies chart name and values
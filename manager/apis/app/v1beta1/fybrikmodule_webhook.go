@@ -5,6 +5,7 @@ package v1beta1
 
 import (
 	"encoding/json"
+	"path/filepath"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -14,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/validate"
 )
 
@@ -44,20 +46,29 @@ func (r *FybrikModule) ValidateDelete() error {
 }
 
 func (r *FybrikModule) ValidateFybrikModule(taxonomyFile string) error {
-	var allErrs []*field.Error
-
-	// Convert FybrikModule Go struct to JSON
-	moduleJSON, err := json.Marshal(&r.Spec)
+	// Validate the supported actions first, so that actions unknown to the taxonomy are reported explicitly
+	allErrs, err := r.validateActions(filepath.Join(filepath.Dir(taxonomyFile), "taxonomy.json"))
 	if err != nil {
 		return err
 	}
 
-	// Validate Fybrik module against taxonomy
-	allErrs, err = validate.TaxonomyCheck(moduleJSON, taxonomyFile)
-	if err != nil {
-		return err
+	if len(allErrs) == 0 {
+		// Convert FybrikModule Go struct to JSON
+		// The actions that extend the taxonomy have been validated by their names
+		moduleJSON, err := json.Marshal(r.specWithoutActionExtensions())
+		if err != nil {
+			return err
+		}
+
+		// Validate Fybrik module against taxonomy
+		allErrs, err = validate.TaxonomyCheck(moduleJSON, taxonomyFile)
+		if err != nil {
+			return err
+		}
 	}
 
+	allErrs = append(allErrs, r.validateCapabilities()...)
+
 	// Return any error
 	if len(allErrs) == 0 {
 		return nil
@@ -67,3 +78,53 @@ func (r *FybrikModule) ValidateFybrikModule(taxonomyFile string) error {
 		schema.GroupKind{Group: "app.fybrik.io", Kind: "FybrikModule"},
 		r.Name, allErrs)
 }
+
+// specWithoutActionExtensions returns the module spec without the supported actions that are registered extensions of the taxonomy
+func (r *FybrikModule) specWithoutActionExtensions() *FybrikModuleSpec {
+	spec := r.Spec.DeepCopy()
+	for i := range spec.Capabilities {
+		actions := []ModuleSupportedAction{}
+		for _, action := range spec.Capabilities[i].Actions {
+			if !validate.IsActionExtension(action.Name) {
+				actions = append(actions, action)
+			}
+		}
+		if len(actions) < len(spec.Capabilities[i].Actions) {
+			spec.Capabilities[i].Actions = actions
+		}
+	}
+	return spec
+}
+
+// validateActions checks that the actions supported by the module capabilities are defined by the given taxonomy file
+func (r *FybrikModule) validateActions(actionTaxonomy string) (field.ErrorList, error) {
+	validator, err := validate.NewActionValidator(actionTaxonomy)
+	if err != nil {
+		return nil, err
+	}
+	var allErrs field.ErrorList
+	capabilitiesPath := field.NewPath("spec", "capabilities")
+	for i := range r.Spec.Capabilities {
+		for j, action := range r.Spec.Capabilities[i].Actions {
+			path := capabilitiesPath.Index(i).Child("actions").Index(j).Child("name")
+			if err := validator.ValidateName(action.Name, path); err != nil {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// validateCapabilities checks that the transactional commit of the written data is declared by write capabilities only
+func (r *FybrikModule) validateCapabilities() field.ErrorList {
+	var allErrs field.ErrorList
+	capabilitiesPath := field.NewPath("spec", "capabilities")
+	for i := range r.Spec.Capabilities {
+		capability := &r.Spec.Capabilities[i]
+		if capability.Transactional && capability.Capability != taxonomy.Capability(taxonomy.WriteFlow) {
+			allErrs = append(allErrs, field.Invalid(capabilitiesPath.Index(i).Child("transactional"), capability.Transactional,
+				"only the write capability can be transactional"))
+		}
+	}
+	return allErrs
+}
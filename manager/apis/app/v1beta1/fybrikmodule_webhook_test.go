@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/yaml"
 )
 
@@ -52,3 +53,43 @@ func TestInvalidModuleWithTaxonomy(t *testing.T) {
 	validateErr := fybrikModule.ValidateFybrikModule(taxonomyFile)
 	assert.NotNil(t, validateErr, "Invalid actions error should be found")
 }
+
+func TestModuleActionsUnknownToTaxonomy(t *testing.T) {
+	t.Parallel()
+
+	moduleYaml, err := os.ReadFile("../../../testdata/unittests/fybrikmodule-validActions.yaml")
+	assert.Nil(t, err)
+	fybrikModule := &FybrikModule{}
+	assert.Nil(t, yaml.Unmarshal(moduleYaml, fybrikModule))
+	actionTaxonomy := "../../../testdata/unittests/sampletaxonomy/taxonomy.json"
+
+	allErrs, err := fybrikModule.validateActions(actionTaxonomy)
+	assert.Nil(t, err)
+	assert.Empty(t, allErrs, "RedactAction and RemoveAction are defined by the taxonomy")
+
+	fybrikModule.Spec.Capabilities[0].Actions[1].Name = "RemovalAction"
+	allErrs, err = fybrikModule.validateActions(actionTaxonomy)
+	assert.Nil(t, err)
+	assert.Len(t, allErrs, 1)
+	assert.Equal(t, field.ErrorTypeNotSupported, allErrs[0].Type)
+	assert.Equal(t, "spec.capabilities[0].actions[1].name", allErrs[0].Field)
+
+	validateErr := fybrikModule.ValidateFybrikModule("../../../testdata/unittests/sampletaxonomy/fybrik_module.json")
+	assert.NotNil(t, validateErr)
+	assert.Contains(t, validateErr.Error(), "RemovalAction")
+}
+
+func TestTransactionalCapability(t *testing.T) {
+	t.Parallel()
+
+	fybrikModule := &FybrikModule{Spec: FybrikModuleSpec{Capabilities: []ModuleCapability{
+		{Capability: "write", Transactional: true},
+		{Capability: "read"},
+	}}}
+	assert.Empty(t, fybrikModule.validateCapabilities())
+
+	fybrikModule.Spec.Capabilities[1].Transactional = true
+	allErrs := fybrikModule.validateCapabilities()
+	assert.Len(t, allErrs, 1)
+	assert.Equal(t, "spec.capabilities[1].transactional", allErrs[0].Field)
+}
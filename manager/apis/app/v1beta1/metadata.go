@@ -9,4 +9,7 @@ type ObservedState struct {
 	Ready bool `json:"ready,omitempty"`
 	// Error indicates that there has been an error to orchestrate the modules and provides the error message
 	Error string `json:"error,omitempty"`
+	// Completed represents that the modules have run to completion, i.e., that the jobs deployed by the modules have succeeded.
+	// The data written by a transactional module is committed once the module has completed.
+	Completed bool `json:"completed,omitempty"`
 }
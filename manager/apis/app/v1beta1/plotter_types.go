@@ -4,6 +4,7 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"fybrik.io/fybrik/pkg/model/datacatalog"
@@ -46,6 +47,22 @@ type StepParameters struct {
 	// Actions are the data transformations that the module supports
 	// +optional
 	Actions []taxonomy.Action `json:"action,omitempty"`
+
+	// Pushdown holds the parts of the actions that the module pushes down to the data source
+	// +optional
+	Pushdown *PushdownHints `json:"pushdown,omitempty"`
+}
+
+// PushdownHints are the columns and rows to be read from the data source by a module that pushes the
+// projections and filters of the governance actions down to the source
+type PushdownHints struct {
+	// Columns are the only columns to be read from the source, all columns are read if not specified
+	// +optional
+	Columns []string `json:"columns,omitempty"`
+
+	// Predicates select the rows to be read from the source, combined by AND
+	// +optional
+	Predicates []taxonomy.FilterPredicate `json:"predicates,omitempty"`
 }
 
 // DataFlowStep contains details on a single data flow step
@@ -124,6 +141,10 @@ type Flow struct {
 	// +required
 	AssetID string `json:"assetId"`
 
+	// Consumer is the name of the consumer of the asset served by this data flow, empty if the asset has no consumers
+	// +optional
+	Consumer string `json:"consumer,omitempty"`
+
 	// +required
 	SubFlows []SubFlow `json:"subFlows"`
 }
@@ -215,6 +236,10 @@ type PlotterSpec struct {
 	// The key is the template name
 	// +required
 	Templates map[string]Template `json:"templates"`
+
+	// ModuleResources are the compute resources requested by and limited for the modules deployed by the plotter
+	// +optional
+	ModuleResources *corev1.ResourceRequirements `json:"moduleResources,omitempty"`
 }
 
 // PlotterStatus defines the observed state of Plotter
@@ -10,6 +10,7 @@ package v1beta1
 import (
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -51,6 +52,11 @@ func (in *AssetContext) DeepCopyInto(out *AssetContext) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Pushdown != nil {
+		in, out := &in.Pushdown, &out.Pushdown
+		*out = new(PushdownHints)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetContext.
@@ -79,6 +85,26 @@ func (in *AssetDetails) DeepCopy() *AssetDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssetSchema) DeepCopyInto(out *AssetSchema) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetSchema.
+func (in *AssetSchema) DeepCopy() *AssetSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(AssetSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssetState) DeepCopyInto(out *AssetState) {
 	*out = *in
@@ -87,7 +113,64 @@ func (in *AssetState) DeepCopyInto(out *AssetState) {
 		*out = make([]Condition, len(*in))
 		copy(*out, *in)
 	}
+	if in.AppliedActions != nil {
+		in, out := &in.AppliedActions, &out.AppliedActions
+		*out = make([]taxonomy.Action, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = make([]ColumnSchema, len(*in))
+		copy(*out, *in)
+	}
 	in.Endpoint.DeepCopyInto(&out.Endpoint)
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make(map[taxonomy.ConnectionType]taxonomy.Connection, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make(map[taxonomy.ConnectionType]ServiceEndpoint, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make(map[taxonomy.ConnectionType][]taxonomy.DataFormat, len(*in))
+		for key, val := range *in {
+			var outVal []taxonomy.DataFormat
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]taxonomy.DataFormat, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ConsumerEndpoints != nil {
+		in, out := &in.ConsumerEndpoints, &out.ConsumerEndpoints
+		*out = make(map[string]taxonomy.Connection, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.DecisionIDs != nil {
+		in, out := &in.DecisionIDs, &out.DecisionIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyReevaluation != nil {
+		in, out := &in.PolicyReevaluation, &out.PolicyReevaluation
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetState.
@@ -169,6 +252,11 @@ func (in *BlueprintModule) DeepCopyInto(out *BlueprintModule) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintModule.
@@ -196,6 +284,11 @@ func (in *BlueprintSpec) DeepCopyInto(out *BlueprintSpec) {
 		*out = new(ApplicationDetails)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ModuleResources != nil {
+		in, out := &in.ModuleResources, &out.ModuleResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintSpec.
@@ -260,6 +353,21 @@ func (in *ChartSpec) DeepCopy() *ChartSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColumnSchema) DeepCopyInto(out *ColumnSchema) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColumnSchema.
+func (in *ColumnSchema) DeepCopy() *ColumnSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(ColumnSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
@@ -275,6 +383,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataConsumer) DeepCopyInto(out *DataConsumer) {
+	*out = *in
+	out.Interface = in.Interface
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataConsumer.
+func (in *DataConsumer) DeepCopy() *DataConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(DataConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataContext) DeepCopyInto(out *DataContext) {
 	*out = *in
@@ -319,6 +443,16 @@ func (in *DataRequirements) DeepCopyInto(out *DataRequirements) {
 		*out = new(taxonomy.Interface)
 		**out = **in
 	}
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]DataConsumer, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpectedColumns != nil {
+		in, out := &in.ExpectedColumns, &out.ExpectedColumns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.FlowParams.DeepCopyInto(&out.FlowParams)
 }
 
@@ -343,6 +477,11 @@ func (in *DataStore) DeepCopyInto(out *DataStore) {
 		}
 	}
 	in.Connection.DeepCopyInto(&out.Connection)
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(AssetSchema)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataStore.
@@ -369,6 +508,11 @@ func (in *DatasetDetails) DeepCopyInto(out *DatasetDetails) {
 		*out = new(datacatalog.ResourceMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(DataStore)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatasetDetails.
@@ -532,6 +676,11 @@ func (in *FybrikApplicationSpec) DeepCopyInto(out *FybrikApplicationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ModuleResources != nil {
+		in, out := &in.ModuleResources, &out.ModuleResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FybrikApplicationSpec.
@@ -559,6 +708,16 @@ func (in *FybrikApplicationStatus) DeepCopyInto(out *FybrikApplicationStatus) {
 		*out = new(ResourceReference)
 		**out = **in
 	}
+	if in.DryRunResult != nil {
+		in, out := &in.DryRunResult, &out.DryRunResult
+		*out = new(ResourceReference)
+		**out = **in
+	}
+	if in.StagedPlotter != nil {
+		in, out := &in.StagedPlotter, &out.StagedPlotter
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.ProvisionedStorage != nil {
 		in, out := &in.ProvisionedStorage, &out.ProvisionedStorage
 		*out = make(map[string]DatasetDetails, len(*in))
@@ -566,6 +725,13 @@ func (in *FybrikApplicationStatus) DeepCopyInto(out *FybrikApplicationStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]Transition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FybrikApplicationStatus.
@@ -658,6 +824,11 @@ func (in *FybrikModuleSpec) DeepCopyInto(out *FybrikModuleSpec) {
 		*out = make([]ResourceStatusIndicator, len(*in))
 		copy(*out, *in)
 	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(ModulePlacement)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FybrikModuleSpec.
@@ -835,7 +1006,9 @@ func (in *ModuleCapability) DeepCopyInto(out *ModuleCapability) {
 	if in.Actions != nil {
 		in, out := &in.Actions, &out.Actions
 		*out = make([]ModuleSupportedAction, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Plugins != nil {
 		in, out := &in.Plugins, &out.Plugins
@@ -895,9 +1068,39 @@ func (in *ModuleInfo) DeepCopy() *ModuleInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModulePlacement) DeepCopyInto(out *ModulePlacement) {
+	*out = *in
+	if in.Geographies != nil {
+		in, out := &in.Geographies, &out.Geographies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModulePlacement.
+func (in *ModulePlacement) DeepCopy() *ModulePlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(ModulePlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleSupportedAction) DeepCopyInto(out *ModuleSupportedAction) {
 	*out = *in
+	if in.RedactionStrategies != nil {
+		in, out := &in.RedactionStrategies, &out.RedactionStrategies
+		*out = make([]taxonomy.RedactionStrategy, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleSupportedAction.
@@ -1010,6 +1213,11 @@ func (in *PlotterSpec) DeepCopyInto(out *PlotterSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ModuleResources != nil {
+		in, out := &in.ModuleResources, &out.ModuleResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlotterSpec.
@@ -1083,6 +1291,31 @@ func (in *Plugin) DeepCopy() *Plugin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PushdownHints) DeepCopyInto(out *PushdownHints) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Predicates != nil {
+		in, out := &in.Predicates, &out.Predicates
+		*out = make([]taxonomy.FilterPredicate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PushdownHints.
+func (in *PushdownHints) DeepCopy() *PushdownHints {
+	if in == nil {
+		return nil
+	}
+	out := new(PushdownHints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceReference) DeepCopyInto(out *ResourceReference) {
 	*out = *in
@@ -1129,6 +1362,21 @@ func (in *Selector) DeepCopy() *Selector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpoint) DeepCopyInto(out *ServiceEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpoint.
+func (in *ServiceEndpoint) DeepCopy() *ServiceEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepArgument) DeepCopyInto(out *StepArgument) {
 	*out = *in
@@ -1175,6 +1423,11 @@ func (in *StepParameters) DeepCopyInto(out *StepParameters) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Pushdown != nil {
+		in, out := &in.Pushdown, &out.Pushdown
+		*out = new(PushdownHints)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepParameters.
@@ -1242,6 +1495,22 @@ func (in *Template) DeepCopy() *Template {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Transition) DeepCopyInto(out *Transition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Transition.
+func (in *Transition) DeepCopy() *Transition {
+	if in == nil {
+		return nil
+	}
+	out := new(Transition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Vault) DeepCopyInto(out *Vault) {
 	*out = *in
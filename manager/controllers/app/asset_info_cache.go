@@ -0,0 +1,160 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// DegradedModePolicy defines the governance applied to the assets whose metadata is read from the asset info cache,
+// while the data catalog is unavailable
+type DegradedModePolicy string
+
+const (
+	// DegradedModeDisabled reports the assets as failed while the data catalog is unavailable
+	DegradedModeDisabled DegradedModePolicy = ""
+	// DegradedModeDeny denies the access to the cached assets
+	DegradedModeDeny DegradedModePolicy = "deny"
+	// DegradedModeRedactPII redacts the columns of the cached assets that are tagged as PII,
+	// in addition to the governance actions returned by the policy manager
+	DegradedModeRedactPII DegradedModePolicy = "redact-pii"
+)
+
+// defaultAssetInfoCacheTTLMs is the time the asset metadata is cached if the degraded mode is enabled
+const defaultAssetInfoCacheTTLMs = 300000
+
+// PIIColumnTag is the tag of the columns that hold personal information, which are redacted in the redact-pii degraded mode
+const PIIColumnTag = "PII"
+
+// CatalogUnavailableReason explains a denial of an access while the data catalog is unavailable
+const CatalogUnavailableReason = "the data catalog is unavailable"
+
+// degradedModePolicy returns the degraded mode policy defined by the environment, or an error if it is unknown
+func degradedModePolicy() (DegradedModePolicy, error) {
+	policy := DegradedModePolicy(strings.TrimSpace(os.Getenv(environment.CatalogDegradedModeKey)))
+	switch policy {
+	case DegradedModeDisabled, DegradedModeDeny, DegradedModeRedactPII:
+		return policy, nil
+	}
+	return DegradedModeDisabled, errors.Errorf("unknown degraded mode policy %q, expected %q or %q",
+		policy, DegradedModeDeny, DegradedModeRedactPII)
+}
+
+// assetInfoCacheTTL returns the time the asset metadata is cached to be used while the data catalog is unavailable
+func assetInfoCacheTTL() time.Duration {
+	return time.Duration(environment.GetEnvAsInt(environment.AssetInfoCacheTTLKey, defaultAssetInfoCacheTTLMs)) * time.Millisecond
+}
+
+// assetInfoKey identifies a data catalog request
+type assetInfoKey struct {
+	assetID        taxonomy.AssetID
+	version        string
+	credentialPath string
+}
+
+func newAssetInfoKey(request *datacatalog.GetAssetRequest, credentialPath string) assetInfoKey {
+	return assetInfoKey{assetID: request.AssetID, version: request.Version, credentialPath: credentialPath}
+}
+
+// cachedAssetInfo is a response of the data catalog kept across reconciles
+type cachedAssetInfo struct {
+	response  *datacatalog.GetAssetResponse
+	fetchedAt time.Time
+}
+
+// AssetInfoCache keeps the valid responses of the data catalog for the given TTL, so that previously working
+// applications keep functioning with the degraded mode policy during brief outages of the data catalog.
+// The responses are keyed on the asset, its requested version and the credentials used to retrieve it.
+// A nil cache is valid and does not store anything.
+type AssetInfoCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex  sync.Mutex
+	assets map[assetInfoKey]*cachedAssetInfo
+}
+
+// NewAssetInfoCache creates an empty cache of asset metadata with the given TTL,
+// or returns nil if the TTL is not positive
+func NewAssetInfoCache(ttl time.Duration) *AssetInfoCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &AssetInfoCache{ttl: ttl, now: time.Now, assets: map[assetInfoKey]*cachedAssetInfo{}}
+}
+
+// add stores a copy of a valid response of the data catalog
+func (c *AssetInfoCache) add(request *datacatalog.GetAssetRequest, credentialPath string, response *datacatalog.GetAssetResponse) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.assets[newAssetInfoKey(request, credentialPath)] = &cachedAssetInfo{response: response.DeepCopy(), fetchedAt: c.now()}
+}
+
+// get returns a copy of the cached response for the asset, unless it is older than the TTL
+func (c *AssetInfoCache) get(request *datacatalog.GetAssetRequest, credentialPath string) (*datacatalog.GetAssetResponse, bool) {
+	if c == nil {
+		return nil, false
+	}
+	key := newAssetInfoKey(request, credentialPath)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, found := c.assets[key]
+	if !found {
+		return nil, false
+	}
+	if c.now().Sub(cached.fetchedAt) > c.ttl {
+		delete(c.assets, key)
+		return nil, false
+	}
+	return cached.response.DeepCopy(), true
+}
+
+// isCatalogUnavailable returns true if the data catalog could not be reached, rather than rejecting the request
+func isCatalogUnavailable(err error) bool {
+	var connectorErr connectors.ConnectorError
+	return errors.As(err, &connectorErr) && connectorErr.IsRetryable()
+}
+
+// addDegraded records that the cached metadata of the given asset is used
+func (c *ApplicationContext) addDegraded(datasetID string) {
+	if c.Degraded != nil {
+		c.Degraded[datasetID] = true
+	}
+}
+
+// degradedModeMessage is reported for the assets whose cached metadata is used
+func degradedModeMessage(policy DegradedModePolicy) string {
+	return fmt.Sprintf("%s, the cached asset metadata is used with the %s degraded mode policy", CatalogUnavailableReason, policy)
+}
+
+// piiColumns returns the columns of the asset that are tagged as PII
+func piiColumns(metadata *datacatalog.ResourceMetadata) []string {
+	columns := []string{}
+	for _, column := range metadata.Columns {
+		if column.Tags == nil {
+			continue
+		}
+		for tag, value := range column.Tags.Items {
+			if strings.EqualFold(tag, PIIColumnTag) && strings.EqualFold(fmt.Sprint(value), "true") {
+				columns = append(columns, column.Name)
+				break
+			}
+		}
+	}
+	return columns
+}
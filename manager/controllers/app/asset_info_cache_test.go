@@ -0,0 +1,62 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+func TestAssetInfoCache(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(NewAssetInfoCache(0)).To(gomega.BeNil())
+
+	now := time.Now()
+	cache := NewAssetInfoCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	piiTags := &taxonomy.Tags{}
+	piiTags.Items = map[string]interface{}{"pii": true}
+	response := &datacatalog.GetAssetResponse{ResourceMetadata: datacatalog.ResourceMetadata{
+		Columns: []datacatalog.ResourceColumn{{Name: "Name"}, {Name: "SSN", Tags: piiTags}},
+	}}
+	request := &datacatalog.GetAssetRequest{AssetID: "s3/pii-asset", OperationType: datacatalog.READ}
+	cache.add(request, "creds", response)
+
+	// the responses are kept per credentials and per version
+	_, found := cache.get(request, "other-creds")
+	g.Expect(found).To(gomega.BeFalse())
+	_, found = cache.get(&datacatalog.GetAssetRequest{AssetID: "s3/pii-asset", Version: "v2"}, "creds")
+	g.Expect(found).To(gomega.BeFalse())
+	cached, found := cache.get(request, "creds")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(cached).To(gomega.Equal(response))
+	g.Expect(piiColumns(&cached.ResourceMetadata)).To(gomega.Equal([]string{"SSN"}))
+
+	// the cached response is not affected by the changes of the returned copies
+	cached.ResourceMetadata.Columns = nil
+	cached, _ = cache.get(request, "creds")
+	g.Expect(cached.ResourceMetadata.Columns).To(gomega.HaveLen(2))
+
+	// expired responses are evicted
+	now = now.Add(2 * time.Minute)
+	_, found = cache.get(request, "creds")
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestIsCatalogUnavailable(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(isCatalogUnavailable(connectors.NewUnavailableError(errors.New("connection refused")))).To(gomega.BeTrue())
+	// the catalog rejected the request
+	g.Expect(isCatalogUnavailable(connectors.NewHTTPError(404, errors.New("the asset does not exist")))).To(gomega.BeFalse())
+	g.Expect(isCatalogUnavailable(errors.New("the asset does not exist"))).To(gomega.BeFalse())
+}
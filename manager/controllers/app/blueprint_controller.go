@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"emperror.dev/errors"
 	distributionref "github.com/distribution/distribution/reference"
@@ -16,6 +18,7 @@ import (
 	credentialprovidersecrets "github.com/vdemeester/k8s-pkg-credentialprovider/secrets"
 	"gopkg.in/yaml.v2"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -37,6 +40,7 @@ import (
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/helm"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/utils"
 )
 
@@ -51,6 +55,9 @@ type BlueprintReconciler struct {
 	Log    zerolog.Logger
 	Scheme *runtime.Scheme
 	Helmer helm.Interface
+	// chartLock serializes the chart pulls of the modules that are deployed concurrently,
+	// since they share the registry login
+	chartLock sync.Mutex
 }
 
 // Reconcile receives a Blueprint CRD
@@ -189,6 +196,37 @@ func (r *BlueprintReconciler) applyChartResource(ctx context.Context, cfg *actio
 	nbytes, _ := yaml.Marshal(args)
 	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("--- Values.yaml ---\n\n" + string(nbytes) + "\n\n")
 
+	chrt, err := r.fetchChart(ctx, cfg, chartSpec, log)
+	if err != nil {
+		return nil, err
+	}
+	inst, err := r.Helmer.IsInstalled(cfg, releaseName)
+	// TODO should we return err if it is not nil?
+	var rel *release.Release
+	start := time.Now()
+	if inst && err == nil {
+		rel, err = r.Helmer.Upgrade(ctx, cfg, chrt, releaseNamespace, releaseName, args)
+		metrics.ObserveSince(metrics.ModuleDeploymentDuration, start, err, "upgrade")
+		if err != nil {
+			return nil, errors.WithMessage(err, chartSpec.Name+": failed upgrade")
+		}
+	} else {
+		rel, err = r.Helmer.Install(ctx, cfg, chrt, releaseNamespace, releaseName, args)
+		metrics.ObserveSince(metrics.ModuleDeploymentDuration, start, err, "install")
+		if err != nil {
+			return nil, errors.WithMessage(err, chartSpec.Name+": failed install")
+		}
+	}
+	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("--- Release Status ---\n\n" + string(rel.Info.Status) + "\n\n")
+	return rel, nil
+}
+
+// fetchChart pulls and loads the chart of a module, one chart at a time
+func (r *BlueprintReconciler) fetchChart(ctx context.Context, cfg *action.Configuration, chartSpec fapp.ChartSpec,
+	log *zerolog.Logger) (*chart.Chart, error) {
+	r.chartLock.Lock()
+	defer r.chartLock.Unlock()
+
 	var registrySuccessfulLogin string
 	var err error
 	registrySuccessfulLogin, err = r.obtainSecrets(ctx, log, chartSpec)
@@ -217,26 +255,11 @@ func (r *BlueprintReconciler) applyChartResource(ctx context.Context, cfg *actio
 			return nil, errors.WithMessage(err, "failed to logout from helm registry: "+registrySuccessfulLogin)
 		}
 	}
-	chart, err := r.Helmer.Load(chartSpec.Name, tmpDir)
+	chrt, err := r.Helmer.Load(chartSpec.Name, tmpDir)
 	if err != nil {
 		return nil, errors.WithMessage(err, chartSpec.Name+": failed chart load")
 	}
-	inst, err := r.Helmer.IsInstalled(cfg, releaseName)
-	// TODO should we return err if it is not nil?
-	var rel *release.Release
-	if inst && err == nil {
-		rel, err = r.Helmer.Upgrade(ctx, cfg, chart, releaseNamespace, releaseName, args)
-		if err != nil {
-			return nil, errors.WithMessage(err, chartSpec.Name+": failed upgrade")
-		}
-	} else {
-		rel, err = r.Helmer.Install(ctx, cfg, chart, releaseNamespace, releaseName, args)
-		if err != nil {
-			return nil, errors.WithMessage(err, chartSpec.Name+": failed install")
-		}
-	}
-	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("--- Release Status ---\n\n" + string(rel.Info.Status) + "\n\n")
-	return rel, nil
+	return chrt, nil
 }
 
 // CopyMap copies a map
@@ -283,6 +306,44 @@ func (r *BlueprintReconciler) updateModuleState(blueprint *fapp.Blueprint, insta
 	blueprint.Status.ModulesState[instanceName] = state
 }
 
+// moduleDeployment is the outcome of the deployment of a blueprint module
+type moduleDeployment struct {
+	instanceName string
+	module       fapp.BlueprintModule
+	releaseName  string
+	// blockedBy is a module that this module depends on, and whose deployment has failed
+	blockedBy string
+	release   *release.Release
+	// applied is true if the chart of the module has been applied
+	applied bool
+	err     error
+}
+
+// deployModule checks the release of a module, and applies its chart if the release is outdated, missing or failed.
+// It can be called concurrently for the modules that do not depend on each other, since every call uses its own
+// helm configuration, which is not safe for concurrent use.
+func (r *BlueprintReconciler) deployModule(ctx context.Context, log *zerolog.Logger,
+	releaseNamespace string, args map[string]interface{}, updateRequired bool, deployment *moduleDeployment) {
+	cfg, err := r.Helmer.GetConfig(releaseNamespace, log.Printf)
+	if err != nil {
+		deployment.err = err
+		return
+	}
+	// check the release status
+	rel, err := r.Helmer.Status(cfg, deployment.releaseName)
+	// nonexistent release or a failed release - re-apply the chart
+	if updateRequired || err != nil || rel == nil || rel.Info.Status == release.StatusFailed {
+		// Process templates with arguments
+		deployment.applied = true
+		rel, err = r.applyChartResource(ctx, cfg, deployment.module.Chart, args, releaseNamespace, deployment.releaseName, log)
+		if err != nil {
+			deployment.err = err
+			return
+		}
+	}
+	deployment.release = rel
+}
+
 //nolint:gocyclo
 func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configuration, log *zerolog.Logger,
 	blueprint *fapp.Blueprint) (ctrl.Result, error) {
@@ -294,6 +355,7 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 	blueprint.Status.ObservedGeneration = blueprint.GetGeneration()
 	// reset blueprint state
 	blueprint.Status.ObservedState.Ready = false
+	blueprint.Status.ObservedState.Completed = false
 	blueprint.Status.ObservedState.Error = ""
 	if blueprint.Status.Releases == nil {
 		blueprint.Status.Releases = map[string]int64{}
@@ -301,8 +363,12 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 	if blueprint.Status.ModulesState == nil {
 		blueprint.Status.ModulesState = make(map[string]fapp.ObservedState)
 	}
-	// count the overall number of Helm releases and how many of them are ready
-	numReleases, numReady := 0, 0
+	// count the overall number of Helm releases and how many of them are ready, or have run to completion
+	numReleases, numReady, numCompleted := 0, 0, 0
+	// the jobs of the ready modules are polled until they complete
+	jobsRunning := false
+	// a failed deployment of a chart is retried by the next reconcile, rather than by polling its resources
+	deploymentFailed := false
 	// Add debug information to module labels
 	if blueprint.Labels == nil {
 		blueprint.Labels = map[string]string{}
@@ -310,49 +376,88 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 	blueprint.Labels[managerUtils.BlueprintNameLabel] = blueprint.Name
 	blueprint.Labels[managerUtils.BlueprintNamespaceLabel] = blueprint.Namespace
 
-	for instanceName, module := range blueprint.Spec.Modules {
-		// Get arguments by type
-		helmValues := HelmValues{
-			ModuleArguments: module.Arguments,
-			Context:         blueprint.Spec.Application.Context,
-			Labels:          blueprint.Labels,
-			UUID:            uuid,
-		}
-		args, err := utils.StructToMap(&helmValues)
-		if err != nil {
-			return ctrl.Result{}, errors.WithMessage(err, "Blueprint step arguments are invalid")
-		}
+	// the modules are deployed in waves, such that a module is deployed after the modules that it depends on,
+	// while the modules of a wave are deployed concurrently
+	failed := map[string]bool{}
+	for _, wave := range deploymentWaves(blueprint.Spec.Modules) {
+		deployments := make([]moduleDeployment, len(wave))
+		var wg sync.WaitGroup
+		for i, instanceName := range wave {
+			module := blueprint.Spec.Modules[instanceName]
+			// Get arguments by type
+			helmValues := HelmValues{
+				ModuleArguments: module.Arguments,
+				Context:         blueprint.Spec.Application.Context,
+				Labels:          blueprint.Labels,
+				UUID:            uuid,
+				Resources:       blueprint.Spec.ModuleResources,
+			}
+			args, err := utils.StructToMap(&helmValues)
+			if err != nil {
+				wg.Wait()
+				return ctrl.Result{}, errors.WithMessage(err, "Blueprint step arguments are invalid")
+			}
 
-		releaseName := managerUtils.GetReleaseName(managerUtils.GetApplicationNameFromLabels(blueprint.Labels),
-			uuid,
-			instanceName)
-		log.Trace().Msg("Release name: " + releaseName)
-		numReleases++
-
-		// check the release status
-		rel, err := r.Helmer.Status(cfg, releaseName)
-		// nonexistent release or a failed release - re-apply the chart
-		if updateRequired || err != nil || rel == nil || rel.Info.Status == release.StatusFailed {
-			// Process templates with arguments
-			chart := module.Chart
-			if rel, err = r.applyChartResource(ctx, cfg, chart, args, blueprint.Spec.ModulesNamespace, releaseName, log); err != nil {
-				blueprint.Status.ObservedState.Error += errors.Wrap(err, "ChartDeploymentFailure: ").Error() + "\n"
-				r.updateModuleState(blueprint, instanceName, false, err.Error())
-			} else {
-				r.updateModuleState(blueprint, instanceName, false, "")
+			releaseName := managerUtils.GetReleaseName(managerUtils.GetApplicationNameFromLabels(blueprint.Labels),
+				uuid,
+				instanceName)
+			log.Trace().Msg("Release name: " + releaseName)
+			deployments[i] = moduleDeployment{instanceName: instanceName, module: module, releaseName: releaseName}
+			if dependency := failedDependency(&module, failed); dependency != "" {
+				deployments[i].blockedBy = dependency
+				continue
 			}
+			wg.Add(1)
+			go func(deployment *moduleDeployment) {
+				defer wg.Done()
+				r.deployModule(ctx, log, blueprint.Spec.ModulesNamespace, args, updateRequired, deployment)
+			}(&deployments[i])
 		}
-		if rel != nil && rel.Info.Status == release.StatusDeployed {
-			status, errMsg := r.checkReleaseStatus(rel, uuid)
-			if status == corev1.ConditionFalse {
-				blueprint.Status.ObservedState.Error += "ResourceAllocationFailure: " + errMsg + "\n"
-				r.updateModuleState(blueprint, instanceName, false, errMsg)
-			} else if status == corev1.ConditionTrue {
-				r.updateModuleState(blueprint, instanceName, true, "")
-				numReady++
+		wg.Wait()
+
+		for i := range deployments {
+			deployment := &deployments[i]
+			numReleases++
+			switch {
+			case deployment.blockedBy != "":
+				errMsg := "module " + deployment.module.Name + " is not deployed, since the deployment of " +
+					deployment.blockedBy + " that it depends on has failed"
+				blueprint.Status.ObservedState.Error += "ChartDeploymentFailure: " + errMsg + "\n"
+				r.updateModuleState(blueprint, deployment.instanceName, false, errMsg)
+				failed[deployment.instanceName] = true
+				deploymentFailed = true
+			case deployment.err != nil:
+				blueprint.Status.ObservedState.Error += errors.Wrap(deployment.err, "ChartDeploymentFailure: ").Error() + "\n"
+				r.updateModuleState(blueprint, deployment.instanceName, false, deployment.err.Error())
+				failed[deployment.instanceName] = true
+				deploymentFailed = true
+			case deployment.applied:
+				r.updateModuleState(blueprint, deployment.instanceName, false, "")
 			}
+			if rel := deployment.release; rel != nil && rel.Info.Status == release.StatusDeployed {
+				status, errMsg := r.checkReleaseStatus(rel, uuid)
+				if status == corev1.ConditionFalse {
+					// the module is named, so that the users of the application know which module has failed
+					errMsg = "module " + deployment.module.Name + ": " + errMsg
+					blueprint.Status.ObservedState.Error += "ResourceAllocationFailure: " + errMsg + "\n"
+					r.updateModuleState(blueprint, deployment.instanceName, false, errMsg)
+				} else if status == corev1.ConditionTrue {
+					r.updateModuleState(blueprint, deployment.instanceName, true, "")
+					numReady++
+					// a module reports the completion of its task, e.g., a transactional write, by the completion of its jobs
+					numJobs, numJobsCompleted := releaseJobs(rel)
+					if numJobs > 0 && numJobsCompleted == numJobs {
+						state := blueprint.Status.ModulesState[deployment.instanceName]
+						state.Completed = true
+						blueprint.Status.ModulesState[deployment.instanceName] = state
+						numCompleted++
+					} else if numJobs > 0 {
+						jobsRunning = true
+					}
+				}
+			}
+			blueprint.Status.Releases[deployment.releaseName] = blueprint.Status.ObservedGeneration
 		}
-		blueprint.Status.Releases[releaseName] = blueprint.Status.ObservedGeneration
 	}
 	// clean-up
 	for release, version := range blueprint.Status.Releases {
@@ -369,11 +474,18 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 	if numReady == numReleases {
 		// all modules have been orchestrated successfully - the data is ready for use
 		blueprint.Status.ObservedState.Ready = true
-		return ctrl.Result{}, nil
+		blueprint.Status.ObservedState.Completed = numCompleted == numReleases
+		if !jobsRunning {
+			return ctrl.Result{}, nil
+		}
+		// continue polling until the jobs of the modules complete
+		interval, _ := environment.GetResourcesPollingInterval()
+		return ctrl.Result{RequeueAfter: interval}, nil
 	}
 
-	// the status is unknown yet - continue polling
-	if blueprint.Status.ObservedState.Error == "" {
+	// the status is unknown yet, or the resources have failed and may recover, e.g., once the image of a module can be pulled
+	// - continue polling
+	if !deploymentFailed {
 		log.Trace().Msg("blueprint.Status.ObservedState is not ready, will try again")
 		// if an error exists it is logged in LogEnvVariables and a default value is used
 		interval, _ := environment.GetResourcesPollingInterval()
@@ -437,8 +549,46 @@ func (r *BlueprintReconciler) getExpectedResults(kind string) (*fapp.ResourceSta
 	return nil, nil
 }
 
+// podFailureReasons are the reasons of waiting containers that fail to start
+var podFailureReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// podFailure returns a message describing a container of the pod that fails to start, e.g., since its image can not be pulled,
+// or an empty string if there is none. Such pods are reported as progressing rather than as failed by kstatus.
+func podFailure(res *unstructured.Unstructured) string {
+	pod := &corev1.Pod{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(res.Object, pod); err != nil {
+		return ""
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		waiting := status.State.Waiting
+		if waiting == nil || !podFailureReasons[waiting.Reason] {
+			continue
+		}
+		msg := "container " + status.Name + " failed to start: " + waiting.Reason
+		if waiting.Message != "" {
+			msg += ": " + waiting.Message
+		}
+		return msg
+	}
+	return ""
+}
+
 // checkResourceStatus returns the computed state and an error message if exists
 func (r *BlueprintReconciler) checkResourceStatus(res *unstructured.Unstructured) (corev1.ConditionStatus, string) {
+	if res.GetKind() == "Pod" {
+		if msg := podFailure(res); msg != "" {
+			return corev1.ConditionFalse, msg
+		}
+	}
 	// get indications how to compute the resource status based on a module spec
 	expected, err := r.getExpectedResults(res.GetKind())
 	if err != nil {
@@ -503,6 +653,28 @@ func (r *BlueprintReconciler) matchesCondition(res *unstructured.Unstructured, c
 	return true
 }
 
+// releaseJobs returns the number of jobs deployed by the given helm release, and how many of them have run to completion
+func releaseJobs(rel *release.Release) (int, int) {
+	numJobs, numCompleted := 0, 0
+	for versionKind := range rel.Info.Resources {
+		for _, obj := range rel.Info.Resources[versionKind] {
+			unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil || (&unstructured.Unstructured{Object: unstr}).GetKind() != "Job" {
+				continue
+			}
+			numJobs++
+			conditions, _, _ := unstructured.NestedSlice(unstr, "status", "conditions")
+			for _, condition := range conditions {
+				if c, ok := condition.(map[string]interface{}); ok && c["type"] == "Complete" && c["status"] == string(corev1.ConditionTrue) {
+					numCompleted++
+					break
+				}
+			}
+		}
+	}
+	return numJobs, numCompleted
+}
+
 func (r *BlueprintReconciler) checkReleaseStatus(rel *release.Release, uuid string) (corev1.ConditionStatus, string) {
 	log := r.Log.With().Str(managerUtils.FybrikAppUUID, uuid).Logger()
 
@@ -7,9 +7,21 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"emperror.dev/errors"
 	"github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/release"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -93,6 +105,415 @@ func TestBlueprintReconcile(t *testing.T) {
 		Should(gomega.HaveKeyWithValue("notebook1234-notebook-read-module", blueprint.Status.ObservedGeneration))
 }
 
+// The pod of a deployed module can not pull its image
+// Result: the blueprint is not ready, reports the module that fails to start with the reason, and keeps polling its status
+func TestBlueprintModuleFailsToStart(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read blueprint file for test")
+	blueprint.Spec.ModulesNamespace = environment.GetDefaultModulesNamespace()
+	// the releases have been deployed by a previous reconcile
+	blueprint.SetGeneration(1)
+	blueprint.Status.ObservedGeneration = 1
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, blueprint)
+
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "module-pod", Namespace: blueprint.Spec.ModulesNamespace},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "server",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image \"ghcr.io/fybrik/missing:0.1.0\"",
+				}},
+			}},
+		},
+	}
+	rel := &release.Release{Info: &release.Info{
+		Status:    release.StatusDeployed,
+		Resources: map[string][]runtime.Object{"v1/Pod(related)": {pod}},
+	}}
+	r := &BlueprintReconciler{
+		Client: cl,
+		Name:   "BlueprintTestController",
+		Log:    logging.LogInit(logging.CONTROLLER, "test-blueprint-controller"),
+		Scheme: s,
+		Helmer: helm.NewFake(rel),
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(blueprint)}
+	res, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(res.RequeueAfter).To(gomega.BeNumerically(">", 0))
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, blueprint)).To(gomega.Succeed())
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeFalse())
+	g.Expect(blueprint.Status.ObservedState.Error).To(gomega.ContainSubstring(
+		"module arrow-flight: container server failed to start: ImagePullBackOff"))
+	g.Expect(blueprint.Status.ModulesState["notebook-read-module"].Error).To(gomega.Equal(
+		"module arrow-flight: container server failed to start: ImagePullBackOff: Back-off pulling image \"ghcr.io/fybrik/missing:0.1.0\""))
+}
+
+// The modules of a blueprint run jobs, e.g., to write a new asset
+// Result: the modules are ready once their jobs have started, and are completed once the jobs have run to completion.
+// The blueprint keeps polling the jobs until they complete.
+func TestBlueprintModuleJobCompletion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read blueprint file for test")
+	blueprint.Spec.ModulesNamespace = environment.GetDefaultModulesNamespace()
+	// the releases have been deployed by a previous reconcile
+	blueprint.SetGeneration(1)
+	blueprint.Status.ObservedGeneration = 1
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, blueprint)
+
+	startTime := metav1.Now()
+	job := &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{Name: "module-job", Namespace: blueprint.Spec.ModulesNamespace},
+		Status:     batchv1.JobStatus{StartTime: &startTime, Active: 1},
+	}
+	rel := &release.Release{Info: &release.Info{
+		Status:    release.StatusDeployed,
+		Resources: map[string][]runtime.Object{"batch/v1/Job": {job}},
+	}}
+	r := &BlueprintReconciler{
+		Client: cl,
+		Name:   "BlueprintTestController",
+		Log:    logging.LogInit(logging.CONTROLLER, "test-blueprint-controller"),
+		Scheme: s,
+		Helmer: helm.NewFake(rel),
+	}
+	req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(blueprint)}
+	res, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(res.RequeueAfter).To(gomega.BeNumerically(">", 0))
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, blueprint)).To(gomega.Succeed())
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeTrue())
+	g.Expect(blueprint.Status.ObservedState.Completed).To(gomega.BeFalse())
+	for _, state := range blueprint.Status.ModulesState {
+		g.Expect(state.Ready).To(gomega.BeTrue())
+		g.Expect(state.Completed).To(gomega.BeFalse())
+	}
+
+	job.Status.Active = 0
+	job.Status.Succeeded = 1
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	res, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(res.RequeueAfter).To(gomega.BeZero())
+	g.Expect(cl.Get(context.Background(), req.NamespacedName, blueprint)).To(gomega.Succeed())
+	g.Expect(blueprint.Status.ObservedState.Completed).To(gomega.BeTrue())
+	for _, state := range blueprint.Status.ModulesState {
+		g.Expect(state.Completed).To(gomega.BeTrue())
+	}
+}
+
+// moduleDeploymentTemplate is the template of the module deployment, which uses the resources value as charts commonly do
+const moduleDeploymentTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+spec:
+  template:
+    spec:
+      containers:
+        - name: server
+          image: ghcr.io/fybrik/module:0.1.0
+          {{- with .Values.resources }}
+          resources:
+            {{- toYaml . | nindent 12 }}
+          {{- end }}
+`
+
+// renderingHelmer renders the module chart with the values of the installed releases,
+// so that the resources generated for the modules can be checked
+type renderingHelmer struct {
+	*helm.Fake
+	lock      sync.Mutex
+	manifests map[string]string
+}
+
+func (h *renderingHelmer) Load(ref, chartPath string) (*chart.Chart, error) {
+	return &chart.Chart{
+		Metadata:  &chart.Metadata{APIVersion: "v2", Name: "module", Version: "0.1.0"},
+		Templates: []*chart.File{{Name: "templates/deployment.yaml", Data: []byte(moduleDeploymentTemplate)}},
+		// the default resources of the chart
+		Values: map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "100m"}}},
+	}, nil
+}
+
+func (h *renderingHelmer) render(chrt *chart.Chart, kubeNamespace, releaseName string, vals map[string]interface{}) error {
+	values, err := chartutil.ToRenderValues(chrt, vals, chartutil.ReleaseOptions{Name: releaseName, Namespace: kubeNamespace}, nil)
+	if err != nil {
+		return err
+	}
+	rendered, err := engine.Render(chrt, values)
+	if err != nil {
+		return err
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.manifests[releaseName] = rendered["module/templates/deployment.yaml"]
+	return nil
+}
+
+func (h *renderingHelmer) Install(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
+	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	if err := h.render(chrt, kubeNamespace, releaseName, vals); err != nil {
+		return nil, err
+	}
+	return h.Fake.Install(ctx, cfg, chrt, kubeNamespace, releaseName, vals)
+}
+
+func (h *renderingHelmer) Upgrade(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
+	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	if err := h.render(chrt, kubeNamespace, releaseName, vals); err != nil {
+		return nil, err
+	}
+	return h.Fake.Upgrade(ctx, cfg, chrt, kubeNamespace, releaseName, vals)
+}
+
+// An application limits the resources of its modules
+// Result: the resources are propagated from the plotter to the blueprint, and override the defaults of the module charts
+func TestBlueprintModuleResources(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	resources := &corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	plotter := &fapp.Plotter{Spec: fapp.PlotterSpec{ModulesNamespace: environment.GetDefaultModulesNamespace(),
+		ModuleResources: resources}}
+	g.Expect((&PlotterReconciler{}).GenerateBlueprint(nil, "thegreendragon", plotter).ModuleResources).To(gomega.Equal(resources))
+
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read blueprint file for test")
+	blueprint.Spec.ModulesNamespace = environment.GetDefaultModulesNamespace()
+	blueprint.Spec.ModuleResources = resources
+	// a new generation of the blueprint, whose charts are applied
+	blueprint.SetGeneration(1)
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, blueprint)
+	helmer := &renderingHelmer{Fake: helm.NewEmptyFake(), manifests: map[string]string{}}
+	r := &BlueprintReconciler{
+		Client: cl,
+		Name:   "BlueprintTestController",
+		Log:    logging.LogInit(logging.CONTROLLER, "test-blueprint-controller"),
+		Scheme: s,
+		Helmer: helmer,
+	}
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(blueprint)})
+	g.Expect(err).To(gomega.BeNil())
+
+	g.Expect(helmer.manifests).To(gomega.HaveKey("notebook1234-notebook-read-module"))
+	deployment := &appsv1.Deployment{}
+	g.Expect(yaml.Unmarshal([]byte(helmer.manifests["notebook1234-notebook-read-module"]), deployment)).To(gomega.Succeed())
+	g.Expect(deployment.Spec.Template.Spec.Containers).To(gomega.HaveLen(1))
+	container := deployment.Spec.Template.Spec.Containers[0]
+	g.Expect(container.Resources.Limits.Cpu().Equal(resource.MustParse("2"))).To(gomega.BeTrue())
+	g.Expect(container.Resources.Limits.Memory().Equal(resource.MustParse("4Gi"))).To(gomega.BeTrue())
+	g.Expect(container.Resources.Requests.Cpu().Equal(resource.MustParse("500m"))).To(gomega.BeTrue())
+	g.Expect(container.Resources.Requests.Memory().Equal(resource.MustParse("1Gi"))).To(gomega.BeTrue())
+}
+
+// deployingHelmer records the installations of the module releases, each of which takes a while,
+// so that the order and the concurrency of the deployments can be checked
+type deployingHelmer struct {
+	*helm.Fake
+	lock sync.Mutex
+	// events lists the starts and the ends of the installations in their order
+	events      []string
+	inFlight    int
+	maxInFlight int
+	// failing is a release whose installation fails
+	failing string
+	// configs lists the helm configurations of the installations
+	configs map[*action.Configuration]bool
+}
+
+// GetConfig returns a new helm configuration on every call, as the helm implementation does
+func (h *deployingHelmer) GetConfig(kubeNamespace string, log action.DebugLog) (*action.Configuration, error) {
+	return &action.Configuration{}, nil
+}
+
+func (h *deployingHelmer) record(event string, inFlight int) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.events = append(h.events, event)
+	h.inFlight += inFlight
+	if h.inFlight > h.maxInFlight {
+		h.maxInFlight = h.inFlight
+	}
+}
+
+func (h *deployingHelmer) deploy(cfg *action.Configuration, releaseName string) error {
+	h.lock.Lock()
+	if h.configs == nil {
+		h.configs = map[*action.Configuration]bool{}
+	}
+	h.configs[cfg] = true
+	h.lock.Unlock()
+	h.record("start "+releaseName, 1)
+	time.Sleep(200 * time.Millisecond)
+	h.record("end "+releaseName, -1)
+	if releaseName == h.failing {
+		return errors.New("installation failed")
+	}
+	return nil
+}
+
+func (h *deployingHelmer) Install(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
+	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	if err := h.deploy(cfg, releaseName); err != nil {
+		return nil, err
+	}
+	return h.Fake.Install(ctx, cfg, chrt, kubeNamespace, releaseName, vals)
+}
+
+// Upgrade is called as well for new releases, since the fake helmer holds a single release
+func (h *deployingHelmer) Upgrade(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
+	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	if err := h.deploy(cfg, releaseName); err != nil {
+		return nil, err
+	}
+	return h.Fake.Upgrade(ctx, cfg, chrt, kubeNamespace, releaseName, vals)
+}
+
+// eventIndex returns the position of an event, or -1 if it has not been recorded
+func (h *deployingHelmer) eventIndex(event string) int {
+	for i, e := range h.events {
+		if e == event {
+			return i
+		}
+	}
+	return -1
+}
+
+// dependentBlueprint returns a blueprint of two independent modules, and a chained pair of modules
+// in which the "transform" module reads the data served by the "read" module
+func dependentBlueprint() *fapp.Blueprint {
+	module := func(name string, dependsOn ...string) fapp.BlueprintModule {
+		return fapp.BlueprintModule{Name: name, Chart: fapp.ChartSpec{Name: "ghcr.io/fybrik/" + name + ":0.1.0"}, DependsOn: dependsOn}
+	}
+	blueprint := &fapp.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dependent-modules",
+			Namespace: environment.GetInternalCRsNamespace(),
+			Labels: map[string]string{
+				utils.ApplicationNameLabel:      "notebook",
+				utils.ApplicationNamespaceLabel: "default",
+			},
+			Annotations: map[string]string{utils.FybrikAppUUID: "1234"},
+			// a new generation of the blueprint, whose charts are applied
+			Generation: 1,
+		},
+		Spec: fapp.BlueprintSpec{
+			Cluster:          "thegreendragon",
+			ModulesNamespace: environment.GetDefaultModulesNamespace(),
+			Application:      &fapp.ApplicationDetails{},
+			Modules: map[string]fapp.BlueprintModule{
+				"copy-db2":  module("copy"),
+				"copy-s3":   module("copy"),
+				"read":      module("read"),
+				"transform": module("transform", "read"),
+			},
+		},
+	}
+	return blueprint
+}
+
+func reconcileDependentBlueprint(g *gomega.WithT, helmer *deployingHelmer) *fapp.Blueprint {
+	blueprint := dependentBlueprint()
+	s := utils.NewScheme(g)
+	cl := fake.NewFakeClientWithScheme(s, blueprint)
+	r := &BlueprintReconciler{
+		Client: cl,
+		Name:   "BlueprintTestController",
+		Log:    logging.LogInit(logging.CONTROLLER, "test-blueprint-controller"),
+		Scheme: s,
+		Helmer: helmer,
+	}
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(blueprint)})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(blueprint), blueprint)).To(gomega.Succeed())
+	return blueprint
+}
+
+// A blueprint holds two independent modules, and a module that reads the data served by another module
+// Result: the independent modules and the first module of the chain are deployed concurrently,
+// and the second module of the chain is deployed once the first one is deployed
+func TestBlueprintConcurrentDeployment(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	helmer := &deployingHelmer{Fake: helm.NewEmptyFake()}
+	blueprint := reconcileDependentBlueprint(g, helmer)
+	releaseName := func(instanceName string) string {
+		return utils.GetReleaseName("notebook", "1234", instanceName)
+	}
+
+	g.Expect(helmer.events).To(gomega.HaveLen(8))
+	g.Expect(helmer.maxInFlight).To(gomega.Equal(3))
+	// the modules are deployed with distinct helm configurations
+	g.Expect(helmer.configs).To(gomega.HaveLen(4))
+	for _, instanceName := range []string{"copy-db2", "copy-s3", "read"} {
+		// the independent modules start before any of them ends
+		g.Expect(helmer.eventIndex("start " + releaseName(instanceName))).To(gomega.BeNumerically("<", 3))
+	}
+	g.Expect(helmer.eventIndex("start " + releaseName("transform"))).To(
+		gomega.BeNumerically(">", helmer.eventIndex("end "+releaseName("read"))))
+	g.Expect(blueprint.Status.Releases).To(gomega.HaveLen(4))
+	g.Expect(blueprint.Status.ObservedState.Error).To(gomega.BeEmpty())
+}
+
+// The deployment of a module that another module depends on fails
+// Result: the dependent module is not deployed, while the independent modules are deployed
+func TestBlueprintFailedDependency(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	helmer := &deployingHelmer{Fake: helm.NewEmptyFake(), failing: utils.GetReleaseName("notebook", "1234", "read")}
+	blueprint := reconcileDependentBlueprint(g, helmer)
+
+	g.Expect(helmer.events).To(gomega.HaveLen(6))
+	g.Expect(helmer.eventIndex("start " + utils.GetReleaseName("notebook", "1234", "transform"))).To(gomega.Equal(-1))
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeFalse())
+	g.Expect(blueprint.Status.ModulesState["read"].Error).To(gomega.ContainSubstring("installation failed"))
+	g.Expect(blueprint.Status.ModulesState["transform"].Error).To(gomega.ContainSubstring("transform is not deployed"))
+	g.Expect(blueprint.Status.ModulesState["copy-db2"].Error).To(gomega.BeEmpty())
+}
+
+func TestDeploymentWaves(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	modules := map[string]fapp.BlueprintModule{
+		"copy":      {},
+		"read":      {DependsOn: []string{"copy"}},
+		"transform": {DependsOn: []string{"read", "copy"}},
+		// a dependency on a module in another cluster is ignored
+		"write": {DependsOn: []string{"remote-read"}},
+	}
+	g.Expect(deploymentWaves(modules)).To(gomega.Equal([][]string{{"copy", "write"}, {"read"}, {"transform"}}))
+
+	// the modules of a cycle are deployed together
+	modules = map[string]fapp.BlueprintModule{
+		"a": {DependsOn: []string{"b"}},
+		"b": {DependsOn: []string{"a"}},
+		"c": {},
+	}
+	g.Expect(deploymentWaves(modules)).To(gomega.Equal([][]string{{"c"}, {"a", "b"}}))
+	g.Expect(deploymentWaves(nil)).To(gomega.BeEmpty())
+}
+
 // This test checks that a short release name is not truncated
 func TestShortReleaseName(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,62 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"sort"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+)
+
+// deploymentWaves orders the modules of a blueprint in waves, such that a module is deployed after the modules that it
+// depends on, while the modules of a wave are deployed concurrently. The instance names of each wave are sorted.
+// The dependencies on modules that are not in the blueprint are ignored, and the modules of a dependency cycle
+// are deployed together in the last wave.
+func deploymentWaves(modules map[string]fapp.BlueprintModule) [][]string {
+	waves := [][]string{}
+	planned := map[string]bool{}
+	for len(planned) < len(modules) {
+		wave := []string{}
+		for instanceName := range modules {
+			if !planned[instanceName] && dependenciesPlanned(modules, instanceName, planned) {
+				wave = append(wave, instanceName)
+			}
+		}
+		if len(wave) == 0 {
+			// the remaining modules depend on each other
+			for instanceName := range modules {
+				if !planned[instanceName] {
+					wave = append(wave, instanceName)
+				}
+			}
+		}
+		sort.Strings(wave)
+		for _, instanceName := range wave {
+			planned[instanceName] = true
+		}
+		waves = append(waves, wave)
+	}
+	return waves
+}
+
+// dependenciesPlanned returns true if the modules of the blueprint that the given module depends on
+// are deployed in the previous waves
+func dependenciesPlanned(modules map[string]fapp.BlueprintModule, instanceName string, planned map[string]bool) bool {
+	for _, dependency := range modules[instanceName].DependsOn {
+		if _, found := modules[dependency]; found && dependency != instanceName && !planned[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+// failedDependency returns the name of a module that the given module depends on, and whose deployment has failed
+func failedDependency(module *fapp.BlueprintModule, failed map[string]bool) string {
+	for _, dependency := range module.DependsOn {
+		if failed[dependency] {
+			return dependency
+		}
+	}
+	return ""
+}
@@ -38,6 +38,12 @@ func (r *PlotterReconciler) RefineInstances(instances []ModuleInstanceSpec) []Mo
 			instance.Module.Arguments.Assets = append(instance.Module.Arguments.Assets, instances[ind].Module.Arguments.Assets...)
 			// AssetID is used for step name generation
 			instance.Module.AssetIDs = append(instance.Module.AssetIDs, instances[ind].Module.AssetIDs...)
+			// the united instance depends on the modules of all assets
+			for _, dependency := range instances[ind].Module.DependsOn {
+				if !dependsOn(&instance.Module, dependency) {
+					instance.Module.DependsOn = append(instance.Module.DependsOn, dependency)
+				}
+			}
 			instanceMap[key] = instance
 		}
 	}
@@ -82,17 +88,40 @@ func (r *PlotterReconciler) GenerateBlueprint(instances []ModuleInstanceSpec,
 			WorkloadSelector: plotter.Spec.Selector.WorkloadSelector,
 			Context:          plotter.Spec.AppInfo,
 		},
+		ModuleResources: plotter.Spec.ModuleResources.DeepCopy(),
 	}
 	// Create the map that contains BlueprintModules
 	for ind := range instances {
-		modulename := instances[ind].Module.Name
-		instanceName := modulename
-		if instances[ind].Scope == fapp.Asset {
-			// Need unique name for each module
-			// if the module scope is one per asset then concat the id of the asset to it
-			instanceName = utils.CreateStepName(modulename, instances[ind].Module.AssetIDs[0])
+		instanceName := moduleInstanceName(&instances[ind])
+		module := instances[ind].Module
+		// a module of a united instance may read the data served by another module of the same instance
+		module.DependsOn = nil
+		for _, dependency := range instances[ind].Module.DependsOn {
+			if dependency != instanceName {
+				module.DependsOn = append(module.DependsOn, dependency)
+			}
 		}
-		spec.Modules[instanceName] = instances[ind].Module
+		spec.Modules[instanceName] = module
 	}
 	return spec
 }
+
+// dependsOn returns true if the module depends on the module instance of the given name
+func dependsOn(module *fapp.BlueprintModule, instanceName string) bool {
+	for _, dependency := range module.DependsOn {
+		if dependency == instanceName {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleInstanceName returns the name of the module instance in the blueprint
+func moduleInstanceName(instance *ModuleInstanceSpec) string {
+	if instance.Scope == fapp.Asset {
+		// Need unique name for each module
+		// if the module scope is one per asset then concat the id of the asset to it
+		return utils.CreateStepName(instance.Module.Name, instance.Module.AssetIDs[0])
+	}
+	return instance.Module.Name
+}
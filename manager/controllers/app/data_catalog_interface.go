@@ -4,10 +4,10 @@
 package app
 
 import (
-	"github.com/rs/zerolog/log"
-
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	dcclient "fybrik.io/fybrik/pkg/connectors/datacatalog/clients"
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/vault"
 )
@@ -21,8 +21,10 @@ import (
 // - an error if happened
 // - the new asset identifier
 func (r *FybrikApplicationReconciler) RegisterAsset(assetID string, catalogID string,
-	info *fapp.DatasetDetails, input *fapp.FybrikApplication) (string, error) {
-	r.Log.Trace().Msg("RegisterAsset")
+	info *fapp.DatasetDetails, appContext ApplicationContext) (string, error) {
+	input := appContext.Application
+	log := appContext.Log.With().Str(logging.DATASETID, assetID).Logger()
+	log.Trace().Msg("RegisterAsset")
 	details := datacatalog.ResourceDetails{}
 	if info.Details != nil {
 		details.Connection = info.Details.Connection
@@ -58,7 +60,7 @@ func (r *FybrikApplicationReconciler) RegisterAsset(assetID string, catalogID st
 
 	var err error
 	var response *datacatalog.CreateAssetResponse
-	if response, err = r.DataCatalog.CreateAsset(&request, credentialPath); err != nil {
+	if response, err = dcclient.WithCorrelationID(r.DataCatalog, appContext.CorrelationID).CreateAsset(&request, credentialPath); err != nil {
 		log.Error().Err(err).Msg("failed to receive the catalog connector response")
 		return "", err
 	}
@@ -0,0 +1,89 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+const (
+	// DryRunPlotterKey is the ConfigMap key holding the Plotter spec computed in the dry-run mode
+	DryRunPlotterKey = "plotter.yaml"
+	dryRunSuffix     = "-dry-run"
+	// DryRunPlanned is the message of the DryRun condition of the datasets whose data path has been computed
+	DryRunPlanned = "the data path has been computed in the dry-run mode, no module has been deployed"
+)
+
+// storeDryRunResult stores the computed Plotter spec in a ConfigMap owned by the application.
+// A Plotter generated for a previous version of the application is deleted, since nothing should be deployed in the dry-run mode.
+func (r *FybrikApplicationReconciler) storeDryRunResult(applicationContext ApplicationContext, plotterSpec *fappv1.PlotterSpec) error {
+	application := applicationContext.Application
+	if application.Status.Generated != nil {
+		if err := r.ResourceInterface.DeleteResource(application.Status.Generated); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		application.Status.Generated = nil
+	}
+	content, err := yaml.Marshal(plotterSpec)
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      application.Name + dryRunSuffix,
+			Namespace: application.Namespace,
+		},
+	}
+	if _, err := ctrl.CreateOrUpdate(context.Background(), r.Client, configMap, func() error {
+		configMap.Labels = ownerLabels(client.ObjectKeyFromObject(application))
+		configMap.Data = map[string]string{DryRunPlotterKey: string(content)}
+		// the ConfigMap is garbage collected together with the application
+		return ctrlutil.SetOwnerReference(application, configMap, r.Client.Scheme())
+	}); err != nil {
+		return err
+	}
+	application.Status.DryRunResult = &fappv1.ResourceReference{
+		Name:       configMap.Name,
+		Namespace:  configMap.Namespace,
+		Kind:       "ConfigMap",
+		AppVersion: application.GetGeneration(),
+	}
+	applicationContext.Log.Info().Str(logging.ACTION, logging.CREATE).
+		Msgf("Dry-run: the Plotter spec has been stored in the ConfigMap %s", configMap.Name)
+	// the data path has been computed for the assets that are neither denied nor failed,
+	// they are not reported as ready since nothing serves their data
+	for _, asset := range application.Spec.Data {
+		state := application.Status.AssetStates[asset.DataSetID]
+		if state.Conditions[DenyConditionIndex].Status != corev1.ConditionTrue &&
+			state.Conditions[ErrorConditionIndex].Status != corev1.ConditionTrue && !isAssetNotFound(&state) {
+			setDryRunCondition(applicationContext, asset.DataSetID, DryRunPlanned)
+		}
+	}
+	return nil
+}
+
+// deleteDryRunResult removes the ConfigMap created in the dry-run mode for a previous version of the application
+func (r *FybrikApplicationReconciler) deleteDryRunResult(applicationContext ApplicationContext) error {
+	ref := applicationContext.Application.Status.DryRunResult
+	if ref == nil {
+		return nil
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}}
+	if err := r.Client.Delete(context.Background(), configMap); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	applicationContext.Application.Status.DryRunResult = nil
+	return nil
+}
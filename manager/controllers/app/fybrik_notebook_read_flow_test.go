@@ -6,29 +6,27 @@ package app
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net"
 	"os"
-	"os/exec"
-	"strconv"
 	"strings"
 	"testing"
-	"time"
 
+	"emperror.dev/errors"
 	"github.com/apache/arrow/go/v7/arrow"
 	"github.com/apache/arrow/go/v7/arrow/array"
 	"github.com/apache/arrow/go/v7/arrow/flight"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -36,13 +34,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/pkg/connection"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/test"
 )
 
 const (
-	readFlow                      string        = "notebook-test-readflow"
-	PortFowardingMaxRetryAttempts int           = 25
-	PortForwardingDelay           time.Duration = 5
+	readFlow string = "notebook-test-readflow"
 )
 
 type ArrowRequest struct {
@@ -50,28 +49,82 @@ type ArrowRequest struct {
 	Columns []string `json:"columns,omitempty"`
 }
 
-func RunPortForwardCommandWithRetryAttemps(modulesNamespace, svcName string, portNum int) (string, error) {
-	i := 0
-	var listenPort string
-	var err error
-	var cmd *exec.Cmd
-	for {
-		listenPort, cmd, err = test.RunPortForward(modulesNamespace, svcName, portNum)
-		if err == nil {
-			return listenPort, nil
-		} else if i > PortFowardingMaxRetryAttempts {
-			break
-		}
+// ensureS3Object uploads the file to the given key unless an object already exists there.
+// The upload is done only if the object is known to be absent, other failures, e.g., missing permissions, are returned.
+func ensureS3Object(client s3iface.S3API, bucket, key, filename string) (bool, error) {
+	_, err := client.HeadObject(&s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err == nil {
+		return false, nil
+	}
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) || (awsErr.Code() != "NotFound" && awsErr.Code() != s3.ErrCodeNoSuchKey) {
+		return false, errors.Wrapf(err, "could not check whether %s/%s exists", bucket, key)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, errors.Wrap(err, "could not open the local test data file")
+	}
+	defer f.Close()
+	_, err = client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   f,
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "could not upload %s to %s/%s", filename, bucket, key)
+	}
+	return true, nil
+}
 
-		err = test.StopPortForward(cmd)
-		if err != nil {
-			return "", errors.New("failed to terminate port-forward " + err.Error())
-		}
+// fakeS3 fails the existence checks with the given error and counts the uploads
+type fakeS3 struct {
+	s3iface.S3API
+	headErr error
+	uploads int
+}
 
-		time.Sleep(PortForwardingDelay * time.Second)
-		i++
+func (f *fakeS3) HeadObject(*s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	if f.headErr != nil {
+		return nil, f.headErr
+	}
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func (f *fakeS3) PutObject(*s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	f.uploads++
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestEnsureS3Object(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	filename := "../../testdata/data.csv"
+
+	// the object exists
+	client := &fakeS3{}
+	uploaded, err := ensureS3Object(client, "bucket1", "data.csv", filename)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(uploaded).To(gomega.BeFalse())
+	g.Expect(client.uploads).To(gomega.BeZero())
+
+	// the object does not exist
+	client = &fakeS3{headErr: awserr.New("NotFound", "Not Found", nil)}
+	uploaded, err = ensureS3Object(client, "bucket1", "data.csv", filename)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(uploaded).To(gomega.BeTrue())
+	g.Expect(client.uploads).To(gomega.Equal(1))
+
+	// the existence of the object is unknown
+	for _, headErr := range []error{
+		awserr.New("AccessDenied", "Access Denied", nil),
+		awserr.New(request.ErrCodeRequestError, "send request failed", nil),
+	} {
+		client = &fakeS3{headErr: headErr}
+		uploaded, err = ensureS3Object(client, "bucket1", "data.csv", filename)
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(uploaded).To(gomega.BeFalse())
+		g.Expect(client.uploads).To(gomega.BeZero())
 	}
-	return "", errors.New("Port Forwarding command failed with error")
 }
 
 func TestS3NotebookReadFlow(t *testing.T) {
@@ -88,6 +141,8 @@ func TestS3NotebookReadFlow(t *testing.T) {
 
 	g := gomega.NewWithT(t)
 	defer GinkgoRecover()
+	// the port-forwards are terminated when the test completes
+	portForwarder := test.NewPortForwarder(t, test.RetryPolicy{})
 
 	// Copy data.csv file to S3
 	// S3 is assumed to be exposed on localhost at port 9090
@@ -105,29 +160,11 @@ func TestS3NotebookReadFlow(t *testing.T) {
 		S3ForcePathStyle: aws.Bool(true),
 	}))
 	s3Client := s3.New(sess)
-	object, err := s3Client.GetObject(&s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key1,
-	})
-	if err != nil { // Could not retrieve object. Assume it does not exist
-		uploader := s3manager.NewUploader(sess)
-
-		f, ferr := os.Open(filename)
-		g.Expect(ferr).To(gomega.BeNil(), "Opening local test data file")
-
-		// Upload the file to S3.
-		var result *s3manager.UploadOutput
-		result, err = uploader.Upload(&s3manager.UploadInput{
-			Bucket: aws.String(bucket),
-			Key:    aws.String(key1),
-			Body:   f,
-		})
-		g.Expect(err).To(gomega.BeNil(), "S3 upload")
-		if result != nil {
-			log.Printf("file uploaded to, %s\n", result.Location)
-		}
+	uploaded, err := ensureS3Object(s3Client, bucket, key1, filename)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	if uploaded {
+		log.Printf("%s uploaded to %s\n", filename, bucket)
 	} else {
-		g.Expect(object).ToNot(gomega.BeNil())
 		log.Println("Object already exists in S3!")
 	}
 
@@ -178,6 +215,10 @@ func TestS3NotebookReadFlow(t *testing.T) {
 	fmt.Println("Expecting plotter to be constructed")
 	g.Eventually(func() *fapp.ResourceReference {
 		_ = k8sClient.Get(context.Background(), applicationKey, application)
+		// a missing asset is reported at once, there is no point in waiting for the plotter
+		if state, found := application.Status.AssetStates[catalogedAsset]; found && isAssetNotFound(&state) {
+			gomega.StopTrying("the cataloged asset does not exist: " + state.Conditions[AssetNotFoundConditionIndex].Message).Now()
+		}
 		return application.Status.Generated
 	}, timeout, interval).ShouldNot(gomega.BeNil())
 
@@ -265,6 +306,10 @@ func TestS3NotebookReadFlow(t *testing.T) {
 	fmt.Println("Expecting plotter to be constructed")
 	g.Eventually(func() *fapp.ResourceReference {
 		_ = k8sClient.Get(context.Background(), applicationKey, application)
+		// a missing asset is reported at once, there is no point in waiting for the plotter
+		if state, found := application.Status.AssetStates[catalogedAsset]; found && isAssetNotFound(&state) {
+			gomega.StopTrying("the cataloged asset does not exist: " + state.Conditions[AssetNotFoundConditionIndex].Message).Now()
+		}
 		return application.Status.Generated
 	}, timeout, interval).ShouldNot(gomega.BeNil())
 
@@ -293,26 +338,24 @@ func TestS3NotebookReadFlow(t *testing.T) {
 	g.Expect(application.Status.AssetStates[catalogedAsset].Conditions[ReadyConditionIndex].Status).To(gomega.Equal(v1.ConditionTrue))
 
 	// Forward port of arrow flight service to local port
-	connection := application.Status.AssetStates[catalogedAsset].
-		Endpoint.AdditionalProperties.Items["fybrik-arrow-flight"].(map[string]interface{})
-	hostname := fmt.Sprintf("%v", connection["hostname"])
-	port := fmt.Sprintf("%v", connection["port"])
-	svcName := strings.Replace(hostname, "."+modulesNamespace, "", 1)
+	assetState := application.Status.AssetStates[catalogedAsset]
+	flightEndpoint := arrowFlightEndpoint(g, &assetState)
+	// the in-cluster service of the endpoint is forwarded
+	service, found := assetState.GetService(mockup.ArrowFlight)
+	g.Expect(found).To(gomega.BeTrue())
 
-	fmt.Printf("Starting kubectl port-forward for arrow-flight service %s port %s in ns %s\n", svcName, port, modulesNamespace)
-	portNum, err := strconv.Atoi(port)
-	g.Expect(err).To(gomega.BeNil(), "wrong port number %s", port)
+	fmt.Printf("Starting kubectl port-forward for arrow-flight service %s port %d in ns %s\n", service.Name, service.Port, service.Namespace)
 
-	listenPort, err := RunPortForwardCommandWithRetryAttemps(modulesNamespace, svcName, portNum)
+	listenPort, err := portForwarder.Forward(context.Background(), service.Namespace, service.Name, int(service.Port))
 	if err != nil {
 		g.Fail("Port Forwarding command failed with error " + err.Error())
 	}
 	fmt.Println("kubectl port-forward succeeded")
 
 	// Reading data via arrow flight
-	opts := make([]grpc.DialOption, 0)
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock(), grpc.WithTimeout(timeout))
-	flightClient, err := flight.NewFlightClient(net.JoinHostPort("localhost", listenPort), nil, opts...)
+	// the TLS settings advertised by the endpoint are honored
+	flightClient, err := test.NewFlightClient(context.Background(), k8sClient, net.JoinHostPort("localhost", listenPort), flightEndpoint,
+		grpc.WithBlock(), grpc.WithTimeout(timeout))
 	g.Expect(err).To(gomega.BeNil(), "Connect to arrow-flight service")
 	defer flightClient.Close()
 
@@ -332,14 +375,11 @@ func TestS3NotebookReadFlow(t *testing.T) {
 	stream, err := flightClient.DoGet(context.Background(), info.Endpoint[0].Ticket)
 	g.Expect(err).To(gomega.BeNil())
 
-	reader, err := flight.NewRecordReader(stream)
-	defer reader.Release()
-	g.Expect(err).To(gomega.BeNil())
-	var record arrow.Record
-
-	for reader.Next() {
-		record = reader.Record()
+	// the redacted values are replaced by the mask of the applied redact action
+	redact := appliedRedaction(g, &assetState)
 
+	// the stream is read one batch at a time, so that large datasets do not have to fit in memory
+	stats, err := test.ReadFlightStream(stream, test.StreamOptions{}, func(record arrow.Record) error {
 		g.Expect(record.ColumnName(0)).To(gomega.Equal("step"))
 		g.Expect(record.ColumnName(1)).To(gomega.Equal("type"))
 		g.Expect(record.ColumnName(3)).To(gomega.Equal("nameOrig"))
@@ -352,9 +392,33 @@ func TestS3NotebookReadFlow(t *testing.T) {
 		g.Expect(dt.Name()).To(gomega.Equal((&arrow.StringType{}).Name()))
 		data := array.NewStringData(column.Data())
 		for i := 0; i < data.Len(); i++ {
-			g.Expect(data.Value(i)).To(gomega.Equal("XXXXX"))
+			g.Expect(data.Value(i)).To(gomega.Equal(redact.MaskValue(data.Value(i))))
 		}
-	}
-	record.Release()
+		return nil
+	})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(stats.Rows).To(gomega.BeNumerically(">", 0))
 	fmt.Println("read-flow test succeeded")
 }
+
+// arrowFlightEndpoint returns the arrow-flight endpoint serving the asset
+func arrowFlightEndpoint(g *gomega.WithT, assetState *fapp.AssetState) *connection.ArrowFlightConnection {
+	details, found := assetState.GetEndpoint(mockup.ArrowFlight)
+	g.Expect(found).To(gomega.BeTrue())
+	endpoint, err := connection.DecodeArrowFlight(details)
+	g.Expect(err).To(gomega.BeNil())
+	return endpoint
+}
+
+// appliedRedaction returns the redact action applied to the data of the asset
+func appliedRedaction(g *gomega.WithT, assetState *fapp.AssetState) taxonomy.RedactAction {
+	redact := taxonomy.RedactAction{}
+	for i := range assetState.AppliedActions {
+		if assetState.AppliedActions[i].Name == taxonomy.RedactActionName {
+			g.Expect(taxonomy.DecodeActionProperties(&assetState.AppliedActions[i], &redact)).To(gomega.Succeed())
+			return redact
+		}
+	}
+	g.Fail("the redact action is not applied to the asset")
+	return redact
+}
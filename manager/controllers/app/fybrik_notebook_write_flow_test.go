@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"strconv"
 	"strings"
 	"testing"
 
@@ -19,10 +18,13 @@ import (
 	"github.com/apache/arrow/go/v7/arrow/csv"
 	"github.com/apache/arrow/go/v7/arrow/flight"
 	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	. "github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -32,6 +34,8 @@ import (
 
 	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	fappv2 "fybrik.io/fybrik/manager/apis/app/v1beta2"
+	"fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/pkg/test"
 )
 
 const (
@@ -115,6 +119,31 @@ func testWriteDeny(t *testing.T, k8sClient client.Client) {
 	}, timeout, interval).Should(gomega.Succeed())
 }
 
+// expectS3ObjectExists checks that the object has been written to S3.
+// S3 is assumed to be exposed on localhost at port 9090
+func expectS3ObjectExists(g *gomega.WithT, bucket, object string) {
+	region := "theshire"
+	endpoint := "http://localhost:9090"
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials:      credentials.NewStaticCredentials("ak", "sk", ""),
+		Endpoint:         &endpoint,
+		Region:           &region,
+		S3ForcePathStyle: aws.Bool(true),
+	}))
+	s3Client := s3.New(sess)
+	// the module may store the object as a directory of files
+	g.Eventually(func() int {
+		res, err := s3Client.ListObjectsV2(&s3.ListObjectsV2Input{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(object),
+		})
+		if err != nil {
+			return 0
+		}
+		return len(res.Contents)
+	}, timeout, interval).ShouldNot(gomega.BeZero())
+}
+
 func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 	// This test checks the following scenarios:
 	// (a) how to write data generated by the workload to an object store.
@@ -122,6 +151,8 @@ func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 	fmt.Println("Starting allow write scenario")
 	var err error
 	g := gomega.NewWithT(t)
+	// the port-forwards are terminated when the test completes
+	portForwarder := test.NewPortForwarder(t, test.RetryPolicy{})
 	// Module installed by setup script directly from remote arrow-flight-module repository
 	// Installing application
 	writeApplication := &fappv1.FybrikApplication{}
@@ -216,25 +247,23 @@ func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 
 	g.Expect(writeApplication.Status.AssetStates["new-data"].Endpoint.Name).ToNot(gomega.BeEmpty())
 	// Forward port of arrow flight service to local port
-	connection := writeApplication.Status.AssetStates["new-data"].
-		Endpoint.AdditionalProperties.Items["fybrik-arrow-flight"].(map[string]interface{})
-	hostname := fmt.Sprintf("%v", connection["hostname"])
-	port := fmt.Sprintf("%v", connection["port"])
-	svcName := strings.Replace(hostname, "."+modulesNamespace, "", 1)
+	assetState := writeApplication.Status.AssetStates["new-data"]
+	flightEndpoint := arrowFlightEndpoint(g, &assetState)
+	// the in-cluster service of the endpoint is forwarded
+	service, found := assetState.GetService(mockup.ArrowFlight)
+	g.Expect(found).To(gomega.BeTrue())
 
 	fmt.Println("Starting kubectl port-forward for arrow-flight")
-	portNum, err := strconv.Atoi(port)
-	g.Expect(err).To(gomega.BeNil())
-	listenPort, err := RunPortForwardCommandWithRetryAttemps(modulesNamespace, svcName, portNum)
+	listenPort, err := portForwarder.Forward(context.Background(), service.Namespace, service.Name, int(service.Port))
 	if err != nil {
 		g.Fail("Port Forwarding command failed with error " + err.Error())
 	}
 	fmt.Printf("kubectl port-forward command succeeded")
 
 	// Writing data via arrow flight
-	opts := make([]grpc.DialOption, 0)
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock(), grpc.WithTimeout(timeout))
-	flightClient, err := flight.NewFlightClient(net.JoinHostPort("localhost", listenPort), nil, opts...)
+	// the TLS settings advertised by the endpoint are honored
+	flightClient, err := test.NewFlightClient(context.Background(), k8sClient, net.JoinHostPort("localhost", listenPort), flightEndpoint,
+		grpc.WithBlock(), grpc.WithTimeout(timeout))
 	g.Expect(err).To(gomega.BeNil(), "Connect to arrow-flight service")
 	defer flightClient.Close()
 
@@ -297,6 +326,9 @@ func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 	err = writeStream.CloseSend()
 	g.Expect(err).To(gomega.BeNil())
 
+	fmt.Println("Expecting the written object to land in S3")
+	expectS3ObjectExists(g, newBucket, newObject)
+
 	// cleanup
 	g.Eventually(func() error {
 		return k8sClient.Delete(context.Background(), writeApplication)
@@ -348,25 +380,23 @@ func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 	fmt.Printf("data access module namespace notebook test: %s\n", modulesNamespace)
 
 	// Forward port of arrow flight service to local port
-	connection = readApplication.Status.AssetStates[newCatalogedAsset].
-		Endpoint.AdditionalProperties.Items["fybrik-arrow-flight"].(map[string]interface{})
-	hostname = fmt.Sprintf("%v", connection["hostname"])
-	port = fmt.Sprintf("%v", connection["port"])
-	svcName = strings.Replace(hostname, "."+modulesNamespace, "", 1)
+	assetState = readApplication.Status.AssetStates[newCatalogedAsset]
+	flightEndpoint = arrowFlightEndpoint(g, &assetState)
+	// the in-cluster service of the endpoint is forwarded
+	service, found = assetState.GetService(mockup.ArrowFlight)
+	g.Expect(found).To(gomega.BeTrue())
 
 	fmt.Println("Starting kubectl port-forward for arrow-flight")
-	portNum, err = strconv.Atoi(port)
-	g.Expect(err).To(gomega.BeNil())
-	listenPort, err = RunPortForwardCommandWithRetryAttemps(modulesNamespace, svcName, portNum)
+	listenPort, err = portForwarder.Forward(context.Background(), service.Namespace, service.Name, int(service.Port))
 	if err != nil {
 		g.Fail("Port Forwarding command failed with error " + err.Error())
 	}
 	fmt.Println("kubectl port-forward command succeeded")
 
 	// Reading data via arrow flight
-	opts = make([]grpc.DialOption, 0)
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock(), grpc.WithTimeout(timeout))
-	flightClient, err = flight.NewFlightClient(net.JoinHostPort("localhost", listenPort), nil, opts...)
+	// the TLS settings advertised by the endpoint are honored
+	flightClient, err = test.NewFlightClient(context.Background(), k8sClient, net.JoinHostPort("localhost", listenPort), flightEndpoint,
+		grpc.WithBlock(), grpc.WithTimeout(timeout))
 	g.Expect(err).To(gomega.BeNil(), "Connect to arrow-flight service")
 	defer flightClient.Close()
 
@@ -386,14 +416,11 @@ func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 	stream, err := flightClient.DoGet(context.Background(), info.Endpoint[0].Ticket)
 	g.Expect(err).To(gomega.BeNil())
 
-	reader, err := flight.NewRecordReader(stream)
-	defer reader.Release()
-	g.Expect(err).To(gomega.BeNil())
-	var record arrow.Record
-
-	for reader.Next() {
-		record = reader.Record()
+	// the redacted values are replaced by the mask of the applied redact action
+	redact := appliedRedaction(g, &assetState)
 
+	// the stream is read one batch at a time, so that large datasets do not have to fit in memory
+	stats, err := test.ReadFlightStream(stream, test.StreamOptions{}, func(record arrow.Record) error {
 		g.Expect(record.ColumnName(0)).To(gomega.Equal("step"))
 		g.Expect(record.ColumnName(1)).To(gomega.Equal("type"))
 		g.Expect(record.ColumnName(3)).To(gomega.Equal("nameOrig"))
@@ -406,10 +433,12 @@ func testWriteAllowed(t *testing.T, k8sClient client.Client) {
 		g.Expect(dt.Name()).To(gomega.Equal((&arrow.StringType{}).Name()))
 		data := array.NewStringData(column.Data())
 		for i := 0; i < data.Len(); i++ {
-			g.Expect(data.Value(i)).To(gomega.Equal("XXXXX"))
+			g.Expect(data.Value(i)).To(gomega.Equal(redact.MaskValue(data.Value(i))))
 		}
-	}
-	record.Release()
+		return nil
+	})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(stats.Rows).To(gomega.BeNumerically(">", 0))
 
 	// cleanup
 	g.Eventually(func() error {
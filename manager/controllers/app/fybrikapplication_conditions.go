@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/pkg/logging"
@@ -20,7 +21,13 @@ const (
 	DenyConditionIndex int64 = 1
 	// ErrorCondition means that an error was encountered during blueprint construction
 	ErrorConditionIndex int64 = 2
-	numConditions       int   = 3
+	// WarningCondition means that a problem that does not prevent the access to a dataset was encountered
+	WarningConditionIndex int64 = 3
+	// AssetNotFoundCondition means that the dataset does not exist in the data catalog
+	AssetNotFoundConditionIndex int64 = 4
+	// DryRunCondition means that the data path of a dataset has been computed in the dry-run mode, without deploying it
+	DryRunConditionIndex int64 = 5
+	numConditions        int   = 6
 )
 
 // Helper functions to manage conditions
@@ -40,6 +47,9 @@ func initStatus(application *fapp.FybrikApplication) {
 
 func resetAssetState(application *fapp.FybrikApplication, assetID string) {
 	conditions := make([]fapp.Condition, numConditions)
+	conditions[DryRunConditionIndex] = fapp.Condition{Type: fapp.DryRunCondition, Status: corev1.ConditionFalse}
+	conditions[AssetNotFoundConditionIndex] = fapp.Condition{Type: fapp.AssetNotFoundCondition, Status: corev1.ConditionFalse}
+	conditions[WarningConditionIndex] = fapp.Condition{Type: fapp.WarningCondition, Status: corev1.ConditionFalse}
 	conditions[ErrorConditionIndex] = fapp.Condition{Type: fapp.ErrorCondition, Status: corev1.ConditionFalse}
 	conditions[DenyConditionIndex] = fapp.Condition{Type: fapp.DenyCondition, Status: corev1.ConditionFalse}
 	conditions[ReadyConditionIndex] = fapp.Condition{Type: fapp.ReadyCondition, Status: corev1.ConditionFalse}
@@ -64,13 +74,64 @@ func setDenyCondition(appContext ApplicationContext, assetID, msg string) {
 		Str(logging.DATASETID, assetID).Msg("Setting deny condition: " + msg)
 }
 
+func setWarningCondition(appContext ApplicationContext, assetID, msg string) {
+	appContext.Application.Status.AssetStates[assetID].Conditions[WarningConditionIndex] = fapp.Condition{
+		Type:    fapp.WarningCondition,
+		Status:  corev1.ConditionTrue,
+		Message: msg}
+	appContext.Log.Warn().Bool(logging.FORUSER, true).Bool(logging.AUDIT, true).
+		Str(logging.DATASETID, assetID).Msg("Setting warning condition: " + msg)
+}
+
+// setAssetNotFoundCondition reports that the dataset does not exist in the data catalog.
+// The condition is terminal, i.e., the application is not reconciled again until it is changed.
+func setAssetNotFoundCondition(appContext ApplicationContext, assetID, msg string) {
+	appContext.Application.Status.AssetStates[assetID].Conditions[AssetNotFoundConditionIndex] = fapp.Condition{
+		Type:    fapp.AssetNotFoundCondition,
+		Status:  corev1.ConditionTrue,
+		Message: msg}
+	appContext.Log.Error().Bool(logging.FORUSER, true).Bool(logging.AUDIT, true).
+		Str(logging.DATASETID, assetID).Msg("Setting asset not found condition: " + msg)
+}
+
 func setReadyCondition(appContext ApplicationContext, assetID string) {
 	appContext.Application.Status.AssetStates[assetID].Conditions[ReadyConditionIndex].Status = corev1.ConditionTrue
 	appContext.Log.Info().Bool(logging.FORUSER, true).Bool(logging.AUDIT, true).
 		Str(logging.DATASETID, assetID).Msg("Setting ready condition")
 }
 
-// determine if the application is ready
+// recordTransition appends a transition of the reconcile to the status history.
+// The oldest transitions are dropped to keep at most MaxTransitionHistory transitions.
+func recordTransition(application *fapp.FybrikApplication, transitionType fapp.TransitionType, message string) {
+	application.Status.History = append(application.Status.History, fapp.Transition{
+		Type:               transitionType,
+		Time:               metav1.Now(),
+		Message:            message,
+		ObservedGeneration: application.GetGeneration(),
+	})
+	if excess := len(application.Status.History) - fapp.MaxTransitionHistory; excess > 0 {
+		application.Status.History = append([]fapp.Transition{}, application.Status.History[excess:]...)
+	}
+}
+
+// setDryRunCondition reports that the data path of the dataset has been computed in the dry-run mode.
+// The dataset is not ready, since nothing is deployed.
+func setDryRunCondition(appContext ApplicationContext, assetID, msg string) {
+	appContext.Application.Status.AssetStates[assetID].Conditions[DryRunConditionIndex] = fapp.Condition{
+		Type:    fapp.DryRunCondition,
+		Status:  corev1.ConditionTrue,
+		Message: msg}
+	appContext.Log.Info().Bool(logging.FORUSER, true).Bool(logging.AUDIT, true).
+		Str(logging.DATASETID, assetID).Msg("Setting dry-run condition: " + msg)
+}
+
+// isAssetNotFound returns true if the data catalog has reported that the asset does not exist
+func isAssetNotFound(state *fapp.AssetState) bool {
+	return len(state.Conditions) > int(AssetNotFoundConditionIndex) &&
+		state.Conditions[AssetNotFoundConditionIndex].Status == corev1.ConditionTrue
+}
+
+// determine if the application is ready, i.e., every asset is either ready, denied or not found in the data catalog
 func isReady(application *fapp.FybrikApplication) bool {
 	if len(application.Spec.Data) == 0 {
 		return true
@@ -84,13 +145,31 @@ func isReady(application *fapp.FybrikApplication) bool {
 			return false
 		}
 		if assetState.Conditions[DenyConditionIndex].Status == corev1.ConditionFalse &&
-			assetState.Conditions[ReadyConditionIndex].Status == corev1.ConditionFalse {
+			assetState.Conditions[ReadyConditionIndex].Status == corev1.ConditionFalse && !isAssetNotFound(&assetState) {
 			return false
 		}
 	}
 	return true
 }
 
+// countAssets returns the number of ready assets and the number of denied assets
+func countAssets(application *fapp.FybrikApplication) (int, int) {
+	var ready, denied int
+	for _, asset := range application.Spec.Data {
+		assetState, found := application.Status.AssetStates[asset.DataSetID]
+		if !found || len(assetState.Conditions) == 0 {
+			continue
+		}
+		switch {
+		case assetState.Conditions[DenyConditionIndex].Status == corev1.ConditionTrue:
+			denied++
+		case assetState.Conditions[ReadyConditionIndex].Status == corev1.ConditionTrue:
+			ready++
+		}
+	}
+	return ready, denied
+}
+
 func getErrorMessages(application *fapp.FybrikApplication) string {
 	if application.Status.ErrorMessage != "" {
 		return application.Status.ErrorMessage
@@ -9,21 +9,30 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"reflect"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -32,6 +41,8 @@ import (
 	"fybrik.io/fybrik/manager/controllers"
 	"fybrik.io/fybrik/manager/controllers/utils"
 	"fybrik.io/fybrik/pkg/adminconfig"
+	"fybrik.io/fybrik/pkg/connection"
+	"fybrik.io/fybrik/pkg/connectors"
 	dcclient "fybrik.io/fybrik/pkg/connectors/datacatalog/clients"
 	pmclient "fybrik.io/fybrik/pkg/connectors/policymanager/clients"
 	storage "fybrik.io/fybrik/pkg/connectors/storagemanager/clients"
@@ -39,12 +50,15 @@ import (
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/infrastructure"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/multicluster"
+	"fybrik.io/fybrik/pkg/random"
 	"fybrik.io/fybrik/pkg/serde"
+	"fybrik.io/fybrik/pkg/tracing"
 	"fybrik.io/fybrik/pkg/validate"
 	"fybrik.io/fybrik/pkg/vault"
 )
@@ -62,12 +76,68 @@ type FybrikApplicationReconciler struct {
 	StorageManager    storage.StorageManagerInterface
 	ConfigEvaluator   adminconfig.EvaluatorInterface
 	Infrastructure    *infrastructure.AttributeManager
+	// Recorder emits events on FybrikApplications, optional
+	Recorder record.EventRecorder
+	// Solver selects the modules of the data paths, the DefaultSolver is used if not set
+	Solver Solver
+	// ModulePreferences choose between the modules that are equally capable of performing an action, optional
+	ModulePreferences []datapath.ModulePreference
+	// PolicyDecisions caches the policy manager responses across reconciles, optional
+	PolicyDecisions *AsyncPolicyDecisionCache
+	// DefaultDeny denies the access to data unless a policy returns an explicit Allow action
+	DefaultDeny bool
+	// FailClosed tears down the data paths of the assets whose policy decisions can not be evaluated,
+	// e.g., while the policy manager is unreachable, rather than keeping them until the decisions are evaluated again
+	FailClosed bool
+	// ActionPrecedence determines which of the conflicting policy actions are applied, DefaultActionPrecedence if not set
+	ActionPrecedence ActionPrecedence
+	// AssetInfo caches the data catalog responses to be used while the data catalog is unavailable, optional
+	AssetInfo *AssetInfoCache
+	// DegradedMode is the governance applied to the assets whose cached metadata is used while the data catalog is unavailable
+	DegradedMode DegradedModePolicy
+	// ModulesNamespaceAuthorizer checks the permissions in the modules namespaces requested by the applications,
+	// the namespaces are not checked if not set
+	ModulesNamespaceAuthorizer ModulesNamespaceAuthorizer
+	// NamedPolicyManagers are the policy managers that the applications may select by name instead of PolicyManager,
+	// e.g., to evaluate the staging policies, optional
+	NamedPolicyManagers map[string]pmclient.PolicyManager
 }
 
 type ApplicationContext struct {
 	Log         *zerolog.Logger
 	Application *fappv1.FybrikApplication
 	UUID        string
+	// CorrelationID identifies the reconcile in the logs and in the requests sent to the connectors
+	CorrelationID string
+	// TraceContext holds the span of the reconcile, which is the parent of the spans of the connector requests.
+	// The policy manager requests are canceled once it is done, e.g., when the deadline of the reconcile elapses.
+	TraceContext context.Context
+	// PolicyDecisions caches the policy manager responses during a single reconcile
+	PolicyDecisions *PolicyDecisionCache
+	// AsyncPolicyDecisions caches the policy manager responses across reconciles, nil if disabled
+	AsyncPolicyDecisions *AsyncPolicyDecisionCache
+	// PolicyManager evaluates the governance actions of the application
+	PolicyManager pmclient.PolicyManager
+	// DefaultDeny denies the access to data unless a policy returns an explicit Allow action
+	DefaultDeny bool
+	// FailClosed tears down the data paths of the assets whose policy decisions can not be evaluated
+	FailClosed bool
+	// ActionPrecedence determines which of the conflicting policy actions are applied
+	ActionPrecedence ActionPrecedence
+	// Unevaluated holds the assets whose policy decisions could not be evaluated
+	Unevaluated map[string]bool
+	// Degraded holds the assets whose cached metadata is used while the data catalog is unavailable
+	Degraded map[string]bool
+	// Failures holds the categories of the asset failures, which determine when the reconcile is repeated
+	Failures map[string]FailureCategory
+}
+
+// traceContext returns the context of the spans of the connector requests
+func (c *ApplicationContext) traceContext() context.Context {
+	if c.TraceContext == nil {
+		return context.Background()
+	}
+	return c.TraceContext
 }
 
 var ApplicationTaxonomy = environment.GetDataDir() + "/taxonomy/fybrik_application.json"
@@ -78,6 +148,12 @@ const (
 	FybrikApplicationKind = "FybrikApplication"
 	PlotterUpdatePrefix   = "plotter_"
 	Separator             = " ; "
+	// PolicyDeniedReason is the reason of events reporting a denied access to a dataset
+	PolicyDeniedReason = "PolicyDenied"
+	// DefaultDenyReason explains a denial of an access that no policy allows while the access is denied by default
+	DefaultDenyReason = "no policy explicitly allows the access"
+	// endpointDataFormatKey is the key of the data formats in the connection details of an endpoint
+	endpointDataFormatKey = "dataformat"
 )
 
 // ErrorMessages that are reported to the user
@@ -90,6 +166,21 @@ const (
 	InsufficientStorage         string = "no bucket was provisioned for implicit copy"
 	InvalidClusterConfiguration string = "cluster configuration does not support the requirements"
 	NoDeployedModules           string = "There are no deployed modules in the environment"
+	UnsupportedTransformation   string = "no deployed module supports the transformation required by the governance actions"
+	ModuleNotInGeography        string = "no module available in required geography"
+	UnsupportedDataFormat       string = "no deployed module converts the data to the requested data format"
+	UnsupportedInterface        string = "no deployed module supports the requested interface"
+	InvalidFilterPredicate      string = "governance actions contain an invalid filter predicate"
+	PolicyConflict              string = "governance actions conflict"
+	ModuleHintNotFound          string = "the module requested by moduleHint is not deployed"
+	ModuleHintNotSuitable       string = "the module requested by moduleHint does not satisfy the requirements"
+	ModulesNamespaceForbidden   string = "the manager is not permitted to deploy modules in the requested modules namespace"
+	PolicyManagerNotFound       string = "the policy manager requested by the application is not configured"
+	AssetConnectionMissing      string = "asset has no connection information"
+	AssetVersionMismatch        string = "the data catalog did not return the requested version of the asset"
+	InvalidS3Connection         string = "the S3 connection of the asset is invalid"
+	ColumnsUnavailable          string = "expected columns are unavailable"
+	InternalError               string = "internal error while reconciling the application"
 )
 
 // Reconcile reconciles FybrikApplication CRD
@@ -97,7 +188,12 @@ const (
 // The outcome is a Plotter containing multiple Blueprints that run on different clusters
 //
 //nolint:gocyclo
-func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.ReconcileDuration, start, err) }()
+	ctx, span := tracing.Tracer().Start(ctx, "FybrikApplication.Reconcile",
+		trace.WithAttributes(tracing.ApplicationKey.String(req.NamespacedName.String())))
+	defer func() { tracing.End(span, tracing.OutcomeSuccess, err) }()
 	sublog := r.Log.With().Str(FybrikApplicationKind, req.NamespacedName.String()).Logger()
 
 	sublog.Trace().Msg("*** FybrikApplication Reconcile ***")
@@ -117,11 +213,27 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	}
 
 	uuid := utils.GetFybrikApplicationUUID(application)
-	log := sublog.With().Str(utils.FybrikAppUUID, uuid).Logger()
+	// the correlation id is generated per reconcile, so that the connector requests of a reconcile can be traced
+	correlationID, err := random.Hex(connectors.CorrelationIDLength)
+	if err != nil {
+		sublog.Warn().Err(err).Msg("could not generate a correlation id")
+	}
+	log := sublog.With().Str(utils.FybrikAppUUID, uuid).Str(logging.CORRELATIONID, correlationID).Logger()
+	span.SetAttributes(tracing.CorrelationIDKey.String(correlationID))
 
 	// Log the fybrikapplication
 	logging.LogStructure(FybrikApplicationKind, application, &log, zerolog.TraceLevel, true, true)
-	applicationContext := ApplicationContext{Log: &log, Application: application, UUID: uuid}
+	applicationContext := ApplicationContext{Log: &log, Application: application, UUID: uuid, CorrelationID: correlationID,
+		TraceContext: ctx, PolicyDecisions: NewPolicyDecisionCache(), AsyncPolicyDecisions: r.PolicyDecisions, DefaultDeny: r.DefaultDeny,
+		FailClosed: r.FailClosed, ActionPrecedence: r.ActionPrecedence, Unevaluated: map[string]bool{}, Degraded: map[string]bool{},
+		Failures: map[string]FailureCategory{}}
+	observedStatus := application.Status.DeepCopy()
+	// a panic, e.g., of a connector client, fails the application instead of the manager
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = r.reportPanic(ctx, applicationContext, observedStatus, recovered)
+		}
+	}()
 	if plotterUpdate && (application.Status.Generated == nil || application.Status.Generated.AppVersion != application.GetGeneration()) {
 		// plotter update has been received but it does not match the fybrik application status
 		// this can happen if the plotter has just been created, and the application status was not updated by the server
@@ -132,10 +244,10 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// If the object has a scheduled deletion time, delete it and all resources it has created
 	if !applicationContext.Application.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, r.removeFinalizers(ctx, applicationContext)
+		r.PolicyDecisions.forget(application.UID)
+		return r.removeFinalizers(ctx, applicationContext)
 	}
 
-	observedStatus := application.Status.DeepCopy()
 	appVersion := application.GetGeneration()
 
 	// validate fybrik application if the resource has been created or modified
@@ -148,8 +260,8 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// no datasets are specified - remove finalizers and old resources
 	if len(applicationContext.Application.Spec.Data) == 0 {
-		if err := r.removeFinalizers(ctx, applicationContext); err != nil {
-			return ctrl.Result{}, err
+		if result, err := r.removeFinalizers(ctx, applicationContext); err != nil || result.RequeueAfter > 0 {
+			return result, err
 		}
 		applicationContext.Log.Info().Msg("No plotter will be generated since no datasets are specified")
 		return ctrl.Result{}, nil
@@ -158,25 +270,50 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// check if reconcile is required
 	// reconcile is required if the spec has been changed, or the previous reconcile has failed to allocate a Plotter resource
 	generationComplete := observedStatus.Generated != nil && (observedStatus.Generated.AppVersion == appVersion)
+	if application.Spec.DryRun {
+		// no plotter is generated in the dry-run mode
+		generationComplete = observedStatus.DryRunResult != nil && (observedStatus.DryRunResult.AppVersion == appVersion)
+	} else if application.Spec.RequireApproval && !generationComplete {
+		// a staged Plotter waits for approval, the application is reconciled again once it is annotated
+		generationComplete = observedStatus.StagedPlotter != nil && (observedStatus.StagedPlotter.AppVersion == appVersion) &&
+			!isPlotterApproved(application, observedStatus.StagedPlotterDigest)
+	}
 	if plotterUpdate {
 		// check plotter status and update the application status accordingly
-		resourceStatus, err := r.ResourceInterface.GetResourceStatus(application.Status.Generated)
+		resourceStatus, assetsStatus, err := r.ResourceInterface.GetResourceStatus(application.Status.Generated)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		r.checkReadiness(applicationContext, resourceStatus)
-	} else if (observedStatus.ObservedGeneration != appVersion) || !generationComplete {
-		// spec has been changed, or there was a failure to allocate a plotter
+		r.checkReadiness(applicationContext, resourceStatus, assetsStatus)
+	} else if (observedStatus.ObservedGeneration != appVersion) || !generationComplete || policyWindowElapsed(observedStatus) ||
+		observedStatus.PolicyManager != policyManagerName(application) {
+		// spec has been changed, or there was a failure to allocate a plotter,
+		// or the policy decisions have changed since an action has started or stopped to apply,
+		// or another policy manager has been selected
+		// the finalizer is added before a plotter is allocated, so that the plotter is removed
+		// even if the manager fails before the application status is updated
+		if !application.Spec.DryRun {
+			if err := r.addFinalizers(ctx, applicationContext); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		if result, err := r.reconcile(applicationContext); err != nil || result.Requeue || (result.RequeueAfter > 0) {
 			// another attempt will be done
 			// users should be informed in case of errors
 			// ignore an update error, a new reconcile will be made in any case
+			r.recordDenyEvents(application, observedStatus)
 			_ = utils.UpdateStatus(ctx, r.Client, application, observedStatus)
 			return result, err
 		}
 		application.Status.ObservedGeneration = appVersion
 	}
+	r.recordDenyEvents(application, observedStatus)
 	application.Status.Ready = isReady(application)
+	application.Status.ReadyAssets, application.Status.DeniedAssets = countAssets(application)
+	if application.Status.Ready && !observedStatus.Ready {
+		recordTransition(application, fappv1.ReadyTransition,
+			fmt.Sprintf("%d assets are ready, %d assets are denied", application.Status.ReadyAssets, application.Status.DeniedAssets))
+	}
 	log.Trace().Str(logging.ACTION, logging.UPDATE).Msg("Updating status for desired generation " + fmt.Sprint(application.GetGeneration()))
 	if err := utils.UpdateStatus(ctx, r.Client, application, observedStatus); err != nil {
 		return ctrl.Result{}, err
@@ -187,15 +324,39 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			return ctrl.Result{}, err
 		}
 	}
+	result := requeueResult(applicationContext)
 	if errorMsg := getErrorMessages(application); errorMsg != "" {
-		log.Warn().Str(logging.ACTION, logging.UPDATE).Msg("Reconcile failed with errors")
-		// trigger a new reconcile
-		return ctrl.Result{Requeue: true}, nil
+		if !result.Requeue && result.RequeueAfter == 0 {
+			log.Warn().Str(logging.ACTION, logging.UPDATE).Msg("Reconcile failed with errors that can not be resolved by retrying")
+		} else {
+			log.Warn().Str(logging.ACTION, logging.UPDATE).Msg("Reconcile failed with errors")
+		}
 	}
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
-func (r *FybrikApplicationReconciler) checkReadiness(applicationContext ApplicationContext, status fappv1.ObservedState) {
+// reportPanic reports a panic of the reconcile as an error of every asset of the application.
+// The error is terminal, i.e., the application is not reconciled again until it is changed.
+func (r *FybrikApplicationReconciler) reportPanic(ctx context.Context, applicationContext ApplicationContext,
+	observedStatus *fappv1.FybrikApplicationStatus, recovered interface{}) error {
+	applicationContext.Log.Error().Str("stack", string(debug.Stack())).Msgf("recovered from a panic: %v", recovered)
+	application := applicationContext.Application
+	if application.Status.AssetStates == nil {
+		application.Status.AssetStates = make(map[string]fappv1.AssetState)
+	}
+	msg := fmt.Sprintf("%s: %v", InternalError, recovered)
+	for _, dataCtx := range application.Spec.Data {
+		resetAssetState(application, dataCtx.DataSetID)
+		setErrorCondition(applicationContext, dataCtx.DataSetID, msg)
+	}
+	application.Status.Ready = false
+	application.Status.ReadyAssets, application.Status.DeniedAssets = countAssets(application)
+	application.Status.ObservedGeneration = application.GetGeneration()
+	return utils.UpdateStatus(ctx, r.Client, application, observedStatus)
+}
+
+func (r *FybrikApplicationReconciler) checkReadiness(applicationContext ApplicationContext, status fappv1.ObservedState,
+	assetsStatus map[string]fappv1.ObservedState) {
 	if applicationContext.Application.Status.AssetStates == nil {
 		initStatus(applicationContext.Application)
 	}
@@ -204,24 +365,36 @@ func (r *FybrikApplicationReconciler) checkReadiness(applicationContext Applicat
 	// Temporary fix: all assets that are not in Deny state are updated based on the received status
 	for _, dataCtx := range applicationContext.Application.Spec.Data {
 		assetID := dataCtx.DataSetID
-		if applicationContext.Application.Status.AssetStates[assetID].Conditions[DenyConditionIndex].Status == v1.ConditionTrue {
+		state := applicationContext.Application.Status.AssetStates[assetID]
+		if state.Conditions[DenyConditionIndex].Status == v1.ConditionTrue || isAssetNotFound(&state) {
 			// should not appear in the plotter status
 			continue
 		}
 		if status.Error != "" {
-			setErrorCondition(applicationContext, assetID, status.Error)
+			// the errors of the blueprints, e.g., a module that fails to start, are reported to the user
+			// the data partially written by a transactional module is discarded
+			if err := r.discardStagedWrite(applicationContext, assetID); err != nil {
+				applicationContext.Log.Error().Err(err).Str(logging.DATASETID, assetID).Msg("could not discard the staged data")
+			}
+			setErrorCondition(applicationContext, assetID, strings.TrimSpace(status.Error))
 			continue
 		}
 		if !status.Ready {
 			continue
 		}
+		// the data written by a transactional module becomes visible once the write has completed,
+		// i.e., once the module has run to completion rather than once it is ready
+		if isStaged(applicationContext, assetID) && !assetsStatus[assetID].Completed {
+			continue
+		}
+		if err := r.commitStagedWrite(applicationContext, assetID); err != nil {
+			setErrorCondition(applicationContext, assetID, StagedWriteCommitFailed+": "+err.Error())
+			continue
+		}
 
-		// register assets if the ready state has been received
-		if dataCtx.Requirements.FlowParams.Catalog != "" {
-			if applicationContext.Application.Status.AssetStates[assetID].CatalogedAsset != "" {
-				// the asset has been already cataloged
-				continue
-			}
+		// register assets if the ready state has been received, unless the asset has been already cataloged
+		cataloged := applicationContext.Application.Status.AssetStates[assetID].CatalogedAsset != ""
+		if dataCtx.Requirements.FlowParams.Catalog != "" && !cataloged {
 			// mark the bucket as persistent and register the asset
 			provisioned, found := applicationContext.Application.Status.ProvisionedStorage[assetID]
 			if !found {
@@ -246,7 +419,7 @@ func (r *FybrikApplicationReconciler) checkReadiness(applicationContext Applicat
 			applicationContext.Application.Status.ProvisionedStorage[assetID] = provisioned
 			// register the asset
 			if newAssetID, err := r.RegisterAsset(assetID, dataCtx.Requirements.FlowParams.Catalog,
-				&provisioned, applicationContext.Application); err == nil {
+				&provisioned, applicationContext); err == nil {
 				state := applicationContext.Application.Status.AssetStates[assetID]
 				state.CatalogedAsset = newAssetID
 				applicationContext.Application.Status.AssetStates[assetID] = state
@@ -260,31 +433,60 @@ func (r *FybrikApplicationReconciler) checkReadiness(applicationContext Applicat
 	}
 }
 
+// recordDenyEvents emits a warning event for every asset that has transitioned into the denied state
+func (r *FybrikApplicationReconciler) recordDenyEvents(application *fappv1.FybrikApplication, observedStatus *fappv1.FybrikApplicationStatus) {
+	if r.Recorder == nil {
+		return
+	}
+	for _, asset := range application.Spec.Data {
+		state := application.Status.AssetStates[asset.DataSetID]
+		if len(state.Conditions) == 0 || state.Conditions[DenyConditionIndex].Status != v1.ConditionTrue {
+			continue
+		}
+		if observed, found := observedStatus.AssetStates[asset.DataSetID]; found && len(observed.Conditions) > 0 &&
+			observed.Conditions[DenyConditionIndex].Status == v1.ConditionTrue {
+			// the asset has been already denied
+			continue
+		}
+		r.Recorder.Eventf(application, v1.EventTypeWarning, PolicyDeniedReason,
+			"Access to dataset %s has been denied: %s (policy decisions: %s)", asset.DataSetID,
+			state.Conditions[DenyConditionIndex].Message, strings.Join(state.DecisionIDs, ","))
+	}
+}
+
 func (r *FybrikApplicationReconciler) getFinalizerName() string {
 	return r.Name + ".finalizer"
 }
 
 // removeFinalizers removes finalizers for FybrikApplication
-func (r *FybrikApplicationReconciler) removeFinalizers(ctx context.Context, applicationContext ApplicationContext) error {
+// The finalizer is removed only after the generated resources are gone, otherwise a new reconcile is requested.
+func (r *FybrikApplicationReconciler) removeFinalizers(ctx context.Context, applicationContext ApplicationContext) (ctrl.Result, error) {
 	// finalizer
 	finalizerName := r.getFinalizerName()
 	original := applicationContext.Application.DeepCopy()
 	initStatus(applicationContext.Application)
 	applicationContext.Application.Status.ObservedGeneration = applicationContext.Application.GetGeneration()
-	if err := r.deleteExternalResources(applicationContext); err != nil {
-		return err
+	deleted, err := r.deleteExternalResources(applicationContext)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 	if err := utils.UpdateStatus(ctx, r.Client, applicationContext.Application, &original.Status); err != nil {
-		return err
+		return ctrl.Result{}, err
+	}
+	if !deleted {
+		// the plotter removes the blueprints before it is deleted
+		applicationContext.Log.Debug().Str(logging.ACTION, logging.DELETE).Msg("Waiting for the generated resources to be deleted")
+		interval, _ := environment.GetResourcesPollingInterval()
+		return ctrl.Result{RequeueAfter: interval}, nil
 	}
 	if ctrlutil.ContainsFinalizer(applicationContext.Application, finalizerName) {
 		// remove the finalizer from the list and update it, because it needs to be deleted together with the object
 		ctrlutil.RemoveFinalizer(applicationContext.Application, finalizerName)
 		if err := r.Patch(ctx, applicationContext.Application, client.MergeFrom(original)); err != nil {
-			return err
+			return ctrl.Result{}, err
 		}
 	}
-	return nil
+	return ctrl.Result{}, nil
 }
 
 // addFinalizers adds finalizers for FybrikApplication
@@ -298,11 +500,15 @@ func (r *FybrikApplicationReconciler) addFinalizers(ctx context.Context, applica
 		if err := r.Patch(ctx, applicationContext.Application, client.MergeFrom(original)); err != nil {
 			return err
 		}
+		// the patched object holds the stored status, keep the status computed by the current reconcile
+		applicationContext.Application.Status = original.Status
 	}
 	return nil
 }
 
-func (r *FybrikApplicationReconciler) deleteExternalResources(applicationContext ApplicationContext) error {
+// deleteExternalResources deletes the provisioned storage and the generated resource.
+// It returns true if the generated resource no longer exists.
+func (r *FybrikApplicationReconciler) deleteExternalResources(applicationContext ApplicationContext) (bool, error) {
 	// clear provisioned storage
 	// References to buckets (Dataset resources) are deleted. Buckets that are persistent will not be removed upon Dataset deletion.
 	var deletedKeys []string
@@ -322,25 +528,86 @@ func (r *FybrikApplicationReconciler) deleteExternalResources(applicationContext
 		delete(applicationContext.Application.Status.ProvisionedStorage, datasetID)
 	}
 	if len(errMsgs) != 0 {
-		return errors.New(strings.Join(errMsgs, Separator))
+		return false, errors.New(strings.Join(errMsgs, Separator))
 	}
 	// delete the generated resource
-	if applicationContext.Application.Status.Generated == nil {
-		return nil
+	generated := applicationContext.Application.Status.Generated
+	if generated == nil {
+		return true, nil
 	}
 
 	applicationContext.Log.Trace().Str(logging.ACTION, logging.DELETE).
-		Msgf("Reconcile: FybrikApplication is deleting the generated %s", applicationContext.Application.Status.Generated.Kind)
-	if err := r.ResourceInterface.DeleteResource(applicationContext.Application.Status.Generated); err != nil {
-		return err
+		Msgf("Reconcile: FybrikApplication is deleting the generated %s", generated.Kind)
+	if err := r.ResourceInterface.DeleteResource(generated); err != nil {
+		return false, err
+	}
+	if r.ResourceInterface.ResourceExists(generated) {
+		return false, nil
 	}
 	applicationContext.Application.Status.Generated = nil
+	return true, nil
+}
+
+// revokeAccess deletes the generated resource if an asset that has been granted access by the previous reconcile
+// is denied now, e.g., since a policy has changed, and the resource is not regenerated without the asset.
+// Deleting the resource tears down the data-plane endpoints, so that the revocation is enforced.
+// If the policies fail closed, the resource is deleted as well if the policy decisions of such an asset can not be evaluated,
+// and it is generated again once they are evaluated.
+func (r *FybrikApplicationReconciler) revokeAccess(applicationContext ApplicationContext,
+	previousStates map[string]fappv1.AssetState) error {
+	application := applicationContext.Application
+	generated := application.Status.Generated
+	if generated == nil {
+		return nil
+	}
+	var revoked, unevaluated []string
+	for _, asset := range application.Spec.Data {
+		previous, found := previousStates[asset.DataSetID]
+		if !found || len(previous.Conditions) == 0 || previous.Conditions[DenyConditionIndex].Status == v1.ConditionTrue {
+			continue
+		}
+		switch {
+		case application.Status.AssetStates[asset.DataSetID].Conditions[DenyConditionIndex].Status == v1.ConditionTrue:
+			revoked = append(revoked, asset.DataSetID)
+		case applicationContext.FailClosed && applicationContext.Unevaluated[asset.DataSetID]:
+			unevaluated = append(unevaluated, asset.DataSetID)
+		}
+	}
+	var reasons []string
+	if len(revoked) > 0 {
+		reasons = append(reasons, "access to "+strings.Join(revoked, ",")+" has been revoked")
+	}
+	if len(unevaluated) > 0 {
+		reasons = append(reasons, "policy decisions of "+strings.Join(unevaluated, ",")+" can not be evaluated")
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return r.deleteGenerated(applicationContext, strings.Join(reasons, Separator))
+}
+
+// deleteGenerated deletes the resource generated for the application, and records the revocation of the access
+// for the given reason
+func (r *FybrikApplicationReconciler) deleteGenerated(applicationContext ApplicationContext, reason string) error {
+	application := applicationContext.Application
+	generated := application.Status.Generated
+	if generated == nil {
+		return nil
+	}
+	applicationContext.Log.Warn().Bool(logging.FORUSER, true).Bool(logging.AUDIT, true).Str(logging.ACTION, logging.DELETE).
+		Msgf("Deleting the generated %s: %s", generated.Kind, reason)
+	if err := r.ResourceInterface.DeleteResource(generated); err != nil {
+		return err
+	}
+	application.Status.Generated = nil
+	recordTransition(application, fappv1.AccessRevokedTransition, reason)
 	return nil
 }
 
 // setVirtualEndpoints populates the endpoints in the status of the fybrikapplication
-func setVirtualEndpoints(application *fappv1.FybrikApplication, flows []fappv1.Flow) {
-	endpointMap := make(map[string]taxonomy.Connection)
+func setVirtualEndpoints(application *fappv1.FybrikApplication, flows []fappv1.Flow, modulesNamespace string) {
+	apis := make(map[string]datacatalog.ResourceDetails)
+	consumerAPIs := make(map[string]map[string]datacatalog.ResourceDetails)
 	for _, flow := range flows {
 		// sanity check
 		if len(flow.SubFlows) == 0 {
@@ -350,19 +617,157 @@ func setVirtualEndpoints(application *fappv1.FybrikApplication, flows []fappv1.F
 		for _, sequentialSteps := range subflow.Steps {
 			// Check the last step in the sequential flow (this will expose the api)
 			lastStep := sequentialSteps[len(sequentialSteps)-1]
-			if lastStep.Parameters.API != nil {
-				endpointMap[flow.AssetID] = lastStep.Parameters.API.Connection
+			if lastStep.Parameters.API == nil {
+				continue
 			}
+			if flow.Consumer == "" {
+				apis[flow.AssetID] = *lastStep.Parameters.API
+				continue
+			}
+			if consumerAPIs[flow.AssetID] == nil {
+				consumerAPIs[flow.AssetID] = make(map[string]datacatalog.ResourceDetails)
+			}
+			consumerAPIs[flow.AssetID][flow.Consumer] = *lastStep.Parameters.API
 		}
 	}
 	// populate endpoints in application status
 	for _, asset := range application.Spec.Data {
 		state := application.Status.AssetStates[asset.DataSetID]
-		state.Endpoint = endpointMap[asset.DataSetID]
+		api := apis[asset.DataSetID]
+		state.ConsumerEndpoints = nil
+		for consumer, consumerAPI := range consumerAPIs[asset.DataSetID] {
+			if state.ConsumerEndpoints == nil {
+				state.ConsumerEndpoints = make(map[string]taxonomy.Connection)
+			}
+			state.ConsumerEndpoints[consumer] = consumerAPI.Connection
+		}
+		if len(asset.Requirements.Consumers) > 0 {
+			api = consumerAPIs[asset.DataSetID][asset.Requirements.Consumers[0].Name]
+		}
+		state.Endpoint = api.Connection
+		state.Endpoints = endpointsByProtocol(state.Endpoint)
+		state.Services = servicesByProtocol(state.Endpoints, modulesNamespace)
+		state.Formats = formatsByProtocol(&api, state.Endpoints)
 		application.Status.AssetStates[asset.DataSetID] = state
 	}
 }
 
+// formatsByProtocol lists the data formats advertised by the module for every endpoint.
+// The format of the protocol named by the connection is the data format of the module API,
+// while the other protocols may advertise a format or a list of formats by the "dataformat" key of their details.
+func formatsByProtocol(api *datacatalog.ResourceDetails,
+	endpoints map[taxonomy.ConnectionType]taxonomy.Connection) map[taxonomy.ConnectionType][]taxonomy.DataFormat {
+	var formats map[taxonomy.ConnectionType][]taxonomy.DataFormat
+	add := func(protocol taxonomy.ConnectionType, format taxonomy.DataFormat) {
+		if format == "" {
+			return
+		}
+		if formats == nil {
+			formats = make(map[taxonomy.ConnectionType][]taxonomy.DataFormat)
+		}
+		for _, known := range formats[protocol] {
+			if known == format {
+				return
+			}
+		}
+		formats[protocol] = append(formats[protocol], format)
+	}
+	if api.Connection.Name != "" {
+		add(api.Connection.Name, api.DataFormat)
+	}
+	for protocol, endpoint := range endpoints {
+		details, _ := endpoint.AdditionalProperties.Items[string(protocol)].(map[string]interface{})
+		switch advertised := details[endpointDataFormatKey].(type) {
+		case string:
+			add(protocol, taxonomy.DataFormat(advertised))
+		case []interface{}:
+			for _, format := range advertised {
+				if s, ok := format.(string); ok {
+					add(protocol, taxonomy.DataFormat(s))
+				}
+			}
+		}
+	}
+	return formats
+}
+
+// endpointsByProtocol splits the connection exposed by a module into a connection per protocol.
+// The module may define the details of more protocols besides the one named by the connection,
+// e.g., an S3-compatible proxy alongside the arrow flight service.
+func endpointsByProtocol(exposed taxonomy.Connection) map[taxonomy.ConnectionType]taxonomy.Connection {
+	var endpoints map[taxonomy.ConnectionType]taxonomy.Connection
+	for protocol, details := range exposed.AdditionalProperties.Items {
+		if _, ok := details.(map[string]interface{}); !ok {
+			continue
+		}
+		if endpoints == nil {
+			endpoints = make(map[taxonomy.ConnectionType]taxonomy.Connection)
+		}
+		endpoints[taxonomy.ConnectionType(protocol)] = taxonomy.Connection{
+			Name: taxonomy.ConnectionType(protocol),
+			AdditionalProperties: serde.Properties{
+				Items: map[string]interface{}{protocol: details},
+			},
+		}
+	}
+	return endpoints
+}
+
+// servicesByProtocol identifies the services in the modules namespace that serve the endpoints.
+// The hostname of such an endpoint is the DNS name of the service, i.e., <service>.<namespace>[.svc[.cluster.local]].
+// The endpoints of the modules share the hostname and port properties of the arrow-flight connections.
+func servicesByProtocol(endpoints map[taxonomy.ConnectionType]taxonomy.Connection,
+	modulesNamespace string) map[taxonomy.ConnectionType]fappv1.ServiceEndpoint {
+	var services map[taxonomy.ConnectionType]fappv1.ServiceEndpoint
+	for protocol, endpoint := range endpoints {
+		details, _ := endpoint.AdditionalProperties.Items[string(protocol)].(map[string]interface{})
+		server, err := connection.DecodeArrowFlight(details)
+		if err != nil {
+			continue
+		}
+		hostname := server.Hostname
+		for _, suffix := range []string{".svc.cluster.local", ".svc"} {
+			hostname = strings.TrimSuffix(hostname, suffix)
+		}
+		name := strings.TrimSuffix(hostname, "."+modulesNamespace)
+		if name == hostname || name == "" || strings.Contains(name, ".") {
+			continue
+		}
+		service := fappv1.ServiceEndpoint{Name: name, Namespace: modulesNamespace, Port: server.Port}
+		if services == nil {
+			services = make(map[taxonomy.ConnectionType]fappv1.ServiceEndpoint)
+		}
+		services[protocol] = service
+	}
+	return services
+}
+
+// consumerDataInfos returns the requirements of a data path for every consumer of the dataset,
+// or of a single data path with the interface of the data context if the dataset has no consumers.
+// The workload cluster of a consumer running in a specific cluster holds the cluster name only.
+func consumerDataInfos(dataset *fappv1.DataContext) []datapath.DataInfo {
+	newDataInfo := func() datapath.DataInfo {
+		return datapath.DataInfo{
+			Context:             dataset.DeepCopy(),
+			DataDetails:         &datacatalog.GetAssetResponse{},
+			StorageRequirements: make(map[taxonomy.ProcessingLocation][]taxonomy.Action),
+		}
+	}
+	if len(dataset.Requirements.Consumers) == 0 {
+		return []datapath.DataInfo{newDataInfo()}
+	}
+	infos := []datapath.DataInfo{}
+	for _, consumer := range dataset.Requirements.Consumers {
+		info := newDataInfo()
+		info.Consumer = consumer.Name
+		info.Context.Requirements.Interface = consumer.Interface.DeepCopy()
+		info.Context.Requirements.Consumers = nil
+		info.WorkloadCluster.Name = consumer.ClusterName
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 // reconcile receives either FybrikApplication CRD
 // or a status update from the generated resource
 func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationContext) (ctrl.Result, error) {
@@ -371,11 +776,25 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 
 	// Data User created or updated the FybrikApplication
 
+	// the asset states of the previous reconcile are kept to detect revoked access
+	previousStates := applicationContext.Application.Status.AssetStates
 	// clear status
 	initStatus(applicationContext.Application)
+	// the assets that have been registered in the catalog are not registered again
+	for assetID, state := range applicationContext.Application.Status.AssetStates {
+		state.CatalogedAsset = previousStates[assetID].CatalogedAsset
+		applicationContext.Application.Status.AssetStates[assetID] = state
+	}
 	if applicationContext.Application.Status.ProvisionedStorage == nil {
 		applicationContext.Application.Status.ProvisionedStorage = make(map[string]fappv1.DatasetDetails)
 	}
+	if allowed, err := r.checkModulesNamespace(context.Background(), applicationContext); !allowed || err != nil {
+		return ctrl.Result{}, err
+	}
+	if !r.selectPolicyManager(&applicationContext) {
+		// no policy manager evaluates the governance actions, the access granted by a previous evaluation is revoked
+		return ctrl.Result{}, r.deleteGenerated(applicationContext, applicationContext.Application.Status.ErrorMessage)
+	}
 
 	// create a list of requirements for creating a data flow (actions, interface to app, data format) per a single data set
 	env, err := r.Environment()
@@ -390,16 +809,39 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 			Str(logging.ACTION, logging.CREATE).Msg("Could not determine in which cluster the workload runs")
 		return ctrl.Result{}, err
 	}
+	// the asset metadata is retrieved for all data paths first, so that their governance actions can be requested at once
+	var assets []assetDataInfo
+	for i := range applicationContext.Application.Spec.Data {
+		// a data path is constructed for every consumer of the dataset
+		for _, req := range consumerDataInfos(&applicationContext.Application.Spec.Data[i]) {
+			// impossible interface requirements are rejected before querying the connectors
+			if err := validateRequiredInterface(env, req.Context); err != nil {
+				AnalyzeError(applicationContext, req.Context.DataSetID, err)
+				continue
+			}
+			cluster := workloadCluster
+			if req.WorkloadCluster.Name != "" {
+				if cluster, err = findCluster(req.WorkloadCluster.Name, env); err != nil {
+					AnalyzeError(applicationContext, req.Context.DataSetID, err)
+					continue
+				}
+			}
+			catalogMsg, err := r.getAssetInfo(&req, applicationContext)
+			if err != nil {
+				AnalyzeError(applicationContext, req.Context.DataSetID, err)
+				continue
+			}
+			assets = append(assets, assetDataInfo{info: req, cluster: cluster, catalogMsg: catalogMsg})
+		}
+	}
+	r.prefetchGovernanceActions(applicationContext, assets)
 	var requirements []datapath.DataInfo
 	// messages from the connectors
 	messages := map[string]string{}
-	for _, dataset := range applicationContext.Application.Spec.Data {
-		req := datapath.DataInfo{
-			Context:             dataset.DeepCopy(),
-			DataDetails:         &datacatalog.GetAssetResponse{},
-			StorageRequirements: make(map[taxonomy.ProcessingLocation][]taxonomy.Action),
-		}
-		if messages[req.Context.DataSetID], err = r.constructDataInfo(&req, applicationContext, workloadCluster, env); err != nil {
+	for i := range assets {
+		req := assets[i].info
+		if messages[req.Context.DataSetID], err = r.constructDataInfo(&req, applicationContext, assets[i].cluster, env,
+			assets[i].catalogMsg); err != nil {
 			AnalyzeError(applicationContext, req.Context.DataSetID, err)
 			continue
 		}
@@ -407,30 +849,66 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 	}
 	// check if can proceed
 	if len(requirements) == 0 {
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.revokeAccess(applicationContext, previousStates)
 	}
+	recordTransition(applicationContext.Application, fappv1.PolicyEvaluatedTransition,
+		fmt.Sprintf("governance actions of %d data paths have been evaluated", len(requirements)))
+	warnUnavailableColumns(applicationContext, requirements)
+	schedulePolicyReevaluation(applicationContext, requirements)
 
 	provisionedStorage, plotterSpec, err := r.buildSolution(applicationContext, env, requirements)
 	if err != nil {
 		applicationContext.Log.Error().Err(err).Bool(logging.FORUSER, true).Bool(logging.AUDIT, true).Msg("Plotter construction failed")
 	}
+	if errors.Is(err, errUnsupportedTransformation) {
+		// reported in the asset conditions, another attempt fails in the same way
+		return ctrl.Result{}, r.revokeAccess(applicationContext, previousStates)
+	}
 	// check if can proceed
 	if err != nil || getErrorMessages(applicationContext.Application) != "" {
+		if revokeErr := r.revokeAccess(applicationContext, previousStates); revokeErr != nil {
+			return ctrl.Result{}, revokeErr
+		}
 		return ctrl.Result{}, err
 	}
+	recordTransition(applicationContext.Application, fappv1.ModuleSelectedTransition,
+		fmt.Sprintf("%d module capabilities have been selected", len(plotterSpec.Templates)))
 	// clean irrelevant buckets and update the application status with the provisioned storage
 	if err := r.updateProvisionedStorageStatus(applicationContext, provisionedStorage); err != nil {
 		return ctrl.Result{}, err
 	}
-	setVirtualEndpoints(applicationContext.Application, plotterSpec.Flows)
+	setVirtualEndpoints(applicationContext.Application, plotterSpec.Flows, plotterSpec.ModulesNamespace)
+	if applicationContext.Application.Spec.DryRun {
+		return ctrl.Result{}, r.storeDryRunResult(applicationContext, plotterSpec)
+	}
+	if err := r.deleteDryRunResult(applicationContext); err != nil {
+		return ctrl.Result{}, err
+	}
+	if applicationContext.Application.Spec.RequireApproval {
+		digest, err := plotterDigest(plotterSpec)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !isPlotterApproved(applicationContext.Application, digest) {
+			return ctrl.Result{}, r.stagePlotter(applicationContext, plotterSpec, digest)
+		}
+	}
+	if err := r.deleteStagedPlotter(applicationContext); err != nil {
+		return ctrl.Result{}, err
+	}
 	ownerRef := &fappv1.ResourceReference{
 		Name:       applicationContext.Application.Name,
 		Namespace:  applicationContext.Application.Namespace,
 		AppVersion: applicationContext.Application.GetGeneration()}
 
+	decisions, err := policyDecisionsAnnotation(applicationContext.Application)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	annotations := map[string]string{utils.PolicyDecisionsAnnotation: decisions}
 	resourceRef := r.ResourceInterface.CreateResourceReference(ownerRef)
 	if err := r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, plotterSpec,
-		applicationContext.Application.Labels, applicationContext.UUID); err != nil {
+		generatedResourceLabels(applicationContext), annotations, applicationContext.UUID); err != nil {
 		applicationContext.Log.Error().Err(err).Str(logging.ACTION, logging.CREATE).Msgf("Error creating %s", resourceRef.Kind)
 		if err.Error() == InvalidClusterConfiguration {
 			applicationContext.Application.Status.ErrorMessage = err.Error()
@@ -439,6 +917,7 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 		return ctrl.Result{}, err
 	}
 	applicationContext.Application.Status.Generated = resourceRef
+	recordTransition(applicationContext.Application, fappv1.PlotterCreatedTransition, resourceRef.Namespace+"/"+resourceRef.Name)
 	applicationContext.Log.Trace().Str(logging.ACTION, logging.CREATE).Msgf("Created %s successfully!", resourceRef.Kind)
 	// propagating connector messages to the status
 	for key, val := range messages {
@@ -469,10 +948,11 @@ func (r *FybrikApplicationReconciler) Environment() (*datapath.Environment, erro
 		return nil, err
 	}
 	return &datapath.Environment{
-		Modules:          moduleMap,
-		Clusters:         clusters,
-		StorageAccounts:  accounts,
-		AttributeManager: r.Infrastructure,
+		Modules:           moduleMap,
+		Clusters:          clusters,
+		StorageAccounts:   accounts,
+		AttributeManager:  r.Infrastructure,
+		ModulePreferences: r.ModulePreferences,
 	}, nil
 }
 
@@ -503,7 +983,6 @@ func (r *FybrikApplicationReconciler) ValidateAssetResponse(response interface{}
 	if err != nil {
 		return err
 	}
-	r.Log.Info().Msg("responseJSON:" + string(responseJSON))
 
 	// Validate Fybrik module against taxonomy
 	allErrs, err = validate.TaxonomyCheck(responseJSON, taxonomyFile)
@@ -521,23 +1000,21 @@ func (r *FybrikApplicationReconciler) ValidateAssetResponse(response interface{}
 		datasetID, allErrs)
 }
 
-// constructDataInfo collects the following information about the asset:
-// - asset metadata
-// - governance actions to be performed on the data
-// - potential governance actions in case of caching the asset in a specific location
-// - decisions after evaluating config policies
-// The function returns an error received in the process of communication with connectors or evaluating policies
-// It also returns messages from data catalog and/or policy manager
-// to be propagated to the application status (relevant for the ready state of the asset)
-func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo, appContext ApplicationContext,
-	workloadCluster multicluster.Cluster, env *datapath.Environment) (string, error) {
+// assetDataInfo is a data path whose asset metadata has been retrieved
+type assetDataInfo struct {
+	info       datapath.DataInfo
+	cluster    multicluster.Cluster
+	catalogMsg string
+}
+
+// getAssetInfo retrieves the asset metadata from the data catalog, or from the application for a new asset.
+// The function returns the message of the data catalog, or an error received from the data catalog.
+func (r *FybrikApplicationReconciler) getAssetInfo(req *datapath.DataInfo, appContext ApplicationContext) (string, error) {
 	// Call the DataCatalog service to get info about the dataset
 	input := appContext.Application
 	log := appContext.Log.With().Str(logging.DATASETID, req.Context.DataSetID).Logger()
 	var err error
-	// retrieve and propagate messages from catalog and policy manager
-	// if there are no errors to construct the data plane
-	var catalogMsg, governanceMsg string
+	var catalogMsg string
 	if !req.Context.Requirements.FlowParams.IsNewDataSet {
 		var credentialPath string
 		if input.Spec.SecretRef != "" {
@@ -549,13 +1026,54 @@ func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo,
 		var response *datacatalog.GetAssetResponse
 		request := datacatalog.GetAssetRequest{
 			AssetID:       taxonomy.AssetID(req.Context.DataSetID),
-			OperationType: datacatalog.READ}
+			OperationType: datacatalog.READ,
+			Version:       req.Context.Version}
 
-		if response, err = r.DataCatalog.GetAssetInfo(&request, credentialPath); err != nil {
+		_, span := tracing.Tracer().Start(appContext.traceContext(), "DataCatalog.GetAssetInfo",
+			trace.WithAttributes(tracing.DatasetIDKey.String(req.Context.DataSetID)))
+		response, err = dcclient.WithCorrelationID(r.DataCatalog, appContext.CorrelationID).GetAssetInfo(&request, credentialPath)
+		tracing.End(span, tracing.OutcomeSuccess, err)
+		if err != nil {
 			log.Error().Err(err).Msg("failed to receive the catalog connector response")
+			if cached, found := r.degradedAssetInfo(&request, credentialPath, err); found {
+				log.Warn().Str("policy", string(r.DegradedMode)).Msg("using the cached asset metadata in the degraded mode")
+				appContext.addDegraded(req.Context.DataSetID)
+				cached.DeepCopyInto(req.DataDetails)
+				return degradedModeMessage(r.DegradedMode), nil
+			}
 			// return the error from the data catalog
 			return "", err
 		}
+		if response.Credentials != "" && !vault.IsVaultPath(response.Credentials) {
+			// the credentials are passed to the modules by reference only, and may be secrets themselves
+			log.Warn().Msg("the credentials returned by the catalog connector are not a Vault path and are ignored")
+			response.Credentials = ""
+		}
+		// without a connection the modules can not access the asset, and the Plotter can not be generated
+		if response.Details.Connection.Name == "" {
+			log.Error().Msg("the catalog connector response has no connection information")
+			return "", errors.New(AssetConnectionMissing)
+		}
+		if err = normalizeS3Connection(&response.Details.Connection); err != nil {
+			log.Error().Err(err).Msg("failed to validate the S3 connection of the asset")
+			return "", err
+		}
+		for i := range response.AlternativeDetails {
+			if response.AlternativeDetails[i].Connection.Name == "" {
+				log.Error().Msg("an alternative connection of the asset has no connection information")
+				return "", errors.New(AssetConnectionMissing)
+			}
+			if err = normalizeS3Connection(&response.AlternativeDetails[i].Connection); err != nil {
+				log.Error().Err(err).Msg("failed to validate the alternative S3 connection of the asset")
+				return "", err
+			}
+		}
+		// the schema and connection of a pinned version are used, thus the catalog must resolve the pinned version
+		if request.Version != "" && response.ResourceMetadata.Version != request.Version {
+			log.Error().Str("version", request.Version).Msgf("the catalog connector returned version %q",
+				response.ResourceMetadata.Version)
+			return "", errors.New(AssetVersionMismatch)
+		}
 
 		err = r.ValidateAssetResponse(response, DataCatalogGetAssetResponseTaxonomy, req.Context.DataSetID)
 		if err != nil {
@@ -564,26 +1082,78 @@ func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo,
 			return "", err
 		}
 		logging.LogStructure("Catalog connector response", response, &log, zerolog.DebugLevel, false, false)
+		r.AssetInfo.add(&request, credentialPath, response)
 		catalogMsg = response.Message
 		response.DeepCopyInto(req.DataDetails)
 	} else if req.Context.Requirements.FlowParams.ResourceMetadata != nil {
 		// Fill req.DataDetails with the metadata from the fybrikapplication
 		req.DataDetails.ResourceMetadata = *req.Context.Requirements.FlowParams.ResourceMetadata
 	}
+	return catalogMsg, nil
+}
+
+// degradedAssetInfo returns the cached metadata of an asset if the degraded mode is enabled
+// and the data catalog could not be reached
+func (r *FybrikApplicationReconciler) degradedAssetInfo(request *datacatalog.GetAssetRequest, credentialPath string,
+	err error) (*datacatalog.GetAssetResponse, bool) {
+	if r.DegradedMode == DegradedModeDisabled || !isCatalogUnavailable(err) {
+		return nil, false
+	}
+	return r.AssetInfo.get(request, credentialPath)
+}
+
+// prefetchGovernanceActions requests the governance actions of all the assets at once, if the policy manager supports it
+func (r *FybrikApplicationReconciler) prefetchGovernanceActions(appContext ApplicationContext, assets []assetDataInfo) {
+	lookups := []policyLookup{}
+	for i := range assets {
+		req := &assets[i].info
+		usage := CreateDataRequest(appContext.Application, req.Context, &req.DataDetails.ResourceMetadata).Usage
+		if op := governanceRequestAction(usage, req, assets[i].cluster); op != nil {
+			lookups = append(lookups, policyLookup{
+				datasetID: req.Context.DataSetID,
+				metadata:  &req.DataDetails.ResourceMetadata,
+				operation: op,
+			})
+		}
+	}
+	prefetchPolicyDecisions(lookups, appContext.PolicyManager, appContext)
+}
+
+// constructDataInfo collects the following information about the asset, whose metadata has been retrieved by getAssetInfo:
+// - governance actions to be performed on the data
+// - potential governance actions in case of caching the asset in a specific location
+// - decisions after evaluating config policies
+// The function returns an error received in the process of communication with connectors or evaluating policies
+// It also returns messages from data catalog and/or policy manager
+// to be propagated to the application status (relevant for the ready state of the asset)
+func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo, appContext ApplicationContext,
+	workloadCluster multicluster.Cluster, env *datapath.Environment, catalogMsg string) (string, error) {
+	input := appContext.Application
 	configEvaluatorInput := &adminconfig.EvaluatorInput{}
 	configEvaluatorInput.Workload.UUID = utils.GetFybrikApplicationUUID(input)
 	input.Spec.AppInfo.DeepCopyInto(&configEvaluatorInput.Workload.Properties)
 	configEvaluatorInput.Workload.Cluster = workloadCluster
 	configEvaluatorInput.Request = CreateDataRequest(input, req.Context, &req.DataDetails.ResourceMetadata)
 
+	if appContext.Degraded[req.Context.DataSetID] && r.DegradedMode == DegradedModeDeny {
+		message := ReadAccessDenied
+		if configEvaluatorInput.Request.Usage == taxonomy.WriteFlow {
+			message = WriteNotAllowed
+		}
+		return "", &PolicyDeniedError{Message: message, Reason: CatalogUnavailableReason}
+	}
 	// Governance actions
-	governanceMsg, err = r.checkGovernanceActions(configEvaluatorInput, req, appContext, env)
+	governanceMsg, err := r.checkGovernanceActions(configEvaluatorInput, req, appContext, env)
 	if err != nil {
 		// return the error received from the policy manager, or generated by Fybrik in case of Deny
 		// the error is extended with an additional message from the policy manager
 		// which may help to understand the reason for the denial
 		return "", err
 	}
+	if appContext.Degraded[req.Context.DataSetID] && r.DegradedMode == DegradedModeRedactPII {
+		redactPIIColumns(req)
+	}
+	mergeRedactActions(req)
 	configDecisions, err := r.ConfigEvaluator.Evaluate(configEvaluatorInput)
 	if err != nil {
 		appContext.Log.Error().Err(err).Msg("Error evaluating config policies")
@@ -598,6 +1168,80 @@ func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo,
 	return msg, nil
 }
 
+// redactPIIColumns adds the redaction of the columns tagged as PII to the governance actions of the asset,
+// including the actions of the potential copies
+func redactPIIColumns(req *datapath.DataInfo) {
+	columns := piiColumns(&req.DataDetails.ResourceMetadata)
+	if len(columns) == 0 {
+		return
+	}
+	req.Actions = append(req.Actions, taxonomy.NewRedactAction(columns...))
+	for geo, actions := range req.StorageRequirements {
+		req.StorageRequirements[geo] = append(actions, taxonomy.NewRedactAction(columns...))
+	}
+}
+
+// mergeRedactActions consolidates the governance actions of the asset, including the actions of the potential copies,
+// so that the redactions returned by different policies are applied by a single transformation
+func mergeRedactActions(req *datapath.DataInfo) {
+	req.Actions = unitedRedactActions(req.Actions)
+	for geo, actions := range req.StorageRequirements {
+		req.StorageRequirements[geo] = unitedRedactActions(actions)
+	}
+}
+
+// unitedRedactActions merges the redactions that differ in their columns only into a single redaction of the union of
+// their columns, in the position of the first one. Redactions with different strategies or masks are kept apart,
+// and the other actions are left unchanged.
+func unitedRedactActions(actions []taxonomy.Action) []taxonomy.Action {
+	if actions == nil {
+		return nil
+	}
+	united := make([]taxonomy.Action, 0, len(actions))
+	for i := range actions {
+		merged := false
+		if actions[i].Name == taxonomy.RedactActionName {
+			for j := range united {
+				if action, ok := taxonomy.UniteColumns(&united[j], &actions[i]); ok {
+					united[j] = action
+					merged = true
+					break
+				}
+			}
+		}
+		if !merged {
+			united = append(united, actions[i])
+		}
+	}
+	return united
+}
+
+// governanceRequestAction returns the operation of the workload on the asset that the policy manager is queried about,
+// or nil if no query is required, i.e., when writing a new asset
+func governanceRequestAction(usage taxonomy.DataFlow, req *datapath.DataInfo,
+	workloadCluster multicluster.Cluster) *policymanager.RequestAction {
+	region := workloadCluster.Metadata.Region
+	switch usage {
+	case taxonomy.WriteFlow:
+		if req.Context.Requirements.FlowParams.IsNewDataSet {
+			return nil
+		}
+		// update an existing dataset
+		return &policymanager.RequestAction{
+			ActionType:         usage,
+			Destination:        req.DataDetails.ResourceMetadata.Geography,
+			ProcessingLocation: taxonomy.ProcessingLocation(region),
+		}
+	case taxonomy.ReadFlow, taxonomy.DeleteFlow:
+		return &policymanager.RequestAction{
+			ActionType:         usage,
+			Destination:        region,
+			ProcessingLocation: taxonomy.ProcessingLocation(region),
+		}
+	}
+	return nil
+}
+
 // checkGovernanceActions consults the policy manager to retrieve:
 // - the governance actions to be performed on the asset
 // - the potential governance actions to be performed in case of caching to a specific location
@@ -608,27 +1252,9 @@ func (r *FybrikApplicationReconciler) checkGovernanceActions(configEvaluatorInpu
 	req *datapath.DataInfo, appContext ApplicationContext, env *datapath.Environment) (string, error) {
 	var err error
 	var msg string
-	switch configEvaluatorInput.Request.Usage {
-	case taxonomy.WriteFlow:
-		if !req.Context.Requirements.FlowParams.IsNewDataSet {
-			// update an existing dataset
-			// query the policy manager whether the operation is allowed
-			reqAction := policymanager.RequestAction{
-				ActionType:         configEvaluatorInput.Request.Usage,
-				Destination:        req.DataDetails.ResourceMetadata.Geography,
-				ProcessingLocation: taxonomy.ProcessingLocation(configEvaluatorInput.Workload.Cluster.Metadata.Region),
-			}
-			req.Actions, msg, err = LookupPolicyDecisions(req.Context.DataSetID, &req.DataDetails.ResourceMetadata,
-				r.PolicyManager, appContext, &reqAction)
-		}
-	case taxonomy.ReadFlow, taxonomy.DeleteFlow:
-		reqAction := policymanager.RequestAction{
-			ActionType:         configEvaluatorInput.Request.Usage,
-			Destination:        configEvaluatorInput.Workload.Cluster.Metadata.Region,
-			ProcessingLocation: taxonomy.ProcessingLocation(configEvaluatorInput.Workload.Cluster.Metadata.Region),
-		}
+	if reqAction := governanceRequestAction(configEvaluatorInput.Request.Usage, req, configEvaluatorInput.Workload.Cluster); reqAction != nil {
 		req.Actions, msg, err = LookupPolicyDecisions(req.Context.DataSetID, &req.DataDetails.ResourceMetadata,
-			r.PolicyManager, appContext, &reqAction)
+			appContext.PolicyManager, appContext, reqAction)
 	}
 	if err != nil {
 		return "", err
@@ -647,6 +1273,10 @@ func (r *FybrikApplicationReconciler) checkGovernanceActions(configEvaluatorInpu
 		// Use the existing resource metadata if the asset is not new
 		resMetadata = &req.DataDetails.ResourceMetadata
 	}
+	// a copy flow reads the source asset and writes it to the selected storage account,
+	// so reading the asset to the destination must be allowed as well
+	isCopy := configEvaluatorInput.Request.Usage == taxonomy.CopyFlow
+	var readDenied error
 	for accountInd := range env.StorageAccounts {
 		geo := env.StorageAccounts[accountInd].Spec.Geography
 		reqAction := policymanager.RequestAction{
@@ -656,22 +1286,42 @@ func (r *FybrikApplicationReconciler) checkGovernanceActions(configEvaluatorInpu
 		}
 		// get governance actions to consider only if a copy will be made to this destination
 		// messages from the policy manager are disregarded
-		actions, _, err := LookupPolicyDecisions(req.Context.DataSetID, resMetadata, r.PolicyManager, appContext, &reqAction)
-		if err == nil {
-			req.StorageRequirements[geo] = actions
-		} else if err.Error() != WriteNotAllowed {
-			// received an error from the connector
-			return "", err
+		actions, _, err := LookupPolicyDecisions(req.Context.DataSetID, resMetadata, appContext.PolicyManager, appContext, &reqAction)
+		if err != nil {
+			if err.Error() != WriteNotAllowed {
+				// received an error from the connector
+				return "", err
+			}
+			continue
+		}
+		if isCopy {
+			reqAction.ActionType = taxonomy.ReadFlow
+			readActions, _, err := LookupPolicyDecisions(req.Context.DataSetID, &req.DataDetails.ResourceMetadata,
+				appContext.PolicyManager, appContext, &reqAction)
+			if err != nil {
+				if err.Error() != ReadAccessDenied {
+					return "", err
+				}
+				readDenied = err
+				continue
+			}
+			// the actions of the source are enforced together with the actions of the destination
+			actions = append(readActions, actions...)
 		}
+		req.StorageRequirements[geo] = actions
 	}
 	accountRequired := (req.Context.Requirements.FlowParams.IsNewDataSet && configEvaluatorInput.Request.Usage == taxonomy.WriteFlow) ||
-		(configEvaluatorInput.Request.Usage == taxonomy.CopyFlow)
+		isCopy
 	// no account is defined, return an error for write and copy flows
 	if len(env.StorageAccounts) == 0 && accountRequired {
 		return "", errors.New(StorageAccountUndefined)
 	}
-	// write is denied to all accounts, return Deny for write and copy flows
 	if len(req.StorageRequirements) == 0 && accountRequired {
+		// reading the source is denied to all accounts the data can be written to
+		if readDenied != nil {
+			return "", readDenied
+		}
+		// write is denied to all accounts, return Deny for write and copy flows
 		return "", errors.New(WriteNotAllowed)
 	}
 	// no errors - return the message from the policy manager
@@ -694,6 +1344,11 @@ func (r *FybrikApplicationReconciler) GetWorkloadCluster(appContext ApplicationC
 		clusterName = environment.GetLocalClusterName()
 	}
 	// find the cluster by its name as it is specified in FybrikApplication workload selector
+	return findCluster(clusterName, env)
+}
+
+// findCluster returns the cluster of the given name
+func findCluster(clusterName string, env *datapath.Environment) (multicluster.Cluster, error) {
 	for _, cluster := range env.Clusters {
 		if cluster.Name == clusterName {
 			return cluster, nil
@@ -708,18 +1363,44 @@ func NewFybrikApplicationReconciler(mgr ctrl.Manager, name string,
 	storageManager storage.StorageManagerInterface, evaluator adminconfig.EvaluatorInterface,
 	attributeManager *infrastructure.AttributeManager) *FybrikApplicationReconciler {
 	log := logging.LogInit(logging.CONTROLLER, name)
+	degradedMode, err := degradedModePolicy()
+	if err != nil {
+		log.Error().Err(err).Msg("the degraded mode is disabled")
+	}
+	var assetInfo *AssetInfoCache
+	if degradedMode != DegradedModeDisabled {
+		assetInfo = NewAssetInfoCache(assetInfoCacheTTL())
+	}
+	modulePreferences, err := ModulePreferencesFromEnvironment()
+	if err != nil {
+		log.Error().Err(err).Msg("the module preferences are ignored")
+	}
+	actionPrecedence, err := ActionPrecedenceFromEnvironment()
+	if err != nil {
+		log.Error().Err(err).Msg("the default action precedence is used")
+		actionPrecedence = DefaultActionPrecedence
+	}
 	return &FybrikApplicationReconciler{
-		Client:            mgr.GetClient(),
-		Name:              name,
-		Log:               log,
-		Scheme:            mgr.GetScheme(),
-		PolicyManager:     policyManager,
-		ResourceInterface: NewPlotterInterface(mgr.GetClient()),
-		ClusterManager:    cm,
-		StorageManager:    storageManager,
-		DataCatalog:       catalog,
-		ConfigEvaluator:   evaluator,
-		Infrastructure:    attributeManager,
+		Client:                     mgr.GetClient(),
+		Name:                       name,
+		Log:                        log,
+		Scheme:                     mgr.GetScheme(),
+		PolicyManager:              policyManager,
+		ResourceInterface:          NewPlotterInterface(mgr.GetClient()),
+		ClusterManager:             cm,
+		StorageManager:             storageManager,
+		DataCatalog:                catalog,
+		ConfigEvaluator:            evaluator,
+		Infrastructure:             attributeManager,
+		Recorder:                   mgr.GetEventRecorderFor(name),
+		PolicyDecisions:            NewAsyncPolicyDecisionCache(policyDecisionsCacheTTL()),
+		DefaultDeny:                environment.IsDefaultDeny(),
+		FailClosed:                 environment.IsPolicyFailClosed(),
+		ActionPrecedence:           actionPrecedence,
+		AssetInfo:                  assetInfo,
+		DegradedMode:               degradedMode,
+		ModulesNamespaceAuthorizer: &AccessReviewAuthorizer{Client: mgr.GetClient()},
+		ModulePreferences:          modulePreferences,
 	}
 }
 
@@ -752,12 +1433,26 @@ func (r *FybrikApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{MaxConcurrentReconciles: numReconciles}).
-		For(&fappv1.FybrikApplication{}).
+		For(&fappv1.FybrikApplication{}, builder.WithPredicates(predicate.Funcs{UpdateFunc: applicationChanged})).
 		Watches(&source.Kind{
 			Type: &fappv1.Plotter{},
 		}, handler.EnqueueRequestsFromMapFunc(mapFn)).Complete(r)
 }
 
+// applicationChanged returns true if an update of a FybrikApplication requires a reconcile, i.e.,
+// if its spec, labels or annotations have been changed, or it is being deleted.
+// The status updates made by the reconcile do not trigger another reconcile of the same generation:
+// failed reconciles are retried by requeuing, and changes of the generated plotter are watched separately.
+func applicationChanged(e event.UpdateEvent) bool {
+	if e.ObjectOld == nil || e.ObjectNew == nil {
+		return true
+	}
+	return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() ||
+		!e.ObjectNew.GetDeletionTimestamp().Equal(e.ObjectOld.GetDeletionTimestamp()) ||
+		!reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels()) ||
+		!reflect.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+}
+
 // AnalyzeError analyzes whether the given error is fatal, or a retrial attempt can be made.
 // Reasons for retrial can be either communication problems with external services, or kubernetes
 // problems to perform some action on a resource.
@@ -766,18 +1461,40 @@ func AnalyzeError(appContext ApplicationContext, assetID string, err error) {
 	if err == nil {
 		return
 	}
-	const format string = "%d"
-	denyCodes := []string{fmt.Sprintf(format, http.StatusNotFound), fmt.Sprintf(format, http.StatusForbidden)}
 	cause := errors.Cause(err).Error()
-	for _, code := range denyCodes {
-		if strings.HasPrefix(err.Error(), code) {
+	// errors reported by the connector clients are classified by their type
+	var connectorErr connectors.ConnectorError
+	if errors.As(err, &connectorErr) {
+		switch connectorErr.(type) {
+		case *connectors.AssetNotFoundError:
+			setAssetNotFoundCondition(appContext, assetID, cause)
+			return
+		case *connectors.AuthError:
 			setDenyCondition(appContext, assetID, cause)
 			return
 		}
+		setErrorCondition(appContext, assetID, cause)
+		if connectorErr.IsRetryable() {
+			appContext.addFailure(assetID, TransientFailure)
+		} else {
+			appContext.addFailure(assetID, TerminalFailure)
+		}
+		return
 	}
-	switch cause {
-	case dcclient.AssetIDNotFound, dcclient.AccessForbidden, ReadAccessDenied, CopyNotAllowed, WriteNotAllowed:
+	const format string = "%d"
+	if strings.HasPrefix(err.Error(), fmt.Sprintf(format, http.StatusNotFound)) {
+		setAssetNotFoundCondition(appContext, assetID, cause)
+		return
+	}
+	if strings.HasPrefix(err.Error(), fmt.Sprintf(format, http.StatusForbidden)) {
 		setDenyCondition(appContext, assetID, cause)
+		return
+	}
+	switch cause {
+	case dcclient.AssetIDNotFound:
+		setAssetNotFoundCondition(appContext, assetID, cause)
+	case dcclient.AccessForbidden, ReadAccessDenied, CopyNotAllowed, WriteNotAllowed:
+		setDenyCondition(appContext, assetID, denyMessage(err, cause))
 	default:
 		setErrorCondition(appContext, assetID, cause)
 	}
@@ -790,6 +1507,25 @@ func ownerLabels(id types.NamespacedName) map[string]string {
 	}
 }
 
+// generatedResourceLabels returns the labels of the resources generated for the application, i.e., the application labels
+// and the cost center of the application. The labels are propagated to the Blueprints and to the module deployments.
+func generatedResourceLabels(applicationContext ApplicationContext) map[string]string {
+	application := applicationContext.Application
+	labels := make(map[string]string, len(application.Labels)+1)
+	for key, val := range application.Labels {
+		labels[key] = val
+	}
+	if costCenter, found := application.Annotations[utils.CostCenterLabel]; found {
+		if errs := validation.IsValidLabelValue(costCenter); len(errs) > 0 {
+			applicationContext.Log.Warn().Str(logging.ACTION, logging.CREATE).
+				Msgf("Ignoring the cost center annotation %q: %s", costCenter, strings.Join(errs, ", "))
+		} else {
+			labels[utils.CostCenterLabel] = costCenter
+		}
+	}
+	return labels
+}
+
 // GetAllModules returns all CRDs of the kind FybrikModule mapped by their name
 func (r *FybrikApplicationReconciler) GetAllModules() (map[string]*fappv1.FybrikModule, error) {
 	ctx := context.Background()
@@ -850,6 +1586,16 @@ func (r *FybrikApplicationReconciler) getStorageAccounts() ([]*fappv2.FybrikStor
 }
 
 func (r *FybrikApplicationReconciler) deleteTemporaryStorage(datasetDetails fappv1.DatasetDetails) error {
+	if datasetDetails.Staging != nil {
+		// the data staged by a transactional module has not been committed
+		if err := r.StorageManager.DeleteStorage(&storagemanager.DeleteStorageRequest{
+			Connection: datasetDetails.Staging.Connection,
+			Secret:     datasetDetails.SecretRef,
+			Opts:       storagemanager.Options{},
+		}); err != nil {
+			return err
+		}
+	}
 	req := &storagemanager.DeleteStorageRequest{
 		Connection: datasetDetails.Details.Connection,
 		Secret:     datasetDetails.SecretRef,
@@ -884,6 +1630,7 @@ func (r *FybrikApplicationReconciler) updateProvisionedStorageStatus(application
 			Details:          details,
 			ResourceMetadata: &datacatalog.ResourceMetadata{Geography: string(info.StorageAccount.Geography)},
 			Persistent:       info.Persistent,
+			Staging:          info.Staging.DeepCopy(),
 		}
 	}
 	return nil
@@ -898,6 +1645,7 @@ func (r *FybrikApplicationReconciler) buildSolution(applicationContext Applicati
 		UUID:               applicationContext.UUID,
 		StorageManager:     r.StorageManager,
 		ProvisionedStorage: make(map[string]NewAssetInfo),
+		DryRun:             applicationContext.Application.Spec.DryRun,
 	}
 
 	plotterSpec := &fappv1.PlotterSpec{
@@ -905,11 +1653,20 @@ func (r *FybrikApplicationReconciler) buildSolution(applicationContext Applicati
 		AppInfo:          applicationContext.Application.Spec.AppInfo,
 		Assets:           map[string]fappv1.AssetDetails{},
 		Flows:            []fappv1.Flow{},
-		ModulesNamespace: environment.GetDefaultModulesNamespace(),
+		ModulesNamespace: modulesNamespace(applicationContext.Application),
 		Templates:        map[string]fappv1.Template{},
+		ModuleResources:  applicationContext.Application.Spec.ModuleResources.DeepCopy(),
 	}
 
-	paths, err := solve(env, requirements, applicationContext.Log)
+	// governance actions must be supported by the deployed modules before binding them
+	if err := r.checkTransformationSupport(applicationContext, env, requirements); err != nil {
+		return plotterGen.ProvisionedStorage, plotterSpec, err
+	}
+	var solver Solver = &DefaultSolver{}
+	if r.Solver != nil {
+		solver = r.Solver
+	}
+	paths, err := solve(solver, env, requirements, applicationContext.Log)
 	if err != nil {
 		applicationContext.Application.Status.ErrorMessage = err.Error()
 		return plotterGen.ProvisionedStorage, plotterSpec, nil
@@ -932,10 +1689,235 @@ func (r *FybrikApplicationReconciler) buildSolution(applicationContext Applicati
 			setErrorCondition(applicationContext, requirements[ind].Context.DataSetID, err.Error())
 			return plotterGen.ProvisionedStorage, plotterSpec, err
 		}
+		recordAppliedActions(applicationContext, requirements[ind].Context.DataSetID, &paths[ind])
+		recordSchema(applicationContext, &requirements[ind])
 	}
 	return plotterGen.ProvisionedStorage, plotterSpec, nil
 }
 
+// recordAppliedActions stores the governance actions applied along the data path of an asset in the asset state.
+// An action applied for several consumers of the asset is recorded once.
+func recordAppliedActions(applicationContext ApplicationContext, datasetID string, path *datapath.Solution) {
+	state, found := applicationContext.Application.Status.AssetStates[datasetID]
+	if !found {
+		return
+	}
+	for _, element := range path.DataPath {
+		for i := range element.Actions {
+			recorded := false
+			for j := range state.AppliedActions {
+				if reflect.DeepEqual(state.AppliedActions[j], element.Actions[i]) {
+					recorded = true
+					break
+				}
+			}
+			if !recorded {
+				state.AppliedActions = append(state.AppliedActions, element.Actions[i])
+			}
+		}
+	}
+	applicationContext.Application.Status.AssetStates[datasetID] = state
+}
+
+// recordSchema stores the columns of the data served to the application in the asset state,
+// i.e., the columns defined by the data catalog that are not removed by the applied governance actions.
+// The schema is recorded for read flows of assets whose columns are defined by the catalog.
+func recordSchema(applicationContext ApplicationContext, item *datapath.DataInfo) {
+	if item.Context.Flow != "" && item.Context.Flow != taxonomy.ReadFlow {
+		return
+	}
+	if item.DataDetails == nil || len(item.DataDetails.ResourceMetadata.Columns) == 0 {
+		return
+	}
+	state, found := applicationContext.Application.Status.AssetStates[item.Context.DataSetID]
+	if !found {
+		return
+	}
+	columns := item.DataDetails.ResourceMetadata.Columns
+	removed := removedColumns(columns, state.AppliedActions)
+	state.Schema = []fappv1.ColumnSchema{}
+	for _, column := range columns {
+		if !removed[column.Name] {
+			state.Schema = append(state.Schema, fappv1.ColumnSchema{Name: column.Name, Type: column.Type})
+		}
+	}
+	applicationContext.Application.Status.AssetStates[item.Context.DataSetID] = state
+}
+
+// removedColumns returns the columns that the actions remove from the data, i.e., the columns not allowed by a projection
+func removedColumns(columns []datacatalog.ResourceColumn, actions []taxonomy.Action) map[string]bool {
+	removed := map[string]bool{}
+	for i := range actions {
+		if actions[i].Name != taxonomy.ProjectionActionName {
+			continue
+		}
+		projection := taxonomy.ProjectionAction{}
+		if err := taxonomy.DecodeActionProperties(&actions[i], &projection); err != nil {
+			continue
+		}
+		allowed := map[string]bool{}
+		for _, column := range projection.Columns {
+			allowed[column] = true
+		}
+		for _, column := range columns {
+			if !allowed[column.Name] {
+				removed[column.Name] = true
+			}
+		}
+	}
+	return removed
+}
+
+// warnUnavailableColumns compares the columns that the data user expects to read with the columns allowed by the governance
+// actions before the data is read, and sets a warning condition of the asset listing the expected columns that are unavailable
+func warnUnavailableColumns(applicationContext ApplicationContext, requirements []datapath.DataInfo) {
+	unavailable := map[string][]string{}
+	listed := map[string]map[string]bool{}
+	for ind := range requirements {
+		item := &requirements[ind]
+		if item.Context.Flow != "" && item.Context.Flow != taxonomy.ReadFlow {
+			continue
+		}
+		assetID := item.Context.DataSetID
+		if listed[assetID] == nil {
+			listed[assetID] = map[string]bool{}
+		}
+		// the columns of an asset read by several consumers are listed once
+		for _, column := range unavailableColumns(item) {
+			if !listed[assetID][column] {
+				listed[assetID][column] = true
+				unavailable[assetID] = append(unavailable[assetID], column)
+			}
+		}
+	}
+	for assetID, columns := range unavailable {
+		setWarningCondition(applicationContext, assetID, ColumnsUnavailable+": "+strings.Join(columns, ", "))
+	}
+}
+
+// unavailableColumns returns the expected columns of a data path, in their order, with the reason they are unavailable:
+// the columns not defined by the data catalog, the columns removed by a projection and the redacted columns
+func unavailableColumns(item *datapath.DataInfo) []string {
+	expected := item.Context.Requirements.ExpectedColumns
+	if len(expected) == 0 {
+		return nil
+	}
+	defined := map[string]bool{}
+	if item.DataDetails != nil {
+		for _, column := range item.DataDetails.ResourceMetadata.Columns {
+			defined[column.Name] = true
+		}
+	}
+	expectedColumns := make([]datacatalog.ResourceColumn, 0, len(expected))
+	for _, name := range expected {
+		expectedColumns = append(expectedColumns, datacatalog.ResourceColumn{Name: name})
+	}
+	removed := removedColumns(expectedColumns, item.Actions)
+	redacted := map[string]bool{}
+	for i := range item.Actions {
+		if item.Actions[i].Name != taxonomy.RedactActionName {
+			continue
+		}
+		redact := taxonomy.RedactAction{}
+		if err := taxonomy.DecodeActionProperties(&item.Actions[i], &redact); err != nil {
+			continue
+		}
+		for _, column := range redact.Columns {
+			redacted[column] = true
+		}
+	}
+	var unavailable []string
+	for _, name := range expected {
+		switch {
+		// the columns are not checked against the catalog if it does not define them
+		case len(defined) > 0 && !defined[name]:
+			unavailable = append(unavailable, name+" (not defined)")
+		case removed[name]:
+			unavailable = append(unavailable, name+" (removed)")
+		case redacted[name]:
+			unavailable = append(unavailable, name+" (redacted)")
+		}
+	}
+	return unavailable
+}
+
+// errUnsupportedTransformation is returned when the governance actions of some assets can not be applied
+var errUnsupportedTransformation = errors.New(UnsupportedTransformation)
+
+// checkTransformationSupport sets an error condition for each asset with governance actions
+// that are not supported by any of the deployed modules, that conflict with each other, or with malformed filter predicates,
+// and returns errUnsupportedTransformation if there are such assets.
+// The failures are terminal: the same actions fail again until the application or the deployed modules are changed.
+func (r *FybrikApplicationReconciler) checkTransformationSupport(applicationContext ApplicationContext, env *datapath.Environment,
+	requirements []datapath.DataInfo) error {
+	if len(env.Modules) == 0 {
+		// reported by validateBasicConditions
+		return nil
+	}
+	unsupportedAssets := []string{}
+	for ind := range requirements {
+		datasetID := requirements[ind].Context.DataSetID
+		var msg string
+		pathBuilder := PathBuilder{Log: applicationContext.Log, Env: env, Asset: &requirements[ind]}
+		if err := validateColumnActions(requirements[ind].Actions); err != nil {
+			msg = PolicyConflict + ": " + err.Error()
+		} else if err := validateFilterActions(requirements[ind].Actions); err != nil {
+			msg = InvalidFilterPredicate + ": " + err.Error()
+		} else if unsupported := pathBuilder.unsupportedActions(); len(unsupported) > 0 {
+			msg = UnsupportedTransformation + ": " + strings.Join(unsupported, ", ")
+		} else {
+			continue
+		}
+		setErrorCondition(applicationContext, datasetID, msg)
+		applicationContext.addFailure(datasetID, TerminalFailure)
+		unsupportedAssets = append(unsupportedAssets, datasetID)
+	}
+	if len(unsupportedAssets) > 0 {
+		return errors.Wrapf(errUnsupportedTransformation, "assets %s", strings.Join(unsupportedAssets, ", "))
+	}
+	return nil
+}
+
+// validateColumnActions checks that every column is targeted by a single kind of action,
+// e.g., a column can not be redacted and hashed, or redacted and allowed by a projection at the same time.
+// Actions of the same kind may target the same column, e.g., redactions returned by different policies.
+func validateColumnActions(actions []taxonomy.Action) error {
+	columnActions := map[string]taxonomy.ActionName{}
+	for i := range actions {
+		target := struct {
+			Columns []string `json:"columns,omitempty"`
+		}{}
+		if err := taxonomy.DecodeActionProperties(&actions[i], &target); err != nil {
+			// the action does not target columns
+			continue
+		}
+		for _, column := range target.Columns {
+			if name, found := columnActions[column]; found && name != actions[i].Name {
+				return errors.Errorf("column %s is targeted by both %s and %s", column, name, actions[i].Name)
+			}
+			columnActions[column] = actions[i].Name
+		}
+	}
+	return nil
+}
+
+// validateFilterActions checks that the predicates of the filter actions are well formed
+func validateFilterActions(actions []taxonomy.Action) error {
+	for i := range actions {
+		if actions[i].Name != taxonomy.FilterActionName {
+			continue
+		}
+		filter := taxonomy.FilterAction{}
+		if err := taxonomy.DecodeActionProperties(&actions[i], &filter); err != nil {
+			return err
+		}
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // validation of FybrikApplication
 func (r *FybrikApplicationReconciler) validateApp(ctx context.Context, applicationContext ApplicationContext) error {
 	observedStatus := applicationContext.Application.Status
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/mockup"
 	"fybrik.io/fybrik/manager/controllers/utils"
 	"fybrik.io/fybrik/pkg/environment"
 )
@@ -184,11 +185,9 @@ var _ = Describe("FybrikApplication Controller", func() {
 			Expect(application.Status.AssetStates["s3-incomplete/allow-dataset"].Conditions[ReadyConditionIndex].Message).NotTo(BeEmpty())
 			Expect(application.Status.AssetStates["s3-external/new-dataset"].Conditions[ReadyConditionIndex].Message).NotTo(BeEmpty())
 			By("Status should contain the details of the endpoint")
-			connection := application.Status.AssetStates["s3/redact-dataset"].Endpoint
-			Expect(connection).ToNot(BeNil())
-			connectionMap := connection.AdditionalProperties.Items
-			Expect(connectionMap).To(HaveKey("fybrik-arrow-flight"))
-			config := connectionMap["fybrik-arrow-flight"].(map[string]interface{})
+			assetState := application.Status.AssetStates["s3/redact-dataset"]
+			config, found := assetState.GetEndpoint(mockup.ArrowFlight)
+			Expect(found).To(BeTrue())
 			Expect(config["hostname"]).NotTo(BeEmpty())
 			Expect(config["scheme"]).To(Equal("grpc"))
 
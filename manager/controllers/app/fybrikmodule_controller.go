@@ -5,15 +5,11 @@ package app
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 
 	"github.com/rs/zerolog"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -21,7 +17,6 @@ import (
 	"fybrik.io/fybrik/manager/controllers/utils"
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
-	"fybrik.io/fybrik/pkg/validate"
 )
 
 // FybrikModuleReconciler reconciles a FybrikModule object
@@ -102,29 +97,9 @@ func NewFybrikModuleReconciler(mgr ctrl.Manager, name string) *FybrikModuleRecon
 	}
 }
 
+// ValidateFybrikModule validates the module against the taxonomy, as done by the webhook
 func ValidateFybrikModule(module *fapp.FybrikModule, taxonomyFile string) error {
-	var allErrs []*field.Error
-
-	// Convert Fybrik module Go struct to JSON
-	moduleJSON, err := json.Marshal(&module.Spec)
-	if err != nil {
-		return err
-	}
-
-	// Validate Fybrik module against taxonomy
-	allErrs, err = validate.TaxonomyCheck(moduleJSON, taxonomyFile)
-	if err != nil {
-		return err
-	}
-
-	// Return any error
-	if len(allErrs) == 0 {
-		return nil
-	}
-
-	return apierrors.NewInvalid(
-		schema.GroupKind{Group: "app.fybrik.io", Kind: FybrikModuleKind},
-		module.Name, allErrs)
+	return module.ValidateFybrikModule(taxonomyFile)
 }
 
 // SetupWithManager registers Module controller
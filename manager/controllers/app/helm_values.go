@@ -4,6 +4,8 @@
 package app
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 )
@@ -18,4 +20,6 @@ type HelmValues struct {
 	Labels map[string]string `json:"labels"`
 	// Application unique identifier
 	UUID string `json:"uuid"`
+	// Compute resources of the module, which override the defaults of the chart if set
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
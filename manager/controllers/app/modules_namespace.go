@@ -0,0 +1,72 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// ModulesNamespaceAuthorizer checks whether the manager is permitted to deploy modules in a namespace
+type ModulesNamespaceAuthorizer interface {
+	CanDeployModules(ctx context.Context, namespace string) (bool, error)
+}
+
+// AccessReviewAuthorizer checks the permissions of the manager in a namespace by a SelfSubjectAccessReview.
+// The modules may consist of any kind of resources, thus all permissions are required,
+// as granted to the manager in the default modules namespace.
+type AccessReviewAuthorizer struct {
+	Client client.Client
+}
+
+func (a *AccessReviewAuthorizer) CanDeployModules(ctx context.Context, namespace string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "*",
+				Group:     "*",
+				Resource:  "*",
+			},
+		},
+	}
+	if err := a.Client.Create(ctx, review); err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// modulesNamespace returns the namespace where the modules of the application are deployed
+func modulesNamespace(application *fappv1.FybrikApplication) string {
+	if application.Spec.ModulesNamespace != "" {
+		return application.Spec.ModulesNamespace
+	}
+	return environment.GetDefaultModulesNamespace()
+}
+
+// checkModulesNamespace returns false if the manager is not permitted to deploy modules
+// in the namespace requested by the application, and reports it in the application status.
+// The default modules namespace is not checked, since the permissions there are granted when Fybrik is deployed.
+func (r *FybrikApplicationReconciler) checkModulesNamespace(ctx context.Context, applicationContext ApplicationContext) (bool, error) {
+	namespace := modulesNamespace(applicationContext.Application)
+	if namespace == environment.GetDefaultModulesNamespace() || r.ModulesNamespaceAuthorizer == nil {
+		return true, nil
+	}
+	allowed, err := r.ModulesNamespaceAuthorizer.CanDeployModules(ctx, namespace)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not check the permissions in the modules namespace %s", namespace)
+	}
+	if !allowed {
+		applicationContext.Log.Warn().Str("namespace", namespace).Msg(ModulesNamespaceForbidden)
+		applicationContext.Application.Status.ErrorMessage = ModulesNamespaceForbidden + ": " + namespace
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+const (
+	// StagedPlotterKey is the ConfigMap key holding the Plotter spec that waits for approval
+	StagedPlotterKey = "plotter.yaml"
+	// StagedPlotterDigestKey is the ConfigMap key holding the digest that approves the staged Plotter spec
+	StagedPlotterDigestKey = "digest"
+	stagedPlotterSuffix    = "-staged-plotter"
+)
+
+// plotterDigest returns the digest identifying the serialized Plotter spec
+func plotterDigest(plotterSpec *fappv1.PlotterSpec) (string, error) {
+	content, err := yaml.Marshal(plotterSpec)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isPlotterApproved returns true if the application has been annotated with the digest of the Plotter spec
+func isPlotterApproved(application *fappv1.FybrikApplication, digest string) bool {
+	return digest != "" && application.GetAnnotations()[utils.ApprovedPlotterAnnotation] == digest
+}
+
+// stagePlotter stores the Plotter spec that waits for approval in a ConfigMap owned by the application.
+// A Plotter approved for a previous version of the application is kept until the new spec is approved.
+func (r *FybrikApplicationReconciler) stagePlotter(applicationContext ApplicationContext, plotterSpec *fappv1.PlotterSpec,
+	digest string) error {
+	application := applicationContext.Application
+	content, err := yaml.Marshal(plotterSpec)
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      application.Name + stagedPlotterSuffix,
+			Namespace: application.Namespace,
+		},
+	}
+	if _, err := ctrl.CreateOrUpdate(context.Background(), r.Client, configMap, func() error {
+		configMap.Labels = ownerLabels(client.ObjectKeyFromObject(application))
+		configMap.Data = map[string]string{StagedPlotterKey: string(content), StagedPlotterDigestKey: digest}
+		// the ConfigMap is garbage collected together with the application
+		return ctrlutil.SetOwnerReference(application, configMap, r.Client.Scheme())
+	}); err != nil {
+		return err
+	}
+	if application.Status.StagedPlotterDigest != digest {
+		recordTransition(application, fappv1.PlotterStagedTransition, configMap.Namespace+"/"+configMap.Name)
+	}
+	application.Status.StagedPlotter = &fappv1.ResourceReference{
+		Name:       configMap.Name,
+		Namespace:  configMap.Namespace,
+		Kind:       "ConfigMap",
+		AppVersion: application.GetGeneration(),
+	}
+	application.Status.StagedPlotterDigest = digest
+	applicationContext.Log.Info().Bool(logging.FORUSER, true).Str(logging.ACTION, logging.CREATE).
+		Msgf("The Plotter spec has been staged in the ConfigMap %s, annotate the application with %s=%s to approve it",
+			configMap.Name, utils.ApprovedPlotterAnnotation, digest)
+	return nil
+}
+
+// deleteStagedPlotter removes the ConfigMap holding a staged Plotter spec, once the Plotter is created or no approval is required
+func (r *FybrikApplicationReconciler) deleteStagedPlotter(applicationContext ApplicationContext) error {
+	ref := applicationContext.Application.Status.StagedPlotter
+	if ref == nil {
+		return nil
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace}}
+	if err := r.Client.Delete(context.Background(), configMap); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	applicationContext.Application.Status.StagedPlotter = nil
+	applicationContext.Application.Status.StagedPlotterDigest = ""
+	return nil
+}
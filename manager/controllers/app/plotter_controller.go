@@ -203,6 +203,7 @@ func (r *PlotterReconciler) convertPlotterModuleToBlueprintModule(plotter *fapp.
 			Arguments:       args,
 			AssetID:         plotterModule.AssetID,
 			Transformations: plotterModule.ModuleArguments.Actions,
+			Pushdown:        plotterModule.ModuleArguments.Pushdown,
 			Capability:      plotterModule.Capability,
 		},
 	}
@@ -223,7 +224,11 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 	for _, flow := range plotter.Spec.Flows {
 		for _, subFlow := range flow.SubFlows {
 			for _, subFlowStep := range subFlow.Steps {
+				// the modules of a step read the data served by the modules of the previous step in the sequence,
+				// and depend on them if they run in the same cluster
+				var previous []ModuleInstanceSpec
 				for _, seqStep := range subFlowStep {
+					current := []ModuleInstanceSpec{}
 					stepTemplate := plotter.Spec.Templates[seqStep.Template]
 					for _, module := range stepTemplate.Modules {
 						moduleArgs := seqStep.Parameters
@@ -257,9 +262,16 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 						}
 
 						blueprintModule := r.convertPlotterModuleToBlueprintModule(plotter, plotterModule)
+						for ind := range previous {
+							if previous[ind].ClusterName == clusterName {
+								blueprintModule.Module.DependsOn = append(blueprintModule.Module.DependsOn, moduleInstanceName(&previous[ind]))
+							}
+						}
 						// append the module to the modules list
 						moduleInstances = append(moduleInstances, *blueprintModule)
+						current = append(current, *blueprintModule)
 					}
+					previous = current
 				}
 			}
 		}
@@ -287,6 +299,10 @@ func (r *PlotterReconciler) updatePlotterAssetsState(assetToStatusMap map[string
 					Ready: false,
 					Error: errMsg,
 				}
+			} else if moduleState.Completed {
+				// the asset is completed once a module processing it, e.g., the module writing it, has run to completion
+				state.Completed = true
+				assetToStatusMap[assetID] = state
 			}
 		}
 	}
@@ -320,6 +336,7 @@ func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []err
 	plotter.Status.ObservedState.Error = "" // Reset error state
 	// Reconciliation loop per cluster
 	isReady := true
+	isCompleted := true
 
 	blueprintsMap := r.getBlueprintsMap(plotter)
 
@@ -354,6 +371,12 @@ func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []err
 				if plotter.Generation != plotter.Status.ObservedGeneration {
 					log.Trace().Str(logging.ACTION, logging.UPDATE).Msg("Updating blueprint...")
 					remoteBlueprint.Spec = blueprintSpec
+					if remoteBlueprint.Labels == nil {
+						remoteBlueprint.Labels = map[string]string{}
+					}
+					for key, val := range plotter.Labels {
+						remoteBlueprint.Labels[key] = val
+					}
 					err := r.ClusterManager.UpdateBlueprint(cluster, remoteBlueprint)
 					if err != nil {
 						log.Error().Err(err).Msg("Could not update blueprint")
@@ -383,10 +406,13 @@ func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []err
 			if !remoteBlueprint.Status.ObservedState.Ready {
 				isReady = false
 			}
+			if !remoteBlueprint.Status.ObservedState.Completed {
+				isCompleted = false
+			}
 
-			// If Blueprint has an error set it as status of plotter
+			// If Blueprint has an error add it to the status of plotter, the errors of the blueprints of all clusters are reported
 			if remoteBlueprint.Status.ObservedState.Error != "" {
-				plotter.Status.ObservedState.Error = remoteBlueprint.Status.ObservedState.Error
+				plotter.Status.ObservedState.Error += remoteBlueprint.Status.ObservedState.Error
 			}
 			r.updatePlotterAssetsState(assetToStatusMap, remoteBlueprint)
 		} else {
@@ -448,6 +474,7 @@ func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []err
 	// Update observed generation
 	plotter.Status.ObservedGeneration = plotter.ObjectMeta.Generation
 	plotter.Status.ObservedState.Ready = isReady
+	plotter.Status.ObservedState.Completed = isReady && isCompleted
 	plotter.Status.Assets = assetToStatusMap
 	plotterReadyMsg := "Plotter is ready!"
 	if isReady {
@@ -254,3 +254,61 @@ func TestPlotterWithWriteFlow(t *testing.T) {
 	}
 	g.Expect(plotter.Status.Assets).To(gomega.HaveLen(2), "Plotter Asset status list contains two elements")
 }
+
+// The read step of a plotter follows the copy step in the same sequence, and reads the data that it has copied
+// Result: the read module of the blueprint depends on the copy module, which does not depend on any module
+func TestPlotterModuleDependencies(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	plotterYAML, err := os.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &fapp.Plotter{}
+	g.Expect(yaml.Unmarshal(plotterYAML, plotter)).To(gomega.Succeed())
+	dummyManager := dummy.NewDummyClusterManager(make(map[string]*fapp.Blueprint),
+		[]multicluster.Cluster{{Name: "thegreendragon"}})
+	r := &PlotterReconciler{Log: logging.LogInit(logging.CONTROLLER, "test-controller"), ClusterManager: &dummyManager}
+
+	// the copy and the read steps belong to different sub-flows, so that the modules are independent
+	for _, module := range r.getBlueprintsMap(plotter)["thegreendragon"].Modules {
+		g.Expect(module.DependsOn).To(gomega.BeEmpty())
+	}
+
+	subFlows := plotter.Spec.Flows[0].SubFlows
+	subFlows[0].Steps[0] = append(subFlows[0].Steps[0], subFlows[1].Steps[0]...)
+	plotter.Spec.Flows[0].SubFlows = subFlows[:1]
+	modules := r.getBlueprintsMap(plotter)["thegreendragon"].Modules
+	g.Expect(modules).To(gomega.HaveLen(2))
+	copyInstance := ""
+	for instanceName, module := range modules {
+		if module.Name == "implicit-copy-batch-latest" {
+			copyInstance = instanceName
+			g.Expect(module.DependsOn).To(gomega.BeEmpty())
+		}
+	}
+	g.Expect(modules).To(gomega.HaveKey("arrow-flight-read"))
+	g.Expect(modules["arrow-flight-read"].DependsOn).To(gomega.ConsistOf(copyInstance))
+}
+
+// An asset is read by a module serving the workload, and written by a module running a job, which has completed
+// Result: the asset is completed, while the asset that is only served by the module is not
+func TestPlotterAssetsCompletion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	blueprint := &fapp.Blueprint{
+		Spec: fapp.BlueprintSpec{Modules: map[string]fapp.BlueprintModule{
+			"read":  {AssetIDs: []string{"written", "served"}},
+			"write": {AssetIDs: []string{"written"}},
+		}},
+		Status: fapp.BlueprintStatus{ModulesState: map[string]fapp.ObservedState{
+			"read":  {Ready: true},
+			"write": {Ready: true, Completed: true},
+		}},
+	}
+	r := &PlotterReconciler{}
+	assets := map[string]fapp.ObservedState{}
+	r.updatePlotterAssetsState(assets, blueprint)
+	g.Expect(assets).To(gomega.HaveKeyWithValue("written", fapp.ObservedState{Ready: true, Completed: true}))
+	g.Expect(assets).To(gomega.HaveKeyWithValue("served", fapp.ObservedState{Ready: true}))
+}
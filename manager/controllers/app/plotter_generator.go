@@ -33,6 +33,8 @@ type NewAssetInfo struct {
 	StorageAccount *fappv2.FybrikStorageAccountSpec
 	Details        *fappv1.DataStore
 	Persistent     bool
+	// Staging is the storage written by a transactional module until the data is committed, optional
+	Staging *fappv1.DataStore
 }
 
 // PlotterGenerator constructs a plotter based on the requirements (governance actions, data location) and the existing set of FybrikModules
@@ -43,10 +45,41 @@ type PlotterGenerator struct {
 	Owner              types.NamespacedName
 	StorageManager     storage.StorageManagerInterface
 	ProvisionedStorage map[string]NewAssetInfo
+	// DryRun indicates that no storage should be allocated
+	DryRun bool
 }
 
 // Provision allocates storage based on the selected account and generates the destination data store for the plotter
 func (p *PlotterGenerator) Provision(item *datapath.DataInfo, destinationInterface *taxonomy.Interface,
+	account *fappv2.FybrikStorageAccountSpec) (*fappv1.DataStore, error) {
+	datastore, err := p.allocate(item, destinationInterface, account)
+	if err != nil || p.DryRun {
+		return datastore, err
+	}
+	assetInfo := NewAssetInfo{
+		StorageAccount: account,
+		Details:        datastore,
+	}
+	p.ProvisionedStorage[item.Context.DataSetID] = assetInfo
+	logging.LogStructure("ProvisionedStorage element", assetInfo, p.Log, zerolog.DebugLevel, false, true)
+	return datastore, nil
+}
+
+// ProvisionStaging allocates the staging storage written by a transactional module for a provisioned asset
+func (p *PlotterGenerator) ProvisionStaging(item *datapath.DataInfo, destinationInterface *taxonomy.Interface,
+	account *fappv2.FybrikStorageAccountSpec) (*fappv1.DataStore, error) {
+	datastore, err := p.allocate(item, destinationInterface, account)
+	if err != nil || p.DryRun {
+		return datastore, err
+	}
+	assetInfo := p.ProvisionedStorage[item.Context.DataSetID]
+	assetInfo.Staging = datastore
+	p.ProvisionedStorage[item.Context.DataSetID] = assetInfo
+	return datastore, nil
+}
+
+// allocate allocates storage based on the selected account and returns its data store
+func (p *PlotterGenerator) allocate(item *datapath.DataInfo, destinationInterface *taxonomy.Interface,
 	account *fappv2.FybrikStorageAccountSpec) (*fappv1.DataStore, error) {
 	// provisioned storage
 	secretRef := &taxonomy.SecretRef{Name: account.SecretRef, Namespace: environment.GetAdminCRsNamespace()}
@@ -60,6 +93,13 @@ func (p *PlotterGenerator) Provision(item *datapath.DataInfo, destinationInterfa
 			ConfigurationOpts: storagemanager.ConfigOptions{},
 		},
 	}
+	if p.DryRun {
+		// storage is not allocated in the dry-run mode, only the storage type is reported
+		return &fappv1.DataStore{
+			Connection: taxonomy.Connection{Name: account.Type},
+			Format:     destinationInterface.DataFormat,
+		}, nil
+	}
 	response, err := p.StorageManager.AllocateStorage(allocateRequest)
 	if err != nil {
 		return nil, err
@@ -83,18 +123,11 @@ func (p *PlotterGenerator) Provision(item *datapath.DataInfo, destinationInterfa
 		vaultMap[string(taxonomy.WriteFlow)] = fappv1.Vault{}
 		vaultMap[string(taxonomy.ReadFlow)] = fappv1.Vault{}
 	}
-	datastore := &fappv1.DataStore{
+	return &fappv1.DataStore{
 		Vault:      vaultMap,
 		Connection: *response.Connection,
 		Format:     destinationInterface.DataFormat,
-	}
-	assetInfo := NewAssetInfo{
-		StorageAccount: account,
-		Details:        datastore,
-	}
-	p.ProvisionedStorage[item.Context.DataSetID] = assetInfo
-	logging.LogStructure("ProvisionedStorage element", assetInfo, p.Log, zerolog.DebugLevel, false, true)
-	return datastore, nil
+	}, nil
 }
 
 func (p *PlotterGenerator) getAssetDataStore(item *datapath.DataInfo) *fappv1.DataStore {
@@ -129,6 +162,19 @@ func getDatasetCredentials(item *datapath.DataInfo) map[string]fappv1.Vault {
 	return vaultMap
 }
 
+// getAssetSchema returns the schema of the asset as registered in the catalog,
+// or nil if the catalog does not list the asset columns, e.g., for a new asset
+func getAssetSchema(item *datapath.DataInfo) *fappv1.AssetSchema {
+	if item.DataDetails == nil || len(item.DataDetails.ResourceMetadata.Columns) == 0 {
+		return nil
+	}
+	schema := &fappv1.AssetSchema{Columns: []string{}}
+	for _, column := range item.DataDetails.ResourceMetadata.Columns {
+		schema.Columns = append(schema.Columns, column.Name)
+	}
+	return schema
+}
+
 func (p *PlotterGenerator) addTemplate(element *datapath.ResolvedEdge, plotterSpec *fappv1.PlotterSpec, templateName string) {
 	moduleCapability := element.Module.Spec.Capabilities[element.CapabilityIndex]
 	template := fappv1.Template{
@@ -154,8 +200,11 @@ func (p *PlotterGenerator) addInMemoryStep(element *datapath.ResolvedEdge, datas
 		lastStepAPI = steps[len(steps)-1].Parameters.API
 	}
 	assetID := ""
+	var pushdown *fappv1.PushdownHints
 	if lastStepAPI == nil {
+		// only a step reading the data source directly can push the actions down to it
 		assetID = datasetID
+		pushdown = pushdownHints(element)
 	}
 	steps = append(steps, fappv1.DataFlowStep{
 		Cluster:  element.Cluster,
@@ -165,8 +214,9 @@ func (p *PlotterGenerator) addInMemoryStep(element *datapath.ResolvedEdge, datas
 				AssetID: assetID,
 				API:     lastStepAPI,
 			}},
-			API:     api,
-			Actions: element.Actions,
+			API:      api,
+			Actions:  element.Actions,
+			Pushdown: pushdown,
 		},
 	})
 	return steps
@@ -184,11 +234,102 @@ func (p *PlotterGenerator) addStep(element *datapath.ResolvedEdge, datasetID str
 			Arguments: []*fappv1.StepArgument{{AssetID: datasetID}, {AssetID: datasetID + "-copy"}},
 			API:       api,
 			Actions:   element.Actions,
+			Pushdown:  pushdownHints(element),
 		},
 	})
 	return steps
 }
 
+// selectColumns translates the projections among the actions applied by a module into the column selection of the module:
+// a single projection listing the columns allowed by all projections, in the order of the columns registered in the catalog.
+// Columns that are not registered in the catalog are not selected, unless the catalog does not list the columns of the asset.
+func selectColumns(actions []taxonomy.Action, catalogColumns []datacatalog.ResourceColumn) []taxonomy.Action {
+	var allowed []string
+	position := -1
+	selected := []taxonomy.Action{}
+	for i := range actions {
+		if actions[i].Name != taxonomy.ProjectionActionName {
+			selected = append(selected, actions[i])
+			continue
+		}
+		projection := taxonomy.ProjectionAction{}
+		if err := taxonomy.DecodeActionProperties(&actions[i], &projection); err != nil {
+			selected = append(selected, actions[i])
+			continue
+		}
+		if position < 0 {
+			position = len(selected)
+			allowed = projection.Columns
+			continue
+		}
+		// several projections allow only the columns that all of them allow
+		allowed = intersectColumns(allowed, projection.Columns)
+	}
+	if position < 0 {
+		return actions
+	}
+	if len(catalogColumns) > 0 {
+		registered := []string{}
+		for _, column := range catalogColumns {
+			registered = append(registered, column.Name)
+		}
+		allowed = intersectColumns(registered, allowed)
+	}
+	selection := taxonomy.NewProjectionAction(allowed...)
+	return append(selected[:position], append([]taxonomy.Action{selection}, selected[position:]...)...)
+}
+
+// intersectColumns returns the given columns that are also listed in the allowed columns, in their given order
+func intersectColumns(columns, allowed []string) []string {
+	allowedColumns := map[string]bool{}
+	for _, column := range allowed {
+		allowedColumns[column] = true
+	}
+	intersection := []string{}
+	for _, column := range columns {
+		if allowedColumns[column] {
+			intersection = append(intersection, column)
+		}
+	}
+	return intersection
+}
+
+// pushdownHints returns the columns and predicates of the projections and filters that the module pushes down
+// to the data source, or nil if the module does not push down any of the actions
+func pushdownHints(element *datapath.ResolvedEdge) *fappv1.PushdownHints {
+	capability := &element.Module.Spec.Capabilities[element.CapabilityIndex]
+	hints := &fappv1.PushdownHints{}
+	for i := range element.Actions {
+		action := &element.Actions[i]
+		if !capability.PushesDown(action) {
+			continue
+		}
+		switch action.Name {
+		case taxonomy.ProjectionActionName:
+			projection := taxonomy.ProjectionAction{}
+			if err := taxonomy.DecodeActionProperties(action, &projection); err != nil {
+				continue
+			}
+			if hints.Columns == nil {
+				hints.Columns = projection.Columns
+				continue
+			}
+			// several projections allow only the columns that all of them allow
+			hints.Columns = intersectColumns(hints.Columns, projection.Columns)
+		case taxonomy.FilterActionName:
+			filter := taxonomy.FilterAction{}
+			if err := taxonomy.DecodeActionProperties(action, &filter); err != nil {
+				continue
+			}
+			hints.Predicates = append(hints.Predicates, filter.Predicates...)
+		}
+	}
+	if hints.Columns == nil && hints.Predicates == nil {
+		return nil
+	}
+	return hints
+}
+
 // getSupportedFormat returns the first dataformat supported by the module's capability sink interface that matches the protocol
 func (p *PlotterGenerator) getSupportedFormat(capability *fappv1.ModuleCapability, protocol taxonomy.ConnectionType) taxonomy.DataFormat {
 	for _, inter := range capability.SupportedInterfaces {
@@ -232,6 +373,15 @@ func (p *PlotterGenerator) handleNewAsset(item *datapath.DataInfo, selection *da
 		return err
 	}
 
+	// a transactional module writes to a staging storage, which is committed once the write completes
+	writtenDataStore := sinkDataStore
+	if capability.Transactional {
+		if writtenDataStore, err = p.ProvisionStaging(item, element.Sink.Connection, &element.StorageAccount); err != nil {
+			p.Log.Error().Err(err).Str(logging.DATASETID, item.Context.DataSetID).Msg("Staging storage allocation failed")
+			return err
+		}
+	}
+
 	resourceMetadata := datacatalog.ResourceMetadata{
 		Name:      item.Context.DataSetID,
 		Geography: string(element.StorageAccount.Geography),
@@ -253,8 +403,8 @@ func (p *PlotterGenerator) handleNewAsset(item *datapath.DataInfo, selection *da
 
 		item.DataDetails.Credentials = secretPath
 	}
-	item.DataDetails.Details.DataFormat = sinkDataStore.Format
-	item.DataDetails.Details.Connection = sinkDataStore.Connection
+	item.DataDetails.Details.DataFormat = writtenDataStore.Format
+	item.DataDetails.Details.Connection = writtenDataStore.Connection
 
 	return nil
 }
@@ -267,16 +417,25 @@ func (p *PlotterGenerator) AddFlowInfoForAsset(item *datapath.DataInfo, applicat
 	datasetID := item.Context.DataSetID
 	subflows := make([]fappv1.SubFlow, 0)
 
-	plotterSpec.Assets[item.Context.DataSetID] = fappv1.AssetDetails{
-		DataStore: *p.getAssetDataStore(item),
-	}
 	// DataStore for destination will be determined if an implicit copy is required
 	var steps []fappv1.DataFlowStep
 	flowType := item.Context.Flow
 	if flowType == "" {
 		flowType = taxonomy.ReadFlow
 	}
+	assetDataStore := p.getAssetDataStore(item)
+	if application.Spec.StrictSchema && flowType == taxonomy.WriteFlow {
+		assetDataStore.Schema = getAssetSchema(item)
+	}
+	plotterSpec.Assets[item.Context.DataSetID] = fappv1.AssetDetails{
+		DataStore: *assetDataStore,
+	}
+	var catalogColumns []datacatalog.ResourceColumn
+	if item.DataDetails != nil {
+		catalogColumns = item.DataDetails.ResourceMetadata.Columns
+	}
 	for _, element := range selection.DataPath {
+		element.Actions = selectColumns(element.Actions, catalogColumns)
 		moduleCapability := element.Module.Spec.Capabilities[element.CapabilityIndex]
 		p.Log.Trace().Str(logging.DATASETID, item.Context.DataSetID).Msgf("Adding module %s for capability %s", element.Module.Name,
 			moduleCapability.Capability)
@@ -326,10 +485,14 @@ func (p *PlotterGenerator) AddFlowInfoForAsset(item *datapath.DataInfo, applicat
 	// If everything finished without errors build the flow and add it to the plotter spec
 	// Also add new assets as well as templates
 	flowName := item.Context.DataSetID + "-" + string(flowType)
+	if item.Consumer != "" {
+		flowName += "-" + item.Consumer
+	}
 	flow := fappv1.Flow{
 		Name:     flowName,
 		FlowType: flowType,
 		AssetID:  item.Context.DataSetID,
+		Consumer: item.Consumer,
 		SubFlows: subflows,
 	}
 	plotterSpec.Flows = append(plotterSpec.Flows, flow)
@@ -345,7 +508,7 @@ func moduleAPIToService(api *datacatalog.ResourceDetails, scope fappv1.Capabilit
 		instanceName = managerUtils.CreateStepName(moduleName, assetID)
 	}
 	releaseName := managerUtils.GetReleaseName(appContext.Name, string(appContext.UID), instanceName)
-	releaseNamespace := environment.GetDefaultModulesNamespace()
+	releaseNamespace := modulesNamespace(appContext)
 
 	type Release struct {
 		Name      string `json:"Name"`
@@ -4,28 +4,39 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/gdexlab/go-render/render"
+	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/manager/controllers/utils"
 	connectors "fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/datapath"
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/tracing"
 	"fybrik.io/fybrik/pkg/validate"
 	"fybrik.io/fybrik/pkg/vault"
 )
 
 var PolicyManagerTaxonomy = environment.GetDataDir() + "/taxonomy/policymanager.json#/definitions/GetPolicyDecisionsResponse"
 
+// ActionTaxonomy defines the governance actions that may be returned by the policy manager
+var ActionTaxonomy = environment.GetDataDir() + "/taxonomy/taxonomy.json"
+
 func ConstructOpenAPIReq(datasetID string, resourceMetadata *datacatalog.ResourceMetadata, input *fapp.FybrikApplication,
 	operation *policymanager.RequestAction) *policymanager.GetPolicyDecisionsRequest {
 	return &policymanager.GetPolicyDecisionsRequest{
@@ -35,22 +46,45 @@ func ConstructOpenAPIReq(datasetID string, resourceMetadata *datacatalog.Resourc
 			ID:       taxonomy.AssetID(datasetID),
 			Metadata: resourceMetadata.DeepCopy(),
 		},
+		Identity: requestIdentity(input),
+		Time:     time.Now().UTC().Format(time.RFC3339),
 	}
 }
 
-func ValidatePolicyDecisionsResponse(response *policymanager.GetPolicyDecisionsResponse, taxonomyFile string) error {
-	var allErrs []*field.Error
+// requestIdentity returns the identity of the user that has created the application, as recorded by the webhook
+func requestIdentity(application *fapp.FybrikApplication) *policymanager.RequestIdentity {
+	subject := application.Annotations[fapp.RequesterAnnotation]
+	groups := application.Annotations[fapp.RequesterGroupsAnnotation]
+	if subject == "" && groups == "" {
+		return nil
+	}
+	identity := &policymanager.RequestIdentity{Subject: subject}
+	if groups != "" {
+		identity.Groups = strings.Split(groups, ",")
+	}
+	return identity
+}
 
-	// Convert GetAssetRequest Go struct to JSON
-	responseJSON, err := json.Marshal(response)
+func ValidatePolicyDecisionsResponse(response *policymanager.GetPolicyDecisionsResponse, taxonomyFile string) error {
+	// Validate the actions first, so that unknown actions and missing properties are reported explicitly
+	allErrs, err := validate.ValidatePolicyDecisionActions(response, ActionTaxonomy)
 	if err != nil {
 		return err
 	}
 
-	// Validate Fybrik module against taxonomy
-	allErrs, err = validate.TaxonomyCheck(responseJSON, taxonomyFile)
-	if err != nil {
-		return err
+	if len(allErrs) == 0 {
+		// Convert GetAssetRequest Go struct to JSON
+		// The actions that extend the taxonomy have been validated against their own schemas
+		responseJSON, err := json.Marshal(validate.WithoutActionExtensions(response))
+		if err != nil {
+			return err
+		}
+
+		// Validate Fybrik module against taxonomy
+		allErrs, err = validate.TaxonomyCheck(responseJSON, taxonomyFile)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Return any error
@@ -63,6 +97,114 @@ func ValidatePolicyDecisionsResponse(response *policymanager.GetPolicyDecisionsR
 		response.DecisionID, allErrs)
 }
 
+// PolicyDeniedError is returned when the policy manager denies the requested operation.
+// The error message is one of the Fybrik deny messages, while Reason explains the decision.
+// Reason is taken from the Deny action properties, or from the policy on which the decision was based if not provided.
+type PolicyDeniedError struct {
+	Message  string
+	Reason   string
+	PolicyID string
+}
+
+func (e *PolicyDeniedError) Error() string {
+	return e.Message
+}
+
+// denyMessage returns the message of a deny condition extended with the policy reason and identifier, if known
+func denyMessage(err error, cause string) string {
+	var deniedErr *PolicyDeniedError
+	if !errors.As(err, &deniedErr) {
+		return cause
+	}
+	msg := cause
+	if deniedErr.Reason != "" {
+		msg += ": " + deniedErr.Reason
+	}
+	if deniedErr.PolicyID != "" {
+		msg += " (policy " + deniedErr.PolicyID + ")"
+	}
+	return msg
+}
+
+// requestCredentials returns the path of the application secret that is sent to the policy manager, if any
+func requestCredentials(application *fapp.FybrikApplication) string {
+	if application.Spec.SecretRef == "" {
+		return ""
+	}
+	// creds field is constructed even if vault is not used for credential management
+	// in order to enable the connector to get the credentials directly from the secret
+	// using the secret information extracted from the creds string.
+	return vault.PathForReadingKubeSecret(application.Namespace, application.Spec.SecretRef)
+}
+
+// policyLookup is an operation on an asset whose governance actions are looked up
+type policyLookup struct {
+	datasetID string
+	metadata  *datacatalog.ResourceMetadata
+	operation *policymanager.RequestAction
+}
+
+// prefetchPolicyDecisions sends the given lookups to the policy manager in a single request if batches are supported,
+// and caches the valid decisions for the reconcile, so that LookupPolicyDecisions does not send a request per asset.
+// A batch holds a single request per asset, and skips the requests whose decisions are already cached.
+// A failure of the batch is not reported, since the decisions are requested per asset instead.
+func prefetchPolicyDecisions(lookups []policyLookup, policyManager connectors.PolicyManager, appContext ApplicationContext) {
+	if _, ok := policyManager.(connectors.BatchPolicyManager); !ok {
+		return
+	}
+	batch, ok := connectors.WithCorrelationID(policyManager, appContext.CorrelationID).(connectors.BatchPolicyManager)
+	if !ok {
+		// the policy manager that tags the requests with the correlation id does not support batches
+		return
+	}
+	requests := []*policymanager.GetPolicyDecisionsRequest{}
+	batched := map[string]bool{}
+	for _, lookup := range lookups {
+		if batched[lookup.datasetID] {
+			continue
+		}
+		if _, found := appContext.PolicyDecisions.get(lookup.datasetID, lookup.operation); found {
+			continue
+		}
+		req := ConstructOpenAPIReq(lookup.datasetID, lookup.metadata, appContext.Application, lookup.operation)
+		if appContext.AsyncPolicyDecisions.has(appContext.Application, req) {
+			continue
+		}
+		batched[lookup.datasetID] = true
+		requests = append(requests, req)
+	}
+	if len(requests) == 0 {
+		return
+	}
+	appContext.Log.Debug().Int("requests", len(requests)).Msg("requesting the policy decisions in a batch")
+	ctx, span := tracing.Tracer().Start(appContext.traceContext(), "PolicyManager.GetPoliciesDecisionsBatch",
+		trace.WithAttributes(tracing.RequestsKey.Int(len(requests))))
+	responses, err := batch.GetPoliciesDecisionsBatch(ctx, requests, requestCredentials(appContext.Application))
+	tracing.End(span, tracing.OutcomeSuccess, err)
+	if errors.Is(err, connectors.ErrBatchNotSupported) {
+		appContext.Log.Debug().Msg("the policy decisions are requested per asset, since batches are not supported")
+		return
+	}
+	if err != nil {
+		appContext.Log.Warn().Err(err).Msg("the policy decisions of the batch are requested per asset")
+		return
+	}
+	for _, req := range requests {
+		datasetID := string(req.Resource.ID)
+		resp, found := responses[req.Resource.ID]
+		if !found {
+			continue
+		}
+		if err := ValidatePolicyDecisionsResponse(resp, PolicyManagerTaxonomy); err != nil {
+			appContext.Log.Warn().Err(err).Str(logging.DATASETID, datasetID).Msg("error while validating policy manager response")
+			continue
+		}
+		appContext.Log.Info().Str(logging.DATASETID, datasetID).Msgf("response from policy manager: %s", render.AsCode(resp))
+		appContext.PolicyDecisions.add(datasetID, &req.Action, resp)
+		appContext.AsyncPolicyDecisions.add(appContext.Application, req, resp)
+	}
+}
+
 // LookupPolicyDecisions provides a list of governance actions for the given dataset and the given operation
 // Input:
 // - asset ID
@@ -73,53 +215,354 @@ func ValidatePolicyDecisionsResponse(response *policymanager.GetPolicyDecisionsR
 // Output:
 // - a list of governance actions (upon a successful response)
 // - a message from the connector (upon a successful response)
-// - an error from the connector or an error formulated by Fybrik in case of Deny
+// - an error from the connector or an error formulated by Fybrik in case of Deny or of a missing Allow if denied by default
+// The lookup is traced by a span holding the decision identifier and the outcome, and the reason of a denied access.
 func LookupPolicyDecisions(datasetID string, resourceMetadata *datacatalog.ResourceMetadata,
 	policyManager connectors.PolicyManager, appContext ApplicationContext,
 	op *policymanager.RequestAction) ([]taxonomy.Action, string, error) {
+	ctx, span := tracing.Tracer().Start(appContext.traceContext(), "PolicyManager.LookupPolicyDecisions",
+		trace.WithAttributes(tracing.DatasetIDKey.String(datasetID), tracing.ActionTypeKey.String(string(op.ActionType))))
+	_, cached := appContext.PolicyDecisions.get(datasetID, op)
+	span.SetAttributes(tracing.CachedKey.Bool(cached))
+	actions, message, err := lookupPolicyDecisions(ctx, datasetID, resourceMetadata, policyManager, appContext, op)
+	if resp, found := appContext.PolicyDecisions.get(datasetID, op); found {
+		span.SetAttributes(tracing.DecisionIDKey.String(resp.DecisionID))
+	}
+	// a denied access is a valid decision rather than a failure of the lookup
+	var deniedErr *PolicyDeniedError
+	if errors.As(err, &deniedErr) {
+		span.SetAttributes(tracing.DecisionReasonKey.String(deniedErr.Reason))
+		tracing.End(span, tracing.OutcomeDenied, nil)
+	} else {
+		tracing.End(span, tracing.OutcomeAllowed, err)
+	}
+	return actions, message, err
+}
+
+// lookupPolicyDecisions implements LookupPolicyDecisions, the policy manager request is canceled once the context is done
+func lookupPolicyDecisions(ctx context.Context, datasetID string, resourceMetadata *datacatalog.ResourceMetadata,
+	policyManager connectors.PolicyManager, appContext ApplicationContext,
+	op *policymanager.RequestAction) ([]taxonomy.Action, string, error) {
+	policyManager = connectors.WithCorrelationID(policyManager, appContext.CorrelationID)
 	// call external policy manager to get governance instructions for this operation
 	openapiReq := ConstructOpenAPIReq(datasetID, resourceMetadata, appContext.Application, op)
 	output := render.AsCode(openapiReq)
 	appContext.Log.Debug().Str(logging.DATASETID, datasetID).Msgf("request: %s", output)
 
-	var creds string
-	if appContext.Application.Spec.SecretRef != "" {
-		// creds field is constructed even if vault is not used for credential management
-		// in order to enable the connector to get the credentials directly from the secret
-		// using the secret information extracted from the creds string.
-		creds = vault.PathForReadingKubeSecret(appContext.Application.Namespace, appContext.Application.Spec.SecretRef)
-	}
+	creds := requestCredentials(appContext.Application)
 
-	openapiResp, err := policyManager.GetPoliciesDecisions(openapiReq, creds)
 	var actions []taxonomy.Action
-	if err != nil {
-		return actions, "", err
-	}
-
-	err = ValidatePolicyDecisionsResponse(openapiResp, PolicyManagerTaxonomy)
-	if err != nil {
-		appContext.Log.Error().Err(err).Str(logging.DATASETID, datasetID).Msg("error while validating policy manager response")
-		return actions, "", errors.New("Validation error: " + err.Error())
+	openapiResp, found := appContext.PolicyDecisions.get(datasetID, op)
+	if found {
+		appContext.Log.Debug().Str(logging.DATASETID, datasetID).Msg("using a policy manager response from the reconcile cache")
+	} else {
+		fetch := func(ctx context.Context) (*policymanager.GetPolicyDecisionsResponse, error) {
+			resp, err := policyManager.GetPoliciesDecisions(ctx, openapiReq, creds)
+			if err != nil {
+				return nil, err
+			}
+			err = ValidatePolicyDecisionsResponse(resp, PolicyManagerTaxonomy)
+			if err != nil {
+				appContext.Log.Error().Err(err).Str(logging.DATASETID, datasetID).Msg("error while validating policy manager response")
+				return nil, errors.New("Validation error: " + err.Error())
+			}
+			appContext.Log.Info().Str(logging.DATASETID, datasetID).Msgf("response from policy manager: %s", render.AsCode(resp))
+			return resp, nil
+		}
+		var err error
+		openapiResp, err = appContext.AsyncPolicyDecisions.get(ctx, appContext.Application, openapiReq, fetch, appContext.Log)
+		if err != nil {
+			appContext.addUnevaluated(datasetID)
+			return actions, "", err
+		}
+		appContext.PolicyDecisions.add(datasetID, op, openapiResp)
 	}
 
-	output = render.AsCode(openapiResp)
-	appContext.Log.Info().Str(logging.DATASETID, datasetID).Msgf("response from policy manager: %s", output)
+	recordDecisionID(appContext, datasetID, openapiResp.DecisionID)
 
+	var message string
+	switch openapiReq.Action.ActionType {
+	case taxonomy.ReadFlow:
+		message = ReadAccessDenied
+	case taxonomy.WriteFlow:
+		message = WriteNotAllowed
+	}
+	now := time.Now()
 	result := openapiResp.Result
+	applicable := []policymanager.ResultItem{}
 	for i := 0; i < len(result); i++ {
-		if utils.IsDenied(result[i].Action.Name) {
-			var message string
-			switch openapiReq.Action.ActionType {
-			case taxonomy.ReadFlow:
-				message = ReadAccessDenied
-			case taxonomy.WriteFlow:
-				message = WriteNotAllowed
-			}
-			// access is denied - return the connector message that may help to understand the reason
-			return actions, openapiResp.Message, errors.New(message)
+		applies, boundary, err := policyValidity(&result[i], now)
+		if err != nil {
+			return actions, "", err
+		}
+		if boundary != nil {
+			recordPolicyReevaluation(appContext, datasetID, *boundary)
+		}
+		if !applies {
+			// the action is not valid at this time
+			continue
+		}
+		if !taxonomy.IsDestinationAllowed(&result[i].Action, op.Destination) {
+			// the action allows the access to other destinations only
+			applicable = append(applicable, destinationDenial(&result[i], op.Destination))
+			continue
 		}
-		actions = append(actions, result[i].Action)
+		applicable = append(applicable, result[i])
+	}
+	actions, allowed, denied := resolvePolicyActions(applicable, appContext.ActionPrecedence)
+	if denied != nil {
+		deny := taxonomy.DenyAction{}
+		if err := taxonomy.DecodeActionProperties(&denied.Action, &deny); err != nil {
+			appContext.Log.Warn().Err(err).Str(logging.DATASETID, datasetID).Msg("invalid properties of the Deny action")
+		}
+		if deny.Reason == "" {
+			deny.Reason = denied.Policy
+		}
+		// access is denied - return the connector message that may help to understand the reason
+		return actions, openapiResp.Message, &PolicyDeniedError{Message: message, Reason: deny.Reason, PolicyID: deny.PolicyID}
+	}
+	if appContext.DefaultDeny && !allowed {
+		// no policy allows the access, which is denied by default
+		return actions, openapiResp.Message, &PolicyDeniedError{Message: message, Reason: DefaultDenyReason}
 	}
 	// return the action list and the connector message with additional information
 	return actions, openapiResp.Message, nil
 }
+
+// destinationDenial returns a Deny action replacing an action whose allowed destinations do not include the destination,
+// so that the denial takes precedence over the actions of other policies the same as a Deny returned by the policy manager
+func destinationDenial(item *policymanager.ResultItem, destination string) policymanager.ResultItem {
+	allow := taxonomy.AllowAction{}
+	// the policy id is omitted if the action properties are not those of an Allow action
+	_ = taxonomy.DecodeActionProperties(&item.Action, &allow)
+	reason := fmt.Sprintf("destination %s is not permitted", destination)
+	if destination == "" {
+		reason = "the destination is unknown and can not be permitted"
+	}
+	return policymanager.ResultItem{
+		Action:   taxonomy.NewDenyAction(reason, allow.PolicyID),
+		Policy:   item.Policy,
+		Validity: item.Validity,
+	}
+}
+
+// ActionPrecedence lists the names of the governance actions from the highest to the lowest precedence.
+// It resolves the conflicts of an explicit Allow action with the other actions, and of a Deny action with the actions
+// that restrict the data instead of denying the access:
+// an Allow action overrides the actions ranked below it, e.g., a Deny action ranked below Allow does not deny
+// the access if another policy allows it, and a Deny action is overridden by an applied action ranked above it,
+// e.g., a Deny action ranked below RedactAction does not deny the access if another policy redacts the data.
+// The actions that are not listed are never overridden, and the actions that do not conflict, e.g., a filter
+// and a redaction, are both applied.
+type ActionPrecedence []taxonomy.ActionName
+
+// DefaultActionPrecedence lets the Deny action take precedence over all the other actions,
+// and the Filter and Redact actions take precedence over an explicit Allow action
+var DefaultActionPrecedence = ActionPrecedence{taxonomy.DenyActionName, taxonomy.FilterActionName,
+	taxonomy.RedactActionName, taxonomy.AllowActionName}
+
+// rank returns the position of the action in the precedence table, or -1 if the action is not listed
+func (p ActionPrecedence) rank(name taxonomy.ActionName) int {
+	for i, listed := range p {
+		if listed == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// overrides returns true if the given action ranks above the other action, which is overridden when they conflict
+func (p ActionPrecedence) overrides(name, other taxonomy.ActionName) bool {
+	if p == nil {
+		p = DefaultActionPrecedence
+	}
+	rank := p.rank(name)
+	return rank >= 0 && p.rank(other) > rank
+}
+
+// ActionPrecedenceFromEnvironment returns the precedence of the governance actions,
+// specified as a comma separated list of action names from the highest to the lowest precedence, e.g., "Deny,FilterAction,Allow,RedactAction".
+func ActionPrecedenceFromEnvironment() (ActionPrecedence, error) {
+	value := strings.TrimSpace(os.Getenv(environment.ActionPrecedenceKey))
+	if value == "" {
+		return DefaultActionPrecedence, nil
+	}
+	precedence := ActionPrecedence{}
+	for _, item := range strings.Split(value, ",") {
+		name := taxonomy.ActionName(strings.TrimSpace(item))
+		if name == "" || precedence.rank(name) >= 0 {
+			return nil, errors.Errorf("invalid action precedence %q in %s, expected a list of distinct action names",
+				value, environment.ActionPrecedenceKey)
+		}
+		precedence = append(precedence, name)
+	}
+	return precedence, nil
+}
+
+// resolvePolicyActions consolidates the applicable policy decisions according to the precedence of their actions.
+// It returns the actions to be applied on the data, whether the access is explicitly allowed,
+// and the decision denying the access, if any.
+func resolvePolicyActions(items []policymanager.ResultItem,
+	precedence ActionPrecedence) ([]taxonomy.Action, bool, *policymanager.ResultItem) {
+	allowed := false
+	for i := range items {
+		if utils.IsAllowed(items[i].Action.Name) {
+			allowed = true
+		}
+	}
+	var actions []taxonomy.Action
+	var denials []*policymanager.ResultItem
+	for i := range items {
+		name := items[i].Action.Name
+		if utils.IsAllowed(name) {
+			// an explicit allow is not a governance action to be applied on the data
+			continue
+		}
+		if allowed && precedence.overrides(taxonomy.AllowActionName, name) {
+			continue
+		}
+		if utils.IsDenied(name) {
+			denials = append(denials, &items[i])
+			continue
+		}
+		actions = append(actions, items[i].Action)
+	}
+	for _, denial := range denials {
+		overridden := false
+		for i := range actions {
+			if precedence.overrides(actions[i].Name, denial.Action.Name) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			return actions, allowed, denial
+		}
+	}
+	return actions, allowed, nil
+}
+
+// policyValidity returns whether a policy decision applies at the given time,
+// and the next time at which this changes according to its validity window, if any
+func policyValidity(item *policymanager.ResultItem, now time.Time) (bool, *time.Time, error) {
+	if item.Validity == nil {
+		return true, nil, nil
+	}
+	parse := func(value string) (*time.Time, error) {
+		if value == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid validity window of the %s action", item.Action.Name)
+		}
+		return &t, nil
+	}
+	notBefore, err := parse(item.Validity.NotBefore)
+	if err != nil {
+		return false, nil, err
+	}
+	notAfter, err := parse(item.Validity.NotAfter)
+	if err != nil {
+		return false, nil, err
+	}
+	switch {
+	case notBefore != nil && now.Before(*notBefore):
+		return false, notBefore, nil
+	case notAfter != nil && !now.Before(*notAfter):
+		return false, nil, nil
+	}
+	return true, notAfter, nil
+}
+
+// recordPolicyReevaluation stores the earliest time at which the policy decisions of an asset have to be evaluated again
+func recordPolicyReevaluation(appContext ApplicationContext, datasetID string, at time.Time) {
+	state, found := appContext.Application.Status.AssetStates[datasetID]
+	if !found {
+		return
+	}
+	if state.PolicyReevaluation != nil && !at.Before(state.PolicyReevaluation.Time) {
+		return
+	}
+	reevaluation := metav1.NewTime(at)
+	state.PolicyReevaluation = &reevaluation
+	appContext.Application.Status.AssetStates[datasetID] = state
+}
+
+// policyReevaluationInterval returns the time after which the policy decisions of the granted assets are evaluated again,
+// regardless of spec changes, or 0 if they are not evaluated periodically
+func policyReevaluationInterval() time.Duration {
+	return requeueInterval(environment.PolicyReevaluationIntervalKey, 0)
+}
+
+// schedulePolicyReevaluation requests to evaluate the policy decisions of the given data paths again after the configured interval,
+// so that the access is revoked if a policy has changed to deny it
+func schedulePolicyReevaluation(appContext ApplicationContext, requirements []datapath.DataInfo) {
+	interval := policyReevaluationInterval()
+	if interval <= 0 {
+		return
+	}
+	at := time.Now().Add(interval)
+	for i := range requirements {
+		recordPolicyReevaluation(appContext, requirements[i].Context.DataSetID, at)
+	}
+}
+
+// policyReevaluation returns the earliest time at which the policy decisions of the application assets
+// have to be evaluated again, or nil if the decisions do not depend on time
+func policyReevaluation(status *fapp.FybrikApplicationStatus) *time.Time {
+	var earliest *time.Time
+	for assetID := range status.AssetStates {
+		reevaluation := status.AssetStates[assetID].PolicyReevaluation
+		if reevaluation != nil && (earliest == nil || reevaluation.Time.Before(*earliest)) {
+			earliest = &reevaluation.Time
+		}
+	}
+	return earliest
+}
+
+// policyWindowElapsed returns true if the policy decisions of some asset have to be evaluated again
+func policyWindowElapsed(status *fapp.FybrikApplicationStatus) bool {
+	reevaluation := policyReevaluation(status)
+	return reevaluation != nil && !time.Now().Before(*reevaluation)
+}
+
+// addUnevaluated records that the policy decisions of the given asset could not be evaluated
+func (c *ApplicationContext) addUnevaluated(datasetID string) {
+	if c.Unevaluated != nil {
+		c.Unevaluated[datasetID] = true
+	}
+}
+
+// recordDecisionID stores the identifier of a policy decision in the asset state for audit purposes
+func recordDecisionID(appContext ApplicationContext, datasetID, decisionID string) {
+	if decisionID == "" {
+		return
+	}
+	state, found := appContext.Application.Status.AssetStates[datasetID]
+	if !found {
+		return
+	}
+	for _, id := range state.DecisionIDs {
+		if id == decisionID {
+			return
+		}
+	}
+	state.DecisionIDs = append(state.DecisionIDs, decisionID)
+	appContext.Application.Status.AssetStates[datasetID] = state
+}
+
+// policyDecisionsAnnotation returns the identifiers of the policy decisions per asset, serialized for the Plotter annotations
+func policyDecisionsAnnotation(application *fapp.FybrikApplication) (string, error) {
+	decisions := map[string][]string{}
+	for assetID := range application.Status.AssetStates {
+		if ids := application.Status.AssetStates[assetID].DecisionIDs; len(ids) > 0 {
+			decisions[assetID] = ids
+		}
+	}
+	bytes, err := json.Marshal(decisions)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
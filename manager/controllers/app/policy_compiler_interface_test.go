@@ -0,0 +1,97 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+func TestResolvePolicyActions(t *testing.T) {
+	t.Parallel()
+	allow := policymanager.ResultItem{Policy: "allow", Action: taxonomy.NewAllowAction("allow-analysts")}
+	deny := policymanager.ResultItem{Policy: "deny", Action: taxonomy.NewDenyAction("restricted", "deny-restricted")}
+	redact := policymanager.ResultItem{Policy: "redact", Action: taxonomy.NewRedactAction("SSN")}
+	filter := policymanager.ResultItem{Policy: "filter", Action: taxonomy.NewFilterAction("country = 'UK'")}
+	hash := policymanager.ResultItem{Policy: "hash", Action: taxonomy.Action{Name: taxonomy.HashActionName}}
+	custom := ActionPrecedence{taxonomy.FilterActionName, taxonomy.AllowActionName, taxonomy.RedactActionName, taxonomy.DenyActionName}
+	// the restrictive actions are reordered, so that a redaction is enough to grant the access
+	restrictive := ActionPrecedence{taxonomy.RedactActionName, taxonomy.DenyActionName, taxonomy.FilterActionName,
+		taxonomy.AllowActionName}
+
+	tests := []struct {
+		name  string
+		items []policymanager.ResultItem
+		// the default precedence is used if not set
+		precedence ActionPrecedence
+		actions    []taxonomy.Action
+		allowed    bool
+		denied     bool
+	}{
+		{name: "no decisions"},
+		{name: "deny over allow by default", items: []policymanager.ResultItem{allow, deny}, allowed: true, denied: true},
+		{name: "restrictions over allow by default", items: []policymanager.ResultItem{redact, allow, filter},
+			actions: []taxonomy.Action{redact.Action, filter.Action}, allowed: true},
+		{name: "restrictions without allow", items: []policymanager.ResultItem{redact, filter, hash},
+			actions: []taxonomy.Action{redact.Action, filter.Action, hash.Action}},
+		{name: "allow over deny and redact when customized", items: []policymanager.ResultItem{deny, redact, filter, allow},
+			precedence: custom, actions: []taxonomy.Action{filter.Action}, allowed: true},
+		{name: "deny over restrictions by default", items: []policymanager.ResultItem{redact, deny, filter},
+			actions: []taxonomy.Action{redact.Action, filter.Action}, denied: true},
+		{name: "redact over deny when customized", items: []policymanager.ResultItem{redact, deny},
+			precedence: custom, actions: []taxonomy.Action{redact.Action}},
+		{name: "deny over a filter ranked below it", items: []policymanager.ResultItem{filter, deny},
+			precedence: restrictive, actions: []taxonomy.Action{filter.Action}, denied: true},
+		{name: "redact ranked above deny grants access", items: []policymanager.ResultItem{deny, redact},
+			precedence: restrictive, actions: []taxonomy.Action{redact.Action}},
+		{name: "restrictions are combined when deny is overridden", items: []policymanager.ResultItem{filter, deny, redact},
+			precedence: restrictive, actions: []taxonomy.Action{filter.Action, redact.Action}},
+		{name: "unlisted actions do not override deny", items: []policymanager.ResultItem{hash, deny},
+			precedence: restrictive, actions: []taxonomy.Action{hash.Action}, denied: true},
+		{name: "unlisted actions are not overridden", items: []policymanager.ResultItem{hash, allow, redact},
+			precedence: custom, actions: []taxonomy.Action{hash.Action}, allowed: true},
+		{name: "allow overrides nothing when not listed", items: []policymanager.ResultItem{allow, redact},
+			precedence: ActionPrecedence{taxonomy.DenyActionName, taxonomy.RedactActionName},
+			actions:    []taxonomy.Action{redact.Action}, allowed: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			actions, allowed, denied := resolvePolicyActions(tt.items, tt.precedence)
+			g.Expect(actions).To(gomega.Equal(tt.actions))
+			g.Expect(allowed).To(gomega.Equal(tt.allowed))
+			if tt.denied {
+				g.Expect(denied).NotTo(gomega.BeNil())
+				g.Expect(denied.Policy).To(gomega.Equal(deny.Policy))
+			} else {
+				g.Expect(denied).To(gomega.BeNil())
+			}
+		})
+	}
+}
+
+func TestActionPrecedenceFromEnvironment(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	t.Setenv(environment.ActionPrecedenceKey, "")
+	precedence, err := ActionPrecedenceFromEnvironment()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(precedence).To(gomega.Equal(DefaultActionPrecedence))
+
+	t.Setenv(environment.ActionPrecedenceKey, "Deny, FilterAction, Allow, RedactAction")
+	precedence, err = ActionPrecedenceFromEnvironment()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(precedence).To(gomega.Equal(ActionPrecedence{taxonomy.DenyActionName, taxonomy.FilterActionName,
+		taxonomy.AllowActionName, taxonomy.RedactActionName}))
+
+	t.Setenv(environment.ActionPrecedenceKey, "Deny,Allow,Deny")
+	_, err = ActionPrecedenceFromEnvironment()
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,251 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/rs/zerolog"
+	"k8s.io/apimachinery/pkg/types"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// policyDecisionKey identifies a policy manager request
+type policyDecisionKey struct {
+	datasetID          string
+	actionType         taxonomy.DataFlow
+	destination        string
+	processingLocation taxonomy.ProcessingLocation
+}
+
+// PolicyDecisionCache stores the responses of the policy manager during a single reconcile pass,
+// so that identical requests for the same asset are sent only once.
+// A new cache is created for every reconcile, thus decisions never leak across reconciles.
+// A nil cache is valid and does not store anything.
+type PolicyDecisionCache struct {
+	decisions map[policyDecisionKey]*policymanager.GetPolicyDecisionsResponse
+}
+
+// NewPolicyDecisionCache creates an empty cache of policy decisions
+func NewPolicyDecisionCache() *PolicyDecisionCache {
+	return &PolicyDecisionCache{decisions: map[policyDecisionKey]*policymanager.GetPolicyDecisionsResponse{}}
+}
+
+func newPolicyDecisionKey(datasetID string, op *policymanager.RequestAction) policyDecisionKey {
+	return policyDecisionKey{
+		datasetID:          datasetID,
+		actionType:         op.ActionType,
+		destination:        op.Destination,
+		processingLocation: op.ProcessingLocation,
+	}
+}
+
+// get returns a cached response for the given asset and operation
+func (c *PolicyDecisionCache) get(datasetID string, op *policymanager.RequestAction) (*policymanager.GetPolicyDecisionsResponse, bool) {
+	if c == nil {
+		return nil, false
+	}
+	resp, found := c.decisions[newPolicyDecisionKey(datasetID, op)]
+	return resp, found
+}
+
+// add stores a valid response of the policy manager for the given asset and operation
+func (c *PolicyDecisionCache) add(datasetID string, op *policymanager.RequestAction, resp *policymanager.GetPolicyDecisionsResponse) {
+	if c == nil {
+		return
+	}
+	c.decisions[newPolicyDecisionKey(datasetID, op)] = resp
+}
+
+// fetchPolicyDecisions sends a request to the policy manager, which is canceled once the given context is done,
+// and validates the response
+type fetchPolicyDecisions func(ctx context.Context) (*policymanager.GetPolicyDecisionsResponse, error)
+
+// cachedDecision is a response of the policy manager kept across reconciles
+type cachedDecision struct {
+	response   *policymanager.GetPolicyDecisionsResponse
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// applicationDecisions holds the cached decisions of an application generation, by request signature
+type applicationDecisions struct {
+	generation int64
+	decisions  map[string]*cachedDecision
+}
+
+// AsyncPolicyDecisionCache keeps the responses of the policy manager across reconciles for the given TTL.
+// The decisions are keyed on the signature of the request, and are invalidated when the application spec changes,
+// i.e., when its generation is bumped.
+// A decision older than the TTL is still used by the reconcile while it is refreshed in the background,
+// and is evicted if the refresh fails, so that the next reconcile sends the request again.
+// Refreshed decisions are used from the next reconcile on.
+// A nil cache is valid and does not store anything.
+type AsyncPolicyDecisionCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mutex        sync.Mutex
+	applications map[types.UID]*applicationDecisions
+	// refreshes tracks the background refreshes in progress
+	refreshes sync.WaitGroup
+}
+
+// policyDecisionsCacheTTL returns the time the policy manager responses are cached across reconciles, 0 if they are not cached
+func policyDecisionsCacheTTL() time.Duration {
+	return time.Duration(environment.GetEnvAsInt(environment.PolicyDecisionsCacheTTLKey, 0)) * time.Millisecond
+}
+
+// NewAsyncPolicyDecisionCache creates an empty cache of policy decisions with the given TTL,
+// or returns nil if the TTL is not positive, i.e., if the decisions should not be cached across reconciles
+func NewAsyncPolicyDecisionCache(ttl time.Duration) *AsyncPolicyDecisionCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &AsyncPolicyDecisionCache{ttl: ttl, now: time.Now, applications: map[types.UID]*applicationDecisions{}}
+}
+
+// requestSignature identifies the content of a policy manager request of an application.
+// The time of the request is ignored, since the validity windows of the cached actions are checked on every lookup.
+// The policy manager selected by the application is part of the signature, since the application may select another
+// policy manager without changing its generation.
+func requestSignature(application *fappv1.FybrikApplication, req *policymanager.GetPolicyDecisionsRequest) (string, error) {
+	timeless := *req
+	timeless.Time = ""
+	bytes, err := json.Marshal(&timeless)
+	if err != nil {
+		return "", errors.Wrap(err, "could not serialize the policy manager request")
+	}
+	sum := sha256.Sum256(bytes)
+	return policyManagerName(application) + "/" + hex.EncodeToString(sum[:]), nil
+}
+
+// get returns the cached response to the request of the given application, or fetches it with the given context if none is cached.
+// An expired response is returned while it is fetched again in the background, regardless of the context of the caller.
+func (c *AsyncPolicyDecisionCache) get(ctx context.Context, application *fappv1.FybrikApplication,
+	req *policymanager.GetPolicyDecisionsRequest, fetch fetchPolicyDecisions,
+	log *zerolog.Logger) (*policymanager.GetPolicyDecisionsResponse, error) {
+	if c == nil {
+		return fetch(ctx)
+	}
+	signature, err := requestSignature(application, req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	decisions := c.decisionsOf(application)
+	decision, found := decisions.decisions[signature]
+	if found {
+		expired := c.now().Sub(decision.fetchedAt) > c.ttl
+		if expired && !decision.refreshing {
+			decision.refreshing = true
+			c.refreshes.Add(1)
+			go c.refresh(application.UID, decisions.generation, signature, fetch, log)
+		}
+		c.mutex.Unlock()
+		log.Debug().Bool("expired", expired).Msg("using a policy manager response from the decision cache")
+		return decision.response, nil
+	}
+	c.mutex.Unlock()
+
+	response, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.store(application.UID, decisions.generation, signature, response)
+	return response, nil
+}
+
+// has returns true if a response to the request of the given application is cached, even if it has expired
+func (c *AsyncPolicyDecisionCache) has(application *fappv1.FybrikApplication, req *policymanager.GetPolicyDecisionsRequest) bool {
+	if c == nil {
+		return false
+	}
+	signature, err := requestSignature(application, req)
+	if err != nil {
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, found := c.decisionsOf(application).decisions[signature]
+	return found
+}
+
+// add stores a response to the request of the given application that has been fetched outside of the cache, e.g., in a batch
+func (c *AsyncPolicyDecisionCache) add(application *fappv1.FybrikApplication, req *policymanager.GetPolicyDecisionsRequest,
+	response *policymanager.GetPolicyDecisionsResponse) {
+	if c == nil {
+		return
+	}
+	signature, err := requestSignature(application, req)
+	if err != nil {
+		return
+	}
+	c.mutex.Lock()
+	generation := c.decisionsOf(application).generation
+	c.mutex.Unlock()
+	c.store(application.UID, generation, signature, response)
+}
+
+// decisionsOf returns the decisions of the current generation of the application, dropping the decisions of older generations.
+// The cache must be locked.
+func (c *AsyncPolicyDecisionCache) decisionsOf(application *fappv1.FybrikApplication) *applicationDecisions {
+	decisions, found := c.applications[application.UID]
+	if !found || decisions.generation != application.Generation {
+		decisions = &applicationDecisions{generation: application.Generation, decisions: map[string]*cachedDecision{}}
+		c.applications[application.UID] = decisions
+	}
+	return decisions
+}
+
+// store adds a response unless the generation of the application has changed since the request has been sent
+func (c *AsyncPolicyDecisionCache) store(uid types.UID, generation int64, signature string,
+	response *policymanager.GetPolicyDecisionsResponse) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	decisions, found := c.applications[uid]
+	if !found || decisions.generation != generation {
+		return
+	}
+	decisions.decisions[signature] = &cachedDecision{response: response, fetchedAt: c.now()}
+}
+
+// refresh fetches an expired response again, evicting it on failure
+func (c *AsyncPolicyDecisionCache) refresh(uid types.UID, generation int64, signature string, fetch fetchPolicyDecisions,
+	log *zerolog.Logger) {
+	defer c.refreshes.Done()
+	// the refresh outlives the reconcile that started it
+	response, err := fetch(context.Background())
+	if err == nil {
+		c.store(uid, generation, signature, response)
+		return
+	}
+	log.Warn().Err(err).Msg("could not refresh a policy manager response, the expired response is evicted")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if decisions, found := c.applications[uid]; found && decisions.generation == generation {
+		delete(decisions.decisions, signature)
+	}
+}
+
+// forget drops the decisions of an application, e.g., when it is deleted
+func (c *AsyncPolicyDecisionCache) forget(uid types.UID) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.applications, uid)
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// countingPolicyManager counts the requests sent to the policy manager, failing them if err is set.
+// The requests are counted atomically since the expired decisions are refreshed in the background.
+type countingPolicyManager struct {
+	mockup.MockPolicyManager
+	calls atomic.Int32
+	err   error
+}
+
+func (m *countingPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	m.calls.Add(1)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.MockPolicyManager.GetPoliciesDecisions(ctx, in, creds)
+}
+
+// lookupReadWriteDecisions sends the requests issued for an application that reads and writes the same asset,
+// while two storage accounts are available for implicit copies
+func lookupReadWriteDecisions(policyManager *countingPolicyManager, appContext ApplicationContext) error {
+	datasetID := "s3/allow-dataset"
+	metadata := &datacatalog.ResourceMetadata{Geography: "theshire"}
+	storageWrites := []policymanager.RequestAction{
+		{ActionType: taxonomy.WriteFlow, Destination: "theshire", ProcessingLocation: "theshire"},
+		{ActionType: taxonomy.WriteFlow, Destination: "neverland", ProcessingLocation: "neverland"},
+	}
+	flows := []policymanager.RequestAction{
+		// the workload runs in neverland, the asset is located in theshire
+		{ActionType: taxonomy.ReadFlow, Destination: "neverland", ProcessingLocation: "neverland"},
+		{ActionType: taxonomy.WriteFlow, Destination: "theshire", ProcessingLocation: "neverland"},
+	}
+	for i := range flows {
+		ops := append([]policymanager.RequestAction{flows[i]}, storageWrites...)
+		for j := range ops {
+			if _, _, err := LookupPolicyDecisions(datasetID, metadata, policyManager, appContext, &ops[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestPolicyDecisionCache(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	log := logging.LogInit(logging.CONTROLLER, "test")
+	application := &fappv1.FybrikApplication{}
+
+	policyManager := &countingPolicyManager{}
+	appContext := ApplicationContext{Log: &log, Application: application}
+	g.Expect(lookupReadWriteDecisions(policyManager, appContext)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(6)))
+
+	policyManager = &countingPolicyManager{}
+	appContext.PolicyDecisions = NewPolicyDecisionCache()
+	g.Expect(lookupReadWriteDecisions(policyManager, appContext)).To(gomega.Succeed())
+	// the storage account requests of the write flow are identical to the ones of the read flow
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(4)))
+}
+
+func TestAsyncPolicyDecisionCache(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	log := logging.LogInit(logging.CONTROLLER, "test")
+	application := &fappv1.FybrikApplication{ObjectMeta: metav1.ObjectMeta{UID: "1", Generation: 1}}
+	g.Expect(NewAsyncPolicyDecisionCache(0)).To(gomega.BeNil())
+
+	now := time.Now()
+	cache := NewAsyncPolicyDecisionCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	reconcile := func(policyManager *countingPolicyManager) error {
+		appContext := ApplicationContext{Log: &log, Application: application, PolicyDecisions: NewPolicyDecisionCache(),
+			AsyncPolicyDecisions: cache}
+		err := lookupReadWriteDecisions(policyManager, appContext)
+		// the background refreshes started by the reconcile are complete before the requests are counted
+		cache.refreshes.Wait()
+		return err
+	}
+
+	policyManager := &countingPolicyManager{}
+	g.Expect(reconcile(policyManager)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(4)))
+
+	// the responses are used by the next reconciles
+	g.Expect(reconcile(policyManager)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(4)))
+
+	// expired responses are used while they are refreshed in the background
+	now = now.Add(2 * time.Minute)
+	policyManager.err = errors.New("policy manager is unavailable")
+	g.Expect(reconcile(policyManager)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(8)))
+
+	// the responses that could not be refreshed are evicted
+	err := reconcile(policyManager)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(9)))
+	policyManager.err = nil
+	g.Expect(reconcile(policyManager)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(13)))
+
+	// a change of the application spec invalidates the responses
+	application.Generation = 2
+	g.Expect(reconcile(policyManager)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(17)))
+
+	cache.forget(application.UID)
+	g.Expect(reconcile(policyManager)).To(gomega.Succeed())
+	g.Expect(policyManager.calls.Load()).To(gomega.Equal(int32(21)))
+}
+
+func BenchmarkPolicyDecisionCache(b *testing.B) {
+	log := logging.LogInit(logging.CONTROLLER, "test").Level(zerolog.Disabled)
+	application := &fappv1.FybrikApplication{}
+	for _, useCache := range []bool{false, true} {
+		name := "without-cache"
+		if useCache {
+			name = "with-cache"
+		}
+		b.Run(name, func(b *testing.B) {
+			policyManager := &countingPolicyManager{}
+			for i := 0; i < b.N; i++ {
+				// a new cache for each reconcile pass
+				appContext := ApplicationContext{Log: &log, Application: application}
+				if useCache {
+					appContext.PolicyDecisions = NewPolicyDecisionCache()
+				}
+				if err := lookupReadWriteDecisions(policyManager, appContext); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(policyManager.calls.Load())/float64(b.N), "calls/op")
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+// policyManagerName returns the name of the policy manager selected by the application, or an empty string
+// if the governance actions of the application are evaluated by the default policy manager
+func policyManagerName(application *fappv1.FybrikApplication) string {
+	return application.GetAnnotations()[utils.PolicyManagerAnnotation]
+}
+
+// selectPolicyManager sets the policy manager that evaluates the governance actions of the application,
+// and records its name in the application status.
+// It returns false if the application selects a policy manager that is not configured, and reports it
+// in the application status, so that the governance actions are not evaluated by another policy manager.
+func (r *FybrikApplicationReconciler) selectPolicyManager(applicationContext *ApplicationContext) bool {
+	name := policyManagerName(applicationContext.Application)
+	if name == "" {
+		applicationContext.PolicyManager = r.PolicyManager
+		applicationContext.Application.Status.PolicyManager = ""
+		return true
+	}
+	policyManager, found := r.NamedPolicyManagers[name]
+	if !found {
+		applicationContext.Log.Warn().Str(logging.CONNECTOR, name).Msg(PolicyManagerNotFound)
+		applicationContext.Application.Status.ErrorMessage = PolicyManagerNotFound + ": " + name
+		return false
+	}
+	applicationContext.Log.Debug().Str(logging.CONNECTOR, name).Msg("evaluating the governance actions by the selected policy manager")
+	applicationContext.PolicyManager = policyManager
+	applicationContext.Application.Status.PolicyManager = name
+	return true
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// FailureCategory classifies the failure of an asset by the time it may take to resolve it
+type FailureCategory string
+
+const (
+	// TransientFailure is expected to be resolved shortly, e.g., a connector is unavailable
+	TransientFailure FailureCategory = "transient"
+	// TerminalFailure can not be resolved by another reconcile attempt, e.g., a connector rejects the request as malformed
+	TerminalFailure FailureCategory = "terminal"
+)
+
+// Default requeue intervals in milliseconds
+const (
+	defaultTransientFailureRequeueMs = 5000
+	defaultModuleReadyRequeueMs      = 60000
+)
+
+// addFailure records the category of a failure of the given asset
+func (c *ApplicationContext) addFailure(assetID string, category FailureCategory) {
+	if c.Failures != nil {
+		c.Failures[assetID] = category
+	}
+}
+
+// requeueResult determines when the application is reconciled again:
+// - after a short interval if some assets have transient failures
+// - with the default backoff of the controller if the failures are not classified
+// - never if all failures are terminal
+// - after a long interval if there are no failures but the deployed modules are not ready yet,
+// in addition to the reconciles triggered by the plotter updates
+// - at the latest when a policy decision starts or stops to apply according to its validity window
+func requeueResult(appContext ApplicationContext) ctrl.Result {
+	result := requeueOnFailures(appContext)
+	reevaluation := policyReevaluation(&appContext.Application.Status)
+	if reevaluation == nil || result.Requeue {
+		return result
+	}
+	after := time.Until(*reevaluation)
+	if after <= 0 {
+		return ctrl.Result{Requeue: true}
+	}
+	if result.RequeueAfter == 0 || after < result.RequeueAfter {
+		result.RequeueAfter = after
+	}
+	return result
+}
+
+func requeueOnFailures(appContext ApplicationContext) ctrl.Result {
+	application := appContext.Application
+	transient, unclassified := false, application.Status.ErrorMessage != ""
+	for assetID := range application.Status.AssetStates {
+		state := application.Status.AssetStates[assetID]
+		if state.Conditions[ErrorConditionIndex].Status != corev1.ConditionTrue {
+			continue
+		}
+		switch appContext.Failures[assetID] {
+		case TransientFailure:
+			transient = true
+		case TerminalFailure:
+		default:
+			unclassified = true
+		}
+	}
+	switch {
+	case transient:
+		return ctrl.Result{RequeueAfter: requeueInterval(environment.TransientFailureRequeueKey, defaultTransientFailureRequeueMs)}
+	case unclassified:
+		return ctrl.Result{Requeue: true}
+	case getErrorMessages(application) != "":
+		return ctrl.Result{}
+	case waitsForModules(application):
+		return ctrl.Result{RequeueAfter: requeueInterval(environment.ModuleReadyRequeueKey, defaultModuleReadyRequeueMs)}
+	}
+	return ctrl.Result{}
+}
+
+// waitsForModules returns true if the modules deployed for the application are not ready yet
+func waitsForModules(application *fappv1.FybrikApplication) bool {
+	return application.Status.Generated != nil && !application.Status.Ready
+}
+
+func requeueInterval(key string, defaultMs int) time.Duration {
+	return time.Duration(environment.GetEnvAsInt(key, defaultMs)) * time.Millisecond
+}
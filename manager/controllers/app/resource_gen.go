@@ -23,9 +23,9 @@ import (
 type ContextInterface interface {
 	ResourceExists(ref *fapp.ResourceReference) bool
 	CreateOrUpdateResource(owner *fapp.ResourceReference, ref *fapp.ResourceReference, plotterSpec *fapp.PlotterSpec,
-		labels map[string]string, uuid string) error
+		labels, annotations map[string]string, uuid string) error
 	DeleteResource(ref *fapp.ResourceReference) error
-	GetResourceStatus(ref *fapp.ResourceReference) (fapp.ObservedState, error)
+	GetResourceStatus(ref *fapp.ResourceReference) (fapp.ObservedState, map[string]fapp.ObservedState, error)
 	CreateResourceReference(owner *fapp.ResourceReference) *fapp.ResourceReference
 	GetManagedObject() runtime.Object
 }
@@ -76,30 +76,37 @@ func (c *PlotterInterface) GetResourceSignature(ref *fapp.ResourceReference) *fa
 }
 
 // CreateOrUpdateResource creates a new Plotter resource or updates an existing one
+// The given labels and annotations are updated together with the Plotter spec.
+// An existing Plotter is updated incrementally, see mergePlotterSpec.
 func (c *PlotterInterface) CreateOrUpdateResource(owner, ref *fapp.ResourceReference, plotterSpec *fapp.PlotterSpec,
-	labels map[string]string, uuid string) error {
+	labels, annotations map[string]string, uuid string) error {
+	plotterLabels := make(map[string]string, len(labels))
+	for key, val := range labels {
+		plotterLabels[key] = val
+	}
+	debugLabels := ownerLabels(types.NamespacedName{Namespace: owner.Namespace, Name: owner.Name})
+	for key, val := range debugLabels {
+		plotterLabels[key] = val
+	}
 	plotter := c.GetResourceSignature(ref)
 	if err := c.Client.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, plotter); err == nil {
-		if equality.Semantic.DeepEqual(&plotter.Spec, plotterSpec) {
+		if equality.Semantic.DeepEqual(&plotter.Spec, mergePlotterSpec(&plotter.Spec, plotterSpec)) &&
+			equality.Semantic.DeepEqual(plotter.Labels, plotterLabels) {
 			// nothing needs to be done
 			return nil
 		}
 	}
 	if _, err := ctrl.CreateOrUpdate(context.Background(), c.Client, plotter, func() error {
-		plotter.Spec = *plotterSpec
+		plotter.Spec = *mergePlotterSpec(&plotter.Spec, plotterSpec)
 		ctrlutil.AddFinalizer(plotter, PlotterFinalizerName)
-		plotter.Labels = labels
-		if plotter.Labels == nil {
-			plotter.Labels = make(map[string]string)
-		}
-		debugLabels := ownerLabels(types.NamespacedName{Namespace: owner.Namespace, Name: owner.Name})
-		for key, val := range debugLabels {
-			plotter.Labels[key] = val
-		}
+		plotter.Labels = plotterLabels
 		if plotter.Annotations == nil {
 			plotter.Annotations = make(map[string]string)
 			plotter.Annotations[utils.FybrikAppUUID] = uuid // For logging
 		}
+		for key, val := range annotations {
+			plotter.Annotations[key] = val
+		}
 		return nil
 	}); err != nil {
 		return err
@@ -107,23 +114,53 @@ func (c *PlotterInterface) CreateOrUpdateResource(owner, ref *fapp.ResourceRefer
 	return nil
 }
 
-// DeleteResource deletes the generated Plotter resource
+// mergePlotterSpec returns the desired Plotter spec with its flows in the order of the current spec.
+// The flows that are still required keep their position, and new flows are appended after them,
+// so that changing one asset of the application does not reorder the modules deployed for the other assets.
+func mergePlotterSpec(current, desired *fapp.PlotterSpec) *fapp.PlotterSpec {
+	merged := desired.DeepCopy()
+	if len(current.Flows) == 0 || len(desired.Flows) == 0 {
+		return merged
+	}
+	desiredFlows := make(map[string]int, len(desired.Flows))
+	for i := range desired.Flows {
+		desiredFlows[desired.Flows[i].Name] = i
+	}
+	merged.Flows = make([]fapp.Flow, 0, len(desired.Flows))
+	added := make(map[string]bool, len(desired.Flows))
+	for i := range current.Flows {
+		name := current.Flows[i].Name
+		if index, found := desiredFlows[name]; found && !added[name] {
+			merged.Flows = append(merged.Flows, *desired.Flows[index].DeepCopy())
+			added[name] = true
+		}
+	}
+	for i := range desired.Flows {
+		if !added[desired.Flows[i].Name] {
+			merged.Flows = append(merged.Flows, *desired.Flows[i].DeepCopy())
+			added[desired.Flows[i].Name] = true
+		}
+	}
+	return merged
+}
+
+// DeleteResource deletes the generated Plotter resource, a Plotter that does not exist is ignored
 func (c *PlotterInterface) DeleteResource(ref *fapp.ResourceReference) error {
 	resource := c.GetResourceSignature(ref)
 	err := c.Client.Delete(context.Background(), resource)
-	return err
+	return client.IgnoreNotFound(err)
 }
 
-// GetResourceStatus returns the generated Plotter status
-func (c *PlotterInterface) GetResourceStatus(ref *fapp.ResourceReference) (fapp.ObservedState, error) {
+// GetResourceStatus returns the generated Plotter status, and the status of each asset
+func (c *PlotterInterface) GetResourceStatus(ref *fapp.ResourceReference) (fapp.ObservedState, map[string]fapp.ObservedState, error) {
 	if ref == nil || ref.Namespace == "" {
-		return fapp.ObservedState{}, nil
+		return fapp.ObservedState{}, nil, nil
 	}
 	resource := c.GetResourceSignature(ref)
 	if err := c.Client.Get(context.Background(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, resource); err != nil {
-		return fapp.ObservedState{}, err
+		return fapp.ObservedState{}, nil, err
 	}
-	return resource.Status.ObservedState, nil
+	return resource.Status.ObservedState, resource.Status.Assets, nil
 }
 
 // NewPlotterInterface creates a new plotter interface for FybrikApplication controller
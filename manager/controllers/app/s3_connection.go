@@ -0,0 +1,88 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// s3ConnectionType is the connection type of S3-compatible object stores, e.g., AWS S3, IBM COS, MinIO or Ceph
+const s3ConnectionType taxonomy.ConnectionType = "s3"
+
+// Keys of the S3 connection details
+const (
+	s3EndpointKey       = "endpoint"
+	s3RegionKey         = "region"
+	s3ForcePathStyleKey = "force_path_style"
+)
+
+var s3RegionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// normalizeS3Connection validates the settings of an S3 connection returned by the data catalog,
+// i.e., the endpoint, the region and the path style, and converts them to the types expected by the modules.
+// An endpoint may omit the scheme, and the path style may be given as a string, e.g., "true".
+// Connections of other types are not modified.
+func normalizeS3Connection(connection *taxonomy.Connection) error {
+	if connection.Name != s3ConnectionType {
+		return nil
+	}
+	details, ok := connection.AdditionalProperties.Items[string(s3ConnectionType)].(map[string]interface{})
+	if !ok {
+		return invalidS3Connection("the connection details are missing")
+	}
+	endpoint, ok := details[s3EndpointKey].(string)
+	if !ok || endpoint == "" {
+		return invalidS3Connection("the endpoint is missing")
+	}
+	if err := validateS3Endpoint(endpoint); err != nil {
+		return err
+	}
+	if region, found := details[s3RegionKey]; found {
+		if s, ok := region.(string); !ok || !s3RegionPattern.MatchString(s) {
+			return invalidS3Connection(fmt.Sprintf("invalid region %v", region))
+		}
+	}
+	switch pathStyle := details[s3ForcePathStyleKey].(type) {
+	case nil, bool:
+	case string:
+		value, err := strconv.ParseBool(pathStyle)
+		if err != nil {
+			return invalidS3Connection(fmt.Sprintf("invalid %s value %q", s3ForcePathStyleKey, pathStyle))
+		}
+		details[s3ForcePathStyleKey] = value
+	default:
+		return invalidS3Connection(fmt.Sprintf("invalid %s value %v", s3ForcePathStyleKey, pathStyle))
+	}
+	return nil
+}
+
+// validateS3Endpoint checks that the endpoint is an http(s) URL or a host name with an optional port
+func validateS3Endpoint(endpoint string) error {
+	raw := endpoint
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return invalidS3Connection(fmt.Sprintf("invalid endpoint %q", endpoint))
+	}
+	if port := parsed.Port(); port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return invalidS3Connection(fmt.Sprintf("invalid endpoint %q", endpoint))
+		}
+	}
+	return nil
+}
+
+func invalidS3Connection(reason string) error {
+	return errors.New(InvalidS3Connection + ": " + reason)
+}
@@ -4,6 +4,7 @@
 package app
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
@@ -28,6 +29,10 @@ type PathBuilder struct {
 	Log   *zerolog.Logger
 	Env   *datapath.Environment
 	Asset *datapath.DataInfo
+	// ignorePlacement disregards the placement declared by modules
+	ignorePlacement bool
+	// ignoreDataFormat disregards the data format requested by the application
+	ignoreDataFormat bool
 }
 
 // find a solution for data plane orchestration
@@ -37,14 +42,64 @@ func (p *PathBuilder) solve() (datapath.Solution, error) {
 	// No data path found for the asset
 	if len(solutions) == 0 {
 		msg := "Deployed modules do not provide the functionality required to construct a data path"
+		if p.restrictedByPlacement() {
+			msg = ModuleNotInGeography
+		} else if p.unsupportedDataFormat() {
+			msg = UnsupportedDataFormat
+		}
 		p.Log.Error().Str(logging.DATASETID, p.Asset.Context.DataSetID).Msg(msg)
 		logging.LogStructure("Data Item Context", p.Asset, p.Log, zerolog.TraceLevel, true, true)
 		logging.LogStructure("Module Map", p.Env.Modules, p.Log, zerolog.TraceLevel, true, true)
-		return datapath.Solution{}, errors.New(msg + " for " + p.Asset.Context.DataSetID)
+		msg += " for " + p.Asset.Context.DataSetID
+		if unsupported := p.unsupportedActions(); len(unsupported) > 0 {
+			msg += ": no deployed module supports the governance action(s) " + strings.Join(unsupported, ", ")
+		}
+		return datapath.Solution{}, errors.New(msg)
 	}
 	return solutions[0], nil
 }
 
+// restrictedByPlacement returns true if data paths exist only when disregarding where the modules are allowed to run
+func (p *PathBuilder) restrictedByPlacement() bool {
+	if p.ignorePlacement {
+		return false
+	}
+	relaxed := PathBuilder{Log: p.Log, Env: p.Env, Asset: p.Asset, ignorePlacement: true, ignoreDataFormat: p.ignoreDataFormat}
+	return len(relaxed.FindPaths()) > 0
+}
+
+// unsupportedDataFormat returns true if data paths exist only when disregarding the data format requested by the application,
+// i.e., no module, or chain of modules, converts the data to the requested format
+func (p *PathBuilder) unsupportedDataFormat() bool {
+	required := p.Asset.Context.Requirements.Interface
+	if p.ignoreDataFormat || required == nil || required.DataFormat == "" {
+		return false
+	}
+	relaxed := PathBuilder{Log: p.Log, Env: p.Env, Asset: p.Asset, ignorePlacement: p.ignorePlacement, ignoreDataFormat: true}
+	return len(relaxed.FindPaths()) > 0
+}
+
+// unsupportedActions returns names of the required governance actions that are not supported by any capability of the deployed modules
+func (p *PathBuilder) unsupportedActions() []string {
+	unsupported := []string{}
+	for i := range p.Asset.Actions {
+		action := &p.Asset.Actions[i]
+		found := false
+		for _, module := range p.Env.Modules {
+			for j := range module.Spec.Capabilities {
+				if module.Spec.Capabilities[j].SupportsAction(action) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			unsupported = append(unsupported, string(action.Name))
+		}
+	}
+	return unsupported
+}
+
 // FindPaths finds all valid data paths between the data source and the workload
 // First, data paths are constructed using interface connections, starting from data source.
 // Then, transformations are added to the found paths, and clusters are matched to satisfy restrictions from admin config policies.
@@ -68,10 +123,65 @@ func (p *PathBuilder) FindPaths() []datapath.Solution {
 	}
 	// get valid solutions by extending data paths with transformations and selecting an appropriate cluster for each capability
 	solutions = p.validSolutions(solutions)
-
+	// shorter paths come first, paths of the same length are ordered by the actions performed by preferred modules,
+	// then by the actions pushed down to the data source and then by the names of their modules
+	sort.SliceStable(solutions, func(i, j int) bool {
+		if len(solutions[i].DataPath) != len(solutions[j].DataPath) {
+			return len(solutions[i].DataPath) < len(solutions[j].DataPath)
+		}
+		preferredI, preferredJ := p.preferredActions(&solutions[i]), p.preferredActions(&solutions[j])
+		if preferredI != preferredJ {
+			return preferredI > preferredJ
+		}
+		pushedDownI, pushedDownJ := pushedDownActions(&solutions[i]), pushedDownActions(&solutions[j])
+		if pushedDownI != pushedDownJ {
+			return pushedDownI > pushedDownJ
+		}
+		return solutionKey(&solutions[i]) < solutionKey(&solutions[j])
+	})
 	return solutions
 }
 
+// preferredActions counts the actions of a data path that are performed by modules preferred for these actions
+func (p *PathBuilder) preferredActions(solution *datapath.Solution) int {
+	count := 0
+	for _, element := range solution.DataPath {
+		for _, action := range element.Actions {
+			for i := range p.Env.ModulePreferences {
+				preference := &p.Env.ModulePreferences[i]
+				if preference.Action == action.Name && preference.Matches(element.Module) {
+					count++
+					break
+				}
+			}
+		}
+	}
+	return count
+}
+
+// pushedDownActions counts the actions of a data path that its modules push down to the data source
+func pushedDownActions(solution *datapath.Solution) int {
+	count := 0
+	for _, element := range solution.DataPath {
+		capability := &element.Module.Spec.Capabilities[element.CapabilityIndex]
+		for i := range element.Actions {
+			if capability.PushesDown(&element.Actions[i]) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// solutionKey identifies a data path by the names of its modules
+func solutionKey(solution *datapath.Solution) string {
+	names := make([]string, 0, len(solution.DataPath))
+	for _, element := range solution.DataPath {
+		names = append(names, element.Module.Name)
+	}
+	return strings.Join(names, "/")
+}
+
 // extend the received data paths with transformations and select an appropriate cluster for each capability in a data path
 func (p *PathBuilder) validSolutions(solutions []datapath.Solution) []datapath.Solution {
 	validPaths := []datapath.Solution{}
@@ -138,6 +248,10 @@ func (p *PathBuilder) validateStorageRequirements(element *datapath.ResolvedEdge
 }
 
 func (p *PathBuilder) validate(solution datapath.Solution) bool {
+	// the data written by each module should be readable by the next module in the data path
+	if !p.validateHandoffs(&solution) {
+		return false
+	}
 	// start from data source, check supported actions and cluster restrictions
 	requiredActions := p.Asset.Actions
 	for ind := range solution.DataPath {
@@ -152,14 +266,17 @@ func (p *PathBuilder) validate(solution datapath.Solution) bool {
 			}
 		}
 	}
-	// actions need to be handled somewhere on the path
+	// actions need to be handled somewhere on the path, in the order of their priorities
+	requiredActions = taxonomy.SortActions(requiredActions)
 	for ind := range solution.DataPath {
 		element := solution.DataPath[ind]
 		element.Actions = []taxonomy.Action{}
 		moduleCapability := element.Module.Spec.Capabilities[element.CapabilityIndex]
 		unsupported := []taxonomy.Action{}
 		for _, action := range requiredActions {
-			if supportsGovernanceAction(&element.Edge, action) {
+			// an action can not be applied before the forwarded actions of a lower priority
+			precededByUnsupported := len(unsupported) > 0 && unsupported[0].Priority() < action.Priority()
+			if !precededByUnsupported && supportsGovernanceAction(&element.Edge, action) {
 				element.Actions = append(element.Actions, action)
 			} else {
 				// forward actions to the next capability in the data path
@@ -168,7 +285,7 @@ func (p *PathBuilder) validate(solution datapath.Solution) bool {
 		}
 		requiredActions = unsupported
 		// select a cluster for the capability that satisfy cluster restrictions specified in admin config policies
-		if !p.findCluster(element) {
+		if !p.findCluster(element, p.preferredLocation(&solution, ind)) {
 			p.Log.Debug().Str(logging.DATASETID, p.Asset.Context.DataSetID).Msg("Could not find an available cluster for " +
 				string(moduleCapability.Capability))
 			return false
@@ -196,9 +313,121 @@ func (p *PathBuilder) validate(solution datapath.Solution) bool {
 	return true
 }
 
-// find a cluster that satisfies the requirements
-func (p *PathBuilder) findCluster(element *datapath.ResolvedEdge) bool {
-	for _, cluster := range p.Env.Clusters {
+// validateHandoffs checks that the modules chained in the data path agree on the interfaces of the intermediate handoffs,
+// i.e., each module hands off data in a protocol and a format supported by the next module.
+// The data paths of the write flow start from the asset, so that the data moves towards the first element.
+func (p *PathBuilder) validateHandoffs(solution *datapath.Solution) bool {
+	for ind := 1; ind < len(solution.DataPath); ind++ {
+		producer, consumer := solution.DataPath[ind-1], solution.DataPath[ind]
+		if p.Asset.Context.Flow == taxonomy.WriteFlow {
+			producer, consumer = consumer, producer
+		}
+		if !handsOffTo(&producer.Edge, &consumer.Edge) {
+			p.Log.Debug().Str(logging.DATASETID, p.Asset.Context.DataSetID).Msgf("module %s hands off data that module %s can not consume",
+				producer.Module.Name, consumer.Module.Name)
+			return false
+		}
+	}
+	return true
+}
+
+// handsOffTo returns true if one of the interfaces writing the data of the producer matches an interface reading the data of the consumer
+func handsOffTo(producer, consumer *datapath.Edge) bool {
+	outputs := edgeInterfaces(producer, producer.Sink, func(inter fapp.ModuleInOut) *taxonomy.Interface { return inter.Sink })
+	inputs := edgeInterfaces(consumer, consumer.Source, func(inter fapp.ModuleInOut) *taxonomy.Interface { return inter.Source })
+	for _, output := range outputs {
+		for _, input := range inputs {
+			if match(output, input) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// edgeInterfaces returns the interfaces of the module capability that match the node, either sources or sinks as chosen by the selector.
+// A capability without such interfaces transfers data in-memory via its API.
+func edgeInterfaces(edge *datapath.Edge, node *datapath.Node, selector func(fapp.ModuleInOut) *taxonomy.Interface) []*taxonomy.Interface {
+	if node == nil {
+		return nil
+	}
+	capability := edge.Module.Spec.Capabilities[edge.CapabilityIndex]
+	interfaces := []*taxonomy.Interface{}
+	hasInterfaces := false
+	for _, inter := range capability.SupportedInterfaces {
+		if selected := selector(inter); selected != nil {
+			hasInterfaces = true
+			if match(selected, node.Connection) {
+				interfaces = append(interfaces, selected)
+			}
+		}
+	}
+	if !hasInterfaces && capability.API != nil {
+		interfaces = append(interfaces, &taxonomy.Interface{Protocol: capability.API.Connection.Name, DataFormat: capability.API.DataFormat})
+	}
+	return interfaces
+}
+
+// location describes where a data path element should preferably run
+type location struct {
+	// cluster is the name of the preferred cluster, e.g., the workload cluster
+	cluster string
+	// region is the preferred region of the cluster, e.g., the geography of a data store
+	region string
+}
+
+// preferredLocation returns where the data path element at the given position should preferably run.
+// An element that serves the workload runs in the workload cluster.
+// Otherwise, an element that writes to a data store runs near the destination, e.g., the allocated storage,
+// and an element that reads from a data store runs near the source.
+func (p *PathBuilder) preferredLocation(solution *datapath.Solution, ind int) location {
+	element := solution.DataPath[ind]
+	assetGeography := ""
+	if p.Asset.DataDetails != nil {
+		assetGeography = p.Asset.DataDetails.ResourceMetadata.Geography
+	}
+	writeFlow := p.Asset.Context.Flow == taxonomy.WriteFlow
+	servesWorkload := (!writeFlow && ind == len(solution.DataPath)-1 && element.Sink != nil && element.Sink.Virtual) ||
+		(writeFlow && ind == 0 && element.Source != nil && element.Source.Virtual)
+	switch {
+	case servesWorkload && p.Asset.WorkloadCluster.Name != "":
+		return location{cluster: p.Asset.WorkloadCluster.Name, region: p.Asset.WorkloadCluster.Metadata.Region}
+	case element.Sink != nil && !element.Sink.Virtual:
+		if element.StorageAccount.Geography != "" {
+			return location{region: string(element.StorageAccount.Geography)}
+		}
+		return location{region: assetGeography}
+	case element.Source != nil && !element.Source.Virtual:
+		if ind > 0 && solution.DataPath[ind-1].StorageAccount.Geography != "" {
+			return location{region: string(solution.DataPath[ind-1].StorageAccount.Geography)}
+		}
+		return location{region: assetGeography}
+	}
+	return location{}
+}
+
+// rank orders the clusters by their distance from the location: the preferred cluster, the clusters in the preferred region, the others
+func (l location) rank(cluster *multicluster.Cluster) int {
+	switch {
+	case l.cluster != "" && cluster.Name == l.cluster:
+		return 0
+	case l.region != "" && cluster.Metadata.Region == l.region:
+		return 1
+	default:
+		return 2 //nolint:revive,gomnd
+	}
+}
+
+// find a cluster that satisfies the requirements, preferring the clusters close to the given location
+func (p *PathBuilder) findCluster(element *datapath.ResolvedEdge, preferred location) bool {
+	clusters := append([]multicluster.Cluster{}, p.Env.Clusters...)
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return preferred.rank(&clusters[i]) < preferred.rank(&clusters[j])
+	})
+	for _, cluster := range clusters {
+		if !p.ignorePlacement && !element.Module.Spec.Placement.Allows(cluster.Name, cluster.Metadata.Region) {
+			continue
+		}
 		if p.validateClusterRestrictions(element, cluster) {
 			element.Cluster = cluster.Name
 			return true
@@ -208,13 +437,23 @@ func (p *PathBuilder) findCluster(element *datapath.ResolvedEdge) bool {
 }
 
 // find all data paths up to length = n
-// Only data movements between data stores/endpoints are considered.
-// Transformations are added in the later stage.
+// Only data movements between data stores/endpoints count towards the limit,
+// transformations chained between the modules of a data path do not.
 // Capabilities outside the data path are not handled yet.
 func (p *PathBuilder) findPathsWithinLimit(source, sink *datapath.Node, n int) []datapath.Solution {
+	return p.findChainedPaths(source, sink, n, map[string]bool{})
+}
+
+// find all data paths up to length = n that do not use the given module capabilities
+// A module capability appears at most once in a data path, so that transformations are not chained endlessly.
+func (p *PathBuilder) findChainedPaths(source, sink *datapath.Node, n int, used map[string]bool) []datapath.Solution {
 	solutions := []datapath.Solution{}
 	for _, module := range p.Env.Modules {
 		for capabilityInd, capability := range module.Spec.Capabilities {
+			key := module.Name + "/" + strconv.Itoa(capabilityInd)
+			if used[key] {
+				continue
+			}
 			// check if capability is allowed
 			if !p.allowCapability(capability.Capability) {
 				continue
@@ -242,8 +481,13 @@ func (p *PathBuilder) findPathsWithinLimit(source, sink *datapath.Node, n int) [
 			} else {
 				p.Log.Debug().Msgf("module %s does not satisfy source requirements for capability %s", module.Name, capability.Capability)
 			}
+			// transformations do not access data stores, and are not counted in the data path size
+			remaining := n - 1
+			if capability.Capability == Transform {
+				remaining = n
+			}
 			// try to build data paths using the selected module capability
-			if n > 1 {
+			if remaining > 0 {
 				sources := []*taxonomy.Interface{}
 				for _, inter := range capability.SupportedInterfaces {
 					if inter.Source != nil {
@@ -255,10 +499,11 @@ func (p *PathBuilder) findPathsWithinLimit(source, sink *datapath.Node, n int) [
 						Protocol:   capability.API.Connection.Name,
 						DataFormat: capability.API.DataFormat})
 				}
+				used[key] = true
 				for _, inter := range sources {
 					node := datapath.Node{Connection: inter}
-					// recursive call to find paths of length = n-1 using the supported source of the selected module capability
-					paths := p.findPathsWithinLimit(source, &node, n-1)
+					// recursive call to find the remaining paths using the supported source of the selected module capability
+					paths := p.findChainedPaths(source, &node, remaining, used)
 					// add the selected module to the found paths
 					for i := range paths {
 						auxEdge := datapath.Edge{Module: module, CapabilityIndex: capabilityInd, Source: &node, Sink: sink}
@@ -268,6 +513,7 @@ func (p *PathBuilder) findPathsWithinLimit(source, sink *datapath.Node, n int) [
 						solutions = append(solutions, paths...)
 					}
 				}
+				delete(used, key)
 			}
 		}
 	}
@@ -314,15 +560,7 @@ func GetDependencies(module *fapp.FybrikModule, moduleMap map[string]*fapp.Fybri
 
 // supportsGovernanceAction checks whether the module supports the required governance action
 func supportsGovernanceAction(edge *datapath.Edge, action taxonomy.Action) bool {
-	// Loop over the data transforms (actions) performed by the module for this capability
-	capability := edge.Module.Spec.Capabilities[edge.CapabilityIndex]
-	for _, act := range capability.Actions {
-		// TODO(shlomitk1): check for matching of additional fields declared by the module
-		if act.Name == action.Name {
-			return true
-		}
-	}
-	return false // Action not supported by module
+	return edge.Module.Spec.Capabilities[edge.CapabilityIndex].SupportsAction(&action)
 }
 
 func match(source, sink *taxonomy.Interface) bool {
@@ -342,6 +580,35 @@ func match(source, sink *taxonomy.Interface) bool {
 	return true
 }
 
+// validateRequiredInterface checks that the interface requested by the application, i.e., a protocol and data format pair,
+// is exposed by some capability of the deployed modules, so that an impossible combination is rejected before the asset
+// metadata and the governance actions are requested. Data paths always end with the requested interface: the last module
+// of a read path serves the workload via its sink, and the first module of a write path receives the data via its source.
+// Delete flows do not serve data, and if no module is deployed the check is left to the data path construction.
+func validateRequiredInterface(env *datapath.Environment, dataContext *fapp.DataContext) error {
+	required := dataContext.Requirements.Interface
+	if required == nil || dataContext.Flow == taxonomy.DeleteFlow || len(env.Modules) == 0 {
+		return nil
+	}
+	for _, module := range env.Modules {
+		for i := range module.Spec.Capabilities {
+			edge := &datapath.Edge{Module: module, CapabilityIndex: i}
+			node := &datapath.Node{Connection: required}
+			if dataContext.Flow == taxonomy.WriteFlow {
+				if supportsSourceInterface(edge, node) {
+					return nil
+				}
+			} else if supportsSinkInterface(edge, node) {
+				return nil
+			}
+		}
+	}
+	if required.DataFormat == "" {
+		return errors.Errorf("%s: the %s protocol", UnsupportedInterface, required.Protocol)
+	}
+	return errors.Errorf("%s: the %s protocol with the %s data format", UnsupportedInterface, required.Protocol, required.DataFormat)
+}
+
 // supportsSourceInterface indicates whether the source interface requirements are met.
 //
 //nolint:dupl
@@ -420,6 +687,11 @@ func (p *PathBuilder) getRequiredConnectionNode() *datapath.Node {
 	if p.Asset.Context.Requirements.Interface == nil {
 		return nil
 	}
+	if p.ignoreDataFormat {
+		required := *p.Asset.Context.Requirements.Interface
+		required.DataFormat = ""
+		return &datapath.Node{Connection: &required}
+	}
 	return &datapath.Node{Connection: p.Asset.Context.Requirements.Interface}
 }
 
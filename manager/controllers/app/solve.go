@@ -7,49 +7,67 @@ import (
 	"emperror.dev/errors"
 	"github.com/rs/zerolog"
 
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/pkg/datapath"
-	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
-	"fybrik.io/fybrik/pkg/optimizer"
 )
 
 // find a solution for a data path
 // satisfying governance and admin policies
-// with respect to the optimization strategy
-func solveSingleDataset(env *datapath.Environment, dataset *datapath.DataInfo, log *zerolog.Logger) (datapath.Solution, error) {
-	cspPath := environment.GetCSPPath()
-	if environment.UseCSP() && cspPath != "" {
-		cspOptimizer := optimizer.NewOptimizer(env, dataset, cspPath, log)
-		solution, err := cspOptimizer.Solve()
-		if err == nil {
-			if len(solution.DataPath) > 0 { // solver found a solution
-				return solution, nil
-			}
-			if len(solution.DataPath) == 0 { // solver returned UNSAT
-				msg := "Data path cannot be constructed given the deployed modules and the active restrictions"
-				log.Error().Str(logging.DATASETID, dataset.Context.DataSetID).Msg(msg)
-				logging.LogStructure("Data Item Context", dataset, log, zerolog.TraceLevel, true, true)
-				logging.LogStructure("Module Map", env.Modules, log, zerolog.TraceLevel, true, true)
-				return datapath.Solution{}, errors.New(msg + " for " + dataset.Context.DataSetID)
-			}
-		} else {
-			msg := "Error solving CSP. Fybrik will now search for a solution without considering optimization goals."
-			log.Error().Err(err).Str(logging.DATASETID, dataset.Context.DataSetID).Msg(msg)
-			// now fallback to finding a non-optimized solution
+// with respect to the optimization strategy of the solver
+// If the asset is available via alternative connections, and no solution is found for its connection,
+// the first alternative connection for which a solution is found replaces the connection of the asset.
+func solveSingleDataset(solver Solver, env *datapath.Environment, dataset *datapath.DataInfo,
+	log *zerolog.Logger) (datapath.Solution, error) {
+	solution, err := solveWithModuleHint(solver, env, dataset, log)
+	if err == nil || dataset.DataDetails == nil {
+		return solution, err
+	}
+	for i := range dataset.DataDetails.AlternativeDetails {
+		// the asset details may be shared by the data paths of several consumers
+		alternative := *dataset
+		alternative.DataDetails = dataset.DataDetails.DeepCopy()
+		alternative.DataDetails.Details = dataset.DataDetails.AlternativeDetails[i]
+		if alternativeSolution, alternativeErr := solveWithModuleHint(solver, env, &alternative, log); alternativeErr == nil {
+			log.Debug().Str(logging.DATASETID, dataset.Context.DataSetID).
+				Msgf("the asset is accessed via its alternative %s connection", alternative.DataDetails.Details.Connection.Name)
+			*dataset = alternative
+			return alternativeSolution, nil
 		}
 	}
-	pathBuilder := PathBuilder{Log: log, Env: env, Asset: dataset}
-	return pathBuilder.solve()
+	return solution, err
+}
+
+// find a solution for a data path
+// If the dataset specifies a module hint, the solution may use the requested module only
+func solveWithModuleHint(solver Solver, env *datapath.Environment, dataset *datapath.DataInfo,
+	log *zerolog.Logger) (datapath.Solution, error) {
+	hint := dataset.Context.ModuleHint
+	if hint == "" {
+		return solver.Solve(env, dataset, log)
+	}
+	// only the module requested by the user is considered
+	module, found := env.Modules[hint]
+	if !found {
+		return datapath.Solution{}, errors.Errorf("%s: %s", ModuleHintNotFound, hint)
+	}
+	restricted := *env
+	restricted.Modules = map[string]*fappv1.FybrikModule{hint: module}
+	solution, err := solver.Solve(&restricted, dataset, log)
+	if err != nil {
+		return solution, errors.Wrapf(err, "%s: %s", ModuleHintNotSuitable, hint)
+	}
+	return solution, nil
 }
 
 // find a solution for all data paths at once
-func solve(env *datapath.Environment, datasets []datapath.DataInfo, log *zerolog.Logger) ([]datapath.Solution, error) {
+func solve(solver Solver, env *datapath.Environment, datasets []datapath.DataInfo, log *zerolog.Logger) ([]datapath.Solution, error) {
 	solutions := []datapath.Solution{}
 	if err := validateBasicConditions(env, log); err != nil {
 		return solutions, err
 	}
 	for i := range datasets {
-		solution, err := solveSingleDataset(env, &datasets[i], log)
+		solution, err := solveSingleDataset(solver, env, &datasets[i], log)
 		if err != nil {
 			return solutions, err
 		}
@@ -199,7 +199,7 @@ func TestEmptyEnvironment(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	env := newEnvironment()
 	asset := createReadRequest()
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(&DefaultSolver{}, env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	g.Expect(err.Error()).To(gomega.BeIdenticalTo(NoDeployedModules))
 }
@@ -220,13 +220,13 @@ func TestReadWithTransforms(t *testing.T) {
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: string(account.Spec.Geography)}})
 	asset := createReadRequest()
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	// only read is not enough
 	g.Expect(err).To(gomega.HaveOccurred())
 	addModule(env, copyModule)
 	addStorageAccount(env, account)
 	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 }
@@ -246,10 +246,10 @@ func TestReadModuleSource(t *testing.T) {
 	asset := createReadRequest()
 	asset.DataDetails.Details.Connection.Name = mockup.JdbcDB2
 	asset.DataDetails.Details.DataFormat = ""
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	addModule(env, readModuleDB2)
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	logging.LogStructure("TestReadModuleSource", &solution, &testLog, zerolog.InfoLevel, false, false)
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -275,11 +275,11 @@ func TestReadAndCopyWithTransforms(t *testing.T) {
 	addModule(env, copyModule)
 	addStorageAccount(env, account)
 	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	_, err = solveSingleDataset(env, asset, &testLog)
+	_, err = solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 }
 
@@ -299,7 +299,7 @@ func TestReadAndTransformModules(t *testing.T) {
 	asset.DataDetails.Details.Connection.Name = mockup.S3
 	asset.DataDetails.Details.DataFormat = mockup.Parquet
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(readModule.Name))
@@ -325,7 +325,7 @@ func TestReadAfterRead(t *testing.T) {
 	asset.DataDetails.Details.Connection.Name = mockup.S3
 	asset.DataDetails.Details.DataFormat = mockup.Parquet
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(readModule.Name))
@@ -334,6 +334,92 @@ func TestReadAfterRead(t *testing.T) {
 	g.Expect(solution.DataPath[1].Actions).To(gomega.HaveLen(1))
 }
 
+// The workload requires redacted csv data
+// The read module does not redact, a transform module redacts the data handed off by the read module
+func TestReadCapabilitySplitAcrossModules(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	readModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	readModule.Spec.Capabilities[0].API.DataFormat = mockup.CSV
+	addModule(env, readModule)
+	transformModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-transform.yaml", transformModule)).NotTo(gomega.HaveOccurred())
+	transformModule.Spec.Capabilities = transformModule.Spec.Capabilities[:1]
+	transformModule.Spec.Capabilities[0].SupportedInterfaces[0].Source.DataFormat = mockup.CSV
+	addModule(env, transformModule)
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(readModule.Name))
+	g.Expect(solution.DataPath[0].Actions).To(gomega.BeEmpty())
+	g.Expect(solution.DataPath[1].Module.Name).To(gomega.Equal(transformModule.Name))
+	g.Expect(solution.DataPath[1].Actions).To(gomega.HaveLen(1))
+	// the transform module reads the data served by the read module
+	g.Expect(solution.DataPath[0].Sink).To(gomega.Equal(solution.DataPath[1].Source))
+	g.Expect(solution.DataPath[1].Source.Connection.Protocol).To(gomega.Equal(mockup.ArrowFlight))
+}
+
+// The read module serves csv data, while the transform module accepts parquet data only
+// No data path is found since the transform module can not consume the data handed off by the read module
+func TestIncompatibleHandoff(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	readModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	readModule.Spec.Capabilities[0].API.DataFormat = mockup.CSV
+	addModule(env, readModule)
+	transformModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-transform.yaml", transformModule)).NotTo(gomega.HaveOccurred())
+	transformModule.Spec.Capabilities = transformModule.Spec.Capabilities[:1]
+	transformModule.Spec.Capabilities[0].SupportedInterfaces[0].Source.DataFormat = mockup.Parquet
+	addModule(env, transformModule)
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+// Copy flow with transforms: read, transform and write are performed by three modules
+// Transformations are not counted in the data path size, so that the chain is found within the default limit
+func TestReadTransformWriteChain(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	readModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	addModule(env, readModule)
+	transformModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-transform.yaml", transformModule)).NotTo(gomega.HaveOccurred())
+	addModule(env, transformModule)
+	writeModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-write.yaml", writeModule)).NotTo(gomega.HaveOccurred())
+	writeModule.Spec.Capabilities = writeModule.Spec.Capabilities[1:]
+	addModule(env, writeModule)
+	account := &saApi.FybrikStorageAccount{}
+	g.Expect(readStorageAccountData("../../testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+	addStorageAccount(env, account)
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: string(account.Spec.Geography)}})
+	asset := createCopyRequest()
+	asset.Configuration.ConfigDecisions = adminconfig.DecisionPerCapabilityMap{}
+	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
+	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(3))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(readModule.Name))
+	g.Expect(solution.DataPath[1].Module.Name).To(gomega.Equal(transformModule.Name))
+	g.Expect(solution.DataPath[1].Actions).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[2].Module.Name).To(gomega.Equal(writeModule.Name))
+	g.Expect(solution.DataPath[2].StorageAccount.Geography).To(gomega.Equal(account.Spec.Geography))
+}
+
 // Transform close to the data
 // The locations of the workload and the requested dataset are different
 func TestTransformInDataLocation(t *testing.T) {
@@ -371,11 +457,11 @@ func TestTransformInDataLocation(t *testing.T) {
 	}
 	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
 	asset.StorageRequirements[taxonomy.ProcessingLocation(remoteGeo)] = []taxonomy.Action{}
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	// remove restriction on copy
 	asset.Configuration.ConfigDecisions["copy"] = adminconfig.Decision{Deploy: adminconfig.StatusUnknown}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	// copy
@@ -385,6 +471,117 @@ func TestTransformInDataLocation(t *testing.T) {
 	g.Expect(solution.DataPath[1].Cluster).To(gomega.Equal(cluster1.Name))
 }
 
+// A copy between two clusters: the data is read near the source and written near the destination
+func TestCopyAcrossClusters(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	readModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	addModule(env, readModule)
+	writeModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-write.yaml", writeModule)).NotTo(gomega.HaveOccurred())
+	// the module serves writes only
+	writeModule.Spec.Capabilities = writeModule.Spec.Capabilities[1:]
+	addModule(env, writeModule)
+	account := &saApi.FybrikStorageAccount{}
+	g.Expect(readStorageAccountData("../../testdata/unittests/account-neverland.yaml", account)).NotTo(gomega.HaveOccurred())
+	addStorageAccount(env, account)
+	destinationCluster := multicluster.Cluster{Name: "destination", Metadata: multicluster.ClusterMetadata{
+		Region: string(account.Spec.Geography)}}
+	sourceCluster := multicluster.Cluster{Name: "source", Metadata: multicluster.ClusterMetadata{Region: "theshire"}}
+	addCluster(env, destinationCluster)
+	addCluster(env, sourceCluster)
+	asset := createCopyRequest()
+	asset.DataDetails.ResourceMetadata.Geography = sourceCluster.Metadata.Region
+	asset.Configuration.ConfigDecisions["read"] = adminconfig.Decision{Deploy: adminconfig.StatusUnknown}
+	asset.Configuration.ConfigDecisions["write"] = adminconfig.Decision{Deploy: adminconfig.StatusUnknown}
+	asset.Configuration.ConfigDecisions["copy"] = adminconfig.Decision{Deploy: adminconfig.StatusUnknown}
+	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
+	// read
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(readModule.Name))
+	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal(sourceCluster.Name))
+	// write
+	g.Expect(solution.DataPath[1].Module.Name).To(gomega.Equal(writeModule.Name))
+	g.Expect(solution.DataPath[1].StorageAccount.Geography).To(gomega.Equal(account.Spec.Geography))
+	g.Expect(solution.DataPath[1].Cluster).To(gomega.Equal(destinationCluster.Name))
+}
+
+// addFormatConversionModule deploys a read module that serves csv data in the given format
+func addFormatConversionModule(g *gomega.WithT, env *datapath.Environment, name string, format taxonomy.DataFormat) {
+	module := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", module)).NotTo(gomega.HaveOccurred())
+	module.Name = name
+	module.Spec.Capabilities[0].API.DataFormat = format
+	addModule(env, module)
+}
+
+// The workload requires parquet data from a csv source
+// One of the read modules converts csv to parquet
+func TestReadWithFormatConversion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addFormatConversionModule(g, env, "read-csv", mockup.CSV)
+	addFormatConversionModule(g, env, "read-csv-as-parquet", mockup.Parquet)
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	asset.Context.Requirements.Interface.DataFormat = mockup.Parquet
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-csv-as-parquet"))
+	g.Expect(solution.DataPath[0].Source.Connection.DataFormat).To(gomega.Equal(mockup.CSV))
+	g.Expect(solution.DataPath[0].Sink.Connection.DataFormat).To(gomega.Equal(mockup.Parquet))
+}
+
+// The workload requires parquet data from a csv source
+// A copy module converts csv to parquet, followed by a read module of parquet data
+func TestReadAfterFormatConversion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	readModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	readModule.Spec.Capabilities[0].API.DataFormat = mockup.Parquet
+	addModule(env, readModule)
+	copyModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/implicit-copy-batch-module-csv.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	copyModule.Spec.Capabilities[0].SupportedInterfaces[0].Sink.DataFormat = mockup.Parquet
+	addModule(env, copyModule)
+	account := &saApi.FybrikStorageAccount{}
+	g.Expect(readStorageAccountData("../../testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+	addStorageAccount(env, account)
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	asset.Context.Requirements.Interface.DataFormat = mockup.Parquet
+	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(copyModule.Name))
+	g.Expect(solution.DataPath[0].Sink.Connection.DataFormat).To(gomega.Equal(mockup.Parquet))
+	g.Expect(solution.DataPath[1].Module.Name).To(gomega.Equal(readModule.Name))
+}
+
+// The workload requires parquet data from a csv source
+// No module converts csv to parquet
+func TestUnsupportedDataFormat(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addFormatConversionModule(g, env, "read-csv", mockup.CSV)
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	asset.Context.Requirements.Interface.DataFormat = mockup.Parquet
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.HavePrefix(UnsupportedDataFormat))
+}
+
 // This test checks the copy scenario
 // Two storage accounts are created. Data cannot be stored in one of them according to governance policies.
 func TestCopyFlow(t *testing.T) {
@@ -407,10 +604,10 @@ func TestCopyFlow(t *testing.T) {
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: string(account1.Spec.Geography)}})
 
 	asset := createCopyRequest()
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	asset.StorageRequirements[account2.Spec.Geography] = []taxonomy.Action{}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	// copy
@@ -462,7 +659,7 @@ func TestStorageCostRestrictictions(t *testing.T) {
 		Object:     taxonomy.StorageAccount,
 		Instance:   account2.Name,
 	})
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	// change the restriction to fit one of the accounts
 	asset.Configuration.ConfigDecisions["copy"] = adminconfig.Decision{
@@ -470,7 +667,7 @@ func TestStorageCostRestrictictions(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "storage-cost", Range: &taxonomy.RangeType{Max: 15}}}},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	g.Expect(solution.DataPath[0].StorageAccount.Geography).To(gomega.Equal(account2.Spec.Geography))
@@ -504,7 +701,7 @@ func TestWriteNewAsset(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "geography", Values: adminconfig.StringList{string(account2.Spec.Geography)}}}},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	// write
@@ -534,7 +731,7 @@ func TestStorageAndModuleMismatch(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "geography", Values: adminconfig.StringList{string(account.Spec.Geography)}}}},
 	}
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 }
 
@@ -568,7 +765,7 @@ func TestStorageTypeRestriction(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "type", Values: adminconfig.StringList{string(accountMySQL.Spec.Type)}}}},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	// write
@@ -599,7 +796,7 @@ func TestWriteExistingAsset(t *testing.T) {
 	asset := createUpdateRequest()
 	asset.StorageRequirements[account1.Spec.Geography] = []taxonomy.Action{}
 	asset.StorageRequirements[account2.Spec.Geography] = []taxonomy.Action{}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	// write
@@ -621,7 +818,7 @@ func TestWriteAndTransformModules(t *testing.T) {
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "xyz"}})
 	asset := createUpdateRequest()
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(writeModule.Name))
@@ -646,7 +843,7 @@ func TestDeleteFlow(t *testing.T) {
 	addModule(env, transformModule)
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "xyz"}})
 	asset := createDeleteRequest()
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(deleteModule.Name))
@@ -671,16 +868,42 @@ func TestModuleSelection(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{Modules: []adminconfig.Restriction{{
 			Property: "capabilities.scope",
 			Values:   adminconfig.StringList{"workload"}}}}}
-	_, err := solveSingleDataset(env, asset, &testLog)
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	// wrong scope
 	g.Expect(err).To(gomega.HaveOccurred())
 	addModule(env, workloadLevelModule)
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(workloadLevelModule.Name))
 }
 
+// a custom action, unknown to Fybrik, is required by the governance policies
+// a third-party module declaring the action is selected by its name
+func TestCustomTransformation(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	readModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	tokenizeModule := readModule.DeepCopy()
+	tokenizeModule.Name = "tokenize-module"
+	tokenizeModule.Spec.Capabilities[0].Actions = []fapp.ModuleSupportedAction{{Name: "TokenizeAction"}}
+	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "xyz"}})
+	addModule(env, readModule)
+	asset := createReadRequest()
+	asset.Actions = []taxonomy.Action{{Name: "TokenizeAction"}}
+	_, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	// no module supports the action
+	g.Expect(err).To(gomega.HaveOccurred())
+	addModule(env, tokenizeModule)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(tokenizeModule.Name))
+	g.Expect(solution.DataPath[0].Actions).To(gomega.Equal(asset.Actions))
+}
+
 // a read scenario
 // copy and read modules are deployed
 // transformations are required but not supported by the read module
@@ -733,7 +956,7 @@ func TestOptimalStorage(t *testing.T) {
 		})
 		cost += 5
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	g.Expect(solution.DataPath[0].StorageAccount.Geography).To(gomega.Equal(taxonomy.ProcessingLocation("region2")))
@@ -807,7 +1030,7 @@ func TestOptimalStorageAndClusterCost(t *testing.T) {
 		Object:     taxonomy.Cluster,
 		Instance:   "Expensive",
 	})
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	g.Expect(solution.DataPath[0].StorageAccount.Geography).To(gomega.Equal(taxonomy.ProcessingLocation("region2")))
@@ -857,7 +1080,7 @@ func TestGoalConflict(t *testing.T) {
 			Weight:    "0.8",
 		},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	g.Expect(solution.DataPath[0].Cluster).To(gomega.HavePrefix("cluster"))
@@ -915,7 +1138,7 @@ func TestMinMultipleGoals(t *testing.T) {
 			Weight:    "0.1",
 		},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal("cluster2"))
@@ -973,7 +1196,7 @@ func TestMinMaxGoals(t *testing.T) {
 			Weight:    "0.4",
 		},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
 	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal("cluster4"))
@@ -1039,7 +1262,7 @@ func TestMinDistance(t *testing.T) {
 		Object:     taxonomy.InterRegion,
 		Arguments:  []string{"theshire", "theshire"},
 	})
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	g.Expect(solution.DataPath[0].StorageAccount.Geography).To(gomega.Equal(taxonomy.ProcessingLocation(workloadCluster)))
@@ -1082,7 +1305,7 @@ func TestClusterAttribute(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			Clusters: []adminconfig.Restriction{{Property: "compliance", Values: adminconfig.StringList{"true"}}}},
 	}
-	solution, err := solveSingleDataset(env, asset, &testLog)
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal(allowedCluster.Name))
 }
@@ -0,0 +1,210 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/rs/zerolog"
+
+	fappv2 "fybrik.io/fybrik/manager/apis/app/v1beta2"
+	"fybrik.io/fybrik/pkg/datapath"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/multicluster"
+	"fybrik.io/fybrik/pkg/optimizer"
+)
+
+// Module selection strategies
+const (
+	DefaultStrategy       = "default"
+	CostOptimizedStrategy = "cost"
+)
+
+// DefaultCostMetric measures the cost if the infrastructure does not define a cost model
+const DefaultCostMetric = "cost"
+
+// Solver chooses the modules, clusters and storage accounts that construct the data path of a dataset.
+// The candidate modules, clusters, storage accounts and infrastructure attributes are given by the environment,
+// while the dataset provides the requirements of the application and the governance actions.
+type Solver interface {
+	Solve(env *datapath.Environment, dataset *datapath.DataInfo, log *zerolog.Logger) (datapath.Solution, error)
+}
+
+// NewSolver returns the solver implementing the given module selection strategy
+func NewSolver(strategy string) (Solver, error) {
+	switch strategy {
+	case "", DefaultStrategy:
+		return &DefaultSolver{}, nil
+	case CostOptimizedStrategy:
+		return &CostOptimizedSolver{}, nil
+	}
+	return nil, errors.Errorf("unknown module selection strategy %s", strategy)
+}
+
+// ModulePreferencesFromEnvironment returns the modules preferred for performing actions.
+// The preferences are specified as a comma separated list of action=module pairs, or of action=key=value
+// items that prefer the modules with the given label, e.g., "RedactAction=audited-redact,HashAction=fybrik.io/approved=true".
+func ModulePreferencesFromEnvironment() ([]datapath.ModulePreference, error) {
+	preferences := []datapath.ModulePreference{}
+	value := strings.TrimSpace(os.Getenv(environment.ModulePreferencesKey))
+	if value == "" {
+		return preferences, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 3) //nolint:revive,gomnd
+		preference := datapath.ModulePreference{Action: taxonomy.ActionName(parts[0])}
+		switch {
+		case len(parts) == 2 && parts[0] != "" && parts[1] != "": //nolint:revive,gomnd
+			preference.Module = parts[1]
+		case len(parts) == 3 && parts[0] != "" && parts[1] != "": //nolint:revive,gomnd
+			preference.LabelKey, preference.LabelValue = parts[1], parts[2]
+		default:
+			return nil, errors.Errorf("invalid module preference %q in %s, expected action=module or action=key=value",
+				item, environment.ModulePreferencesKey)
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+// DefaultSolver uses the CSP optimizer if it is enabled, and falls back to the shortest data path otherwise
+type DefaultSolver struct{}
+
+var _ Solver = (*DefaultSolver)(nil)
+
+// Solve finds a solution for a data path using the modules of the given environment
+func (s *DefaultSolver) Solve(env *datapath.Environment, dataset *datapath.DataInfo,
+	log *zerolog.Logger) (datapath.Solution, error) {
+	cspPath := environment.GetCSPPath()
+	if environment.UseCSP() && cspPath != "" {
+		cspOptimizer := optimizer.NewOptimizer(env, dataset, cspPath, log)
+		solution, err := cspOptimizer.Solve()
+		if err == nil {
+			if len(solution.DataPath) > 0 { // solver found a solution
+				return solution, nil
+			}
+			if len(solution.DataPath) == 0 { // solver returned UNSAT
+				msg := "Data path cannot be constructed given the deployed modules and the active restrictions"
+				log.Error().Str(logging.DATASETID, dataset.Context.DataSetID).Msg(msg)
+				logging.LogStructure("Data Item Context", dataset, log, zerolog.TraceLevel, true, true)
+				logging.LogStructure("Module Map", env.Modules, log, zerolog.TraceLevel, true, true)
+				return datapath.Solution{}, errors.New(msg + " for " + dataset.Context.DataSetID)
+			}
+		} else {
+			msg := "Error solving CSP. Fybrik will now search for a solution without considering optimization goals."
+			log.Error().Err(err).Str(logging.DATASETID, dataset.Context.DataSetID).Msg(msg)
+			// now fallback to finding a non-optimized solution
+		}
+	}
+	pathBuilder := PathBuilder{Log: log, Env: env, Asset: dataset}
+	return pathBuilder.solve()
+}
+
+// CostOptimizedSolver chooses the data path with the lowest cost.
+// The cost of a data path is the weighted sum of the infrastructure attributes of the modules, clusters
+// and storage accounts it uses. The weights of the metrics are defined by the cost model of the infrastructure,
+// and resources without such attributes cost nothing. Data paths of the same cost are chosen in the default order.
+type CostOptimizedSolver struct {
+	// Metric, if set, measures the cost instead of the cost model of the infrastructure
+	Metric string
+}
+
+var _ Solver = (*CostOptimizedSolver)(nil)
+
+// Solve finds the cheapest data path using the modules of the given environment
+func (s *CostOptimizedSolver) Solve(env *datapath.Environment, dataset *datapath.DataInfo,
+	log *zerolog.Logger) (datapath.Solution, error) {
+	// the path builder selects the first suitable cluster and storage account,
+	// so the cheapest ones are selected when they are sorted by their cost
+	weights := s.weights(env)
+	sorted := *env
+	sorted.Clusters = append([]multicluster.Cluster{}, env.Clusters...)
+	sort.SliceStable(sorted.Clusters, func(i, j int) bool {
+		return s.cost(env, weights, taxonomy.Cluster, sorted.Clusters[i].Name) <
+			s.cost(env, weights, taxonomy.Cluster, sorted.Clusters[j].Name)
+	})
+	sorted.StorageAccounts = append([]*fappv2.FybrikStorageAccount{}, env.StorageAccounts...)
+	sort.SliceStable(sorted.StorageAccounts, func(i, j int) bool {
+		return s.cost(env, weights, taxonomy.StorageAccount, sorted.StorageAccounts[i].Name) <
+			s.cost(env, weights, taxonomy.StorageAccount, sorted.StorageAccounts[j].Name)
+	})
+	pathBuilder := PathBuilder{Log: log, Env: &sorted, Asset: dataset}
+	solutions := pathBuilder.FindPaths()
+	if len(solutions) == 0 {
+		// report the reason for not finding a data path
+		return pathBuilder.solve()
+	}
+	best := 0
+	bestCost := s.solutionCost(&sorted, weights, &solutions[0])
+	for i := 1; i < len(solutions); i++ {
+		if cost := s.solutionCost(&sorted, weights, &solutions[i]); cost < bestCost {
+			best, bestCost = i, cost
+		}
+	}
+	log.Debug().Str(logging.DATASETID, dataset.Context.DataSetID).Msgf("selected a data path with cost %v", bestCost)
+	return solutions[best], nil
+}
+
+// solutionCost sums up the costs of the resources used by the data path
+func (s *CostOptimizedSolver) solutionCost(env *datapath.Environment, weights map[string]float64,
+	solution *datapath.Solution) float64 {
+	total := 0.0
+	for _, element := range solution.DataPath {
+		total += s.cost(env, weights, taxonomy.Module, element.Module.Name)
+		total += s.cost(env, weights, taxonomy.Cluster, element.Cluster)
+		if element.StorageAccount.ID == "" {
+			continue
+		}
+		for _, account := range env.StorageAccounts {
+			if account.Spec.ID == element.StorageAccount.ID {
+				total += s.cost(env, weights, taxonomy.StorageAccount, account.Name)
+				break
+			}
+		}
+	}
+	return total
+}
+
+// weights returns the weight of each metric contributing to the cost
+func (s *CostOptimizedSolver) weights(env *datapath.Environment) map[string]float64 {
+	if s.Metric != "" || env.AttributeManager == nil {
+		return map[string]float64{s.metric(): 1}
+	}
+	return env.AttributeManager.GetCostWeights(DefaultCostMetric)
+}
+
+func (s *CostOptimizedSolver) metric() string {
+	if s.Metric == "" {
+		return DefaultCostMetric
+	}
+	return s.Metric
+}
+
+// cost sums up the weighted values of the cost attributes of the given resource
+func (s *CostOptimizedSolver) cost(env *datapath.Environment, weights map[string]float64,
+	object taxonomy.InstanceType, instance string) float64 {
+	if env.AttributeManager == nil || instance == "" {
+		return 0
+	}
+	total := 0.0
+	for i := range env.AttributeManager.Attributes {
+		attribute := &env.AttributeManager.Attributes[i]
+		weight, found := weights[attribute.MetricName]
+		if !found || attribute.Object != object || attribute.Instance != instance {
+			continue
+		}
+		value, err := strconv.ParseFloat(attribute.Value, 64)
+		if err != nil {
+			continue
+		}
+		total += weight * value
+	}
+	return total
+}
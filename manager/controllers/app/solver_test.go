@@ -0,0 +1,288 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	saApi "fybrik.io/fybrik/manager/apis/app/v1beta2"
+	"fybrik.io/fybrik/pkg/datapath"
+	"fybrik.io/fybrik/pkg/environment"
+	infraattributes "fybrik.io/fybrik/pkg/model/attributes"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/multicluster"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// addCost defines the cost of a resource
+func addCost(env *datapath.Environment, object taxonomy.InstanceType, instance, value string) {
+	addAttribute(env, &taxonomy.InfrastructureElement{
+		Name:       "cost",
+		MetricName: DefaultCostMetric,
+		Value:      value,
+		Object:     object,
+		Instance:   instance,
+	})
+}
+
+// addReadModule deploys a read module with the given name and supported actions
+func addReadModule(g *gomega.WithT, env *datapath.Environment, name string, actions ...taxonomy.ActionName) {
+	module := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-csv.yaml", module)).NotTo(gomega.HaveOccurred())
+	module.Name = name
+	for _, action := range actions {
+		module.Spec.Capabilities[0].Actions = append(module.Spec.Capabilities[0].Actions,
+			fapp.ModuleSupportedAction{Name: action})
+	}
+	addModule(env, module)
+}
+
+func TestNewSolver(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	solver, err := NewSolver("")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solver).To(gomega.BeAssignableToTypeOf(&DefaultSolver{}))
+	solver, err = NewSolver(CostOptimizedStrategy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solver).To(gomega.Equal(&CostOptimizedSolver{}))
+	_, err = NewSolver("latency")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+// two equivalent read modules are deployed, the cheaper one is listed last
+func TestSolversModuleSelection(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addReadModule(g, env, "read-a")
+	addReadModule(g, env, "read-b")
+	addCluster(env, multicluster.Cluster{Name: "cluster", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	addCost(env, taxonomy.Module, "read-a", "10")
+	addCost(env, taxonomy.Module, "read-b", "5")
+	asset := createReadRequest()
+
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-a"))
+
+	solution, err = solveSingleDataset(&CostOptimizedSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-b"))
+}
+
+// a redaction is supported by an expensive read module, or by a cheap copy followed by a read
+func TestSolversPathLength(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addReadModule(g, env, "read-redact", "RedactAction")
+	addReadModule(g, env, "read")
+	copyModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/implicit-copy-batch-module-csv.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	addModule(env, copyModule)
+	account := &saApi.FybrikStorageAccount{}
+	g.Expect(readStorageAccountData("../../testdata/unittests/account-theshire.yaml", account)).NotTo(gomega.HaveOccurred())
+	account.Name = "account-theshire"
+	addStorageAccount(env, account)
+	addCluster(env, multicluster.Cluster{Name: "cluster", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	addCost(env, taxonomy.Module, "read-redact", "100")
+	addCost(env, taxonomy.Module, copyModule.Name, "20")
+	addCost(env, taxonomy.StorageAccount, account.Name, "30")
+	asset := createReadRequest()
+	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
+	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
+
+	// the shortest path is selected by default
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-redact"))
+
+	solution, err = solveSingleDataset(&CostOptimizedSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal(copyModule.Name))
+	g.Expect(solution.DataPath[0].Actions).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[1].Module.Name).To(gomega.Equal("read"))
+}
+
+// a copy can be made to either storage account, on either cluster
+func TestSolversClusterAndStorageSelection(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	copyModule := &fapp.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/implicit-copy-batch-module-csv.yaml", copyModule)).NotTo(gomega.HaveOccurred())
+	addModule(env, copyModule)
+	asset := createCopyRequest()
+	for _, geography := range []string{"neverland", "theshire"} {
+		account := &saApi.FybrikStorageAccount{}
+		g.Expect(readStorageAccountData("../../testdata/unittests/account-"+geography+".yaml", account)).NotTo(gomega.HaveOccurred())
+		account.Name = "account-" + geography
+		addStorageAccount(env, account)
+		asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
+	}
+	addCluster(env, multicluster.Cluster{Name: "expensive", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	addCluster(env, multicluster.Cluster{Name: "cheap", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	addCost(env, taxonomy.StorageAccount, "account-neverland", "90")
+	addCost(env, taxonomy.StorageAccount, "account-theshire", "60")
+	addCost(env, taxonomy.Cluster, "expensive", "50")
+	addCost(env, taxonomy.Cluster, "cheap", "1")
+
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal("expensive"))
+	g.Expect(solution.DataPath[0].StorageAccount.Geography).To(gomega.Equal(taxonomy.ProcessingLocation("neverland")))
+
+	solution, err = solveSingleDataset(&CostOptimizedSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal("cheap"))
+	g.Expect(solution.DataPath[0].StorageAccount.Geography).To(gomega.Equal(taxonomy.ProcessingLocation("theshire")))
+}
+
+// the cost model combines the cost and the latency of the modules
+func TestSolversCostModel(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addReadModule(g, env, "read-a")
+	addReadModule(g, env, "read-b")
+	addCluster(env, multicluster.Cluster{Name: "cluster", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	addCost(env, taxonomy.Module, "read-a", "5")
+	addCost(env, taxonomy.Module, "read-b", "10")
+	for module, latency := range map[string]string{"read-a": "20", "read-b": "1"} {
+		addAttribute(env, &taxonomy.InfrastructureElement{
+			Name:       "latency",
+			MetricName: "latency",
+			Value:      latency,
+			Object:     taxonomy.Module,
+			Instance:   module,
+		})
+	}
+	asset := createReadRequest()
+
+	// without a cost model the cost is measured by the cost metric only
+	solution, err := solveSingleDataset(&CostOptimizedSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-a"))
+
+	env.AttributeManager.CostModel = []infraattributes.CostWeight{
+		{MetricName: DefaultCostMetric},
+		{MetricName: "latency", Weight: "0.5"},
+	}
+	solution, err = solveSingleDataset(&CostOptimizedSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-b"))
+
+	// an explicit metric overrides the cost model
+	solution, err = solveSingleDataset(&CostOptimizedSolver{Metric: DefaultCostMetric}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-a"))
+}
+
+// without cost attributes the data paths are chosen in a deterministic order
+func TestSolversWithoutCosts(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	for _, name := range []string{"read-c", "read-a", "read-b"} {
+		addReadModule(g, env, name)
+	}
+	addCluster(env, multicluster.Cluster{Name: "cluster", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	for i := 0; i < 10; i++ {
+		solution, err := solveSingleDataset(&CostOptimizedSolver{}, env, asset, &testLog)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+		g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("read-a"))
+	}
+}
+
+// a filter references a column that is redacted, the filter is applied first unless the policies specify otherwise
+func TestSolversActionOrder(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addReadModule(g, env, "read", "RedactAction", taxonomy.FilterActionName)
+	addCluster(env, multicluster.Cluster{Name: "cluster", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	redact := taxonomy.Action{Name: "RedactAction", AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+		"RedactAction": map[string]interface{}{"columns": []string{"Country"}},
+	}}}
+	filter := taxonomy.Action{Name: taxonomy.FilterActionName, AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+		string(taxonomy.FilterActionName): map[string]interface{}{
+			"predicates": []map[string]interface{}{{"column": "Country", "operator": "eq", "value": "theshire"}}},
+	}}}
+	asset := createReadRequest()
+	asset.Actions = []taxonomy.Action{redact, filter}
+
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Actions).To(gomega.Equal([]taxonomy.Action{filter, redact}))
+
+	// an explicit priority overrides the default order
+	redact.AdditionalProperties.Items[taxonomy.ActionPriorityKey] = 1
+	asset.Actions = []taxonomy.Action{filter, redact}
+	solution, err = solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath[0].Actions).To(gomega.Equal([]taxonomy.Action{redact, filter}))
+}
+
+// two read modules can redact, the preferred one is selected regardless of the solver
+func TestSolversModulePreferences(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	env := newEnvironment()
+	addReadModule(g, env, "read")
+	addReadModule(g, env, "redact-a", "RedactAction")
+	addReadModule(g, env, "redact-b", "RedactAction")
+	env.Modules["redact-b"].Labels = map[string]string{"fybrik.io/audited": "true"}
+	addCluster(env, multicluster.Cluster{Name: "cluster", Metadata: multicluster.ClusterMetadata{Region: "theshire"}})
+	asset := createReadRequest()
+	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
+
+	// preferring a module that does not support the action has no effect
+	env.ModulePreferences = []datapath.ModulePreference{{Action: "RedactAction", Module: "read"}}
+	solution, err := solveSingleDataset(&DefaultSolver{}, env, asset, &testLog)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+	g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("redact-a"))
+
+	for _, preference := range []datapath.ModulePreference{
+		{Action: "RedactAction", Module: "redact-b"},
+		{Action: "RedactAction", LabelKey: "fybrik.io/audited", LabelValue: "true"},
+	} {
+		env.ModulePreferences = []datapath.ModulePreference{preference}
+		for _, solver := range []Solver{&DefaultSolver{}, &CostOptimizedSolver{}} {
+			solution, err := solveSingleDataset(solver, env, asset, &testLog)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(solution.DataPath).To(gomega.HaveLen(1))
+			g.Expect(solution.DataPath[0].Module.Name).To(gomega.Equal("redact-b"))
+			g.Expect(solution.DataPath[0].Actions).To(gomega.Equal(asset.Actions))
+		}
+	}
+}
+
+func TestModulePreferencesFromEnvironment(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	t.Setenv(environment.ModulePreferencesKey, "RedactAction=audited-redact, HashAction=fybrik.io/approved=true")
+	preferences, err := ModulePreferencesFromEnvironment()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(preferences).To(gomega.Equal([]datapath.ModulePreference{
+		{Action: "RedactAction", Module: "audited-redact"},
+		{Action: "HashAction", LabelKey: "fybrik.io/approved", LabelValue: "true"},
+	}))
+
+	t.Setenv(environment.ModulePreferencesKey, "RedactAction")
+	_, err = ModulePreferencesFromEnvironment()
+	g.Expect(err).To(gomega.HaveOccurred())
+}
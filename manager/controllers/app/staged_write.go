@@ -0,0 +1,63 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/storagemanager"
+)
+
+// StagedWriteCommitFailed is the error reported when the data written by a transactional module can not be committed
+const StagedWriteCommitFailed = "the written data could not be committed"
+
+// isStaged returns true if the data written to the asset by a transactional module has not been committed yet
+func isStaged(applicationContext ApplicationContext, assetID string) bool {
+	provisioned, found := applicationContext.Application.Status.ProvisionedStorage[assetID]
+	return found && provisioned.Staging != nil
+}
+
+// commitStagedWrite moves the data written by a transactional module from the staging storage to the storage of the asset.
+// It is called once the module has run to completion, and does nothing if no data is staged for the asset.
+func (r *FybrikApplicationReconciler) commitStagedWrite(applicationContext ApplicationContext, assetID string) error {
+	if !isStaged(applicationContext, assetID) {
+		return nil
+	}
+	provisioned := applicationContext.Application.Status.ProvisionedStorage[assetID]
+	req := &storagemanager.CommitStorageRequest{
+		Connection:  provisioned.Staging.Connection,
+		Destination: provisioned.Details.Connection,
+		Secret:      provisioned.SecretRef,
+		Opts:        storagemanager.Options{},
+	}
+	if err := r.StorageManager.CommitStorage(req); err != nil {
+		return err
+	}
+	provisioned.Staging = nil
+	applicationContext.Application.Status.ProvisionedStorage[assetID] = provisioned
+	applicationContext.Log.Info().Bool(logging.FORUSER, true).Str(logging.DATASETID, assetID).
+		Msg("The written data has been committed")
+	return nil
+}
+
+// discardStagedWrite deletes the staging storage of an asset whose transactional module has failed,
+// so that the partially written data never becomes visible. It does nothing if no data is staged for the asset.
+func (r *FybrikApplicationReconciler) discardStagedWrite(applicationContext ApplicationContext, assetID string) error {
+	provisioned, found := applicationContext.Application.Status.ProvisionedStorage[assetID]
+	if !found || provisioned.Staging == nil {
+		return nil
+	}
+	req := &storagemanager.DeleteStorageRequest{
+		Connection: provisioned.Staging.Connection,
+		Secret:     provisioned.SecretRef,
+		Opts:       storagemanager.Options{},
+	}
+	if err := r.StorageManager.DeleteStorage(req); err != nil {
+		return err
+	}
+	provisioned.Staging = nil
+	applicationContext.Application.Status.ProvisionedStorage[assetID] = provisioned
+	applicationContext.Log.Warn().Bool(logging.FORUSER, true).Str(logging.DATASETID, assetID).
+		Msg("The write has failed and the staged data has been discarded")
+	return nil
+}
@@ -4,18 +4,65 @@
 package mockup
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"sort"
 	"strings"
 
+	"fybrik.io/fybrik/pkg/connectors"
 	dc "fybrik.io/fybrik/pkg/connectors/datacatalog/clients"
+	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/serde"
+	"fybrik.io/fybrik/pkg/vault"
 )
 
+// Scenarios of the mock data catalog defined by the asset ID, i.e., the part of the dataset ID after the catalog ID
+const (
+	// PIIAsset is an asset with columns tagged as personal information
+	PIIAsset = "pii-asset"
+	// MissingAsset is an asset that does not exist in any catalog
+	MissingAsset = "missing-asset"
+	// SchemaAsset is an asset whose columns are registered in the catalog, and whose access is allowed
+	SchemaAsset = "schema-dataset"
+	// ColumnTagsAsset is an asset whose typed columns are tagged, and whose columns tagged as PIIColumnTag are redacted
+	ColumnTagsAsset = "column-tags-dataset"
+	// NoConnectionAsset is an asset registered in the catalog without the details of its connection
+	NoConnectionAsset = "no-connection-dataset"
+	// MinIOAsset is an asset stored in MinIO, whose S3 connection defines a custom endpoint, a region and the path style
+	MinIOAsset = "minio-dataset"
+	// ProjectionAsset is an asset whose typed columns are registered in the catalog, and restricted to Name and Country by a projection
+	ProjectionAsset = "projection-dataset"
+	// VersionedAsset is an asset with the versions v1, v2 and v3, whose columns and S3 objects differ.
+	// The S3 object of each version is stored under the version prefix, e.g., "v2/small.csv".
+	VersionedAsset = "versioned-dataset"
+	// AlternativeConnectionsAsset is an asset served by the connection of its catalog, which is also available
+	// as a parquet object in S3
+	AlternativeConnectionsAsset = "alternative-connections-dataset"
+)
+
+// LatestAssetVersion is the version of VersionedAsset returned if no version is requested
+const LatestAssetVersion = "v3"
+
+// versionedAssetColumns are the columns of each version of VersionedAsset
+var versionedAssetColumns = map[string][]datacatalog.ResourceColumn{
+	"v1":               {{Name: "Name", Type: "string"}, {Name: "Age", Type: "integer"}},
+	"v2":               {{Name: "Name", Type: "string"}, {Name: "Age", Type: "integer"}, {Name: "Country", Type: "string"}},
+	LatestAssetVersion: {{Name: "Name", Type: "string"}, {Name: "Country", Type: "string"}},
+}
+
+// MinIOEndpoint is the S3 endpoint of MinIOAsset
+const MinIOEndpoint = "http://minio.fybrik-system:9000"
+
+// PIIColumnTag is the tag of the columns of ColumnTagsAsset that contain personal information
+const PIIColumnTag = "pii"
+
+// DataCatalogDummy is a mock for the DataCatalog interface used in tests.
+// The catalog ID before "/" selects the format, connection and tags of the asset,
+// while the asset ID after "/" may select a scenario, e.g., "s3/pii-asset" or "s3/missing-asset".
 type DataCatalogDummy struct {
 	dataDetails map[string]datacatalog.GetAssetResponse
 }
@@ -32,17 +79,91 @@ func (d *DataCatalogDummy) GetAssetInfo(in *datacatalog.GetAssetRequest, creds s
 	}
 
 	catalogID := splittedID[0]
+	assetID := splittedID[1]
 
 	dataDetails, found := d.dataDetails[catalogID]
+	switch assetID {
+	case MissingAsset:
+		found = false
+	case PIIAsset:
+		piiTags := taxonomy.Tags{}
+		piiTags.Items = map[string]interface{}{"PII": true}
+		dataDetails.ResourceMetadata.Columns = []datacatalog.ResourceColumn{
+			{Name: "Name"},
+			{Name: "SSN", Tags: &piiTags},
+			{Name: "Country"},
+		}
+	case SchemaAsset:
+		dataDetails.ResourceMetadata.Columns = []datacatalog.ResourceColumn{
+			{Name: "Name"},
+			{Name: "Age"},
+			{Name: "Country"},
+		}
+	case ColumnTagsAsset:
+		piiTags := taxonomy.Tags{}
+		piiTags.Items = map[string]interface{}{PIIColumnTag: true}
+		dataDetails.ResourceMetadata.Columns = []datacatalog.ResourceColumn{
+			{Name: "Name", Type: "string", Tags: &piiTags},
+			{Name: "Age", Type: "integer"},
+			{Name: "SSN", Type: "string", Tags: &piiTags},
+			{Name: "Country", Type: "string"},
+		}
+	case ProjectionAsset:
+		dataDetails.ResourceMetadata.Columns = []datacatalog.ResourceColumn{
+			{Name: "Name", Type: "string"},
+			{Name: "Age", Type: "integer"},
+			{Name: "SSN", Type: "string"},
+			{Name: "Country", Type: "string"},
+		}
+	case VersionedAsset:
+		version := in.Version
+		if version == "" {
+			version = LatestAssetVersion
+		}
+		dataDetails.ResourceMetadata.Version = version
+		dataDetails.ResourceMetadata.Columns, found = versionedAssetColumns[version]
+		dataDetails.Details.Connection = taxonomy.Connection{
+			Name: S3,
+			AdditionalProperties: serde.Properties{
+				Items: map[string]interface{}{
+					string(S3): map[string]interface{}{
+						"endpoint":   "s3.eu-gb.cloud-object-storage.appdomain.cloud",
+						"bucket":     "fybrik-test-bucket",
+						"object_key": version + "/small.csv",
+					},
+				},
+			},
+		}
+	case NoConnectionAsset:
+		dataDetails.Details.Connection = taxonomy.Connection{}
+	case AlternativeConnectionsAsset:
+		dataDetails.AlternativeDetails = []datacatalog.ResourceDetails{d.dataDetails[string(S3)].Details}
+	case MinIOAsset:
+		dataDetails.Details.Connection = taxonomy.Connection{
+			Name: S3,
+			AdditionalProperties: serde.Properties{
+				Items: map[string]interface{}{
+					string(S3): map[string]interface{}{
+						"endpoint":         MinIOEndpoint,
+						"bucket":           "fybrik-test-bucket",
+						"object_key":       "small.csv",
+						"region":           "us-east-1",
+						"force_path_style": "true",
+					},
+				},
+			},
+		}
+	}
 	if found {
-		responseBytes, errJSON := json.MarshalIndent(&dataDetails, "", "\t")
+		responseBytes, errJSON := logging.MarshalRedacted(&dataDetails, true)
 		if errJSON != nil {
 			return nil, fmt.Errorf("error in GetAssetInfo in DataCatalogDummy: %v", errJSON)
 		}
 		log.Printf("GetAssetInfo in DataCatalogDummy returns: " + string(responseBytes))
 		return &dataDetails, nil
 	}
-	return nil, errors.New(dc.AssetIDNotFound)
+	// the connectors report a missing asset as a not found response
+	return nil, connectors.NewHTTPError(http.StatusNotFound, errors.New(dc.AssetIDNotFound))
 }
 
 func (d *DataCatalogDummy) CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error) {
@@ -63,6 +184,23 @@ func (m *DataCatalogDummy) UpdateAsset(in *datacatalog.UpdateAssetRequest, creds
 	return &datacatalog.UpdateAssetResponse{Status: "UpdateAsset not implemented in DataCatalogDummy"}, nil
 }
 
+// ListAssets returns all the catalogs known to the mock in a single page, sorted by the catalog ID
+func (d *DataCatalogDummy) ListAssets(in *datacatalog.ListAssetsRequest, creds string) (*datacatalog.ListAssetsResponse, error) {
+	catalogIDs := make([]string, 0, len(d.dataDetails))
+	for catalogID := range d.dataDetails {
+		catalogIDs = append(catalogIDs, catalogID)
+	}
+	sort.Strings(catalogIDs)
+	response := &datacatalog.ListAssetsResponse{Assets: []datacatalog.AssetSummary{}}
+	for _, catalogID := range catalogIDs {
+		response.Assets = append(response.Assets, datacatalog.AssetSummary{
+			AssetID:          taxonomy.AssetID(catalogID + "/" + catalogID),
+			ResourceMetadata: d.dataDetails[catalogID].ResourceMetadata,
+		})
+	}
+	return response, nil
+}
+
 func (d *DataCatalogDummy) Close() error {
 	return nil
 }
@@ -75,6 +213,8 @@ func NewTestCatalog() *DataCatalogDummy {
 
 	tags := taxonomy.Tags{}
 	tags.Items = map[string]interface{}{"PI": true}
+	restrictedTags := taxonomy.Tags{}
+	restrictedTags.Items = map[string]interface{}{"PI": true, RestrictedTag: true}
 
 	geo := "theshire" //nolint:goconst
 	geoExternal := "neverland"
@@ -84,7 +224,7 @@ func NewTestCatalog() *DataCatalogDummy {
 	var parquetFormat taxonomy.DataFormat = "parquet"
 	var jsonFormat taxonomy.DataFormat = "json"
 	dummyResourceName := "xxx"
-	dummyCredentials := "dummy"
+	dummyCredentials := vault.PathForReadingKubeSecret("fybrik-system", "dummy")
 
 	s3Connection := taxonomy.Connection{
 		Name: S3,
@@ -174,6 +314,21 @@ func NewTestCatalog() *DataCatalogDummy {
 		},
 	}
 
+	// assets of s3-restricted are denied by the mock policy manager based on their tags
+	dummyCatalog.dataDetails["s3-restricted"] = datacatalog.GetAssetResponse{
+		ResourceMetadata: datacatalog.ResourceMetadata{
+			Name:      dummyResourceName,
+			Owner:     "teamX",
+			Geography: geo,
+			Tags:      &restrictedTags,
+		},
+		Credentials: dummyCredentials,
+		Details: datacatalog.ResourceDetails{
+			Connection: s3Connection,
+			DataFormat: parquetFormat,
+		},
+	}
+
 	dummyCatalog.dataDetails["s3-incomplete"] = datacatalog.GetAssetResponse{
 		ResourceMetadata: datacatalog.ResourceMetadata{
 			Name:      dummyResourceName,
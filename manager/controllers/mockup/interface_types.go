@@ -13,7 +13,9 @@ const (
 	Kafka       taxonomy.ConnectionType = "kafka"
 	JdbcDB2     taxonomy.ConnectionType = "db2"
 	ArrowFlight taxonomy.ConnectionType = "fybrik-arrow-flight"
+	HTTP        taxonomy.ConnectionType = "http"
 
 	Parquet taxonomy.DataFormat = "parquet"
 	CSV     taxonomy.DataFormat = "csv"
+	Arrow   taxonomy.DataFormat = "arrow"
 )
@@ -4,153 +4,218 @@
 package mockup
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	connectors "fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/random"
 )
 
 const (
-	DenyAction   = "Deny"
-	RedactAction = "RedactAction"
-	FilterAction = "FilterAction"
+	DenyAction       = "Deny"
+	AllowAction      = "Allow"
+	RedactAction     = "RedactAction"
+	FilterAction     = "FilterAction"
+	HashAction       = "HashAction"
+	ProjectionAction = "ProjectionAction"
 )
 
+// RestrictedTag is the catalog tag of assets that are denied regardless of the scenario defined by the asset ID
+const RestrictedTag = "restricted"
+
+// TimeBoundedAccess is the duration of the access to time-bounded-dataset, starting at the time of the request
+const TimeBoundedAccess = time.Hour
+
+// AdminGroup is the group of users that get the full data of identity-dataset, while other users get it redacted
+const AdminGroup = "admin"
+
+// CustomMask replaces the redacted values of masked-dataset
+const CustomMask = "[REDACTED]"
+
+// AllowedDestinations are the destinations to which the access to allowed-destinations-dataset is allowed.
+// Unlike allow-theshire, the destination is checked by the manager rather than by the policy manager.
+var AllowedDestinations = []string{"theshire"}
+
 // MockPolicyManager is a mock for PolicyManager interface used in tests
 type MockPolicyManager struct {
 	connectors.PolicyManager
 }
 
-func deserializeToTaxonomyAction(action map[string]interface{}, taxAction *taxonomy.Action) error {
-	actionBytes, errJSON := json.MarshalIndent(action, "", "\t")
-	if errJSON != nil {
-		return fmt.Errorf("error Marshalling in deserializeToTaxonomyAction: %v", errJSON)
+// hasTag returns true if the asset metadata provided by the catalog sets the given tag to true
+func hasTag(metadata *datacatalog.ResourceMetadata, tag string) bool {
+	if metadata == nil || metadata.Tags == nil {
+		return false
 	}
-	err := json.Unmarshal(actionBytes, taxAction)
-	if err != nil {
-		return fmt.Errorf("error in unmarshalling in deserializeToTaxonomyAction: %v", err)
+	value, _ := metadata.Tags.Items[tag].(bool)
+	return value
+}
+
+// taggedColumns returns the columns of the asset that the catalog tags with the given tag
+func taggedColumns(metadata *datacatalog.ResourceMetadata, tag string) []string {
+	columns := []string{}
+	if metadata == nil {
+		return columns
+	}
+	for _, column := range metadata.Columns {
+		if column.Tags == nil {
+			continue
+		}
+		if value, _ := column.Tags.Items[tag].(bool); value {
+			columns = append(columns, column.Name)
+		}
 	}
-	return nil
+	return columns
+}
+
+// inGroup returns true if the identity of the requesting user belongs to the given group
+func inGroup(identity *policymanager.RequestIdentity, group string) bool {
+	if identity == nil {
+		return false
+	}
+	for _, g := range identity.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
 }
 
 // GetPoliciesDecisions implements the PolicyCompiler interface
 //
 //nolint:funlen
-func (m *MockPolicyManager) GetPoliciesDecisions(input *policymanager.GetPolicyDecisionsRequest,
+func (m *MockPolicyManager) GetPoliciesDecisions(ctx context.Context, input *policymanager.GetPolicyDecisionsRequest,
 	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	log.Printf("Received OpenAPI request in mockup GetPoliciesDecisions: ")
 	log.Printf("ProcessingGeography: %s", input.Action.ProcessingLocation)
 	log.Printf("Destination: " + input.Action.Destination)
-	nameKey := "name"
 	theshireLiteral := "theshire"
 	msg := ""
 	datasetID := string(input.Resource.ID)
 	log.Printf("   DataSetID: " + datasetID)
 	respResult := []policymanager.ResultItem{}
-	policyManagerResult := policymanager.ResultItem{}
 
 	splittedID := strings.SplitN(datasetID, "/", 2)
 	if len(splittedID) != 2 {
 		panic(fmt.Sprintf("Invalid dataset ID for mock: %s", datasetID))
 	}
 	assetID := splittedID[1]
-	switch assetID {
-	case "allow-dataset":
+	scenario := assetID
+	if hasTag(input.Resource.Metadata, RestrictedTag) {
+		scenario = RestrictedTag
+	}
+	switch scenario {
+	case RestrictedTag:
+		respResult = append(respResult, policymanager.ResultItem{
+			Action: taxonomy.NewDenyAction("the asset is tagged as restricted", "restricted-tag"),
+		})
+	case "identity-dataset":
+		if !inGroup(input.Identity, AdminGroup) {
+			respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewRedactAction("SSN")})
+		}
+	case "allow-dataset", SchemaAsset, MinIOAsset, AlternativeConnectionsAsset:
 		// empty result simulates allow
 		// no need to construct any result item
+	case ColumnTagsAsset:
+		// the redacted columns are selected by their tags rather than by their names
+		if columns := taggedColumns(input.Resource.Metadata, PIIColumnTag); len(columns) > 0 {
+			respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewRedactAction(columns...)})
+		}
+	case "time-bounded-dataset":
+		// the access is allowed for a limited time, and denied by default afterwards
+		requestTime, err := time.Parse(time.RFC3339, input.Time)
+		if err != nil {
+			requestTime = time.Now()
+		}
+		respResult = append(respResult, policymanager.ResultItem{
+			Action:   taxonomy.NewAllowAction("time-bounded-allow"),
+			Validity: &policymanager.Validity{NotAfter: requestTime.Add(TimeBoundedAccess).UTC().Format(time.RFC3339)},
+		})
+	case "explicit-allow-dataset":
+		// an explicit allow is required if the access is denied by default
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewAllowAction("explicit-allow")})
 	case "new-dataset":
 		msg = "no checks have been invoked"
 	case "deny-dataset":
-		actionOnDataset := taxonomy.Action{}
-		action := make(map[string]interface{})
-		action[nameKey] = DenyAction
-		denyAction := map[string]interface{}{}
-		action[DenyAction] = denyAction
-
-		err := deserializeToTaxonomyAction(action, &actionOnDataset)
-		if err != nil {
-			log.Print("error in deserializeToTaxonomyAction for scenario deny-dataset :", err)
-			return nil, err
-		}
-		policyManagerResult.Action = actionOnDataset
-		respResult = append(respResult, policyManagerResult)
-
+		respResult = append(respResult, policymanager.ResultItem{
+			Action: taxonomy.NewDenyAction("", ""),
+			Policy: "Deny access to deny-dataset",
+		})
 	case "allow-theshire":
 		if input.Action.Destination != theshireLiteral {
-			actionOnDataset := taxonomy.Action{}
-			action := make(map[string]interface{})
-			action[nameKey] = DenyAction
-			denyAction := map[string]interface{}{}
-			action[DenyAction] = denyAction
-
-			err := deserializeToTaxonomyAction(action, &actionOnDataset)
-			if err != nil {
-				log.Print("error in deserializeToTaxonomyAction for scenario allow-theshire:", err)
-				return nil, err
-			}
-			policyManagerResult.Action = actionOnDataset
-			respResult = append(respResult, policyManagerResult)
+			respResult = append(respResult, policymanager.ResultItem{
+				Action: taxonomy.NewDenyAction("destination not permitted", "allow-theshire-destination"),
+			})
 		}
-
+	case "allowed-destinations-dataset":
+		respResult = append(respResult, policymanager.ResultItem{
+			Action: taxonomy.NewAllowAction("allowed-destinations", AllowedDestinations...),
+		})
 	case "deny-theshire":
 		if input.Action.Destination == theshireLiteral {
-			actionOnDataset := taxonomy.Action{}
-			action := make(map[string]interface{})
-			action[nameKey] = DenyAction
-			denyAction := map[string]interface{}{}
-			action[DenyAction] = denyAction
-
-			err := deserializeToTaxonomyAction(action, &actionOnDataset)
-			if err != nil {
-				log.Print("error in deserializeToTaxonomyAction for scenario deny-theshire:", err)
-				return nil, err
-			}
-			policyManagerResult.Action = actionOnDataset
-			respResult = append(respResult, policyManagerResult)
+			respResult = append(respResult, policymanager.ResultItem{
+				Action: taxonomy.NewDenyAction("destination theshire is not permitted", "deny-theshire-destination"),
+			})
+		}
+	case "copy-dataset", "copy-restricted-dataset":
+		// copy-dataset can not be written to neverland, copy-restricted-dataset can not be read to neverland
+		deniedFlow := taxonomy.WriteFlow
+		if assetID == "copy-restricted-dataset" {
+			deniedFlow = taxonomy.ReadFlow
+		}
+		if input.Action.Destination == "neverland" && input.Action.ActionType == deniedFlow {
+			respResult = append(respResult, policymanager.ResultItem{
+				Action: taxonomy.NewDenyAction(string(deniedFlow)+" to neverland is not permitted", assetID+"-destination"),
+			})
+		} else if input.Action.ActionType == taxonomy.ReadFlow {
+			// the redaction is required on read only
+			respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewRedactAction("SSN")})
 		}
-
 	case "filter-dataset":
-		actionOnCols := taxonomy.Action{}
-		action := make(map[string]interface{})
-		action[nameKey] = FilterAction
-		filterAction := make(map[string]interface{})
-		filterAction["query"] = "Country == 'UK'"
-		action[FilterAction] = filterAction
-
-		err := deserializeToTaxonomyAction(action, &actionOnCols)
-		if err != nil {
-			log.Print("error in deserializeToTaxonomyAction for scenario filter-dataset:", err)
-			return nil, err
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewFilterAction("Country == 'UK'",
+			taxonomy.FilterPredicate{Column: "Country", Operator: taxonomy.Equal, Value: taxonomy.NewStringValue("UK")})})
+	case "hash-dataset":
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewHashAction(taxonomy.SHA256, "SSN")})
+	case "typed-redact-dataset":
+		// redact SSN, replacing the values by the type of the column
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewTypedRedactAction(taxonomy.RedactionStrategies{
+			String:   taxonomy.RedactMaskedString,
+			Numeric:  taxonomy.RedactZero,
+			Temporal: taxonomy.RedactNull,
+			Default:  taxonomy.RedactNull,
+		}, "SSN")})
+	case "masked-dataset":
+		// redact SSN, replacing the values by a custom mask
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewMaskedRedactAction(CustomMask, "SSN")})
+	case ProjectionAsset:
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewProjectionAction("Name", "Country")})
+	case "redact-projection-dataset", "conflicting-actions-dataset":
+		// redact SSN and expose an allow-list of columns, conflicting with the redaction if it includes SSN
+		projectedColumns := []string{"Name", "Country"}
+		if assetID == "conflicting-actions-dataset" {
+			projectedColumns = append(projectedColumns, "SSN")
 		}
-		policyManagerResult.Action = actionOnCols
-		respResult = append(respResult, policyManagerResult)
+		respResult = append(respResult,
+			policymanager.ResultItem{Action: taxonomy.NewRedactAction("SSN")},
+			policymanager.ResultItem{Action: taxonomy.NewProjectionAction(projectedColumns...)})
 	default:
-		actionOnCols := taxonomy.Action{}
-		action := make(map[string]interface{})
-		action[nameKey] = RedactAction
-		redactAction := make(map[string]interface{})
-		redactAction["columns"] = []string{"SSN"}
-		action[RedactAction] = redactAction
-
-		err := deserializeToTaxonomyAction(action, &actionOnCols)
-		if err != nil {
-			log.Print("error in deserializeToTaxonomyAction for scenario default:", err)
-			return nil, err
-		}
-		policyManagerResult.Action = actionOnCols
-		respResult = append(respResult, policyManagerResult)
+		respResult = append(respResult, policymanager.ResultItem{Action: taxonomy.NewRedactAction("SSN")})
 	}
 
 	decisionID, _ := random.Hex(20) //nolint:revive,gomnd
 	policyManagerResp := &policymanager.GetPolicyDecisionsResponse{DecisionID: decisionID, Result: respResult, Message: msg}
 
-	res, err := json.MarshalIndent(policyManagerResp, "", "\t")
+	res, err := logging.MarshalRedacted(policyManagerResp, true)
 	if err != nil {
 		log.Print("error in marshalling policy manager response :", err)
 		return nil, err
@@ -159,3 +224,24 @@ func (m *MockPolicyManager) GetPoliciesDecisions(input *policymanager.GetPolicyD
 
 	return policyManagerResp, nil
 }
+
+// MockBatchPolicyManager is a mock of a policy manager that evaluates the requests of several assets at once.
+// It is a separate type, since the test policy managers that embed MockPolicyManager to override its decisions
+// would otherwise inherit a batch method that bypasses their overrides.
+type MockBatchPolicyManager struct {
+	MockPolicyManager
+}
+
+// GetPoliciesDecisionsBatch implements the BatchPolicyManager interface by evaluating every request by its scenario
+func (m *MockBatchPolicyManager) GetPoliciesDecisionsBatch(ctx context.Context, input []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	responses := map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse{}
+	for _, in := range input {
+		resp, err := m.GetPoliciesDecisions(ctx, in, creds)
+		if err != nil {
+			return nil, err
+		}
+		responses[in.Resource.ID] = resp
+	}
+	return responses, nil
+}
@@ -12,6 +12,14 @@ const (
 	BlueprintNamespaceLabel   = "app.fybrik.io/blueprint-namespace"
 	BlueprintNameLabel        = "app.fybrik.io/blueprint-name"
 	FybrikAppUUID             = "app.fybrik.io/app-uuid"
+	PolicyDecisionsAnnotation = "app.fybrik.io/policy-decisions"
+	// ApprovedPlotterAnnotation approves the creation of a staged Plotter, whose digest is the annotation value
+	ApprovedPlotterAnnotation = "app.fybrik.io/approved-plotter"
+	// PolicyManagerAnnotation selects one of the named policy managers to evaluate the governance actions of a FybrikApplication
+	PolicyManagerAnnotation = "app.fybrik.io/policy-manager"
+	// CostCenterLabel attributes the resources generated for a FybrikApplication to a cost center.
+	// It is copied from the annotation of the same name of the FybrikApplication.
+	CostCenterLabel = "app.fybrik.io/cost-center"
 )
 
 func GetApplicationClusterFromLabels(labels map[string]string) string {
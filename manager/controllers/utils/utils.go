@@ -18,7 +18,12 @@ import (
 
 // IsDenied returns true if the data access is denied
 func IsDenied(actionName taxonomy.ActionName) bool {
-	return actionName == "Deny" // TODO FIX THIS
+	return actionName == taxonomy.DenyActionName
+}
+
+// IsAllowed returns true if the data access is explicitly allowed
+func IsAllowed(actionName taxonomy.ActionName) bool {
+	return actionName == taxonomy.AllowActionName
 }
 
 // Generating a release name based on the blueprint module and application name/uuid
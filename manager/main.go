@@ -5,12 +5,15 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -27,6 +30,7 @@ import (
 	"fybrik.io/fybrik/manager/controllers"
 	"fybrik.io/fybrik/manager/controllers/app"
 	"fybrik.io/fybrik/pkg/adminconfig"
+	"fybrik.io/fybrik/pkg/connectors"
 	dcclient "fybrik.io/fybrik/pkg/connectors/datacatalog/clients"
 	pmclient "fybrik.io/fybrik/pkg/connectors/policymanager/clients"
 	storage "fybrik.io/fybrik/pkg/connectors/storagemanager/clients"
@@ -38,7 +42,10 @@ import (
 	"fybrik.io/fybrik/pkg/multicluster"
 	"fybrik.io/fybrik/pkg/multicluster/local"
 	"fybrik.io/fybrik/pkg/multicluster/razee"
+	"fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 	"fybrik.io/fybrik/pkg/utils"
+	"fybrik.io/fybrik/pkg/validate"
 )
 
 const certSubDir = "/k8s-webhook-server"
@@ -55,6 +62,7 @@ func init() {
 	_ = fappv2.AddToScheme(scheme)
 	_ = corev1.AddToScheme(scheme)
 	_ = coordinationv1.AddToScheme(scheme)
+	_ = authorizationv1.AddToScheme(scheme)
 }
 
 //nolint:funlen,gocyclo
@@ -63,6 +71,28 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 	setupLog.Info().Msg("creating manager. based on: gitTag=" + gitTag + ", latest gitCommit=" + gitCommit)
 	environment.LogEnvVariables(&setupLog)
 
+	// custom actions of the organization are validated without recompiling the taxonomy
+	actionExtensions, err := validate.LoadActionExtensionsFromEnvironment()
+	if err != nil {
+		setupLog.Error().Err(err).Msg("unable to load the taxonomy extensions")
+		return 1
+	}
+	if len(actionExtensions) > 0 {
+		setupLog.Info().Msg("Taxonomy extended with the actions: " + fmt.Sprint(actionExtensions))
+	}
+
+	// the spans of the reconciles and of the connector requests are exported if a collector is configured
+	shutdownTracing, err := tracing.SetupFromEnvironment(context.Background(), "fybrik-manager")
+	if err != nil {
+		setupLog.Error().Err(err).Msg("unable to set up the tracing")
+		return 1
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Warn().Err(err).Msg("unable to flush the spans")
+		}
+	}()
+
 	var applicationNamespaceSelector fields.Selector
 	applicationNamespace := environment.GetApplicationNamespace()
 	if len(applicationNamespace) > 0 {
@@ -134,6 +164,12 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 	if enableApplicationController {
 		setupLog.Trace().Msg("creating FybrikApplication controller")
 
+		// the connector clients are created with the proxy, thus an invalid proxy URL fails the startup
+		if _, err = tls.ParseProxyURL(environment.GetConnectorProxyURL()); err != nil {
+			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("invalid connector proxy URL")
+			return 1
+		}
+
 		// Initialize PolicyManager interface
 		policyManager, err := newPolicyManager()
 		if err != nil {
@@ -145,6 +181,26 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 				setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to close policy manager facade")
 			}
 		}()
+		namedPolicyManagers, err := newNamedPolicyManagers()
+		if err != nil {
+			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to create the named policy managers")
+			return 1
+		}
+		defer func() {
+			for name, namedPolicyManager := range namedPolicyManagers {
+				if err = namedPolicyManager.Close(); err != nil {
+					setupLog.Error().Err(err).Str(logging.CONNECTOR, name).Msg("unable to close policy manager facade")
+				}
+			}
+		}()
+
+		// The manager is ready only if the connectors are reachable
+		for _, checker := range newConnectorReadinessCheckers() {
+			if err = mgr.AddReadyzCheck(checker.Name, checker.Check); err != nil {
+				setupLog.Error().Err(err).Str(logging.CONNECTOR, checker.Name).Msg("unable add a connector readiness check")
+				return 1
+			}
+		}
 
 		// Initialize DataCatalog interface
 		catalog, err := newDataCatalog()
@@ -191,6 +247,11 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 			evaluator,
 			infrastructureManager,
 		)
+		applicationController.NamedPolicyManagers = namedPolicyManagers
+		if applicationController.Solver, err = app.NewSolver(environment.GetModuleSelectionStrategy()); err != nil {
+			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to create the module selection solver")
+			return 1
+		}
 		if err = applicationController.SetupWithManager(mgr); err != nil {
 			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to create controller")
 			return 1
@@ -320,9 +381,35 @@ func newDataCatalog() (dcclient.DataCatalog, error) {
 	connectorURL := os.Getenv("CATALOG_CONNECTOR_URL")
 	setupLog.Info().Str(logging.CONNECTOR, providerName).Str("URL", connectorURL).
 		Msg("setting data catalog client")
-	return dcclient.NewDataCatalog(
+	catalog, err := dcclient.NewDataCatalog(
 		providerName,
 		connectorURL)
+	if err != nil {
+		return nil, err
+	}
+	catalog = dcclient.NewRateLimitedDataCatalog(catalog, newConnectorLimiter(providerName))
+	return dcclient.NewCircuitBreakingDataCatalog(catalog, newConnectorBreaker(providerName)), nil
+}
+
+// newConnectorLimiter returns a limiter of the requests sent to a connector, the limits are defined by the environment variables
+func newConnectorLimiter(name string) *connectors.Limiter {
+	config := connectors.RateLimitConfigFromEnvironment()
+	setupLog.Info().Str(logging.CONNECTOR, name).Msg("Connector rate limits: qps = " + fmt.Sprint(config.QPS) +
+		" burst=" + fmt.Sprint(config.Burst) + " max concurrent calls=" + fmt.Sprint(config.MaxConcurrentCalls))
+	return connectors.NewLimiter(config)
+}
+
+// newConnectorBreaker returns a circuit breaker of the requests sent to a connector, configured by the environment variables
+func newConnectorBreaker(name string) *connectors.CircuitBreaker {
+	config := connectors.BreakerConfigFromEnvironment()
+	setupLog.Info().Str(logging.CONNECTOR, name).Msg("Connector circuit breaker: failure threshold = " +
+		fmt.Sprint(config.FailureThreshold) + " cooldown=" + config.Cooldown.String())
+	return connectors.NewCircuitBreaker(name, config)
+}
+
+// policyManagerTimeout returns the timeout of the policy manager calls that are shared by identical concurrent requests
+func policyManagerTimeout() time.Duration {
+	return connectors.RequestTimeoutFromEnvironment(environment.PolicyManagerRequestTimeoutKey)
 }
 
 func newPolicyManager() (pmclient.PolicyManager, error) {
@@ -331,10 +418,93 @@ func newPolicyManager() (pmclient.PolicyManager, error) {
 	setupLog.Info().Str(logging.CONNECTOR, mainPolicyManagerName).Str("URL", mainPolicyManagerURL).
 		Msg("setting main policy manager client")
 
-	return pmclient.NewOpenAPIPolicyManager(
+	mainPolicyManager, err := pmclient.NewPolicyManager(
 		mainPolicyManagerName,
 		mainPolicyManagerURL,
 	)
+	if err != nil {
+		return nil, err
+	}
+	mainPolicyManager = pmclient.NewRateLimitedPolicyManager(mainPolicyManager, newConnectorLimiter(mainPolicyManagerName))
+	mainPolicyManager = pmclient.NewCircuitBreakingPolicyManager(mainPolicyManager, newConnectorBreaker(mainPolicyManagerName))
+	additionalConfigs, err := pmclient.AdditionalPolicyManagersFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	if len(additionalConfigs) == 0 {
+		// identical requests sent concurrently, e.g., by applications referencing the same dataset, share a single call
+		return pmclient.NewSingleFlightPolicyManager(mainPolicyManager, policyManagerTimeout()), nil
+	}
+	// the main policy manager is consulted first, followed by the additional ones in the specified order
+	policyManagers := []pmclient.PolicyManager{mainPolicyManager}
+	for i := range additionalConfigs {
+		setupLog.Info().Str(logging.CONNECTOR, additionalConfigs[i].Name).Str("URL", additionalConfigs[i].URL).
+			Msg("setting additional policy manager client")
+		policyManager, err := pmclient.NewPolicyManagerWithConfig(&additionalConfigs[i])
+		if err != nil {
+			return nil, err
+		}
+		policyManager = pmclient.NewRateLimitedPolicyManager(policyManager, newConnectorLimiter(additionalConfigs[i].Name))
+		policyManagers = append(policyManagers, pmclient.NewCircuitBreakingPolicyManager(policyManager,
+			newConnectorBreaker(additionalConfigs[i].Name)))
+	}
+	return pmclient.NewSingleFlightPolicyManager(pmclient.NewMultiPolicyManager(policyManagers...), policyManagerTimeout()), nil
+}
+
+// newNamedPolicyManagers returns the policy managers that the applications may select by name
+func newNamedPolicyManagers() (map[string]pmclient.PolicyManager, error) {
+	configs, err := pmclient.NamedPolicyManagersFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	policyManagers := map[string]pmclient.PolicyManager{}
+	for i := range configs {
+		setupLog.Info().Str(logging.CONNECTOR, configs[i].Name).Str("URL", configs[i].URL).
+			Msg("setting named policy manager client")
+		policyManager, err := pmclient.NewPolicyManagerWithConfig(&configs[i])
+		if err != nil {
+			return nil, err
+		}
+		policyManager = pmclient.NewRateLimitedPolicyManager(policyManager, newConnectorLimiter(configs[i].Name))
+		policyManager = pmclient.NewCircuitBreakingPolicyManager(policyManager, newConnectorBreaker(configs[i].Name))
+		policyManagers[configs[i].Name] = pmclient.NewSingleFlightPolicyManager(policyManager, policyManagerTimeout())
+	}
+	return policyManagers, nil
+}
+
+// newConnectorReadinessCheckers returns the readiness checks of the policy manager and data catalog connectors
+func newConnectorReadinessCheckers() []*connectors.ReadinessChecker {
+	checkers := []*connectors.ReadinessChecker{
+		connectors.NewReadinessChecker("policy-manager-"+os.Getenv("MAIN_POLICY_MANAGER_NAME"),
+			os.Getenv("MAIN_POLICY_MANAGER_CONNECTOR_URL")),
+		connectors.NewReadinessChecker("data-catalog-"+os.Getenv("CATALOG_PROVIDER_NAME"),
+			os.Getenv("CATALOG_CONNECTOR_URL")),
+	}
+	additionalConfigs, err := pmclient.AdditionalPolicyManagersFromEnvironment()
+	if err != nil {
+		// the error is reported when the policy manager is created
+		return checkers
+	}
+	for i := range additionalConfigs {
+		checkers = append(checkers, connectors.NewReadinessChecker("policy-manager-"+additionalConfigs[i].Name,
+			additionalConfigs[i].URL))
+	}
+	namedConfigs, err := pmclient.NamedPolicyManagersFromEnvironment()
+	if err != nil {
+		// the error is reported when the policy managers are created
+		return checkers
+	}
+	names := map[string]bool{}
+	for _, checker := range checkers {
+		names[checker.Name] = true
+	}
+	for i := range namedConfigs {
+		// a policy manager that is also an additional policy manager is checked once
+		if name := "policy-manager-" + namedConfigs[i].Name; !names[name] {
+			checkers = append(checkers, connectors.NewReadinessChecker(name, namedConfigs[i].URL))
+		}
+	}
+	return checkers
 }
 
 // newClusterManager decides based on the environment variables that are set which
@@ -46,6 +46,7 @@ fi
 
 # Avoid using webhooks in tests
 kubectl delete validatingwebhookconfiguration fybrik-system-validating-webhook
+kubectl delete mutatingwebhookconfiguration fybrik-system-mutating-webhook
 
 if [[ -z "${LATEST_BACKWARD_SUPPORTED_AFM_VERSION}" ]]; then
   # Use master version of arrow-flight-module according to https://github.com/fybrik/arrow-flight-module#version-compatbility-matrix
@@ -18,6 +18,7 @@ kubectl apply -f neverland-storage-account.yaml -n ${ADMIN_CRS_NAMESPACE}
 
 # Avoid using webhooks in tests
 kubectl delete validatingwebhookconfiguration fybrik-system-validating-webhook
+kubectl delete mutatingwebhookconfiguration fybrik-system-mutating-webhook
 
 # Apply policies
 kubectl -n ${FYBRIK_NAMESPACE} apply -f policy-cm.yaml
@@ -0,0 +1,93 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connection
+
+import (
+	"encoding/json"
+	"net"
+	"strconv"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// ArrowFlightType is the connection type of the endpoints served by the arrow-flight modules
+const ArrowFlightType taxonomy.ConnectionType = "fybrik-arrow-flight"
+
+// ArrowFlightTLSScheme is the scheme of arrow-flight endpoints served over TLS
+const ArrowFlightTLSScheme = "grpc+tls"
+
+// ArrowFlightTLS holds the TLS settings advertised by an arrow-flight endpoint in its "tls" property
+type ArrowFlightTLS struct {
+	// Reference to a secret holding the CA bundle used to verify the server certificate
+	CASecretRef *taxonomy.SecretRef `json:"caSecretRef,omitempty"`
+	// Reference to a secret holding the client certificate and key for mutual TLS
+	ClientCertSecretRef *taxonomy.SecretRef `json:"clientCertSecretRef,omitempty"`
+	// Server name used to verify the server certificate
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// ArrowFlightConnection holds the properties of a fybrik-arrow-flight connection, as defined by the taxonomy
+type ArrowFlightConnection struct {
+	// Server host
+	Hostname string `json:"hostname"`
+	// Server port
+	Port int32 `json:"port"`
+	// Scheme, e.g., grpc or grpc+tls
+	Scheme string `json:"scheme"`
+	// TLS settings required to connect to the server
+	TLS *ArrowFlightTLS `json:"tls,omitempty"`
+}
+
+// DecodeArrowFlight decodes the properties of an arrow-flight connection, e.g., the details of an endpoint
+// in the application status. The port may be given as a number or as a numeric string.
+func DecodeArrowFlight(properties map[string]interface{}) (*ArrowFlightConnection, error) {
+	bytes, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	raw := struct {
+		ArrowFlightConnection
+		Port json.Number `json:"port"`
+	}{}
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid arrow-flight connection")
+	}
+	result := raw.ArrowFlightConnection
+	if raw.Port != "" {
+		port, err := strconv.ParseUint(raw.Port.String(), 10, 16)
+		if err != nil {
+			return nil, errors.Errorf("invalid port %s of the arrow-flight connection", raw.Port)
+		}
+		result.Port = int32(port)
+	}
+	return &result, nil
+}
+
+// ArrowFlightFromConnection decodes the properties of a connection of the fybrik-arrow-flight type
+func ArrowFlightFromConnection(conn *taxonomy.Connection) (*ArrowFlightConnection, error) {
+	if conn.Name != ArrowFlightType {
+		return nil, errors.Errorf("unexpected connection type %s", conn.Name)
+	}
+	properties, ok := conn.AdditionalProperties.Items[string(ArrowFlightType)].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the arrow-flight connection has no properties")
+	}
+	return DecodeArrowFlight(properties)
+}
+
+// Address returns the host and port of the server
+func (c *ArrowFlightConnection) Address() string {
+	return net.JoinHostPort(c.Hostname, strconv.Itoa(int(c.Port)))
+}
+
+// TLSSettings returns the TLS settings of the connection, or nil if the endpoint does not require TLS.
+// An endpoint with the grpc+tls scheme and no TLS settings is verified using the system CA certificates.
+func (c *ArrowFlightConnection) TLSSettings() *ArrowFlightTLS {
+	if c.TLS == nil && c.Scheme == ArrowFlightTLSScheme {
+		return &ArrowFlightTLS{}
+	}
+	return c.TLS
+}
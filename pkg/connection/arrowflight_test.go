@@ -0,0 +1,61 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connection
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+func TestArrowFlightFromConnection(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	endpoint := taxonomy.Connection{}
+	g.Expect(json.Unmarshal([]byte(`{"name": "fybrik-arrow-flight", "fybrik-arrow-flight": {
+		"hostname": "read-path.fybrik-blueprints", "port": 80, "scheme": "grpc+tls",
+		"tls": {"caSecretRef": {"name": "flight-ca", "namespace": "fybrik-system"}, "serverName": "read-path"}}}`),
+		&endpoint)).To(gomega.Succeed())
+
+	flight, err := ArrowFlightFromConnection(&endpoint)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(flight.Hostname).To(gomega.Equal("read-path.fybrik-blueprints"))
+	g.Expect(flight.Port).To(gomega.Equal(int32(80)))
+	g.Expect(flight.Scheme).To(gomega.Equal(ArrowFlightTLSScheme))
+	g.Expect(flight.Address()).To(gomega.Equal("read-path.fybrik-blueprints:80"))
+	g.Expect(flight.TLSSettings()).To(gomega.Equal(&ArrowFlightTLS{
+		CASecretRef: &taxonomy.SecretRef{Name: "flight-ca", Namespace: "fybrik-system"},
+		ServerName:  "read-path",
+	}))
+
+	// other connection types are rejected
+	endpoint.Name = "s3"
+	_, err = ArrowFlightFromConnection(&endpoint)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestDecodeArrowFlight(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	// the port of a rendered module template may be a string
+	flight, err := DecodeArrowFlight(map[string]interface{}{"hostname": "localhost", "port": "8080", "scheme": "grpc"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(flight.Port).To(gomega.Equal(int32(8080)))
+	// an endpoint with the grpc scheme does not require TLS
+	g.Expect(flight.TLSSettings()).To(gomega.BeNil())
+
+	// an endpoint with the grpc+tls scheme is verified with the system CA certificates if it has no TLS settings
+	flight, err = DecodeArrowFlight(map[string]interface{}{"hostname": "localhost", "port": 443, "scheme": ArrowFlightTLSScheme})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(flight.TLSSettings()).To(gomega.Equal(&ArrowFlightTLS{}))
+
+	for _, port := range []interface{}{"http", 70000, -1, 1.5} {
+		_, err = DecodeArrowFlight(map[string]interface{}{"hostname": "localhost", "port": port, "scheme": "grpc"})
+		g.Expect(err).To(gomega.HaveOccurred(), "port %v", port)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// Default values of the circuit breaker configuration
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerCooldownMs       = 30000
+)
+
+// BreakerConfig defines when the requests to a failing connector are suspended
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit, 0 disables the circuit breaker
+	FailureThreshold int
+	// Cooldown is the time during which the requests are suspended before probing the connector again
+	Cooldown time.Duration
+}
+
+// BreakerConfigFromEnvironment returns the circuit breaker configuration defined by the environment variables,
+// default values are used for undefined variables
+func BreakerConfigFromEnvironment() BreakerConfig {
+	cooldown := environment.GetEnvAsInt(environment.ConnectorBreakerCooldownKey, defaultBreakerCooldownMs)
+	return BreakerConfig{
+		FailureThreshold: environment.GetEnvAsInt(environment.ConnectorBreakerThresholdKey, defaultBreakerFailureThreshold),
+		Cooldown:         time.Duration(cooldown) * time.Millisecond,
+	}
+}
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+// List of circuit breaker states
+const (
+	// BreakerClosed lets all requests through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects all requests until the cooldown is over
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through, whose result closes or re-opens the circuit
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitOpenError is returned instead of sending a request to a connector whose circuit is open.
+// It is retryable, since the request is sent again once the cooldown is over.
+type CircuitOpenError struct {
+	// Name of the connector
+	Name string
+	// Failures is the number of consecutive failures that opened the circuit
+	Failures int
+	// RetryAfter is the time at which the connector is probed again
+	RetryAfter time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("connector %s is unavailable after %d consecutive failures, requests are suspended until %s",
+		e.Name, e.Failures, e.RetryAfter.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) IsRetryable() bool {
+	return true
+}
+
+func (e *CircuitOpenError) StatusCode() int {
+	return 0
+}
+
+// CircuitBreaker stops sending requests to a connector after consecutive failures,
+// so that a connector that is down does not block every reconcile until the request timeout.
+// Only retryable failures, e.g., an unreachable connector, count as failures: a connector that rejects a request is up.
+// A nil CircuitBreaker lets all requests through.
+type CircuitBreaker struct {
+	name   string
+	config BreakerConfig
+
+	mutex     sync.Mutex
+	state     BreakerState
+	failures  int
+	openUntil time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// NewCircuitBreaker returns a circuit breaker of the requests sent to a single connector, or nil if it is disabled
+func NewCircuitBreaker(name string, config BreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{name: name, config: config, state: BreakerClosed, now: time.Now}
+}
+
+// State returns the current state of the circuit breaker
+func (b *CircuitBreaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == BreakerOpen && !b.now().Before(b.openUntil) {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Allow returns a CircuitOpenError if the request must not be sent to the connector.
+// Otherwise, Done has to be called with the result of the request.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == BreakerOpen && !b.now().Before(b.openUntil) {
+		b.state = BreakerHalfOpen
+	}
+	switch b.state {
+	case BreakerOpen:
+		return &CircuitOpenError{Name: b.name, Failures: b.failures, RetryAfter: b.openUntil}
+	case BreakerHalfOpen:
+		// a single probe is sent at a time, the other requests are rejected until its result is known
+		if b.probing {
+			return &CircuitOpenError{Name: b.name, Failures: b.failures, RetryAfter: b.openUntil}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Done records the result of a request that has been allowed
+func (b *CircuitBreaker) Done(err error) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	wasProbe := b.state == BreakerHalfOpen && b.probing
+	if wasProbe {
+		b.probing = false
+	}
+	if err == nil || !IsRetryable(err) {
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if wasProbe || b.failures >= b.config.FailureThreshold {
+		b.state = BreakerOpen
+		b.openUntil = b.now().Add(b.config.Cooldown)
+	}
+}
+
+// Abandon records that a request that has been allowed was canceled by its caller before its result is known.
+// The state of the circuit is not changed, and a half-open circuit lets the next probe through.
+func (b *CircuitBreaker) Abandon() {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
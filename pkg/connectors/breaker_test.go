@@ -0,0 +1,100 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/onsi/gomega"
+)
+
+// newTestBreaker returns a circuit breaker with a clock controlled by the test
+func newTestBreaker(threshold int, cooldown time.Duration, clock *time.Time) *CircuitBreaker {
+	breaker := NewCircuitBreaker("stub", BreakerConfig{FailureThreshold: threshold, Cooldown: cooldown})
+	breaker.now = func() time.Time { return *clock }
+	return breaker
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	clock := time.Now()
+	breaker := newTestBreaker(3, time.Minute, &clock)
+	unavailable := NewUnavailableError(errors.New("connection refused"))
+
+	// closed: the failures below the threshold and the rejected requests keep the circuit closed
+	for i := 0; i < 2; i++ {
+		g.Expect(breaker.Allow()).To(gomega.Succeed())
+		breaker.Done(unavailable)
+	}
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	breaker.Done(NewHTTPError(http.StatusNotFound, errors.New("no such asset")))
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerClosed))
+
+	// closed -> open after consecutive failures
+	for i := 0; i < 3; i++ {
+		g.Expect(breaker.Allow()).To(gomega.Succeed())
+		breaker.Done(unavailable)
+	}
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerOpen))
+	err := breaker.Allow()
+	var openErr *CircuitOpenError
+	g.Expect(errors.As(err, &openErr)).To(gomega.BeTrue())
+	g.Expect(openErr.RetryAfter).To(gomega.Equal(clock.Add(time.Minute)))
+	g.Expect(IsRetryable(err)).To(gomega.BeTrue())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("connector stub is unavailable after 3 consecutive failures"))
+
+	// open -> half-open once the cooldown is over, a single probe is let through
+	clock = clock.Add(time.Minute)
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerHalfOpen))
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	g.Expect(breaker.Allow()).To(gomega.HaveOccurred())
+
+	// half-open -> open if the probe fails
+	breaker.Done(unavailable)
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerOpen))
+	g.Expect(breaker.Allow()).To(gomega.HaveOccurred())
+
+	// half-open -> closed if the probe succeeds
+	clock = clock.Add(time.Minute)
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	breaker.Done(nil)
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerClosed))
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+}
+
+func TestCircuitBreakerAbandon(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	clock := time.Now()
+	breaker := newTestBreaker(1, time.Minute, &clock)
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	breaker.Done(NewUnavailableError(errors.New("connection refused")))
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerOpen))
+
+	// an abandoned probe keeps the circuit half-open and lets the next probe through
+	clock = clock.Add(time.Minute)
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	breaker.Abandon()
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerHalfOpen))
+	g.Expect(breaker.Allow()).To(gomega.Succeed())
+	breaker.Done(nil)
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerClosed))
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	breaker := NewCircuitBreaker("stub", BreakerConfig{})
+	g.Expect(breaker).To(gomega.BeNil())
+	for i := 0; i < 10; i++ {
+		g.Expect(breaker.Allow()).To(gomega.Succeed())
+		breaker.Done(NewUnavailableError(errors.New("connection refused")))
+	}
+	g.Expect(breaker.State()).To(gomega.Equal(BreakerClosed))
+}
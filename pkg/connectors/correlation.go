@@ -0,0 +1,21 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+// CorrelationIDHeader is the header of the connector requests that carries the correlation id of the reconcile
+// that issued them, so that the requests of a single reconcile can be traced across the connectors
+const CorrelationIDHeader = "X-Correlation-Id"
+
+// CorrelationIDLength is the number of random bytes of a correlation id, which is hex-encoded
+const CorrelationIDLength = 8
+
+// WithCorrelationIDHeader returns a copy of the default headers of a connector client with the correlation id header set
+func WithCorrelationIDHeader(headers map[string]string, correlationID string) map[string]string {
+	copied := make(map[string]string, len(headers)+1)
+	for key, value := range headers {
+		copied[key] = value
+	}
+	copied[CorrelationIDHeader] = correlationID
+	return copied
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+)
+
+// circuitBreakingDataCatalog sends the requests of a data catalog through a circuit breaker
+type circuitBreakingDataCatalog struct {
+	catalog DataCatalog
+	breaker *connectors.CircuitBreaker
+}
+
+// NewCircuitBreakingDataCatalog returns a data catalog that fails fast with a connectors.CircuitOpenError
+// while the circuit of the given data catalog is open. Closing it closes the given data catalog.
+func NewCircuitBreakingDataCatalog(catalog DataCatalog, breaker *connectors.CircuitBreaker) DataCatalog {
+	return &circuitBreakingDataCatalog{catalog: catalog, breaker: breaker}
+}
+
+func (m *circuitBreakingDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest,
+	creds string) (*datacatalog.GetAssetResponse, error) {
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	response, err := m.catalog.GetAssetInfo(in, creds)
+	m.breaker.Done(err)
+	return response, err
+}
+
+func (m *circuitBreakingDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest,
+	creds string) (*datacatalog.CreateAssetResponse, error) {
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	response, err := m.catalog.CreateAsset(in, creds)
+	m.breaker.Done(err)
+	return response, err
+}
+
+func (m *circuitBreakingDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest,
+	creds string) (*datacatalog.DeleteAssetResponse, error) {
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	response, err := m.catalog.DeleteAsset(in, creds)
+	m.breaker.Done(err)
+	return response, err
+}
+
+func (m *circuitBreakingDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest,
+	creds string) (*datacatalog.UpdateAssetResponse, error) {
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	response, err := m.catalog.UpdateAsset(in, creds)
+	m.breaker.Done(err)
+	return response, err
+}
+
+func (m *circuitBreakingDataCatalog) ListAssets(in *datacatalog.ListAssetsRequest,
+	creds string) (*datacatalog.ListAssetsResponse, error) {
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	response, err := m.catalog.ListAssets(in, creds)
+	m.breaker.Done(err)
+	return response, err
+}
+
+// WithCorrelationID returns a circuit breaking data catalog that shares the circuit breaker,
+// and tags its requests with the correlation id
+func (m *circuitBreakingDataCatalog) WithCorrelationID(correlationID string) DataCatalog {
+	return &circuitBreakingDataCatalog{catalog: WithCorrelationID(m.catalog, correlationID), breaker: m.breaker}
+}
+
+func (m *circuitBreakingDataCatalog) Close() error {
+	return m.catalog.Close()
+}
@@ -15,9 +15,26 @@ type DataCatalog interface {
 	CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error)
 	DeleteAsset(in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error)
 	UpdateAsset(in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error)
+	ListAssets(in *datacatalog.ListAssetsRequest, creds string) (*datacatalog.ListAssetsResponse, error)
 	io.Closer
 }
 
+// CorrelatedDataCatalog is implemented by the data catalogs that can tag their requests with a correlation id
+type CorrelatedDataCatalog interface {
+	// WithCorrelationID returns a data catalog that sends the correlation id with its requests.
+	// The returned data catalog shares the connection of the original one, and is not closed separately.
+	WithCorrelationID(correlationID string) DataCatalog
+}
+
+// WithCorrelationID returns a data catalog that tags its requests with the correlation id if this is supported,
+// and the given data catalog otherwise
+func WithCorrelationID(catalog DataCatalog, correlationID string) DataCatalog {
+	if correlated, ok := catalog.(CorrelatedDataCatalog); ok && correlationID != "" {
+		return correlated.WithCorrelationID(correlationID)
+	}
+	return catalog
+}
+
 func NewDataCatalog(catalogProviderName, catalogConnectorAddress string) (DataCatalog, error) {
 	return NewOpenAPIDataCatalog(catalogProviderName, catalogConnectorAddress), nil
 }
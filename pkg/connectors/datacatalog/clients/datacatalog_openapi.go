@@ -8,11 +8,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"emperror.dev/errors"
 
+	"fybrik.io/fybrik/pkg/connectors"
 	"fybrik.io/fybrik/pkg/connectors/datacatalog/openapiclient"
+	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/tls"
 )
@@ -24,14 +28,16 @@ const (
 )
 
 var _ DataCatalog = (*openAPIDataCatalog)(nil)
+var _ CorrelatedDataCatalog = (*openAPIDataCatalog)(nil)
 
 type openAPIDataCatalog struct {
-	name   string
-	client *openapiclient.APIClient
+	name    string
+	client  *openapiclient.APIClient
+	timeout time.Duration
 }
 
 // NewopenApiDataCatalog creates a DataCatalog facade that connects to a openApi service
-
+// The proxy and the request timeout are defined by the environment variables.
 func NewOpenAPIDataCatalog(name, connectionURL string) DataCatalog {
 	log := logging.LogInit(logging.SETUP, "datacatalog client")
 	configuration := &openapiclient.Configuration{
@@ -45,17 +51,18 @@ func NewOpenAPIDataCatalog(name, connectionURL string) DataCatalog {
 			},
 		},
 		OperationServers: map[string]openapiclient.ServerConfigurations{},
-		HTTPClient:       tls.GetHTTPClient(&log).StandardClient(),
+		HTTPClient:       tls.GetHTTPClientWithProxy(&log, environment.GetConnectorProxyURL()).StandardClient(),
 	}
 	apiClient := openapiclient.NewAPIClient(configuration)
 
 	return &openAPIDataCatalog{
-		name:   name,
-		client: apiClient,
+		name:    name,
+		client:  apiClient,
+		timeout: connectors.RequestTimeoutFromEnvironment(environment.DataCatalogRequestTimeoutKey),
 	}
 }
 
-// getDetailedError generates an error from the response body JSON and the error status code,
+// getDetailedError generates a typed connector error from the response body JSON and the error status code,
 // e.g., "404: asset does not exist"
 func getDetailedError(httpResponse *http.Response, defaultErr error) error {
 	var err error
@@ -68,20 +75,24 @@ func getDetailedError(httpResponse *http.Response, defaultErr error) error {
 	} else {
 		err = defaultErr
 	}
-	return errors.Wrapf(err, "%d", httpResponse.StatusCode)
+	return connectors.NewHTTPError(httpResponse.StatusCode, err)
 }
 
 //nolint:dupl
-func (m *openAPIDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error) {
+func (m *openAPIDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest, creds string) (_ *datacatalog.GetAssetResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.CatalogRequestDuration, start, err, "getAssetInfo") }()
 	printErr := func() string { return fmt.Sprintf("get asset info from %s failed", m.name) }
+	ctx, cancel := connectors.NewRequestContext(context.Background(), m.timeout)
+	defer cancel()
 	resp, httpResponse, err :=
-		m.client.DefaultApi.GetAssetInfo(context.Background()).XRequestDatacatalogCred(creds).GetAssetRequest(*in).Execute()
+		m.client.DefaultApi.GetAssetInfo(ctx).XRequestDatacatalogCred(creds).GetAssetRequest(*in).Execute()
 
 	if httpResponse == nil {
 		if err != nil {
-			return nil, errors.Wrap(err, printErr())
+			return nil, connectors.NewUnavailableError(errors.Wrap(err, printErr()))
 		}
-		return nil, errors.New(printErr())
+		return nil, connectors.NewUnavailableError(errors.New(printErr()))
 	}
 	defer httpResponse.Body.Close()
 	if err != nil {
@@ -91,15 +102,19 @@ func (m *openAPIDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest, creds
 }
 
 //nolint:dupl
-func (m *openAPIDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error) {
+func (m *openAPIDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (_ *datacatalog.CreateAssetResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.CatalogRequestDuration, start, err, "createAsset") }()
 	printErr := func() string { return fmt.Sprintf("create asset info from %s failed", m.name) }
-	resp, httpResponse, err := m.client.DefaultApi.CreateAsset(context.Background()).
+	ctx, cancel := connectors.NewRequestContext(context.Background(), m.timeout)
+	defer cancel()
+	resp, httpResponse, err := m.client.DefaultApi.CreateAsset(ctx).
 		XRequestDatacatalogWriteCred(creds).CreateAssetRequest(*in).Execute()
 	if httpResponse == nil {
 		if err != nil {
-			return nil, errors.Wrap(err, printErr())
+			return nil, connectors.NewUnavailableError(errors.Wrap(err, printErr()))
 		}
-		return nil, errors.New(printErr())
+		return nil, connectors.NewUnavailableError(errors.New(printErr()))
 	}
 	defer httpResponse.Body.Close()
 
@@ -110,15 +125,19 @@ func (m *openAPIDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest, cre
 }
 
 //nolint:dupl
-func (m *openAPIDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error) {
+func (m *openAPIDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest, creds string) (_ *datacatalog.DeleteAssetResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.CatalogRequestDuration, start, err, "deleteAsset") }()
 	printErr := func() string { return fmt.Sprintf("delete asset info from %s failed", m.name) }
+	ctx, cancel := connectors.NewRequestContext(context.Background(), m.timeout)
+	defer cancel()
 	resp, httpResponse, err :=
-		m.client.DefaultApi.DeleteAsset(context.Background()).XRequestDatacatalogCred(creds).DeleteAssetRequest(*in).Execute()
+		m.client.DefaultApi.DeleteAsset(ctx).XRequestDatacatalogCred(creds).DeleteAssetRequest(*in).Execute()
 	if httpResponse == nil {
 		if err != nil {
-			return nil, errors.Wrap(err, printErr())
+			return nil, connectors.NewUnavailableError(errors.Wrap(err, printErr()))
 		}
-		return nil, errors.New(printErr())
+		return nil, connectors.NewUnavailableError(errors.New(printErr()))
 	}
 	defer httpResponse.Body.Close()
 	if err != nil {
@@ -127,15 +146,41 @@ func (m *openAPIDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest, cre
 	return &resp, nil
 }
 
-func (m *openAPIDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error) {
-	resp, httpResponse, err := m.client.DefaultApi.UpdateAsset(
-		context.Background()).XRequestDatacatalogUpdateCred(creds).UpdateAssetRequest(*in).Execute()
+func (m *openAPIDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest, creds string) (_ *datacatalog.UpdateAssetResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.CatalogRequestDuration, start, err, "updateAsset") }()
+	ctx, cancel := connectors.NewRequestContext(context.Background(), m.timeout)
+	defer cancel()
+	resp, httpResponse, err := m.client.DefaultApi.UpdateAsset(ctx).
+		XRequestDatacatalogUpdateCred(creds).UpdateAssetRequest(*in).Execute()
 	printErr := func() string { return fmt.Sprintf("update asset info from %s failed", m.name) }
 	if httpResponse == nil {
 		if err != nil {
-			return nil, errors.Wrap(err, printErr())
+			return nil, connectors.NewUnavailableError(errors.Wrap(err, printErr()))
+		}
+		return nil, connectors.NewUnavailableError(errors.New(printErr()))
+	}
+	defer httpResponse.Body.Close()
+	if err != nil {
+		return nil, getDetailedError(httpResponse, errors.Wrap(err, printErr()))
+	}
+	return &resp, nil
+}
+
+//nolint:dupl
+func (m *openAPIDataCatalog) ListAssets(in *datacatalog.ListAssetsRequest, creds string) (_ *datacatalog.ListAssetsResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.CatalogRequestDuration, start, err, "listAssets") }()
+	printErr := func() string { return fmt.Sprintf("list assets from %s failed", m.name) }
+	ctx, cancel := connectors.NewRequestContext(context.Background(), m.timeout)
+	defer cancel()
+	resp, httpResponse, err :=
+		m.client.DefaultApi.ListAssets(ctx).XRequestDatacatalogCred(creds).ListAssetsRequest(*in).Execute()
+	if httpResponse == nil {
+		if err != nil {
+			return nil, connectors.NewUnavailableError(errors.Wrap(err, printErr()))
 		}
-		return nil, errors.New(printErr())
+		return nil, connectors.NewUnavailableError(errors.New(printErr()))
 	}
 	defer httpResponse.Body.Close()
 	if err != nil {
@@ -144,6 +189,17 @@ func (m *openAPIDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest, cre
 	return &resp, nil
 }
 
+// WithCorrelationID returns a copy of the data catalog that sends the correlation id header with its requests
+func (m *openAPIDataCatalog) WithCorrelationID(correlationID string) DataCatalog {
+	configuration := *m.client.GetConfig()
+	configuration.DefaultHeader = connectors.WithCorrelationIDHeader(configuration.DefaultHeader, correlationID)
+	return &openAPIDataCatalog{
+		name:    m.name,
+		client:  openapiclient.NewAPIClient(&configuration),
+		timeout: m.timeout,
+	}
+}
+
 func (m *openAPIDataCatalog) Close() error {
 	return nil
 }
@@ -0,0 +1,77 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/metrics"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/test"
+)
+
+func TestGetAssetInfoErrors(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// 5xx responses are retried by the client, their mapping is covered by the policy manager client tests
+	request := &datacatalog.GetAssetRequest{AssetID: "s3/missing-dataset", OperationType: datacatalog.READ}
+	tests := []struct {
+		statusCode int
+		body       string
+		expected   error
+		message    string
+		retryable  bool
+	}{
+		{http.StatusNotFound, "", &connectors.AssetNotFoundError{}, AssetIDNotFound, false},
+		{http.StatusForbidden, "", &connectors.AuthError{}, AccessForbidden, false},
+		{http.StatusUnauthorized, "invalid token", &connectors.AuthError{}, "invalid token", false},
+		{http.StatusBadRequest, "unknown operation", &connectors.InvalidRequestError{}, "unknown operation", false},
+	}
+	failures := test.HistogramSampleCount(metrics.CatalogRequestDuration, "getAssetInfo", metrics.OutcomeError)
+	for _, test := range tests {
+		statusCode, body := test.statusCode, test.body
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(body))
+		}))
+		_, err := NewOpenAPIDataCatalog("catalog", server.URL).GetAssetInfo(request, "")
+		server.Close()
+		g.Expect(err).To(gomega.BeAssignableToTypeOf(test.expected), "status code %d", statusCode)
+		g.Expect(errors.Cause(err).Error()).To(gomega.Equal(test.message))
+		g.Expect(connectors.IsRetryable(err)).To(gomega.Equal(test.retryable))
+	}
+	// the latency of every failed request is recorded
+	g.Expect(test.HistogramSampleCount(metrics.CatalogRequestDuration, "getAssetInfo", metrics.OutcomeError)).
+		To(gomega.BeNumerically(">=", failures+uint64(len(tests))))
+}
+
+func TestGetAssetInfoTimeout(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the cancellation of the request is detected once its body is read
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(10 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	catalog := NewOpenAPIDataCatalog("catalog", server.URL).(*openAPIDataCatalog)
+	catalog.timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := catalog.GetAssetInfo(&datacatalog.GetAssetRequest{AssetID: "s3/slow-dataset", OperationType: datacatalog.READ}, "")
+	g.Expect(err).To(gomega.BeAssignableToTypeOf(&connectors.ConnectorUnavailableError{}))
+	g.Expect(connectors.IsRetryable(err)).To(gomega.BeTrue())
+	g.Expect(time.Since(start)).To(gomega.And(gomega.BeNumerically(">=", catalog.timeout), gomega.BeNumerically("<", 2*time.Second)))
+}
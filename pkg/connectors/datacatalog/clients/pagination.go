@@ -0,0 +1,58 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+)
+
+// AssetIterator lists the assets of a data catalog one page at a time.
+// The next page is requested only when NextPage is called, so large catalogs are never fully loaded in memory.
+type AssetIterator struct {
+	catalog   DataCatalog
+	pageSize  int32
+	creds     string
+	pageToken string
+	done      bool
+}
+
+// NewAssetIterator returns an iterator over the assets of the given catalog.
+// A non-positive page size lets the catalog connector choose the page size.
+func NewAssetIterator(catalog DataCatalog, pageSize int32, creds string) *AssetIterator {
+	if pageSize < 0 {
+		pageSize = 0
+	}
+	return &AssetIterator{catalog: catalog, pageSize: pageSize, creds: creds}
+}
+
+// HasNext returns false once the last page has been returned
+func (it *AssetIterator) HasNext() bool {
+	return !it.done
+}
+
+// NextPage requests the next page of assets from the catalog.
+// The iteration ends when the catalog returns an empty page or no cursor for the next page.
+// An empty page is returned if the iteration has already ended.
+func (it *AssetIterator) NextPage() ([]datacatalog.AssetSummary, error) {
+	if it.done {
+		return []datacatalog.AssetSummary{}, nil
+	}
+	response, err := it.catalog.ListAssets(&datacatalog.ListAssetsRequest{PageSize: it.pageSize, PageToken: it.pageToken},
+		it.creds)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Assets) == 0 || response.NextPageToken == "" {
+		it.done = true
+		return response.Assets, nil
+	}
+	if response.NextPageToken == it.pageToken {
+		it.done = true
+		return nil, errors.Errorf("the catalog returned the same page token %s twice", it.pageToken)
+	}
+	it.pageToken = response.NextPageToken
+	return response.Assets, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// newPagedCatalogServer serves the given pages, using the page index as the page token.
+// The token returned with the last page points to an empty page.
+func newPagedCatalogServer(g *gomega.WithT, pages [][]taxonomy.AssetID, requests *[]datacatalog.ListAssetsRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.URL.Path).To(gomega.Equal("/listAssets"))
+		g.Expect(r.Header.Get("X-Request-Datacatalog-Cred")).To(gomega.Equal("creds"))
+		request := datacatalog.ListAssetsRequest{}
+		g.Expect(json.NewDecoder(r.Body).Decode(&request)).To(gomega.Succeed())
+		*requests = append(*requests, request)
+		index := 0
+		if request.PageToken != "" {
+			var err error
+			index, err = strconv.Atoi(request.PageToken)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		}
+		response := datacatalog.ListAssetsResponse{Assets: []datacatalog.AssetSummary{}}
+		if index < len(pages) {
+			for _, id := range pages[index] {
+				response.Assets = append(response.Assets, datacatalog.AssetSummary{
+					AssetID:          id,
+					ResourceMetadata: datacatalog.ResourceMetadata{Name: string(id)},
+				})
+			}
+			response.NextPageToken = strconv.Itoa(index + 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		g.Expect(json.NewEncoder(w).Encode(&response)).To(gomega.Succeed())
+	}))
+}
+
+func TestAssetIterator(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	pages := [][]taxonomy.AssetID{{"s3/a", "s3/b"}, {"s3/c", "s3/d"}, {"s3/e"}}
+	requests := []datacatalog.ListAssetsRequest{}
+	server := newPagedCatalogServer(g, pages, &requests)
+	defer server.Close()
+
+	it := NewAssetIterator(NewOpenAPIDataCatalog("catalog", server.URL), 2, "creds")
+	listed := []taxonomy.AssetID{}
+	for it.HasNext() {
+		page, err := it.NextPage()
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		for _, asset := range page {
+			g.Expect(asset.ResourceMetadata.Name).To(gomega.Equal(string(asset.AssetID)))
+			listed = append(listed, asset.AssetID)
+		}
+	}
+	g.Expect(listed).To(gomega.Equal([]taxonomy.AssetID{"s3/a", "s3/b", "s3/c", "s3/d", "s3/e"}))
+	// the pages are fetched lazily, the last request returns an empty page
+	g.Expect(requests).To(gomega.Equal([]datacatalog.ListAssetsRequest{
+		{PageSize: 2}, {PageSize: 2, PageToken: "1"}, {PageSize: 2, PageToken: "2"}, {PageSize: 2, PageToken: "3"},
+	}))
+	page, err := it.NextPage()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(page).To(gomega.BeEmpty())
+	g.Expect(requests).To(gomega.HaveLen(4))
+}
+
+func TestAssetIteratorRepeatedToken(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := datacatalog.ListAssetsResponse{
+			Assets:        []datacatalog.AssetSummary{{AssetID: "s3/a"}},
+			NextPageToken: "same",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		g.Expect(json.NewEncoder(w).Encode(&response)).To(gomega.Succeed())
+	}))
+	defer server.Close()
+
+	it := NewAssetIterator(NewOpenAPIDataCatalog("catalog", server.URL), 0, "creds")
+	_, err := it.NextPage()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	_, err = it.NextPage()
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(it.HasNext()).To(gomega.BeFalse())
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+)
+
+// rateLimitedDataCatalog sends the requests of a data catalog through a limiter
+type rateLimitedDataCatalog struct {
+	catalog DataCatalog
+	limiter *connectors.Limiter
+}
+
+// NewRateLimitedDataCatalog returns a data catalog that waits for the limiter before every request.
+// Closing it closes the given data catalog.
+func NewRateLimitedDataCatalog(catalog DataCatalog, limiter *connectors.Limiter) DataCatalog {
+	return &rateLimitedDataCatalog{catalog: catalog, limiter: limiter}
+}
+
+func (m *rateLimitedDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error) {
+	release, err := m.limiter.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.catalog.GetAssetInfo(in, creds)
+}
+
+func (m *rateLimitedDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest,
+	creds string) (*datacatalog.CreateAssetResponse, error) {
+	release, err := m.limiter.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.catalog.CreateAsset(in, creds)
+}
+
+func (m *rateLimitedDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest,
+	creds string) (*datacatalog.DeleteAssetResponse, error) {
+	release, err := m.limiter.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.catalog.DeleteAsset(in, creds)
+}
+
+func (m *rateLimitedDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest,
+	creds string) (*datacatalog.UpdateAssetResponse, error) {
+	release, err := m.limiter.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.catalog.UpdateAsset(in, creds)
+}
+
+func (m *rateLimitedDataCatalog) ListAssets(in *datacatalog.ListAssetsRequest, creds string) (*datacatalog.ListAssetsResponse, error) {
+	release, err := m.limiter.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.catalog.ListAssets(in, creds)
+}
+
+// WithCorrelationID returns a rate limited data catalog that shares the limiter, and tags its requests with the correlation id
+func (m *rateLimitedDataCatalog) WithCorrelationID(correlationID string) DataCatalog {
+	return &rateLimitedDataCatalog{catalog: WithCorrelationID(m.catalog, correlationID), limiter: m.limiter}
+}
+
+func (m *rateLimitedDataCatalog) Close() error {
+	return m.catalog.Close()
+}
@@ -389,6 +389,127 @@ func (a *DefaultApiService) GetAssetInfoExecute(r ApiGetAssetInfoRequest) (GetAs
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
+type ApiListAssetsRequest struct {
+	ctx                     _context.Context
+	ApiService              *DefaultApiService
+	xRequestDatacatalogCred *string
+	listAssetsRequest       *ListAssetsRequest
+}
+
+// This header carries credential information related to relevant catalog from which the asset information needs to be retrieved.
+func (r ApiListAssetsRequest) XRequestDatacatalogCred(xRequestDatacatalogCred string) ApiListAssetsRequest {
+	r.xRequestDatacatalogCred = &xRequestDatacatalogCred
+	return r
+}
+
+// List Assets Request
+func (r ApiListAssetsRequest) ListAssetsRequest(listAssetsRequest ListAssetsRequest) ApiListAssetsRequest {
+	r.listAssetsRequest = &listAssetsRequest
+	return r
+}
+
+func (r ApiListAssetsRequest) Execute() (ListAssetsResponse, *_nethttp.Response, error) {
+	return r.ApiService.ListAssetsExecute(r)
+}
+
+/*
+ListAssets This REST API lists the data assets in the data catalog configured in fybrik, one page at a time
+
+	@param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	@return ApiListAssetsRequest
+*/
+func (a *DefaultApiService) ListAssets(ctx _context.Context) ApiListAssetsRequest {
+	return ApiListAssetsRequest{
+		ApiService: a,
+		ctx:        ctx,
+	}
+}
+
+// Execute executes the request
+//
+//	@return ListAssetsResponse
+func (a *DefaultApiService) ListAssetsExecute(r ApiListAssetsRequest) (ListAssetsResponse, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod  = _nethttp.MethodPost
+		localVarPostBody    interface{}
+		formFiles           []formFile
+		localVarReturnValue ListAssetsResponse
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "DefaultApiService.ListAssets")
+	if err != nil {
+		return localVarReturnValue, nil, GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/listAssets"
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+	if r.xRequestDatacatalogCred == nil {
+		return localVarReturnValue, nil, reportError("xRequestDatacatalogCred is required and must be specified")
+	}
+	if r.listAssetsRequest == nil {
+		return localVarReturnValue, nil, reportError("listAssetsRequest is required and must be specified")
+	}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/json"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	localVarHeaderParams["X-Request-Datacatalog-Cred"] = parameterToString(*r.xRequestDatacatalogCred, "")
+	// body params
+	localVarPostBody = r.listAssetsRequest
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, formFiles)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = _ioutil.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
+
 type ApiUpdateAssetRequest struct {
 	ctx                           _context.Context
 	ApiService                    *DefaultApiService
@@ -13,3 +13,5 @@ type DeleteAssetRequest = datacatalog.DeleteAssetRequest
 type DeleteAssetResponse = datacatalog.DeleteAssetResponse
 type UpdateAssetRequest = datacatalog.UpdateAssetRequest
 type UpdateAssetResponse = datacatalog.UpdateAssetResponse
+type ListAssetsRequest = datacatalog.ListAssetsRequest
+type ListAssetsResponse = datacatalog.ListAssetsResponse
@@ -0,0 +1,127 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package connectors defines the errors reported by the connector clients,
+// e.g., the data catalog and the policy manager clients.
+package connectors
+
+import (
+	"fmt"
+	"net/http"
+
+	"emperror.dev/errors"
+)
+
+// ConnectorError is implemented by all errors returned by the connector clients
+type ConnectorError interface {
+	error
+	// IsRetryable returns true if the failure is transient and the request may succeed if repeated
+	IsRetryable() bool
+	// StatusCode returns the HTTP status code of the connector response, or 0 if no response has been received
+	StatusCode() int
+}
+
+// connectorError holds the status code and the detailed error common to all connector errors
+type connectorError struct {
+	statusCode int
+	err        error
+}
+
+// Error returns the detailed error prefixed by the status code, e.g., "404: the asset does not exist"
+func (e *connectorError) Error() string {
+	if e.statusCode == 0 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%d: %s", e.statusCode, e.err.Error())
+}
+
+func (e *connectorError) Unwrap() error {
+	return e.err
+}
+
+func (e *connectorError) StatusCode() int {
+	return e.statusCode
+}
+
+// ConnectorUnavailableError is returned if the connector can not be reached, or fails to process the request
+// because of a server-side problem (5xx responses)
+type ConnectorUnavailableError struct {
+	connectorError
+}
+
+func (e *ConnectorUnavailableError) IsRetryable() bool {
+	return true
+}
+
+// AssetNotFoundError is returned if the requested asset does not exist
+type AssetNotFoundError struct {
+	connectorError
+}
+
+func (e *AssetNotFoundError) IsRetryable() bool {
+	return false
+}
+
+// AuthError is returned if the credentials are missing or do not grant access to the requested resource
+type AuthError struct {
+	connectorError
+}
+
+func (e *AuthError) IsRetryable() bool {
+	return false
+}
+
+// InvalidRequestError is returned if the connector rejects the request as malformed
+type InvalidRequestError struct {
+	connectorError
+}
+
+func (e *InvalidRequestError) IsRetryable() bool {
+	return false
+}
+
+// SignatureError is returned if the signature of a connector response can not be verified,
+// in which case the response may have been tampered with and is not trusted
+type SignatureError struct {
+	connectorError
+}
+
+func (e *SignatureError) IsRetryable() bool {
+	return false
+}
+
+// NewSignatureError returns an error for a response whose signature can not be verified
+func NewSignatureError(err error) error {
+	return &SignatureError{connectorError{err: err}}
+}
+
+// NewUnavailableError returns an error for a request that has not received a response from the connector
+func NewUnavailableError(err error) error {
+	return &ConnectorUnavailableError{connectorError{err: err}}
+}
+
+// NewHTTPError maps the status code of a failed connector response to the matching error type
+func NewHTTPError(statusCode int, err error) error {
+	base := connectorError{statusCode: statusCode, err: err}
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return &AuthError{base}
+	case statusCode == http.StatusNotFound:
+		return &AssetNotFoundError{base}
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError:
+		return &ConnectorUnavailableError{base}
+	default:
+		return &InvalidRequestError{base}
+	}
+}
+
+// IsRetryable returns true if the given error may be resolved by repeating the request.
+// Errors that have not been reported by a connector client are considered retryable.
+func IsRetryable(err error) bool {
+	var connectorErr ConnectorError
+	if errors.As(err, &connectorErr) {
+		return connectorErr.IsRetryable()
+	}
+	return true
+}
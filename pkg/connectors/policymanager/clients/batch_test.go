@@ -0,0 +1,159 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// denyAction is the decision of the denied assets
+const denyAction = `{"name":"Deny","Deny":{}}`
+
+// batchServer is a policy manager that denies the access to the given asset, and allows the access to other assets
+type batchServer struct {
+	*httptest.Server
+	denied taxonomy.AssetID
+	// singles and batches count the requests sent to each endpoint
+	singles int32
+	batches int32
+	// batchSizes lists the number of requests of each batch
+	batchSizes []int
+	// correlationIDs holds the correlation ids of the batches
+	correlationIDs []string
+}
+
+func newBatchServer(denied taxonomy.AssetID) *batchServer {
+	server := &batchServer{denied: denied}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/getPoliciesDecisions", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&server.singles, 1)
+		var request policymanager.GetPolicyDecisionsRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		server.respond(w, server.decide(&request))
+	})
+	mux.HandleFunc("/getPoliciesDecisionsBatch", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&server.batches, 1)
+		server.correlationIDs = append(server.correlationIDs, r.Header.Get(connectors.CorrelationIDHeader))
+		var requests []policymanager.GetPolicyDecisionsRequest
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		server.batchSizes = append(server.batchSizes, len(requests))
+		responses := []*policymanager.GetPolicyDecisionsResponse{}
+		for i := range requests {
+			responses = append(responses, server.decide(&requests[i]))
+		}
+		server.respond(w, responses)
+	})
+	server.Server = httptest.NewServer(mux)
+	DeferCleanup(server.Close)
+	return server
+}
+
+func (s *batchServer) decide(request *policymanager.GetPolicyDecisionsRequest) *policymanager.GetPolicyDecisionsResponse {
+	if request.Resource.ID == s.denied {
+		return newResponse(string(request.Resource.ID), denyAction)
+	}
+	return newResponse(string(request.Resource.ID))
+}
+
+func (s *batchServer) respond(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// newPolicyManagerChain wraps the clients of the given servers as the manager does:
+// every client is rate limited and circuit breaking, the clients are consulted in order,
+// and identical concurrent requests share a single call
+func newPolicyManagerChain(servers ...*batchServer) clients.PolicyManager {
+	policyManagers := []clients.PolicyManager{}
+	for _, server := range servers {
+		policyManager, err := clients.NewPolicyManager("opa", server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		policyManager = clients.NewRateLimitedPolicyManager(policyManager,
+			connectors.NewLimiter(connectors.RateLimitConfigFromEnvironment()))
+		policyManager = clients.NewCircuitBreakingPolicyManager(policyManager,
+			connectors.NewCircuitBreaker("opa", connectors.BreakerConfigFromEnvironment()))
+		policyManagers = append(policyManagers, policyManager)
+	}
+	timeout := connectors.RequestTimeoutFromEnvironment(environment.PolicyManagerRequestTimeoutKey)
+	if len(policyManagers) == 1 {
+		return clients.NewSingleFlightPolicyManager(policyManagers[0], timeout)
+	}
+	return clients.NewSingleFlightPolicyManager(clients.NewMultiPolicyManager(policyManagers...), timeout)
+}
+
+var _ = Describe("Batches of policy manager requests", func() {
+	requests := []*policymanager.GetPolicyDecisionsRequest{
+		newDatasetRequest("s3/first-dataset", ""),
+		newDatasetRequest("s3/second-dataset", ""),
+		newDatasetRequest("s3/third-dataset", ""),
+	}
+
+	It("sends the requests of several assets in a single batch through the client chain", func() {
+		server := newBatchServer("s3/second-dataset")
+		policyManager := newPolicyManagerChain(server)
+
+		// the reconcile tags the requests with its correlation id
+		batch, ok := clients.WithCorrelationID(policyManager, "1234").(clients.BatchPolicyManager)
+		Expect(ok).To(BeTrue())
+		responses, err := batch.GetPoliciesDecisionsBatch(context.Background(), requests, "creds")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(atomic.LoadInt32(&server.batches)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&server.singles)).To(BeZero())
+		Expect(server.batchSizes).To(Equal([]int{3}))
+		Expect(server.correlationIDs).To(Equal([]string{"1234"}))
+		Expect(responses).To(HaveLen(3))
+		for _, req := range requests {
+			Expect(responses[req.Resource.ID].DecisionID).To(Equal(string(req.Resource.ID)))
+		}
+		Expect(responses["s3/second-dataset"].Result[0].Action.Name).To(Equal(taxonomy.DenyActionName))
+		Expect(responses["s3/first-dataset"].Result).To(BeEmpty())
+	})
+
+	It("does not send the denied requests to the remaining policy managers", func() {
+		main := newBatchServer("s3/second-dataset")
+		additional := newBatchServer("s3/third-dataset")
+		policyManager := newPolicyManagerChain(main, additional)
+
+		batch, ok := policyManager.(clients.BatchPolicyManager)
+		Expect(ok).To(BeTrue())
+		responses, err := batch.GetPoliciesDecisionsBatch(context.Background(), requests, "creds")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(main.batchSizes).To(Equal([]int{3}))
+		Expect(additional.batchSizes).To(Equal([]int{2}))
+		Expect(atomic.LoadInt32(&main.singles) + atomic.LoadInt32(&additional.singles)).To(BeZero())
+		Expect(responses["s3/first-dataset"].Result).To(BeEmpty())
+		Expect(responses["s3/first-dataset"].DecisionID).To(Equal("s3/first-dataset,s3/first-dataset"))
+		Expect(responses["s3/second-dataset"].Result[0].Action.Name).To(Equal(taxonomy.DenyActionName))
+		Expect(responses["s3/third-dataset"].Result[0].Action.Name).To(Equal(taxonomy.DenyActionName))
+	})
+
+	It("reports the policy managers that do not support batches", func() {
+		policyManager := clients.NewSingleFlightPolicyManager(clients.NewCircuitBreakingPolicyManager(
+			&fakePolicyManager{response: newResponse("1")}, connectors.NewCircuitBreaker("fake", connectors.BreakerConfig{})), 0)
+
+		batch, ok := policyManager.(clients.BatchPolicyManager)
+		Expect(ok).To(BeTrue())
+		_, err := batch.GetPoliciesDecisionsBatch(context.Background(), requests, "creds")
+		Expect(err).To(MatchError(clients.ErrBatchNotSupported))
+	})
+})
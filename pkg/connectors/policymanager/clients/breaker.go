@@ -0,0 +1,68 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// circuitBreakingPolicyManager sends the requests of a policy manager through a circuit breaker
+type circuitBreakingPolicyManager struct {
+	policyManager PolicyManager
+	breaker       *connectors.CircuitBreaker
+}
+
+// NewCircuitBreakingPolicyManager returns a policy manager that fails fast with a connectors.CircuitOpenError
+// while the circuit of the given policy manager is open. Closing it closes the given policy manager.
+func NewCircuitBreakingPolicyManager(policyManager PolicyManager, breaker *connectors.CircuitBreaker) PolicyManager {
+	return &circuitBreakingPolicyManager{policyManager: policyManager, breaker: breaker}
+}
+
+func (m *circuitBreakingPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	response, err := m.policyManager.GetPoliciesDecisions(ctx, in, creds)
+	if err != nil && ctx.Err() != nil {
+		// a canceled request tells nothing about the connector
+		m.breaker.Abandon()
+		return response, err
+	}
+	m.breaker.Done(err)
+	return response, err
+}
+
+// GetPoliciesDecisionsBatch sends the batch through the circuit breaker, as a single request
+func (m *circuitBreakingPolicyManager) GetPoliciesDecisionsBatch(ctx context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	if _, ok := m.policyManager.(BatchPolicyManager); !ok {
+		return nil, ErrBatchNotSupported
+	}
+	if err := m.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	responses, err := getPoliciesDecisionsBatch(ctx, m.policyManager, in, creds)
+	if err != nil && ctx.Err() != nil {
+		// a canceled request tells nothing about the connector
+		m.breaker.Abandon()
+		return responses, err
+	}
+	m.breaker.Done(err)
+	return responses, err
+}
+
+// WithCorrelationID returns a circuit breaking policy manager that shares the circuit breaker,
+// and tags its requests with the correlation id
+func (m *circuitBreakingPolicyManager) WithCorrelationID(correlationID string) PolicyManager {
+	return &circuitBreakingPolicyManager{policyManager: WithCorrelationID(m.policyManager, correlationID), breaker: m.breaker}
+}
+
+func (m *circuitBreakingPolicyManager) Close() error {
+	return m.policyManager.Close()
+}
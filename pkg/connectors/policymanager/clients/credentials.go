@@ -0,0 +1,48 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// CredentialProvider provides the credential used to authenticate to a policy manager connector.
+// Fetch is called on every request, so a rotated credential is used without restarting the manager.
+type CredentialProvider interface {
+	Fetch() (string, error)
+}
+
+// FileCredentialProvider reads the credential from a file, e.g., a mounted kubernetes secret
+// or a file rendered by the Vault agent. Both are updated in place when the secret is rotated.
+type FileCredentialProvider struct {
+	Path string
+}
+
+// Fetch returns the content of the file without surrounding white spaces
+func (p *FileCredentialProvider) Fetch() (string, error) {
+	content, err := os.ReadFile(p.Path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the policy manager credential")
+	}
+	credential := strings.TrimSpace(string(content))
+	if credential == "" {
+		return "", errors.Errorf("the policy manager credential in %s is empty", p.Path)
+	}
+	return credential, nil
+}
+
+// CredentialProviderFromEnvironment returns a provider reading the credential from the file
+// defined by the environment, or nil if no credential file is configured
+func CredentialProviderFromEnvironment() CredentialProvider {
+	path := os.Getenv(environment.PolicyManagerCredentialsPathKey)
+	if path == "" {
+		return nil
+	}
+	return &FileCredentialProvider{Path: path}
+}
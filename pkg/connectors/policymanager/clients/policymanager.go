@@ -4,13 +4,114 @@
 package clients
 
 import (
+	"context"
 	"io"
+	"net/http"
+	"strings"
 
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 )
 
 // PolicyManager is an interface of a facade to connect to a policy manager.
+// The requests are canceled once the given context is done, e.g., when the deadline of the reconcile elapses.
 type PolicyManager interface {
-	GetPoliciesDecisions(in *policymanager.GetPolicyDecisionsRequest, creds string) (*policymanager.GetPolicyDecisionsResponse, error)
+	GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+		creds string) (*policymanager.GetPolicyDecisionsResponse, error)
 	io.Closer
 }
+
+// BatchPolicyManager is implemented by the policy managers that evaluate the requests of several assets at once,
+// so that an application with many assets does not require a round trip per asset
+type BatchPolicyManager interface {
+	// GetPoliciesDecisionsBatch returns the decisions of the given requests keyed by the ID of their resource.
+	// The requests refer to distinct resources.
+	GetPoliciesDecisionsBatch(ctx context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+		creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error)
+}
+
+// ErrBatchNotSupported is returned for the batches sent to a policy manager that evaluates a single request at a time
+var ErrBatchNotSupported = errors.New("the policy manager does not support batches")
+
+// getPoliciesDecisionsBatch sends the batch to the given policy manager if it supports batches
+func getPoliciesDecisionsBatch(ctx context.Context, policyManager PolicyManager, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	batch, ok := policyManager.(BatchPolicyManager)
+	if !ok {
+		return nil, ErrBatchNotSupported
+	}
+	return batch.GetPoliciesDecisionsBatch(ctx, in, creds)
+}
+
+// batchResponses keys the responses of a batch, which are in the order of its requests, by the ID of their resource
+func batchResponses(in []*policymanager.GetPolicyDecisionsRequest, responses []*policymanager.GetPolicyDecisionsResponse,
+	msg string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	if len(responses) != len(in) {
+		return nil, connectors.NewHTTPError(http.StatusInternalServerError,
+			errors.Errorf("%s: received %d responses to %d requests", msg, len(responses), len(in)))
+	}
+	keyed := make(map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, len(in))
+	for i := range in {
+		keyed[in[i].Resource.ID] = responses[i]
+	}
+	return keyed, nil
+}
+
+// CorrelatedPolicyManager is implemented by the policy managers that can tag their requests with a correlation id
+type CorrelatedPolicyManager interface {
+	// WithCorrelationID returns a policy manager that sends the correlation id with its requests.
+	// The returned policy manager shares the connection of the original one, and is not closed separately.
+	WithCorrelationID(correlationID string) PolicyManager
+}
+
+// WithCorrelationID returns a policy manager that tags its requests with the correlation id if this is supported,
+// and the given policy manager otherwise
+func WithCorrelationID(policyManager PolicyManager, correlationID string) PolicyManager {
+	if correlated, ok := policyManager.(CorrelatedPolicyManager); ok && correlationID != "" {
+		return correlated.WithCorrelationID(correlationID)
+	}
+	return policyManager
+}
+
+// NewPolicyManager creates a PolicyManager facade for the connector at the given URL.
+// The retry policy, the credential provider, the proxy, the signature verification and the request timeout
+// are defined by the environment variables.
+func NewPolicyManager(name, connectionURL string) (PolicyManager, error) {
+	config, err := ConnectorConfigFromEnvironment(name, connectionURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewPolicyManagerWithConfig(config)
+}
+
+// ConnectorConfigFromEnvironment returns the configuration of the connector with the given name and URL.
+// The retry policy, the credential provider, the proxy, the signature verification and the request timeout
+// are defined by the environment variables, and are shared by the main, the additional and the named policy managers.
+func ConnectorConfigFromEnvironment(name, connectionURL string) (*ConnectorConfig, error) {
+	signature, err := SignatureVerifierFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectorConfig{
+		Name:        name,
+		URL:         connectionURL,
+		Retry:       RetryConfigFromEnvironment(),
+		Credentials: CredentialProviderFromEnvironment(),
+		ProxyURL:    environment.GetConnectorProxyURL(),
+		Signature:   signature,
+		Timeout:     connectors.RequestTimeoutFromEnvironment(environment.PolicyManagerRequestTimeoutKey),
+	}, nil
+}
+
+// NewPolicyManagerWithConfig creates a PolicyManager facade for the connector configuration.
+// A gRPC client is used for connector URLs of the form grpc://host:port, and an OpenAPI client otherwise.
+func NewPolicyManagerWithConfig(config *ConnectorConfig) (PolicyManager, error) {
+	if strings.HasPrefix(config.URL, connectors.GRPCScheme) {
+		return NewGRPCPolicyManagerWithConfig(config)
+	}
+	return NewOpenAPIPolicyManagerWithConfig(config)
+}
@@ -0,0 +1,355 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/connectors/policymanager/protobuf"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/tls"
+)
+
+// Metadata keys of the gRPC requests, matching the headers of the OpenAPI requests
+const (
+	RequestCredMetadataKey   = "x-request-cred"
+	AuthorizationMetadataKey = "authorization"
+	CorrelationIDMetadataKey = "x-correlation-id"
+)
+
+// maxGRPCAttempts is the maximal number of attempts supported by the gRPC retry policy
+const maxGRPCAttempts = 5
+
+var _ PolicyManager = (*grpcPolicyManager)(nil)
+var _ CorrelatedPolicyManager = (*grpcPolicyManager)(nil)
+var _ BatchPolicyManager = (*grpcPolicyManager)(nil)
+
+type grpcPolicyManager struct {
+	name        string
+	conn        *grpc.ClientConn
+	client      protobuf.PolicyManagerServiceClient
+	credentials CredentialProvider
+	timeout     time.Duration
+	// correlationID is sent with the requests if set
+	correlationID string
+}
+
+// NewGRPCPolicyManagerWithConfig creates a PolicyManager facade that connects to a gRPC service
+// using the given connector configuration. TLS is used if enabled by the environment.
+func NewGRPCPolicyManagerWithConfig(config *ConnectorConfig, opts ...grpc.DialOption) (PolicyManager, error) {
+	if config.Signature != nil {
+		return nil, errors.New("the responses of the gRPC connector " + config.Name + " can not be verified")
+	}
+	log := logging.LogInit(logging.SETUP, "policymanager client")
+	transport := insecure.NewCredentials()
+	if environment.IsUsingTLS() {
+		tlsConfig, err := tls.GetClientTLSConfig(&log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create a tls configuration for "+config.Name)
+		}
+		transport = credentials.NewTLS(tlsConfig)
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(transport),
+		grpc.WithDefaultServiceConfig(config.Retry.serviceConfig())}, opts...)
+	conn, err := grpc.Dial(strings.TrimPrefix(config.URL, connectors.GRPCScheme), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to "+config.Name)
+	}
+	return &grpcPolicyManager{
+		name:        config.Name,
+		conn:        conn,
+		client:      protobuf.NewPolicyManagerServiceClient(conn),
+		credentials: config.Credentials,
+		timeout:     config.Timeout,
+	}, nil
+}
+
+// serviceConfig returns the gRPC service configuration that retries unavailable connectors according to the retry policy.
+// gRPC bounds the number of attempts and does not support a jitter-free backoff.
+func (c *RetryConfig) serviceConfig() string {
+	if c.MaxRetries <= 0 || c.BaseDelay <= 0 {
+		return "{}"
+	}
+	attempts := c.MaxRetries + 1
+	if attempts > maxGRPCAttempts {
+		attempts = maxGRPCAttempts
+	}
+	maxDelay := c.MaxDelay
+	if maxDelay < c.BaseDelay {
+		maxDelay = c.BaseDelay
+	}
+	return fmt.Sprintf(`{"methodConfig": [{"name": [{"service": %q}], "retryPolicy": {"maxAttempts": %d, `+
+		`"initialBackoff": "%.3fs", "maxBackoff": "%.3fs", "backoffMultiplier": 2, "retryableStatusCodes": ["UNAVAILABLE"]}}]}`,
+		protobuf.PolicyManagerService_ServiceDesc.ServiceName, attempts, c.BaseDelay.Seconds(), maxDelay.Seconds())
+}
+
+func (m *grpcPolicyManager) GetPoliciesDecisions(parent context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (_ *policymanager.GetPolicyDecisionsResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.PolicyRequestDuration, start, err) }()
+	printErr := fmt.Sprintf("get policies decisions from %s failed", m.name)
+	ctx, cancel, err := m.requestContext(parent, creds, printErr)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	req, err := RequestToProto(in)
+	if err != nil {
+		return nil, connectors.NewHTTPError(http.StatusBadRequest, errors.Wrap(err, printErr))
+	}
+	resp, err := m.client.GetPoliciesDecisions(ctx, req)
+	if err != nil {
+		if parent.Err() != nil {
+			// the caller is no longer waiting for the response, which is not a failure of the connector
+			return nil, errors.Wrap(parent.Err(), printErr)
+		}
+		return nil, grpcError(err, printErr)
+	}
+	decisions, err := ResponseFromProto(resp)
+	if err != nil {
+		return nil, connectors.NewHTTPError(http.StatusInternalServerError, errors.Wrap(err, printErr))
+	}
+	return decisions, nil
+}
+
+// GetPoliciesDecisionsBatch sends the requests in a single call, whose responses are in the order of the requests
+func (m *grpcPolicyManager) GetPoliciesDecisionsBatch(parent context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (_ map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.PolicyRequestDuration, start, err) }()
+	printErr := fmt.Sprintf("get policies decisions of a batch from %s failed", m.name)
+	ctx, cancel, err := m.requestContext(parent, creds, printErr)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	batch := &protobuf.GetPolicyDecisionsBatchRequest{}
+	for _, request := range in {
+		req, errConvert := RequestToProto(request)
+		if errConvert != nil {
+			return nil, connectors.NewHTTPError(http.StatusBadRequest, errors.Wrap(errConvert, printErr))
+		}
+		batch.Requests = append(batch.Requests, req)
+	}
+	resp, err := m.client.GetPoliciesDecisionsBatch(ctx, batch)
+	if err != nil {
+		if parent.Err() != nil {
+			// the caller is no longer waiting for the response, which is not a failure of the connector
+			return nil, errors.Wrap(parent.Err(), printErr)
+		}
+		return nil, grpcError(err, printErr)
+	}
+	responses := []*policymanager.GetPolicyDecisionsResponse{}
+	for _, item := range resp.GetResponses() {
+		decisions, errConvert := ResponseFromProto(item)
+		if errConvert != nil {
+			return nil, connectors.NewHTTPError(http.StatusInternalServerError, errors.Wrap(errConvert, printErr))
+		}
+		responses = append(responses, decisions)
+	}
+	return batchResponses(in, responses, printErr)
+}
+
+// requestContext returns the context of a request, which holds the metadata of the request
+func (m *grpcPolicyManager) requestContext(parent context.Context, creds, msg string) (context.Context, context.CancelFunc, error) {
+	ctx, cancel := connectors.NewRequestContext(parent, m.timeout)
+	ctx = metadata.AppendToOutgoingContext(ctx, RequestCredMetadataKey, creds)
+	if m.credentials != nil {
+		// the credential is fetched on every request to support its rotation
+		token, err := m.credentials.Fetch()
+		if err != nil {
+			cancel()
+			return nil, nil, errors.Wrap(err, msg)
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, AuthorizationMetadataKey, "Bearer "+token)
+	}
+	if m.correlationID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, CorrelationIDMetadataKey, m.correlationID)
+	}
+	return ctx, cancel, nil
+}
+
+// WithCorrelationID returns a copy of the policy manager that sends the correlation id metadata with its requests
+func (m *grpcPolicyManager) WithCorrelationID(correlationID string) PolicyManager {
+	correlated := *m
+	correlated.correlationID = correlationID
+	return &correlated
+}
+
+func (m *grpcPolicyManager) Close() error {
+	return m.conn.Close()
+}
+
+// grpcError maps the status of a failed gRPC request to the matching connector error
+func grpcError(err error, msg string) error {
+	st := status.Convert(err)
+	detailed := errors.Wrap(errors.New(st.Message()), msg)
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return connectors.NewUnavailableError(detailed)
+	case codes.Unauthenticated:
+		return connectors.NewHTTPError(http.StatusUnauthorized, detailed)
+	case codes.PermissionDenied:
+		return connectors.NewHTTPError(http.StatusForbidden, detailed)
+	case codes.NotFound:
+		return connectors.NewHTTPError(http.StatusNotFound, detailed)
+	case codes.ResourceExhausted:
+		return connectors.NewHTTPError(http.StatusTooManyRequests, detailed)
+	case codes.Unimplemented:
+		// e.g., a connector that does not support batches
+		return connectors.NewHTTPError(http.StatusMethodNotAllowed, detailed)
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return connectors.NewHTTPError(http.StatusBadRequest, detailed)
+	default:
+		return connectors.NewHTTPError(http.StatusInternalServerError, detailed)
+	}
+}
+
+// RequestToProto converts a policy decisions request to its gRPC message
+func RequestToProto(in *policymanager.GetPolicyDecisionsRequest) (*protobuf.GetPolicyDecisionsRequest, error) {
+	requestContext, err := toStruct(&in.Context)
+	if err != nil {
+		return nil, err
+	}
+	resourceMetadata, err := toStruct(in.Resource.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	out := &protobuf.GetPolicyDecisionsRequest{
+		Context: requestContext,
+		Action: &protobuf.RequestAction{
+			ActionType:         string(in.Action.ActionType),
+			ProcessingLocation: string(in.Action.ProcessingLocation),
+			Destination:        in.Action.Destination,
+		},
+		Resource: &protobuf.Resource{Id: string(in.Resource.ID), Metadata: resourceMetadata},
+		Time:     in.Time,
+	}
+	if in.Identity != nil {
+		out.Identity = &protobuf.RequestIdentity{Subject: in.Identity.Subject, Groups: in.Identity.Groups}
+	}
+	return out, nil
+}
+
+// RequestFromProto converts a gRPC message to a policy decisions request
+func RequestFromProto(in *protobuf.GetPolicyDecisionsRequest) (*policymanager.GetPolicyDecisionsRequest, error) {
+	out := &policymanager.GetPolicyDecisionsRequest{}
+	if err := fromStruct(in.GetContext(), &out.Context); err != nil {
+		return nil, err
+	}
+	out.Action = policymanager.RequestAction{
+		ActionType:         taxonomy.DataFlow(in.GetAction().GetActionType()),
+		ProcessingLocation: taxonomy.ProcessingLocation(in.GetAction().GetProcessingLocation()),
+		Destination:        in.GetAction().GetDestination(),
+	}
+	out.Resource.ID = taxonomy.AssetID(in.GetResource().GetId())
+	if in.GetResource().GetMetadata() != nil {
+		if err := fromStruct(in.GetResource().GetMetadata(), &out.Resource.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	if in.GetIdentity() != nil {
+		out.Identity = &policymanager.RequestIdentity{Subject: in.GetIdentity().GetSubject(), Groups: in.GetIdentity().GetGroups()}
+	}
+	out.Time = in.GetTime()
+	return out, nil
+}
+
+// ResponseToProto converts a policy decisions response to its gRPC message
+func ResponseToProto(in *policymanager.GetPolicyDecisionsResponse) (*protobuf.GetPolicyDecisionsResponse, error) {
+	out := &protobuf.GetPolicyDecisionsResponse{DecisionId: in.DecisionID, Message: in.Message}
+	for i := range in.Result {
+		properties := map[string]interface{}{}
+		if err := convert(&in.Result[i].Action, &properties); err != nil {
+			return nil, err
+		}
+		delete(properties, "name")
+		action := &protobuf.Action{Name: string(in.Result[i].Action.Name)}
+		if len(properties) > 0 {
+			var err error
+			if action.Properties, err = structpb.NewStruct(properties); err != nil {
+				return nil, errors.Wrap(err, "could not convert the properties of "+action.Name)
+			}
+		}
+		item := &protobuf.ResultItem{Policy: in.Result[i].Policy, Action: action}
+		if validity := in.Result[i].Validity; validity != nil {
+			item.Validity = &protobuf.Validity{NotBefore: validity.NotBefore, NotAfter: validity.NotAfter}
+		}
+		out.Result = append(out.Result, item)
+	}
+	return out, nil
+}
+
+// ResponseFromProto converts a gRPC message to a policy decisions response
+func ResponseFromProto(in *protobuf.GetPolicyDecisionsResponse) (*policymanager.GetPolicyDecisionsResponse, error) {
+	out := &policymanager.GetPolicyDecisionsResponse{DecisionID: in.GetDecisionId(), Message: in.GetMessage(),
+		Result: []policymanager.ResultItem{}}
+	for _, item := range in.GetResult() {
+		properties := item.GetAction().GetProperties().AsMap()
+		properties["name"] = item.GetAction().GetName()
+		var action taxonomy.Action
+		if err := convert(properties, &action); err != nil {
+			return nil, err
+		}
+		resultItem := policymanager.ResultItem{Policy: item.GetPolicy(), Action: action}
+		if item.GetValidity() != nil {
+			resultItem.Validity = &policymanager.Validity{NotBefore: item.GetValidity().GetNotBefore(),
+				NotAfter: item.GetValidity().GetNotAfter()}
+		}
+		out.Result = append(out.Result, resultItem)
+	}
+	return out, nil
+}
+
+// toStruct converts a value to a JSON object, nil values are converted to nil
+func toStruct(in interface{}) (*structpb.Struct, error) {
+	var fields map[string]interface{}
+	if err := convert(in, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, nil
+	}
+	out, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert to a JSON object")
+	}
+	return out, nil
+}
+
+// fromStruct converts a JSON object to the given value, nil objects leave the value unchanged
+func fromStruct(in *structpb.Struct, out interface{}) error {
+	if in == nil {
+		return nil
+	}
+	return convert(in.AsMap(), out)
+}
+
+// convert copies a value to another through its JSON serialization
+func convert(in, out interface{}) error {
+	bytes, err := json.Marshal(in)
+	if err != nil {
+		return errors.Wrap(err, "could not serialize to JSON")
+	}
+	return errors.Wrap(json.Unmarshal(bytes, out), "could not deserialize from JSON")
+}
@@ -0,0 +1,266 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/connectors/policymanager/protobuf"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// grpcPolicyManagerServer denies the access to s3/deny-dataset, redacts the SSN column of s3/redact-dataset,
+// redacts the SSN column of s3/time-bounded-dataset from the time of the request until the end of 2030, and fails the requests of other assets with the given status code after an initial number of unavailable responses
+type grpcPolicyManagerServer struct {
+	protobuf.UnimplementedPolicyManagerServiceServer
+	code        codes.Code
+	unavailable int32
+	calls       int32
+	requests    []*policymanager.GetPolicyDecisionsRequest
+	creds       []string
+	// correlationIDs holds the correlation ids of the requests, if any
+	correlationIDs []string
+	// delay is the time it takes to respond, unless the request is canceled
+	delay time.Duration
+	// batches is the number of batches received
+	batches int32
+}
+
+func (s *grpcPolicyManagerServer) GetPoliciesDecisions(ctx context.Context,
+	in *protobuf.GetPolicyDecisionsRequest) (*protobuf.GetPolicyDecisionsResponse, error) {
+	if atomic.AddInt32(&s.calls, 1) <= s.unavailable {
+		return nil, status.Error(codes.Unavailable, "policy manager is starting")
+	}
+	s.recordMetadata(ctx)
+	return s.decide(ctx, in)
+}
+
+func (s *grpcPolicyManagerServer) GetPoliciesDecisionsBatch(ctx context.Context,
+	in *protobuf.GetPolicyDecisionsBatchRequest) (*protobuf.GetPolicyDecisionsBatchResponse, error) {
+	atomic.AddInt32(&s.batches, 1)
+	s.recordMetadata(ctx)
+	out := &protobuf.GetPolicyDecisionsBatchResponse{}
+	for _, req := range in.GetRequests() {
+		resp, err := s.decide(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		out.Responses = append(out.Responses, resp)
+	}
+	return out, nil
+}
+
+// recordMetadata records the credentials and the correlation id of a request
+func (s *grpcPolicyManagerServer) recordMetadata(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.creds = append(s.creds, md.Get(clients.RequestCredMetadataKey)...)
+	s.correlationIDs = append(s.correlationIDs, md.Get(clients.CorrelationIDMetadataKey)...)
+}
+
+// decide returns the decisions of a request
+func (s *grpcPolicyManagerServer) decide(ctx context.Context,
+	in *protobuf.GetPolicyDecisionsRequest) (*protobuf.GetPolicyDecisionsResponse, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	req, err := clients.RequestFromProto(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	s.requests = append(s.requests, req)
+	var action taxonomy.Action
+	var validity *policymanager.Validity
+	switch req.Resource.ID {
+	case "s3/deny-dataset":
+		action = taxonomy.Action{Name: "Deny", AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+			"Deny": map[string]interface{}{}}}}
+	case "s3/redact-dataset":
+		action = taxonomy.Action{Name: "RedactAction", AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+			"RedactAction": map[string]interface{}{"columns": []interface{}{"SSN"}}}}}
+	case "s3/time-bounded-dataset":
+		action = taxonomy.Action{Name: "RedactAction", AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+			"RedactAction": map[string]interface{}{"columns": []interface{}{"SSN"}}}}}
+		validity = &policymanager.Validity{NotBefore: req.Time, NotAfter: "2031-01-01T00:00:00Z"}
+	default:
+		return nil, status.Error(s.code, "policy evaluation failed")
+	}
+	return clients.ResponseToProto(&policymanager.GetPolicyDecisionsResponse{
+		DecisionID: "1",
+		Result:     []policymanager.ResultItem{{Policy: "policy", Action: action, Validity: validity}},
+	})
+}
+
+// newGRPCPolicyManager returns a client of an in-memory gRPC policy manager
+func newGRPCPolicyManager(server *grpcPolicyManagerServer, retry clients.RetryConfig) clients.PolicyManager {
+	return newGRPCPolicyManagerWithConfig(server, &clients.ConnectorConfig{Retry: retry})
+}
+
+// newGRPCPolicyManagerWithConfig returns a client of an in-memory gRPC policy manager with the given configuration
+func newGRPCPolicyManagerWithConfig(server *grpcPolicyManagerServer, config *clients.ConnectorConfig) clients.PolicyManager {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer()
+	protobuf.RegisterPolicyManagerServiceServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	DeferCleanup(grpcServer.Stop)
+
+	config.Name = "grpc"
+	config.URL = connectors.GRPCScheme + "bufnet"
+	policyManager, err := clients.NewGRPCPolicyManagerWithConfig(config,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }))
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(policyManager.Close)
+	return policyManager
+}
+
+var _ = Describe("gRPC policy manager client", func() {
+	request := func(id taxonomy.AssetID) *policymanager.GetPolicyDecisionsRequest {
+		return &policymanager.GetPolicyDecisionsRequest{
+			Context: taxonomy.PolicyManagerRequestContext{Properties: serde.Properties{Items: map[string]interface{}{
+				"intent": "Fraud Detection"}}},
+			Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow, Destination: "neverland"},
+			Resource: policymanager.Resource{ID: id, Metadata: &datacatalog.ResourceMetadata{Geography: "theshire"}},
+			Identity: &policymanager.RequestIdentity{Subject: "alice", Groups: []string{"analysts"}},
+		}
+	}
+	noRetry := clients.RetryConfig{MaxRetries: 0}
+
+	It("returns a deny decision", func() {
+		server := &grpcPolicyManagerServer{}
+		policyManager := newGRPCPolicyManager(server, noRetry)
+
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request("s3/deny-dataset"), "creds")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DecisionID).To(Equal("1"))
+		Expect(resp.Result).To(HaveLen(1))
+		Expect(resp.Result[0].Action.Name).To(Equal(taxonomy.ActionName("Deny")))
+		Expect(server.creds).To(Equal([]string{"creds"}))
+		// the request is received as sent
+		Expect(server.requests).To(Equal([]*policymanager.GetPolicyDecisionsRequest{request("s3/deny-dataset")}))
+	})
+
+	It("returns a redact decision", func() {
+		policyManager := newGRPCPolicyManager(&grpcPolicyManagerServer{}, noRetry)
+
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request("s3/redact-dataset"), "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Result).To(HaveLen(1))
+		Expect(resp.Result[0].Policy).To(Equal("policy"))
+		Expect(resp.Result[0].Action.Name).To(Equal(taxonomy.ActionName("RedactAction")))
+		Expect(resp.Result[0].Action.AdditionalProperties.Items).To(HaveKeyWithValue("RedactAction",
+			map[string]interface{}{"columns": []interface{}{"SSN"}}))
+	})
+
+	It("round-trips the time of the request and the validity of the decisions", func() {
+		server := &grpcPolicyManagerServer{}
+		policyManager := newGRPCPolicyManager(server, noRetry)
+		timeBounded := request("s3/time-bounded-dataset")
+		timeBounded.Time = "2023-06-01T12:00:00Z"
+
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), timeBounded, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.requests).To(Equal([]*policymanager.GetPolicyDecisionsRequest{timeBounded}))
+		Expect(resp.Result).To(HaveLen(1))
+		Expect(resp.Result[0].Validity).To(Equal(&policymanager.Validity{NotBefore: "2023-06-01T12:00:00Z",
+			NotAfter: "2031-01-01T00:00:00Z"}))
+
+		// decisions without a time window are returned without a validity
+		resp, err = policyManager.GetPoliciesDecisions(context.Background(), request("s3/redact-dataset"), "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Result[0].Validity).To(BeNil())
+	})
+
+	It("sends the correlation id metadata", func() {
+		server := &grpcPolicyManagerServer{}
+		policyManager := newGRPCPolicyManager(server, noRetry)
+
+		_, err := clients.WithCorrelationID(policyManager, "1234").GetPoliciesDecisions(context.Background(), request("s3/deny-dataset"), "")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request("s3/deny-dataset"), "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(server.correlationIDs).To(Equal([]string{"1234"}))
+	})
+
+	It("returns the decisions of a batch", func() {
+		server := &grpcPolicyManagerServer{}
+		policyManager := newGRPCPolicyManager(server, noRetry)
+		batch, ok := clients.WithCorrelationID(policyManager, "1234").(clients.BatchPolicyManager)
+		Expect(ok).To(BeTrue())
+
+		responses, err := batch.GetPoliciesDecisionsBatch(context.Background(),
+			[]*policymanager.GetPolicyDecisionsRequest{request("s3/deny-dataset"), request("s3/redact-dataset")}, "creds")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(responses).To(HaveLen(2))
+		Expect(responses["s3/deny-dataset"].Result[0].Action.Name).To(Equal(taxonomy.ActionName("Deny")))
+		Expect(responses["s3/redact-dataset"].Result[0].Action.Name).To(Equal(taxonomy.ActionName("RedactAction")))
+		Expect(atomic.LoadInt32(&server.batches)).To(Equal(int32(1)))
+		Expect(atomic.LoadInt32(&server.calls)).To(BeZero())
+		// the metadata is sent once with the batch
+		Expect(server.creds).To(Equal([]string{"creds"}))
+		Expect(server.correlationIDs).To(Equal([]string{"1234"}))
+	})
+
+	It("retries unavailable connectors", func() {
+		server := &grpcPolicyManagerServer{unavailable: 2}
+		policyManager := newGRPCPolicyManager(server,
+			clients.RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxRetries: 3})
+
+		_, err := policyManager.GetPoliciesDecisions(context.Background(), request("s3/deny-dataset"), "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(atomic.LoadInt32(&server.calls)).To(Equal(int32(3)))
+	})
+
+	It("aborts a request once the timeout elapses", func() {
+		timeout := 200 * time.Millisecond
+		policyManager := newGRPCPolicyManagerWithConfig(&grpcPolicyManagerServer{delay: 10 * time.Second},
+			&clients.ConnectorConfig{Retry: noRetry, Timeout: timeout})
+
+		start := time.Now()
+		_, err := policyManager.GetPoliciesDecisions(context.Background(), request("s3/deny-dataset"), "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.ConnectorUnavailableError{}))
+		Expect(connectors.IsRetryable(err)).To(BeTrue())
+		Expect(time.Since(start)).To(And(BeNumerically(">=", timeout), BeNumerically("<", 2*time.Second)))
+	})
+
+	DescribeTable("map the status code to a typed error",
+		func(code codes.Code, expected interface{}, retryable bool) {
+			policyManager := newGRPCPolicyManager(&grpcPolicyManagerServer{code: code}, noRetry)
+
+			_, err := policyManager.GetPoliciesDecisions(context.Background(), request("s3/allow-dataset"), "")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(expected))
+			Expect(err.Error()).To(ContainSubstring("policy evaluation failed"))
+			Expect(connectors.IsRetryable(err)).To(Equal(retryable))
+		},
+		Entry("bad credentials", codes.Unauthenticated, &connectors.AuthError{}, false),
+		Entry("forbidden", codes.PermissionDenied, &connectors.AuthError{}, false),
+		Entry("dataset not found", codes.NotFound, &connectors.AssetNotFoundError{}, false),
+		Entry("bad request", codes.InvalidArgument, &connectors.InvalidRequestError{}, false),
+		Entry("server failure", codes.Unavailable, &connectors.ConnectorUnavailableError{}, true),
+	)
+
+	It("is selected by the URL scheme", func() {
+		policyManager, err := clients.NewPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "grpc", URL: connectors.GRPCScheme + "localhost:50051", Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policyManager.Close()).To(Succeed())
+	})
+})
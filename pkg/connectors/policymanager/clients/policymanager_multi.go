@@ -0,0 +1,216 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+const (
+	decisionsSeparator = ","
+	messagesSeparator  = "; "
+)
+
+var _ PolicyManager = (*multiPolicyManager)(nil)
+var _ CorrelatedPolicyManager = (*multiPolicyManager)(nil)
+
+// multiPolicyManager consults an ordered list of policy managers and aggregates their decisions
+type multiPolicyManager struct {
+	policyManagers []PolicyManager
+}
+
+// NewMultiPolicyManager creates a PolicyManager facade that consults the given policy managers in order.
+// A Deny decision of any policy manager takes precedence, the remaining policy managers are not consulted.
+func NewMultiPolicyManager(policyManagers ...PolicyManager) PolicyManager {
+	return &multiPolicyManager{policyManagers: policyManagers}
+}
+
+// AdditionalPolicyManagersFromEnvironment returns the configuration of policy managers that are consulted
+// in addition to the main policy manager. They are specified as an ordered comma separated list of name=URL pairs.
+func AdditionalPolicyManagersFromEnvironment() ([]ConnectorConfig, error) {
+	return policyManagersFromEnvironment(environment.AdditionalPolicyManagersKey)
+}
+
+// NamedPolicyManagersFromEnvironment returns the configuration of the policy managers that an application may select
+// by name instead of the main and the additional policy managers, e.g., a policy manager holding the staging policies.
+// They are specified as a comma separated list of name=URL pairs, whose names are unique.
+func NamedPolicyManagersFromEnvironment() ([]ConnectorConfig, error) {
+	configs, err := policyManagersFromEnvironment(environment.NamedPolicyManagersKey)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for i := range configs {
+		if names[configs[i].Name] {
+			return nil, errors.Errorf("duplicate policy manager %q in %s", configs[i].Name, environment.NamedPolicyManagersKey)
+		}
+		names[configs[i].Name] = true
+	}
+	return configs, nil
+}
+
+// policyManagersFromEnvironment parses the comma separated list of name=URL pairs in the given environment variable.
+// The connectors are configured by the environment variables like the main policy manager.
+func policyManagersFromEnvironment(key string) ([]ConnectorConfig, error) {
+	configs := []ConnectorConfig{}
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return configs, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		nameAndURL := strings.SplitN(strings.TrimSpace(item), "=", 2) //nolint:revive,gomnd
+		if len(nameAndURL) != 2 || nameAndURL[0] == "" || nameAndURL[1] == "" {
+			return nil, errors.Errorf("invalid policy manager %q in %s, expected name=URL", item, key)
+		}
+		config, err := ConnectorConfigFromEnvironment(nameAndURL[0], nameAndURL[1])
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, *config)
+	}
+	return configs, nil
+}
+
+func (m *multiPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	responses := []*policymanager.GetPolicyDecisionsResponse{}
+	for _, policyManager := range m.policyManagers {
+		resp, err := policyManager.GetPoliciesDecisions(ctx, in, creds)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+		if isDenied(resp) {
+			break
+		}
+	}
+	return AggregatePolicyDecisions(responses), nil
+}
+
+// GetPoliciesDecisionsBatch sends a batch to every policy manager in order, which holds the requests
+// that have not been denied by the previous policy managers, and aggregates the decisions per resource
+func (m *multiPolicyManager) GetPoliciesDecisionsBatch(ctx context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	responses := map[taxonomy.AssetID][]*policymanager.GetPolicyDecisionsResponse{}
+	pending := in
+	for _, policyManager := range m.policyManagers {
+		if len(pending) == 0 {
+			break
+		}
+		batch, err := getPoliciesDecisionsBatch(ctx, policyManager, pending, creds)
+		if err != nil {
+			return nil, err
+		}
+		allowed := []*policymanager.GetPolicyDecisionsRequest{}
+		for _, req := range pending {
+			resp, found := batch[req.Resource.ID]
+			if !found {
+				return nil, errors.Errorf("no policy decisions of %s in the batch", req.Resource.ID)
+			}
+			responses[req.Resource.ID] = append(responses[req.Resource.ID], resp)
+			if !isDenied(resp) {
+				allowed = append(allowed, req)
+			}
+		}
+		pending = allowed
+	}
+	aggregated := make(map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, len(responses))
+	for id := range responses {
+		aggregated[id] = AggregatePolicyDecisions(responses[id])
+	}
+	return aggregated, nil
+}
+
+// WithCorrelationID returns a copy of the policy manager whose policy managers tag their requests with the correlation id
+func (m *multiPolicyManager) WithCorrelationID(correlationID string) PolicyManager {
+	policyManagers := make([]PolicyManager, len(m.policyManagers))
+	for i, policyManager := range m.policyManagers {
+		policyManagers[i] = WithCorrelationID(policyManager, correlationID)
+	}
+	return &multiPolicyManager{policyManagers: policyManagers}
+}
+
+func (m *multiPolicyManager) Close() error {
+	var errs []error
+	for _, policyManager := range m.policyManagers {
+		errs = append(errs, policyManager.Close())
+	}
+	return errors.Combine(errs...)
+}
+
+// isDenied returns true if the response contains a Deny action
+func isDenied(resp *policymanager.GetPolicyDecisionsResponse) bool {
+	for i := range resp.Result {
+		if resp.Result[i].Action.Name == taxonomy.DenyActionName {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregatePolicyDecisions merges the responses of several policy managers into a single response.
+// If any response denies the access, only the Deny decision is returned.
+// Otherwise, the action lists are merged: columns of actions with the same name are united,
+// and identical actions are returned once.
+func AggregatePolicyDecisions(responses []*policymanager.GetPolicyDecisionsResponse) *policymanager.GetPolicyDecisionsResponse {
+	aggregated := &policymanager.GetPolicyDecisionsResponse{Result: []policymanager.ResultItem{}}
+	var decisionIDs, messages []string
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if resp.DecisionID != "" {
+			decisionIDs = append(decisionIDs, resp.DecisionID)
+		}
+		if resp.Message != "" {
+			messages = append(messages, resp.Message)
+		}
+		if isDenied(resp) {
+			// deny wins
+			aggregated.Result = []policymanager.ResultItem{}
+			for i := range resp.Result {
+				if resp.Result[i].Action.Name == taxonomy.DenyActionName {
+					aggregated.Result = append(aggregated.Result, resp.Result[i])
+				}
+			}
+			break
+		}
+		for i := range resp.Result {
+			aggregated.Result = mergeResultItem(aggregated.Result, &resp.Result[i])
+		}
+	}
+	aggregated.DecisionID = strings.Join(decisionIDs, decisionsSeparator)
+	aggregated.Message = strings.Join(messages, messagesSeparator)
+	return aggregated
+}
+
+// mergeResultItem adds a result item to the list, merging it with an existing item of the same action if possible
+func mergeResultItem(items []policymanager.ResultItem, item *policymanager.ResultItem) []policymanager.ResultItem {
+	for i := range items {
+		if items[i].Action.Name != item.Action.Name || !reflect.DeepEqual(items[i].Validity, item.Validity) {
+			// actions that apply in different time windows are kept apart
+			continue
+		}
+		if reflect.DeepEqual(items[i].Action, item.Action) {
+			return items
+		}
+		if merged, ok := taxonomy.UniteColumns(&items[i].Action, &item.Action); ok {
+			items[i].Action = merged
+			if item.Policy != "" && item.Policy != items[i].Policy {
+				items[i].Policy = strings.TrimPrefix(items[i].Policy+messagesSeparator+item.Policy, messagesSeparator)
+			}
+			return items
+		}
+	}
+	return append(items, *item)
+}
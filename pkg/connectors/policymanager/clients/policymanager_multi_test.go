@@ -0,0 +1,170 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// fakePolicyManager returns a fixed response and counts its invocations
+type fakePolicyManager struct {
+	response *policymanager.GetPolicyDecisionsResponse
+	calls    int
+}
+
+func (m *fakePolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	m.calls++
+	return m.response, nil
+}
+
+func (m *fakePolicyManager) Close() error {
+	return nil
+}
+
+func newResponse(decisionID string, actions ...string) *policymanager.GetPolicyDecisionsResponse {
+	resp := &policymanager.GetPolicyDecisionsResponse{DecisionID: decisionID, Result: []policymanager.ResultItem{}}
+	for _, action := range actions {
+		item := policymanager.ResultItem{}
+		Expect(json.Unmarshal([]byte(action), &item.Action)).To(Succeed())
+		resp.Result = append(resp.Result, item)
+	}
+	return resp
+}
+
+func redactedColumns(action *taxonomy.Action) []interface{} {
+	props := action.AdditionalProperties.Items["RedactAction"].(map[string]interface{})
+	return props["columns"].([]interface{})
+}
+
+var _ = Describe("Aggregation of policy decisions", func() {
+	const (
+		redactSSN   = `{"name":"RedactAction","RedactAction":{"columns":["SSN"]}}`
+		redactEmail = `{"name":"RedactAction","RedactAction":{"columns":["Email","SSN"]}}`
+		filter      = `{"name":"FilterAction","FilterAction":{"query":"Country == 'UK'"}}`
+		deny        = `{"name":"Deny","Deny":{}}`
+	)
+
+	It("unites the columns of conflicting redact actions", func() {
+		resp := clients.AggregatePolicyDecisions([]*policymanager.GetPolicyDecisionsResponse{
+			newResponse("1", redactSSN, filter),
+			newResponse("2", redactEmail, filter),
+		})
+		Expect(resp.DecisionID).To(Equal("1,2"))
+		Expect(resp.Result).To(HaveLen(2))
+		Expect(resp.Result[0].Action.Name).To(Equal(taxonomy.ActionName("RedactAction")))
+		Expect(redactedColumns(&resp.Result[0].Action)).To(Equal([]interface{}{"SSN", "Email"}))
+		Expect(resp.Result[1].Action.Name).To(Equal(taxonomy.ActionName("FilterAction")))
+	})
+
+	It("returns the deny decision only", func() {
+		resp := clients.AggregatePolicyDecisions([]*policymanager.GetPolicyDecisionsResponse{
+			newResponse("1", redactSSN),
+			newResponse("2", deny),
+		})
+		Expect(resp.Result).To(HaveLen(1))
+		Expect(resp.Result[0].Action.Name).To(Equal(taxonomy.DenyActionName))
+	})
+
+	It("does not consult the remaining policy managers after a deny", func() {
+		corporate := &fakePolicyManager{response: newResponse("1", deny)}
+		team := &fakePolicyManager{response: newResponse("2", redactSSN)}
+		policyManager := clients.NewMultiPolicyManager(corporate, team)
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), &policymanager.GetPolicyDecisionsRequest{}, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Result).To(HaveLen(1))
+		Expect(resp.Result[0].Action.Name).To(Equal(taxonomy.DenyActionName))
+		Expect(corporate.calls).To(Equal(1))
+		Expect(team.calls).To(Equal(0))
+		Expect(policyManager.Close()).To(Succeed())
+	})
+
+	It("merges the actions of all policy managers", func() {
+		corporate := &fakePolicyManager{response: newResponse("1", redactSSN)}
+		team := &fakePolicyManager{response: newResponse("2", filter)}
+		policyManager := clients.NewMultiPolicyManager(corporate, team)
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), &policymanager.GetPolicyDecisionsRequest{}, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Result).To(HaveLen(2))
+		Expect(team.calls).To(Equal(1))
+	})
+})
+
+var _ = Describe("Configuration of the named policy managers", func() {
+	It("returns the policy managers by name", func() {
+		setenv(environment.NamedPolicyManagersKey, "staging=http://staging-opa-connector:8080, prod=http://opa-connector:8080")
+		configs, err := clients.NamedPolicyManagersFromEnvironment()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configs).To(HaveLen(2))
+		Expect(configs[0].Name).To(Equal("staging"))
+		Expect(configs[0].URL).To(Equal("http://staging-opa-connector:8080"))
+		Expect(configs[1].Name).To(Equal("prod"))
+	})
+
+	It("fetches the credential of the policy managers like the main policy manager", func() {
+		setenv(environment.PolicyManagerCredentialsPathKey, writeFile(GinkgoT().TempDir(), "token", []byte("secret\n")))
+		setenv(environment.NamedPolicyManagersKey, "staging=http://staging-opa-connector:8080")
+		configs, err := clients.NamedPolicyManagersFromEnvironment()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configs).To(HaveLen(1))
+		Expect(configs[0].Credentials).ToNot(BeNil())
+		credential, err := configs[0].Credentials.Fetch()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credential).To(Equal("secret"))
+	})
+
+	It("rejects duplicate names", func() {
+		setenv(environment.NamedPolicyManagersKey, "staging=http://staging-opa-connector:8080,staging=http://opa-connector:8080")
+		_, err := clients.NamedPolicyManagersFromEnvironment()
+		Expect(err).To(MatchError(ContainSubstring("duplicate policy manager")))
+	})
+
+	It("rejects a policy manager without a URL", func() {
+		setenv(environment.NamedPolicyManagersKey, "staging")
+		_, err := clients.NamedPolicyManagersFromEnvironment()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Signature verification of the additional policy managers", func() {
+	It("rejects an unsigned response of an additional policy manager", func() {
+		publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		Expect(err).ToNot(HaveOccurred())
+		keyPath := writeFile(GinkgoT().TempDir(), "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		setenv(environment.PolicyManagerPublicKeyPathKey, keyPath)
+		setenv(environment.PolicyManagerMaxRetriesKey, "0")
+		unsigned := newFlakyServer(0, http.StatusOK, new(int32))
+		defer unsigned.Close()
+		setenv(environment.AdditionalPolicyManagersKey, "team="+unsigned.URL)
+
+		configs, err := clients.AdditionalPolicyManagersFromEnvironment()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configs).To(HaveLen(1))
+		Expect(configs[0].Signature).ToNot(BeNil())
+		policyManager, err := clients.NewPolicyManagerWithConfig(&configs[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = clients.NewMultiPolicyManager(policyManager).GetPoliciesDecisions(context.Background(),
+			&policymanager.GetPolicyDecisionsRequest{Resource: policymanager.Resource{ID: "s3/allow-dataset"}}, "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.SignatureError{}))
+		Expect(err).To(MatchError(ContainSubstring("not signed")))
+	})
+})
@@ -8,73 +8,196 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"emperror.dev/errors"
 
+	"fybrik.io/fybrik/pkg/connectors"
 	"fybrik.io/fybrik/pkg/connectors/policymanager/openapiclient"
+	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/tls"
 )
 
 var _ PolicyManager = (*openAPIPolicyManager)(nil)
+var _ CorrelatedPolicyManager = (*openAPIPolicyManager)(nil)
+var _ BatchPolicyManager = (*openAPIPolicyManager)(nil)
 
 type openAPIPolicyManager struct {
-	name   string
-	client *openapiclient.APIClient
+	name        string
+	client      *openapiclient.APIClient
+	credentials CredentialProvider
+	signature   *SignatureVerifier
+	timeout     time.Duration
 }
 
 // NewopenApiPolicyManager creates a PolicyManager facade that connects to a openApi service
-
+// The retry policy, the credential provider, the proxy, the signature verification and the request timeout
+// are defined by the environment variables.
 func NewOpenAPIPolicyManager(name, connectionURL string) (PolicyManager, error) {
+	signature, err := SignatureVerifierFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenAPIPolicyManagerWithConfig(&ConnectorConfig{
+		Name:        name,
+		URL:         connectionURL,
+		Retry:       RetryConfigFromEnvironment(),
+		Credentials: CredentialProviderFromEnvironment(),
+		ProxyURL:    environment.GetConnectorProxyURL(),
+		Signature:   signature,
+		Timeout:     connectors.RequestTimeoutFromEnvironment(environment.PolicyManagerRequestTimeoutKey),
+	})
+}
+
+// NewOpenAPIPolicyManagerWithConfig creates a PolicyManager facade that connects to a openApi service
+// using the given connector configuration
+func NewOpenAPIPolicyManagerWithConfig(config *ConnectorConfig) (PolicyManager, error) {
+	if _, err := tls.ParseProxyURL(config.ProxyURL); err != nil {
+		return nil, errors.Wrap(err, "invalid proxy of "+config.Name)
+	}
 	log := logging.LogInit(logging.SETUP, "policymanager client")
+	httpClient := tls.GetHTTPClientWithProxy(&log, config.ProxyURL)
+	if httpClient == nil {
+		return nil, errors.New("failed to create an http client for " + config.Name)
+	}
+	config.Retry.apply(httpClient)
 	configuration := &openapiclient.Configuration{
 		DefaultHeader: make(map[string]string),
 		UserAgent:     "OpenAPI-Generator/1.0.0/go",
 		Debug:         false,
 		Servers: openapiclient.ServerConfigurations{
 			{
-				URL:         connectionURL,
+				URL:         config.URL,
 				Description: "No description provided",
 			},
 		},
 		OperationServers: map[string]openapiclient.ServerConfigurations{},
-		HTTPClient:       tls.GetHTTPClient(&log).StandardClient(),
+		HTTPClient:       httpClient.StandardClient(),
 	}
 	apiClient := openapiclient.NewAPIClient(configuration)
 
 	return &openAPIPolicyManager{
-		name:   name,
-		client: apiClient,
+		name:        config.Name,
+		client:      apiClient,
+		credentials: config.Credentials,
+		signature:   config.Signature,
+		timeout:     config.Timeout,
 	}, nil
 }
 
-// getDetailedError generates an error from the response body JSON if available,
+// getDetailedError generates a typed connector error from the response body JSON if available,
 // otherwise it extends the base error (e.g., 400 Bad Request) with the given message string
 func getDetailedError(httpResponse *http.Response, baseError error, defaultMsg string) error {
 	if bodyBytes, errRead := io.ReadAll(httpResponse.Body); errRead == nil && len(bodyBytes) > 0 {
-		return errors.New(string(bodyBytes))
+		return connectors.NewHTTPError(httpResponse.StatusCode, errors.New(string(bodyBytes)))
 	}
-	return errors.Wrap(baseError, defaultMsg)
+	return connectors.NewHTTPError(httpResponse.StatusCode, errors.Wrap(baseError, defaultMsg))
 }
 
-func (m *openAPIPolicyManager) GetPoliciesDecisions(in *policymanager.GetPolicyDecisionsRequest,
-	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
-	printErr := func() string { return fmt.Sprintf("get policies decisions from %s failed", m.name) }
-	resp, httpResponse, err := m.client.DefaultApi.GetPoliciesDecisions(context.Background()).XRequestCred(creds).
+func (m *openAPIPolicyManager) GetPoliciesDecisions(parent context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (_ *policymanager.GetPolicyDecisionsResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.PolicyRequestDuration, start, err) }()
+	printErr := fmt.Sprintf("get policies decisions from %s failed", m.name)
+	ctx, cancel, err := m.requestContext(parent, printErr)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	resp, httpResponse, err := m.client.DefaultApi.GetPoliciesDecisions(ctx).XRequestCred(creds).
 		GetPolicyDecisionsRequest(*in).Execute()
+	if err = m.checkResponse(parent, httpResponse, err, printErr); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPoliciesDecisionsBatch sends the requests in a single call, whose responses are in the order of the requests
+func (m *openAPIPolicyManager) GetPoliciesDecisionsBatch(parent context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (_ map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObserveSince(metrics.PolicyRequestDuration, start, err) }()
+	printErr := fmt.Sprintf("get policies decisions of a batch from %s failed", m.name)
+	ctx, cancel, err := m.requestContext(parent, printErr)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	requests := make([]policymanager.GetPolicyDecisionsRequest, len(in))
+	for i := range in {
+		requests[i] = *in[i]
+	}
+	resp, httpResponse, err := m.client.DefaultApi.GetPoliciesDecisionsBatch(ctx).XRequestCred(creds).
+		GetPolicyDecisionsRequest(requests).Execute()
+	if err = m.checkResponse(parent, httpResponse, err, printErr); err != nil {
+		return nil, err
+	}
+	responses := make([]*policymanager.GetPolicyDecisionsResponse, len(resp))
+	for i := range resp {
+		responses[i] = &resp[i]
+	}
+	return batchResponses(in, responses, printErr)
+}
 
+// requestContext returns the context of a request, which holds the token of the connector if any
+func (m *openAPIPolicyManager) requestContext(parent context.Context, msg string) (context.Context, context.CancelFunc, error) {
+	ctx, cancel := connectors.NewRequestContext(parent, m.timeout)
+	if m.credentials != nil {
+		// the credential is fetched on every request to support its rotation
+		token, err := m.credentials.Fetch()
+		if err != nil {
+			cancel()
+			return nil, nil, errors.Wrap(err, msg)
+		}
+		ctx = context.WithValue(ctx, openapiclient.ContextAccessToken, token)
+	}
+	return ctx, cancel, nil
+}
+
+// checkResponse maps a failed request to the matching connector error, and verifies the signature of a successful response
+func (m *openAPIPolicyManager) checkResponse(parent context.Context, httpResponse *http.Response, err error, msg string) error {
 	if httpResponse == nil {
+		if parent.Err() != nil {
+			// the caller is no longer waiting for the response, which is not a failure of the connector
+			return errors.Wrap(parent.Err(), msg)
+		}
 		if err != nil {
-			return nil, errors.Wrap(err, printErr())
+			return connectors.NewUnavailableError(errors.Wrap(err, msg))
 		}
-		return nil, errors.New(printErr())
+		return connectors.NewUnavailableError(errors.New(msg))
 	}
 	defer httpResponse.Body.Close()
 	if err != nil {
-		return nil, getDetailedError(httpResponse, err, printErr())
+		return getDetailedError(httpResponse, err, msg)
+	}
+	if m.signature != nil {
+		// the body has been read by the client, and is kept in the response to be read again
+		body, errRead := io.ReadAll(httpResponse.Body)
+		if errRead != nil {
+			return connectors.NewUnavailableError(errors.Wrap(errRead, msg))
+		}
+		if errVerify := m.signature.Verify(body, httpResponse.Header.Get(SignatureHeader)); errVerify != nil {
+			return connectors.NewSignatureError(errors.Wrap(errVerify, msg))
+		}
+	}
+	return nil
+}
+
+// WithCorrelationID returns a copy of the policy manager that sends the correlation id header with its requests
+func (m *openAPIPolicyManager) WithCorrelationID(correlationID string) PolicyManager {
+	configuration := *m.client.GetConfig()
+	configuration.DefaultHeader = connectors.WithCorrelationIDHeader(configuration.DefaultHeader, correlationID)
+	return &openAPIPolicyManager{
+		name:        m.name,
+		client:      openapiclient.NewAPIClient(&configuration),
+		credentials: m.credentials,
+		signature:   m.signature,
+		timeout:     m.timeout,
 	}
-	return &resp, nil
 }
 
 func (m *openAPIPolicyManager) Close() error {
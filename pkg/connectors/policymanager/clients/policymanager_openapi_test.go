@@ -0,0 +1,450 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/metrics"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/test"
+)
+
+// newFlakyServer returns a policy manager server that fails with the given status code
+// for the first numFailures requests and responds with an allow decision afterwards
+func newFlakyServer(numFailures int32, statusCode int, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= numFailures {
+			w.WriteHeader(statusCode)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"decision_id":"1","result":[]}`))
+	}))
+}
+
+// newSlowServer returns a policy manager server that responds with an allow decision after the given delay,
+// unless the request is canceled
+func newSlowServer(delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the cancellation of the request is detected once its body is read
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"decision_id":"1","result":[]}`))
+	}))
+}
+
+// rotatingCredentials returns a new credential on every call
+type rotatingCredentials struct {
+	calls int
+}
+
+func (p *rotatingCredentials) Fetch() (string, error) {
+	p.calls++
+	return "token-" + strconv.Itoa(p.calls), nil
+}
+
+// failingCredentials fails to provide a credential
+type failingCredentials struct{}
+
+func (p *failingCredentials) Fetch() (string, error) {
+	return "", errors.New("secret not mounted")
+}
+
+// newRecordingServer returns a policy manager server that records the given header of the requests
+func newRecordingServer(header string, values *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*values = append(*values, r.Header.Get(header))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"decision_id":"1","result":[]}`))
+	}))
+}
+
+// newProxyServer returns a forward proxy that records the URLs of the requests
+// and responds on behalf of the policy manager
+func newProxyServer(urls *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*urls = append(*urls, r.URL.String())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"decision_id":"proxied","result":[]}`))
+	}))
+}
+
+// newSigningServer returns a policy manager server that signs its responses with the given key
+func newSigningServer(key ed25519.PrivateKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := []byte(`{"decision_id":"signed","result":[]}`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(clients.SignatureHeader, base64.StdEncoding.EncodeToString(ed25519.Sign(key, body)))
+		_, _ = w.Write(body)
+	}))
+}
+
+// newSignatureVerifier returns a verifier of the given public key
+func newSignatureVerifier(key ed25519.PublicKey) *clients.SignatureVerifier {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	verifier, err := clients.NewSignatureVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	Expect(err).ToNot(HaveOccurred())
+	return verifier
+}
+
+var _ = Describe("OpenAPI policy manager client", func() {
+	request := &policymanager.GetPolicyDecisionsRequest{
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow},
+		Resource: policymanager.Resource{ID: "s3/allow-dataset"},
+	}
+	retry := clients.RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxRetries: 3, Jitter: true}
+
+	It("retries on 5xx responses", func() {
+		var calls int32
+		server := newFlakyServer(2, http.StatusServiceUnavailable, &calls)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: retry})
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DecisionID).To(Equal("1"))
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(3)))
+	})
+
+	It("records the request latency", func() {
+		var calls int32
+		server := newFlakyServer(1, http.StatusBadRequest, &calls)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: retry})
+		Expect(err).ToNot(HaveOccurred())
+		successes := test.HistogramSampleCount(metrics.PolicyRequestDuration, metrics.OutcomeSuccess)
+		failures := test.HistogramSampleCount(metrics.PolicyRequestDuration, metrics.OutcomeError)
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(HaveOccurred())
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(test.HistogramSampleCount(metrics.PolicyRequestDuration, metrics.OutcomeError)).To(BeNumerically(">", failures))
+		Expect(test.HistogramSampleCount(metrics.PolicyRequestDuration, metrics.OutcomeSuccess)).To(BeNumerically(">", successes))
+	})
+
+	It("fails after exhausting the retries", func() {
+		var calls int32
+		server := newFlakyServer(10, http.StatusServiceUnavailable, &calls)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: retry})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(HaveOccurred())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(retry.MaxRetries + 1)))
+	})
+
+	It("does not retry on 4xx responses", func() {
+		var calls int32
+		server := newFlakyServer(1, http.StatusBadRequest, &calls)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: retry})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(HaveOccurred())
+		Expect(atomic.LoadInt32(&calls)).To(Equal(int32(1)))
+	})
+})
+
+var _ = Describe("Errors of the OpenAPI policy manager client", func() {
+	request := &policymanager.GetPolicyDecisionsRequest{
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow},
+		Resource: policymanager.Resource{ID: "s3/allow-dataset"},
+	}
+	noRetry := clients.RetryConfig{MaxRetries: 0}
+
+	DescribeTable("map the status code to a typed error",
+		func(statusCode int, expected interface{}, retryable bool) {
+			var calls int32
+			server := newFlakyServer(1, statusCode, &calls)
+			defer server.Close()
+			policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+				&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(expected))
+			Expect(connectors.IsRetryable(err)).To(Equal(retryable))
+		},
+		Entry("bad credentials", http.StatusUnauthorized, &connectors.AuthError{}, false),
+		Entry("forbidden", http.StatusForbidden, &connectors.AuthError{}, false),
+		Entry("dataset not found", http.StatusNotFound, &connectors.AssetNotFoundError{}, false),
+		Entry("bad request", http.StatusBadRequest, &connectors.InvalidRequestError{}, false),
+		Entry("too many requests", http.StatusTooManyRequests, &connectors.ConnectorUnavailableError{}, true),
+		Entry("server failure", http.StatusServiceUnavailable, &connectors.ConnectorUnavailableError{}, true),
+	)
+
+	It("reports an unreachable connector", func() {
+		server := newFlakyServer(0, http.StatusOK, new(int32))
+		server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.ConnectorUnavailableError{}))
+		Expect(connectors.IsRetryable(err)).To(BeTrue())
+	})
+
+	It("aborts a request once the timeout elapses", func() {
+		server := newSlowServer(10 * time.Second)
+		defer server.Close()
+		timeout := 200 * time.Millisecond
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry, Timeout: timeout})
+		Expect(err).ToNot(HaveOccurred())
+
+		start := time.Now()
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.ConnectorUnavailableError{}))
+		Expect(connectors.IsRetryable(err)).To(BeTrue())
+		Expect(time.Since(start)).To(And(BeNumerically(">=", timeout), BeNumerically("<", 2*time.Second)))
+	})
+
+	It("aborts a request once the context of the caller is canceled", func() {
+		server := newSlowServer(10 * time.Second)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(200*time.Millisecond, cancel)
+		start := time.Now()
+		_, err = policyManager.GetPoliciesDecisions(ctx, request, "")
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(err).ToNot(BeAssignableToTypeOf(&connectors.ConnectorUnavailableError{}))
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+	})
+
+	It("aborts a request once the deadline of the caller elapses", func() {
+		server := newSlowServer(10 * time.Second)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry, Timeout: time.Minute})
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err = policyManager.GetPoliciesDecisions(ctx, request, "")
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+
+	It("bounds the retries by the timeout", func() {
+		var calls int32
+		server := newFlakyServer(100, http.StatusServiceUnavailable, &calls)
+		defer server.Close()
+		timeout := 200 * time.Millisecond
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{Name: "opa", URL: server.URL,
+			Retry:   clients.RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: 100 * time.Millisecond, MaxRetries: 20},
+			Timeout: timeout})
+		Expect(err).ToNot(HaveOccurred())
+
+		start := time.Now()
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically("<", 2*time.Second))
+		Expect(atomic.LoadInt32(&calls)).To(BeNumerically("<", 20))
+	})
+
+	It("fetches the credential on every request", func() {
+		authorizations := []string{}
+		server := newRecordingServer("Authorization", &authorizations)
+		defer server.Close()
+		credentials := &rotatingCredentials{}
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry, Credentials: credentials})
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 3; i++ {
+			_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(authorizations).To(Equal([]string{"Bearer token-1", "Bearer token-2", "Bearer token-3"}))
+	})
+
+	It("does not send a request without a credential", func() {
+		authorizations := []string{}
+		server := newRecordingServer("Authorization", &authorizations)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry, Credentials: &failingCredentials{}})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(MatchError(ContainSubstring("secret not mounted")))
+		Expect(authorizations).To(BeEmpty())
+	})
+
+	It("sends the correlation id header", func() {
+		correlationIDs := []string{}
+		server := newRecordingServer(connectors.CorrelationIDHeader, &correlationIDs)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: server.URL, Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = clients.WithCorrelationID(policyManager, "1234").GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		// the correlation id is not sent by the original policy manager
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(correlationIDs).To(Equal([]string{"1234", ""}))
+	})
+
+	It("sends the requests through the configured proxy", func() {
+		urls := []string{}
+		proxy := newProxyServer(&urls)
+		defer proxy.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: "http://policy-manager.example", Retry: noRetry, ProxyURL: proxy.URL})
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DecisionID).To(Equal("proxied"))
+		Expect(urls).To(Equal([]string{"http://policy-manager.example/getPoliciesDecisions"}))
+	})
+
+	It("rejects an invalid proxy URL", func() {
+		for _, proxyURL := range []string{"proxy.example:3128", "http://", "http://[::1"} {
+			_, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+				Name: "opa", URL: "http://policy-manager.example", Retry: noRetry, ProxyURL: proxyURL})
+			Expect(err).To(MatchError(ContainSubstring("invalid proxy of opa")), proxyURL)
+		}
+	})
+
+	It("reads a rotated credential from a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "token")
+		credentials := &clients.FileCredentialProvider{Path: path}
+		_, err := credentials.Fetch()
+		Expect(err).To(HaveOccurred())
+
+		Expect(os.WriteFile(path, []byte("first\n"), 0o600)).To(Succeed())
+		Expect(credentials.Fetch()).To(Equal("first"))
+		Expect(os.WriteFile(path, []byte("second"), 0o600)).To(Succeed())
+		Expect(credentials.Fetch()).To(Equal("second"))
+		Expect(os.WriteFile(path, []byte(" "), 0o600)).To(Succeed())
+		_, err = credentials.Fetch()
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Signature verification of the OpenAPI policy manager client", func() {
+	request := &policymanager.GetPolicyDecisionsRequest{
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow},
+		Resource: policymanager.Resource{ID: "s3/allow-dataset"},
+	}
+	noRetry := clients.RetryConfig{MaxRetries: 0}
+	publicKey, privateKey, _ := ed25519.GenerateKey(rand.Reader)
+	otherPublicKey, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	It("accepts a response with a valid signature", func() {
+		server := newSigningServer(privateKey)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: server.URL, Retry: noRetry, Signature: newSignatureVerifier(publicKey)})
+		Expect(err).ToNot(HaveOccurred())
+
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DecisionID).To(Equal("signed"))
+	})
+
+	It("rejects a response with an invalid signature as a terminal error", func() {
+		server := newSigningServer(privateKey)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: server.URL, Retry: noRetry, Signature: newSignatureVerifier(otherPublicKey)})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.SignatureError{}))
+		Expect(err).To(MatchError(ContainSubstring("signature of the policy manager response is invalid")))
+		Expect(connectors.IsRetryable(err)).To(BeFalse())
+	})
+
+	It("rejects an unsigned response", func() {
+		server := newFlakyServer(0, http.StatusOK, new(int32))
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: server.URL, Retry: noRetry, Signature: newSignatureVerifier(publicKey)})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.SignatureError{}))
+		Expect(err).To(MatchError(ContainSubstring("not signed")))
+	})
+
+	It("does not verify the responses if the verification is disabled", func() {
+		server := newSigningServer(privateKey)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: server.URL, Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.DecisionID).To(Equal("signed"))
+
+		unsigned := newFlakyServer(0, http.StatusOK, new(int32))
+		defer unsigned.Close()
+		policyManager, err = clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: unsigned.URL, Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("verifies the responses of a policy manager tagged with a correlation id", func() {
+		server := newSigningServer(privateKey)
+		defer server.Close()
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{
+			Name: "opa", URL: server.URL, Retry: noRetry, Signature: newSignatureVerifier(otherPublicKey)})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = clients.WithCorrelationID(policyManager, "1234").GetPoliciesDecisions(context.Background(), request, "")
+		Expect(err).To(BeAssignableToTypeOf(&connectors.SignatureError{}))
+	})
+
+	It("rejects a public key that is not PEM encoded", func() {
+		_, err := clients.NewSignatureVerifier([]byte("not a key"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,57 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// rateLimitedPolicyManager sends the requests of a policy manager through a limiter
+type rateLimitedPolicyManager struct {
+	policyManager PolicyManager
+	limiter       *connectors.Limiter
+}
+
+// NewRateLimitedPolicyManager returns a policy manager that waits for the limiter before every request.
+// Closing it closes the given policy manager.
+func NewRateLimitedPolicyManager(policyManager PolicyManager, limiter *connectors.Limiter) PolicyManager {
+	return &rateLimitedPolicyManager{policyManager: policyManager, limiter: limiter}
+}
+
+func (m *rateLimitedPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	release, err := m.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return m.policyManager.GetPoliciesDecisions(ctx, in, creds)
+}
+
+// GetPoliciesDecisionsBatch waits for the limiter once, since the batch is sent in a single request
+func (m *rateLimitedPolicyManager) GetPoliciesDecisionsBatch(ctx context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	if _, ok := m.policyManager.(BatchPolicyManager); !ok {
+		return nil, ErrBatchNotSupported
+	}
+	release, err := m.limiter.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return getPoliciesDecisionsBatch(ctx, m.policyManager, in, creds)
+}
+
+// WithCorrelationID returns a rate limited policy manager that shares the limiter, and tags its requests with the correlation id
+func (m *rateLimitedPolicyManager) WithCorrelationID(correlationID string) PolicyManager {
+	return &rateLimitedPolicyManager{policyManager: WithCorrelationID(m.policyManager, correlationID), limiter: m.limiter}
+}
+
+func (m *rateLimitedPolicyManager) Close() error {
+	return m.policyManager.Close()
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// Default values of the retry configuration
+const (
+	defaultMaxRetries  = 4
+	defaultBaseDelayMs = 500
+	defaultMaxDelayMs  = 10000
+)
+
+// RetryConfig defines the exponential backoff applied to transient failures of the policy manager,
+// i.e., connection errors and 5xx responses. Client errors (4xx) are never retried.
+type RetryConfig struct {
+	// BaseDelay is the delay before the first retry, it is doubled on every subsequent retry
+	BaseDelay time.Duration
+	// MaxDelay bounds the delay between two retries
+	MaxDelay time.Duration
+	// MaxRetries is the maximal number of retries, 0 disables retrying
+	MaxRetries int
+	// Jitter randomizes the delays to avoid synchronized retries of multiple clients
+	Jitter bool
+}
+
+// ConnectorConfig contains the configuration of a policy manager connector client
+type ConnectorConfig struct {
+	// Name of the policy manager
+	Name string
+	// URL of the policy manager connector
+	URL string
+	// Retry policy for transient failures
+	Retry RetryConfig
+	// Credentials provides the credential sent to the connector as a bearer token, nil if no credential is required
+	Credentials CredentialProvider
+	// ProxyURL is the proxy through which the connector is called, overriding the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables. The environment variables are used if not specified.
+	ProxyURL string
+	// Signature verifies the signature of the connector responses, nil if the responses are not signed.
+	// Only the responses of OpenAPI connectors can be verified.
+	Signature *SignatureVerifier
+	// Timeout bounds every request sent to the connector including its retries, 0 for no timeout
+	Timeout time.Duration
+}
+
+// RetryConfigFromEnvironment returns the retry configuration defined by the environment variables,
+// default values are used for undefined variables
+func RetryConfigFromEnvironment() RetryConfig {
+	return RetryConfig{
+		BaseDelay:  time.Duration(environment.GetEnvAsInt(environment.PolicyManagerRetryBaseDelayKey, defaultBaseDelayMs)) * time.Millisecond,
+		MaxDelay:   time.Duration(environment.GetEnvAsInt(environment.PolicyManagerRetryMaxDelayKey, defaultMaxDelayMs)) * time.Millisecond,
+		MaxRetries: environment.GetEnvAsInt(environment.PolicyManagerMaxRetriesKey, defaultMaxRetries),
+		Jitter:     environment.GetEnvAsBool(environment.PolicyManagerRetryJitterKey, true),
+	}
+}
+
+// apply configures the retryable client according to the retry configuration
+func (c *RetryConfig) apply(client *retryablehttp.Client) {
+	client.RetryMax = c.MaxRetries
+	client.RetryWaitMin = c.BaseDelay
+	client.RetryWaitMax = c.MaxDelay
+	client.CheckRetry = checkRetry
+	client.Backoff = c.backoff
+	// return the last response rather than a generic error once the retries are exhausted
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+}
+
+// checkRetry retries on connection errors and 5xx responses only
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil || ctx.Err() != nil {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError, nil
+}
+
+// backoff returns an exponential delay bounded by MaxDelay. If jitter is enabled
+// the delay is chosen randomly between half of the exponential delay and the full one.
+func (c *RetryConfig) backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	sleep := retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+	if !c.Jitter || sleep <= 1 {
+		return sleep
+	}
+	half := sleep / 2 //nolint:revive,gomnd
+	//nolint:gosec // no need for a cryptographically secure random number
+	return half + time.Duration(rand.Int63n(int64(sleep-half)))
+}
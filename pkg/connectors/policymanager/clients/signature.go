@@ -0,0 +1,87 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// SignatureHeader is the header of a policy manager response holding the base64 encoded signature of the response body
+const SignatureHeader = "X-Policy-Signature"
+
+// SignatureVerifier verifies that the policy manager responses are signed by the private key of the policy manager,
+// so that tampered responses are rejected before their actions are trusted.
+// The body is signed with Ed25519, or its SHA-256 digest is signed with ECDSA (ASN.1) or RSA (PKCS #1 v1.5).
+type SignatureVerifier struct {
+	key crypto.PublicKey
+}
+
+// NewSignatureVerifier returns a verifier of the signatures made by the private key of the given PEM encoded public key
+func NewSignatureVerifier(pemKey []byte) (*SignatureVerifier, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("the policy manager public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the policy manager public key")
+	}
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey, *rsa.PublicKey:
+		return &SignatureVerifier{key: key}, nil
+	default:
+		return nil, errors.Errorf("unsupported type %T of the policy manager public key", key)
+	}
+}
+
+// SignatureVerifierFromEnvironment returns a verifier of the public key in the file defined by the environment,
+// or nil if the responses of the policy manager are not signed
+func SignatureVerifierFromEnvironment() (*SignatureVerifier, error) {
+	path := os.Getenv(environment.PolicyManagerPublicKeyPathKey)
+	if path == "" {
+		return nil, nil
+	}
+	pemKey, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the policy manager public key")
+	}
+	return NewSignatureVerifier(pemKey)
+}
+
+// Verify checks the base64 encoded signature of the response body
+func (v *SignatureVerifier) Verify(body []byte, signature string) error {
+	if signature == "" {
+		return errors.New("the policy manager response is not signed")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(err, "the signature of the policy manager response is not base64 encoded")
+	}
+	digest := sha256.Sum256(body)
+	valid := false
+	switch key := v.key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, body, decoded)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], decoded)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], decoded) == nil
+	}
+	if !valid {
+		return errors.New("the signature of the policy manager response is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"golang.org/x/sync/singleflight"
+
+	"fybrik.io/fybrik/pkg/connectors"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// singleFlightPolicyManager shares a single in-flight call between identical concurrent requests of a policy manager
+type singleFlightPolicyManager struct {
+	policyManager PolicyManager
+	group         *singleflight.Group
+	timeout       time.Duration
+}
+
+// NewSingleFlightPolicyManager returns a policy manager that sends identical concurrent requests only once,
+// e.g., when many applications reference the same dataset at the same time.
+// Requests are identical if they have the same content and credentials, regardless of their time.
+// The callers that join an in-flight call share its response or its error. The shared call is bounded by the given
+// timeout (0 for no timeout) rather than by the context of any single caller, so that a canceled caller does not fail
+// the others, while each caller stops waiting once its own context is done. Closing it closes the given policy manager.
+func NewSingleFlightPolicyManager(policyManager PolicyManager, timeout time.Duration) PolicyManager {
+	return &singleFlightPolicyManager{policyManager: policyManager, group: &singleflight.Group{}, timeout: timeout}
+}
+
+func (m *singleFlightPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	key, err := singleFlightKey(in, creds)
+	if err != nil {
+		return nil, err
+	}
+	results := m.group.DoChan(key, func() (interface{}, error) {
+		sharedCtx, cancel := connectors.NewRequestContext(context.Background(), m.timeout)
+		defer cancel()
+		return m.policyManager.GetPoliciesDecisions(sharedCtx, in, creds)
+	})
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*policymanager.GetPolicyDecisionsResponse), nil
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "get policies decisions failed")
+	}
+}
+
+// GetPoliciesDecisionsBatch sends the batch as is, since the batches of distinct applications rarely match
+func (m *singleFlightPolicyManager) GetPoliciesDecisionsBatch(ctx context.Context, in []*policymanager.GetPolicyDecisionsRequest,
+	creds string) (map[taxonomy.AssetID]*policymanager.GetPolicyDecisionsResponse, error) {
+	return getPoliciesDecisionsBatch(ctx, m.policyManager, in, creds)
+}
+
+// singleFlightKey identifies the content and the credentials of a request, ignoring its time
+func singleFlightKey(in *policymanager.GetPolicyDecisionsRequest, creds string) (string, error) {
+	timeless := *in
+	timeless.Time = ""
+	bytes, err := json.Marshal(&timeless)
+	if err != nil {
+		return "", errors.Wrap(err, "could not serialize the policy manager request")
+	}
+	hash := sha256.New()
+	hash.Write(bytes)
+	hash.Write([]byte{0})
+	hash.Write([]byte(creds))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WithCorrelationID returns a policy manager that shares the in-flight calls, and tags its requests with the correlation id.
+// A request that joins an in-flight call is sent with the correlation id of the call.
+func (m *singleFlightPolicyManager) WithCorrelationID(correlationID string) PolicyManager {
+	return &singleFlightPolicyManager{policyManager: WithCorrelationID(m.policyManager, correlationID), group: m.group,
+		timeout: m.timeout}
+}
+
+func (m *singleFlightPolicyManager) Close() error {
+	return m.policyManager.Close()
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// blockingPolicyManager counts its invocations, and responds once it is released unless its context is done before
+type blockingPolicyManager struct {
+	response *policymanager.GetPolicyDecisionsResponse
+	release  chan struct{}
+	calls    int32
+}
+
+func (m *blockingPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	atomic.AddInt32(&m.calls, 1)
+	select {
+	case <-m.release:
+		return m.response, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *blockingPolicyManager) Close() error {
+	return nil
+}
+
+func newDatasetRequest(datasetID, requestTime string) *policymanager.GetPolicyDecisionsRequest {
+	return &policymanager.GetPolicyDecisionsRequest{
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow},
+		Resource: policymanager.Resource{ID: taxonomy.AssetID(datasetID)},
+		Time:     requestTime,
+	}
+}
+
+var _ = Describe("Deduplication of policy manager requests", func() {
+	const requests = 10
+
+	// getConcurrently sends the requests at the same time, and releases the policy manager once they are all in flight
+	getConcurrently := func(policyManager clients.PolicyManager, underlying *blockingPolicyManager,
+		reqs []*policymanager.GetPolicyDecisionsRequest) []*policymanager.GetPolicyDecisionsResponse {
+		responses := make([]*policymanager.GetPolicyDecisionsResponse, len(reqs))
+		var done sync.WaitGroup
+		for i := range reqs {
+			done.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer done.Done()
+				resp, err := policyManager.GetPoliciesDecisions(context.Background(), reqs[i], "creds")
+				Expect(err).NotTo(HaveOccurred())
+				responses[i] = resp
+			}(i)
+		}
+		Eventually(func() int32 { return atomic.LoadInt32(&underlying.calls) }).Should(BeNumerically(">", 0))
+		// let the other requests join the in-flight call
+		time.Sleep(100 * time.Millisecond)
+		close(underlying.release)
+		done.Wait()
+		return responses
+	}
+
+	It("shares a single call between identical concurrent requests", func() {
+		underlying := &blockingPolicyManager{response: newResponse("1"), release: make(chan struct{})}
+		policyManager := clients.NewSingleFlightPolicyManager(underlying, 0)
+		reqs := []*policymanager.GetPolicyDecisionsRequest{}
+		for i := 0; i < requests; i++ {
+			// the time of the requests is ignored
+			reqs = append(reqs, newDatasetRequest("s3/popular-dataset", time.Unix(int64(i), 0).UTC().Format(time.RFC3339)))
+		}
+		responses := getConcurrently(policyManager, underlying, reqs)
+		Expect(atomic.LoadInt32(&underlying.calls)).To(Equal(int32(1)))
+		for _, resp := range responses {
+			Expect(resp.DecisionID).To(Equal("1"))
+		}
+	})
+
+	It("sends distinct requests separately", func() {
+		underlying := &blockingPolicyManager{response: newResponse("1"), release: make(chan struct{})}
+		policyManager := clients.NewSingleFlightPolicyManager(underlying, 0)
+		reqs := []*policymanager.GetPolicyDecisionsRequest{
+			newDatasetRequest("s3/first-dataset", ""),
+			newDatasetRequest("s3/second-dataset", ""),
+		}
+		getConcurrently(policyManager, underlying, reqs)
+		Expect(atomic.LoadInt32(&underlying.calls)).To(Equal(int32(2)))
+	})
+
+	It("completes the shared call for the joined requests when the first caller is canceled", func() {
+		underlying := &blockingPolicyManager{response: newResponse("1"), release: make(chan struct{})}
+		policyManager := clients.NewSingleFlightPolicyManager(underlying, time.Minute)
+		firstCtx, cancelFirst := context.WithCancel(context.Background())
+		firstErr := make(chan error, 1)
+		go func() {
+			_, err := policyManager.GetPoliciesDecisions(firstCtx, newDatasetRequest("s3/popular-dataset", ""), "creds")
+			firstErr <- err
+		}()
+		Eventually(func() int32 { return atomic.LoadInt32(&underlying.calls) }).Should(Equal(int32(1)))
+		joined := make(chan *policymanager.GetPolicyDecisionsResponse, 1)
+		go func() {
+			defer GinkgoRecover()
+			resp, err := policyManager.GetPoliciesDecisions(context.Background(), newDatasetRequest("s3/popular-dataset", ""), "creds")
+			Expect(err).NotTo(HaveOccurred())
+			joined <- resp
+		}()
+		// let the second request join the in-flight call
+		time.Sleep(100 * time.Millisecond)
+		cancelFirst()
+		Eventually(firstErr).Should(Receive(MatchError(context.Canceled)))
+		close(underlying.release)
+		var resp *policymanager.GetPolicyDecisionsResponse
+		Eventually(joined).Should(Receive(&resp))
+		Expect(resp.DecisionID).To(Equal("1"))
+		Expect(atomic.LoadInt32(&underlying.calls)).To(Equal(int32(1)))
+	})
+
+	It("stops waiting for the shared call once the context of the caller is done", func() {
+		underlying := &blockingPolicyManager{response: newResponse("1"), release: make(chan struct{})}
+		defer close(underlying.release)
+		policyManager := clients.NewSingleFlightPolicyManager(underlying, 0)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := policyManager.GetPoliciesDecisions(ctx, newDatasetRequest("s3/popular-dataset", ""), "creds")
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+})
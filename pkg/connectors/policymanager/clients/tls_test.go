@@ -0,0 +1,174 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// testCA is a private certificate authority issuing the certificates of the TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA() *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fybrik test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).ToNot(HaveOccurred())
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a certificate signed by the CA and its private key, both PEM encoded
+func (ca *testCA) issue(serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:     []string{"localhost"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).ToNot(HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+// newTLSServer returns a policy manager server with a certificate issued by the given CA.
+// Client certificates issued by the CA are required if clientCA is set.
+func newTLSServer(ca *testCA, clientCA *testCA) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"decision_id":"tls","result":[]}`))
+	}))
+	certPEM, keyPEM := ca.issue(2, x509.ExtKeyUsageServerAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	Expect(err).ToNot(HaveOccurred())
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		server.TLS.ClientCAs = pool
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	server.StartTLS()
+	return server
+}
+
+// setenv sets an environment variable for the duration of the spec
+func setenv(key, value string) {
+	Expect(os.Setenv(key, value)).To(Succeed())
+	DeferCleanup(os.Unsetenv, key)
+}
+
+func writeFile(dir, name string, content []byte) string {
+	path := filepath.Join(dir, name)
+	Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+	return path
+}
+
+var _ = Describe("TLS of the OpenAPI policy manager client", func() {
+	request := &policymanager.GetPolicyDecisionsRequest{
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow},
+		Resource: policymanager.Resource{ID: "s3/allow-dataset"},
+	}
+	noRetry := clients.RetryConfig{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	getDecisions := func(url string) error {
+		policyManager, err := clients.NewOpenAPIPolicyManagerWithConfig(
+			&clients.ConnectorConfig{Name: "opa", URL: url, Retry: noRetry})
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := policyManager.GetPoliciesDecisions(context.Background(), request, "")
+		if err == nil {
+			Expect(resp.DecisionID).To(Equal("tls"))
+		}
+		return err
+	}
+
+	It("trusts a private CA only when its bundle is configured", func() {
+		ca := newTestCA()
+		server := newTLSServer(ca, nil)
+		defer server.Close()
+		Expect(getDecisions(server.URL)).To(HaveOccurred())
+
+		setenv(environment.ConnectorCABundlePathKey, writeFile(GinkgoT().TempDir(), "ca.crt", ca.pem))
+		Expect(getDecisions(server.URL)).To(Succeed())
+	})
+
+	It("does not trust a server whose CA is not in the bundle", func() {
+		server := newTLSServer(newTestCA(), nil)
+		defer server.Close()
+		setenv(environment.ConnectorCABundlePathKey, writeFile(GinkgoT().TempDir(), "ca.crt", newTestCA().pem))
+		Expect(getDecisions(server.URL)).To(HaveOccurred())
+	})
+
+	It("fails to create a client with an invalid CA bundle", func() {
+		setenv(environment.ConnectorCABundlePathKey, writeFile(GinkgoT().TempDir(), "ca.crt", []byte("not a certificate")))
+		_, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{Name: "opa", URL: "https://localhost"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("presents the configured client certificate for mutual TLS", func() {
+		ca := newTestCA()
+		server := newTLSServer(ca, ca)
+		defer server.Close()
+		dir := GinkgoT().TempDir()
+		setenv(environment.ConnectorCABundlePathKey, writeFile(dir, "ca.crt", ca.pem))
+		Expect(getDecisions(server.URL)).To(HaveOccurred())
+
+		certPEM, keyPEM := ca.issue(3, x509.ExtKeyUsageClientAuth)
+		setenv(environment.ConnectorClientCertPathKey, writeFile(dir, "tls.crt", certPEM))
+		setenv(environment.ConnectorClientKeyPathKey, writeFile(dir, "tls.key", keyPEM))
+		Expect(getDecisions(server.URL)).To(Succeed())
+	})
+
+	It("fails to create a client with a client certificate but no private key", func() {
+		certPEM, _ := newTestCA().issue(3, x509.ExtKeyUsageClientAuth)
+		setenv(environment.ConnectorClientCertPathKey, writeFile(GinkgoT().TempDir(), "tls.crt", certPEM))
+		_, err := clients.NewOpenAPIPolicyManagerWithConfig(&clients.ConnectorConfig{Name: "opa", URL: "https://localhost"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -145,3 +145,123 @@ func (a *DefaultApiService) GetPoliciesDecisionsExecute(r ApiGetPoliciesDecision
 
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
+
+type ApiGetPoliciesDecisionsBatchRequest struct {
+	ctx                       _context.Context
+	ApiService                *DefaultApiService
+	xRequestCred              *string
+	getPolicyDecisionsRequest *[]GetPolicyDecisionsRequest
+}
+
+func (r ApiGetPoliciesDecisionsBatchRequest) XRequestCred(xRequestCred string) ApiGetPoliciesDecisionsBatchRequest {
+	r.xRequestCred = &xRequestCred
+	return r
+}
+
+// Policy Manager Request Objects, a request per data set.
+func (r ApiGetPoliciesDecisionsBatchRequest) GetPolicyDecisionsRequest(getPolicyDecisionsRequest []GetPolicyDecisionsRequest) ApiGetPoliciesDecisionsBatchRequest {
+	r.getPolicyDecisionsRequest = &getPolicyDecisionsRequest
+	return r
+}
+
+func (r ApiGetPoliciesDecisionsBatchRequest) Execute() ([]GetPolicyDecisionsResponse, *_nethttp.Response, error) {
+	return r.ApiService.GetPoliciesDecisionsBatchExecute(r)
+}
+
+/*
+GetPoliciesDecisionsBatch This REST API gets data governance decisions for several data sets at once, the decisions are returned in the order of the requests
+
+	@param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	@return ApiGetPoliciesDecisionsBatchRequest
+*/
+func (a *DefaultApiService) GetPoliciesDecisionsBatch(ctx _context.Context) ApiGetPoliciesDecisionsBatchRequest {
+	return ApiGetPoliciesDecisionsBatchRequest{
+		ApiService: a,
+		ctx:        ctx,
+	}
+}
+
+// Execute executes the request
+//
+//	@return []GetPolicyDecisionsResponse
+func (a *DefaultApiService) GetPoliciesDecisionsBatchExecute(r ApiGetPoliciesDecisionsBatchRequest) ([]GetPolicyDecisionsResponse, *_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod  = _nethttp.MethodPost
+		localVarPostBody    interface{}
+		formFiles           []formFile
+		localVarReturnValue []GetPolicyDecisionsResponse
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "DefaultApiService.GetPoliciesDecisionsBatch")
+	if err != nil {
+		return localVarReturnValue, nil, GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/getPoliciesDecisionsBatch"
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+	if r.xRequestCred == nil {
+		return localVarReturnValue, nil, reportError("xRequestCred is required and must be specified")
+	}
+	if r.getPolicyDecisionsRequest == nil {
+		return localVarReturnValue, nil, reportError("getPolicyDecisionsRequest is required and must be specified")
+	}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/json"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{"application/json"}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	localVarHeaderParams["X-Request-Cred"] = parameterToString(*r.xRequestCred, "")
+	// body params
+	localVarPostBody = r.getPolicyDecisionsRequest
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, formFiles)
+	if err != nil {
+		return localVarReturnValue, nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = _ioutil.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarReturnValue, localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	err = a.client.decode(&localVarReturnValue, localVarBody, localVarHTTPResponse.Header.Get("Content-Type"))
+	if err != nil {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: err.Error(),
+		}
+		return localVarReturnValue, localVarHTTPResponse, newErr
+	}
+
+	return localVarReturnValue, localVarHTTPResponse, nil
+}
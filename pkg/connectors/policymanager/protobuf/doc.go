@@ -0,0 +1,7 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protobuf holds the gRPC interface of the policy manager connectors, generated from policymanager.proto
+package protobuf
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative policymanager.proto
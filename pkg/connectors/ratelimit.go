@@ -0,0 +1,88 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"golang.org/x/time/rate"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// Default values of the rate limit configuration
+const (
+	defaultRateLimitQPS       = 10.0
+	defaultRateLimitBurst     = 20
+	defaultMaxConcurrentCalls = 10
+)
+
+// RateLimitConfig bounds the load that the manager puts on a connector
+type RateLimitConfig struct {
+	// QPS is the sustained number of requests per second, 0 disables the rate limit
+	QPS float64
+	// Burst is the number of requests that may be sent at once before the sustained rate applies
+	Burst int
+	// MaxConcurrentCalls is the maximal number of requests waiting for a response, 0 for no limit
+	MaxConcurrentCalls int
+}
+
+// RateLimitConfigFromEnvironment returns the rate limit configuration defined by the environment variables,
+// default values are used for undefined variables
+func RateLimitConfigFromEnvironment() RateLimitConfig {
+	return RateLimitConfig{
+		QPS:                float64(environment.GetEnvAsFloat32(environment.ConnectorRateLimitQPSKey, defaultRateLimitQPS)),
+		Burst:              environment.GetEnvAsInt(environment.ConnectorRateLimitBurstKey, defaultRateLimitBurst),
+		MaxConcurrentCalls: environment.GetEnvAsInt(environment.ConnectorMaxConcurrentCallsKey, defaultMaxConcurrentCalls),
+	}
+}
+
+// Limiter throttles the requests sent to a connector with a token bucket,
+// and bounds the number of requests that wait for a response.
+// A nil Limiter does not limit the requests.
+type Limiter struct {
+	tokens *rate.Limiter
+	calls  chan struct{}
+}
+
+// NewLimiter returns a limiter of the requests sent to a single connector
+func NewLimiter(config RateLimitConfig) *Limiter {
+	limiter := &Limiter{}
+	if config.QPS > 0 {
+		burst := config.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter.tokens = rate.NewLimiter(rate.Limit(config.QPS), burst)
+	}
+	if config.MaxConcurrentCalls > 0 {
+		limiter.calls = make(chan struct{}, config.MaxConcurrentCalls)
+	}
+	return limiter
+}
+
+// Acquire waits until a request may be sent to the connector.
+// The returned function has to be called once the response is received, to let other requests through.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	release := func() {}
+	if l.calls != nil {
+		select {
+		case l.calls <- struct{}{}:
+			release = func() { <-l.calls }
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "waiting for an outstanding connector request to complete")
+		}
+	}
+	if l.tokens != nil {
+		if err := l.tokens.Wait(ctx); err != nil {
+			release()
+			return nil, errors.Wrap(err, "waiting for the connector rate limit")
+		}
+	}
+	return release, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/tls"
+)
+
+// HealthPath is the lightweight endpoint used to check whether a connector is reachable
+const HealthPath = "/healthz"
+
+// GRPCScheme is the URL scheme of the connectors that are reached through gRPC, e.g., grpc://host:port
+const GRPCScheme = "grpc://"
+
+const (
+	defaultReadinessGracePeriodMs = 60000
+	defaultReadinessTimeout       = 5 * time.Second
+)
+
+// ReadinessChecker checks whether a connector is reachable.
+// Any response of the connector below 500 means that the connector is up, even if it does not implement the health endpoint.
+// The check fails only if the connector has been unreachable for longer than the grace period,
+// so that transient connector outages do not affect the readiness of the manager.
+type ReadinessChecker struct {
+	Name        string
+	URL         string
+	GracePeriod time.Duration
+	Client      *http.Client
+
+	mutex       sync.Mutex
+	lastSuccess time.Time
+	now         func() time.Time
+}
+
+// NewReadinessChecker returns a checker of the connector at the given URL.
+// The grace period is taken from the environment, and starts when the checker is created.
+// The connector is reached with the TLS and proxy settings of the connector clients.
+func NewReadinessChecker(name, url string) *ReadinessChecker {
+	gracePeriod := environment.GetEnvAsInt(environment.ConnectorReadinessGracePeriodKey, defaultReadinessGracePeriodMs)
+	return &ReadinessChecker{
+		Name:        name,
+		URL:         strings.TrimSuffix(url, "/"),
+		GracePeriod: time.Duration(gracePeriod) * time.Millisecond,
+		Client:      newReadinessClient(name),
+		lastSuccess: time.Now(),
+		now:         time.Now,
+	}
+}
+
+// newReadinessClient returns an http client with the TLS and proxy settings of the connector clients.
+// The client does not retry, since a failed check is repeated by the next probe.
+func newReadinessClient(name string) *http.Client {
+	log := logging.LogInit(logging.SETUP, "readiness checker")
+	retryClient := tls.GetHTTPClientWithProxy(&log, environment.GetConnectorProxyURL())
+	if retryClient == nil {
+		log.Error().Str(logging.CONNECTOR, name).Msg("failed to apply the connector TLS settings to the readiness checker")
+		return &http.Client{Timeout: defaultReadinessTimeout}
+	}
+	client := retryClient.HTTPClient
+	client.Timeout = defaultReadinessTimeout
+	return client
+}
+
+// Check implements the healthz.Checker signature of controller-runtime
+func (c *ReadinessChecker) Check(req *http.Request) error {
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+	err := c.ping(ctx)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	if err == nil {
+		c.lastSuccess = now
+		return nil
+	}
+	if now.Sub(c.lastSuccess) <= c.GracePeriod {
+		return nil
+	}
+	return errors.WrapIff(err, "connector %s has been unreachable since %s", c.Name, c.lastSuccess.Format(time.RFC3339))
+}
+
+// ping sends a request to the health endpoint of the connector.
+// gRPC connectors have no health endpoint, they are reachable if a connection can be opened.
+func (c *ReadinessChecker) ping(ctx context.Context) error {
+	if strings.HasPrefix(c.URL, GRPCScheme) {
+		dialer := net.Dialer{Timeout: c.Client.Timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", strings.TrimPrefix(c.URL, GRPCScheme))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+HealthPath, http.NoBody)
+	if err != nil {
+		return err
+	}
+	response, err := c.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusInternalServerError {
+		return errors.Errorf("health endpoint returned %s", response.Status)
+	}
+	return nil
+}
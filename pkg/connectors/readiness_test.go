@@ -0,0 +1,139 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// newStubConnector returns a connector that responds to the health endpoint with the given status code
+func newStubConnector(statusCode int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != HealthPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(statusCode)
+	}))
+}
+
+// newTestChecker returns a checker of the given URL with a clock controlled by the test
+func newTestChecker(url string, gracePeriod time.Duration, clock *time.Time) *ReadinessChecker {
+	checker := NewReadinessChecker("stub", url)
+	checker.GracePeriod = gracePeriod
+	checker.lastSuccess = *clock
+	checker.now = func() time.Time { return *clock }
+	return checker
+}
+
+func TestReadinessCheckerUp(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	for _, statusCode := range []int{http.StatusOK, http.StatusNotFound} {
+		server := newStubConnector(statusCode)
+		defer server.Close()
+		clock := time.Now()
+		checker := newTestChecker(server.URL, 0, &clock)
+		clock = clock.Add(time.Hour)
+		g.Expect(checker.Check(nil)).To(gomega.Succeed())
+	}
+}
+
+func TestReadinessCheckerDown(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	unavailable := newStubConnector(http.StatusServiceUnavailable)
+	defer unavailable.Close()
+	unreachable := newStubConnector(http.StatusOK)
+	unreachable.Close()
+
+	for _, url := range []string{unavailable.URL, unreachable.URL} {
+		clock := time.Now()
+		checker := newTestChecker(url, time.Minute, &clock)
+		// the connector is down for less than the grace period
+		clock = clock.Add(30 * time.Second)
+		g.Expect(checker.Check(nil)).To(gomega.Succeed())
+		// the connector is down for longer than the grace period
+		clock = clock.Add(time.Minute)
+		err := checker.Check(nil)
+		g.Expect(err).To(gomega.HaveOccurred())
+		g.Expect(err.Error()).To(gomega.ContainSubstring("connector stub has been unreachable"))
+	}
+}
+
+func TestReadinessCheckerRecovery(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	statusCode := int32(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&statusCode)))
+	}))
+	defer server.Close()
+
+	clock := time.Now()
+	checker := newTestChecker(server.URL, time.Minute, &clock)
+	clock = clock.Add(2 * time.Minute)
+	g.Expect(checker.Check(nil)).ToNot(gomega.Succeed())
+
+	// the grace period restarts once the connector is reachable again
+	atomic.StoreInt32(&statusCode, http.StatusOK)
+	g.Expect(checker.Check(nil)).To(gomega.Succeed())
+	atomic.StoreInt32(&statusCode, http.StatusServiceUnavailable)
+	clock = clock.Add(30 * time.Second)
+	g.Expect(checker.Check(nil)).To(gomega.Succeed())
+}
+
+func TestReadinessCheckerGRPC(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	url := GRPCScheme + listener.Addr().String()
+
+	clock := time.Now()
+	checker := newTestChecker(url, 0, &clock)
+	clock = clock.Add(time.Hour)
+	g.Expect(checker.Check(nil)).To(gomega.Succeed())
+
+	// the connector is unreachable once it stops listening
+	listener.Close()
+	clock = clock.Add(time.Hour)
+	g.Expect(checker.Check(nil)).ToNot(gomega.Succeed())
+}
+
+// TestReadinessCheckerTLS checks that the connector is reached with the TLS settings of the connector clients.
+// It does not run in parallel since it sets the CA bundle of the connectors in the environment.
+func TestReadinessCheckerTLS(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// the certificate of the server is not trusted without the CA bundle
+	clock := time.Now()
+	checker := newTestChecker(server.URL, 0, &clock)
+	clock = clock.Add(time.Hour)
+	g.Expect(checker.Check(nil)).ToNot(gomega.Succeed())
+
+	bundle := filepath.Join(t.TempDir(), "ca.crt")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	g.Expect(os.WriteFile(bundle, certPEM, 0o600)).To(gomega.Succeed())
+	t.Setenv(environment.ConnectorCABundlePathKey, bundle)
+	checker = newTestChecker(server.URL, 0, &clock)
+	clock = clock.Add(time.Hour)
+	g.Expect(checker.Check(nil)).To(gomega.Succeed())
+}
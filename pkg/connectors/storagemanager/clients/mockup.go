@@ -32,6 +32,10 @@ func (m *mockupStorageManager) DeleteStorage(request *storagemanager.DeleteStora
 	return nil
 }
 
+func (m *mockupStorageManager) CommitStorage(request *storagemanager.CommitStorageRequest) error {
+	return nil
+}
+
 func (m *mockupStorageManager) GetSupportedStorageTypes() (*storagemanager.GetSupportedStorageTypesResponse, error) {
 	return &storagemanager.GetSupportedStorageTypesResponse{ConnectionTypes: []taxonomy.ConnectionType{"mysql", "db2", "s3"}}, nil
 }
@@ -17,6 +17,8 @@ type StorageManagerInterface interface {
 	AllocateStorage(request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error)
 	// DeleteStorage deletes the allocated storage
 	DeleteStorage(request *storagemanager.DeleteStorageRequest) error
+	// CommitStorage moves the data written to a staging storage to the allocated storage, and deletes the staging storage
+	CommitStorage(request *storagemanager.CommitStorageRequest) error
 	// GetSupportedStorageTypes returns a list of supported connection types
 	GetSupportedStorageTypes() (*storagemanager.GetSupportedStorageTypesResponse, error)
 	io.Closer
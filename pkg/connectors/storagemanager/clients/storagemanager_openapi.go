@@ -11,6 +11,7 @@ import (
 	"github.com/rs/zerolog"
 
 	openapiclient "fybrik.io/fybrik/pkg/connectors/storagemanager/openapiclient"
+	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 	"fybrik.io/fybrik/pkg/tls"
@@ -43,7 +44,7 @@ func NewOpenAPIStorageManager(address string) StorageManagerInterface {
 			},
 		},
 		OperationServers: map[string]openapiclient.ServerConfigurations{},
-		HTTPClient:       tls.GetHTTPClient(&log).StandardClient(),
+		HTTPClient:       tls.GetHTTPClientWithProxy(&log, environment.GetConnectorProxyURL()).StandardClient(),
 	}
 	apiClient := openapiclient.NewAPIClient(configuration)
 
@@ -90,6 +91,22 @@ func (m *openAPIStorageManager) DeleteStorage(request *storagemanager.DeleteStor
 	return err
 }
 
+// storage commit request
+func (m *openAPIStorageManager) CommitStorage(request *storagemanager.CommitStorageRequest) error {
+	httpResponse, err := m.Client.DefaultApi.CommitStorage(context.Background()).CommitStorageRequest(*request).Execute()
+	if httpResponse == nil {
+		if err != nil {
+			return err
+		}
+		return errors.New(StorageManagerCommunicationError)
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode == http.StatusNotImplemented {
+		return errors.New(StorageTypeNotSupported)
+	}
+	return err
+}
+
 // request to get supported connections
 func (m *openAPIStorageManager) GetSupportedStorageTypes() (*storagemanager.GetSupportedStorageTypesResponse, error) {
 	resp, httpResponse, err :=
@@ -136,6 +136,104 @@ func (a *DefaultApiService) AllocateStorageExecute(r ApiAllocateStorageRequest)
 	return localVarReturnValue, localVarHTTPResponse, nil
 }
 
+type ApiCommitStorageRequest struct {
+	ctx                  _context.Context
+	ApiService           *DefaultApiService
+	commitStorageRequest *CommitStorageRequest
+}
+
+// Commit Storage Request
+func (r ApiCommitStorageRequest) CommitStorageRequest(commitStorageRequest CommitStorageRequest) ApiCommitStorageRequest {
+	r.commitStorageRequest = &commitStorageRequest
+	return r
+}
+
+func (r ApiCommitStorageRequest) Execute() (*_nethttp.Response, error) {
+	return r.ApiService.CommitStorageExecute(r)
+}
+
+/*
+CommitStorage This REST API moves the data written to a staging storage to the allocated storage, and deletes the staging storage
+
+	@param ctx _context.Context - for authentication, logging, cancellation, deadlines, tracing, etc. Passed from http.Request or context.Background().
+	@return ApiCommitStorageRequest
+*/
+func (a *DefaultApiService) CommitStorage(ctx _context.Context) ApiCommitStorageRequest {
+	return ApiCommitStorageRequest{
+		ApiService: a,
+		ctx:        ctx,
+	}
+}
+
+// Execute executes the request
+func (a *DefaultApiService) CommitStorageExecute(r ApiCommitStorageRequest) (*_nethttp.Response, error) {
+	var (
+		localVarHTTPMethod = _nethttp.MethodPost
+		localVarPostBody   interface{}
+		formFiles          []formFile
+	)
+
+	localBasePath, err := a.client.cfg.ServerURLWithContext(r.ctx, "DefaultApiService.CommitStorage")
+	if err != nil {
+		return nil, GenericOpenAPIError{error: err.Error()}
+	}
+
+	localVarPath := localBasePath + "/commitStorage"
+
+	localVarHeaderParams := make(map[string]string)
+	localVarQueryParams := _neturl.Values{}
+	localVarFormParams := _neturl.Values{}
+	if r.commitStorageRequest == nil {
+		return nil, reportError("commitStorageRequest is required and must be specified")
+	}
+
+	// to determine the Content-Type header
+	localVarHTTPContentTypes := []string{"application/json"}
+
+	// set Content-Type header
+	localVarHTTPContentType := selectHeaderContentType(localVarHTTPContentTypes)
+	if localVarHTTPContentType != "" {
+		localVarHeaderParams["Content-Type"] = localVarHTTPContentType
+	}
+
+	// to determine the Accept header
+	localVarHTTPHeaderAccepts := []string{}
+
+	// set Accept header
+	localVarHTTPHeaderAccept := selectHeaderAccept(localVarHTTPHeaderAccepts)
+	if localVarHTTPHeaderAccept != "" {
+		localVarHeaderParams["Accept"] = localVarHTTPHeaderAccept
+	}
+	// body params
+	localVarPostBody = r.commitStorageRequest
+	req, err := a.client.prepareRequest(r.ctx, localVarPath, localVarHTTPMethod, localVarPostBody, localVarHeaderParams, localVarQueryParams, localVarFormParams, formFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	localVarHTTPResponse, err := a.client.callAPI(req)
+	if err != nil || localVarHTTPResponse == nil {
+		return localVarHTTPResponse, err
+	}
+
+	localVarBody, err := _ioutil.ReadAll(localVarHTTPResponse.Body)
+	localVarHTTPResponse.Body.Close()
+	localVarHTTPResponse.Body = _ioutil.NopCloser(bytes.NewBuffer(localVarBody))
+	if err != nil {
+		return localVarHTTPResponse, err
+	}
+
+	if localVarHTTPResponse.StatusCode >= 300 {
+		newErr := GenericOpenAPIError{
+			body:  localVarBody,
+			error: localVarHTTPResponse.Status,
+		}
+		return localVarHTTPResponse, newErr
+	}
+
+	return localVarHTTPResponse, nil
+}
+
 type ApiDeleteStorageRequest struct {
 	ctx                  _context.Context
 	ApiService           *DefaultApiService
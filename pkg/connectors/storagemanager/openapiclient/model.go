@@ -7,5 +7,6 @@ import "fybrik.io/fybrik/pkg/model/storagemanager"
 
 type AllocateStorageRequest = storagemanager.AllocateStorageRequest
 type DeleteStorageRequest = storagemanager.DeleteStorageRequest
+type CommitStorageRequest = storagemanager.CommitStorageRequest
 type AllocateStorageResponse = storagemanager.AllocateStorageResponse
 type GetSupportedStorageTypesResponse = storagemanager.GetSupportedStorageTypesResponse
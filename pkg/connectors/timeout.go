@@ -0,0 +1,36 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connectors
+
+import (
+	"context"
+	"time"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// defaultRequestTimeoutMs bounds the requests sent to a connector, unless configured otherwise
+const defaultRequestTimeoutMs = 30000
+
+// RequestTimeoutFromEnvironment returns the timeout of the requests sent to a connector,
+// defined in milliseconds by the given environment variable. A default timeout is used if the variable is undefined,
+// and 0 disables the timeout.
+func RequestTimeoutFromEnvironment(key string) time.Duration {
+	timeout := environment.GetEnvAsInt(key, defaultRequestTimeoutMs)
+	if timeout < 0 {
+		timeout = 0
+	}
+	return time.Duration(timeout) * time.Millisecond
+}
+
+// NewRequestContext returns the context of a request sent to a connector, which is canceled once the timeout elapses
+// or the given parent context is done.
+// The timeout bounds the request including its retries, so that a slow connector does not block the reconcile.
+// A zero timeout does not bound the request.
+func NewRequestContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
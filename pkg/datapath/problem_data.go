@@ -19,6 +19,9 @@ type DataInfo struct {
 	DataDetails *datacatalog.GetAssetResponse
 	// Pointer to the relevant data context in the Fybrik application spec
 	Context *fappv1.DataContext
+	// Name of the consumer served by the data path, empty if the asset has no consumers.
+	// The interface of the consumer replaces the interface of the data context.
+	Consumer string
 	// Evaluated config policies
 	Configuration adminconfig.EvaluatorOutput
 	// Workload cluster
@@ -36,4 +39,26 @@ type Environment struct {
 	Clusters         []multicluster.Cluster
 	StorageAccounts  []*fappv2.FybrikStorageAccount
 	AttributeManager *infrastructure.AttributeManager
+	// ModulePreferences break the ties between data paths whose modules are equally capable of performing an action
+	ModulePreferences []ModulePreference
+}
+
+// ModulePreference prefers the modules selected by their name or by a label to perform an action.
+// It does not affect the actions that are performed, only the choice between modules that support the action.
+type ModulePreference struct {
+	Action taxonomy.ActionName
+	// Module is the name of the preferred module, empty if the modules are selected by a label
+	Module string
+	// LabelKey and LabelValue select the preferred modules by a label
+	LabelKey   string
+	LabelValue string
+}
+
+// Matches checks whether the given module is preferred for the action of the preference
+func (p *ModulePreference) Matches(module *fappv1.FybrikModule) bool {
+	if p.Module != "" {
+		return module.Name == p.Module
+	}
+	value, found := module.Labels[p.LabelKey]
+	return found && value == p.LabelValue
 }
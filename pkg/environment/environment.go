@@ -24,8 +24,11 @@ const (
 	EnableWebhooksKey                 string = "ENABLE_WEBHOOKS"
 	MainPolicyManagerNameKey          string = "MAIN_POLICY_MANAGER_NAME"
 	MainPolicyManagerConnectorURLKey  string = "MAIN_POLICY_MANAGER_CONNECTOR_URL"
+	AdditionalPolicyManagersKey       string = "ADDITIONAL_POLICY_MANAGERS"
+	NamedPolicyManagersKey            string = "NAMED_POLICY_MANAGERS"
 	LoggingVerbosityKey               string = "LOGGING_VERBOSITY"
 	PrettyLoggingKey                  string = "PRETTY_LOGGING"
+	UnredactedLoggingVerbosityKey     string = "UNREDACTED_LOGGING_VERBOSITY"
 	CatalogProviderNameKey            string = "CATALOG_PROVIDER_NAME"
 	DatapathLimitKey                  string = "DATAPATH_LIMIT"
 	UseCSPKey                         string = "USE_CSP"
@@ -47,6 +50,38 @@ const (
 	ResourcesPollingInterval          string = "RESOURCE_POLLING_INTERVAL"
 	DiscoveryBurst                    string = "DISCOVERY_BURST"
 	DiscoveryQPS                      string = "DISCOVERY_QPS"
+	PolicyManagerMaxRetriesKey        string = "POLICY_MANAGER_MAX_RETRIES"
+	PolicyManagerRetryBaseDelayKey    string = "POLICY_MANAGER_RETRY_BASE_DELAY"
+	PolicyManagerRetryMaxDelayKey     string = "POLICY_MANAGER_RETRY_MAX_DELAY"
+	PolicyManagerRetryJitterKey       string = "POLICY_MANAGER_RETRY_JITTER"
+	PolicyManagerCredentialsPathKey   string = "POLICY_MANAGER_CREDENTIALS_PATH"
+	PolicyManagerPublicKeyPathKey     string = "POLICY_MANAGER_PUBLIC_KEY_PATH"
+	ModuleSelectionStrategyKey        string = "MODULE_SELECTION_STRATEGY"
+	ModulePreferencesKey              string = "MODULE_PREFERENCES"
+	ConnectorReadinessGracePeriodKey  string = "CONNECTOR_READINESS_GRACE_PERIOD"
+	TransientFailureRequeueKey        string = "TRANSIENT_FAILURE_REQUEUE_INTERVAL"
+	ModuleReadyRequeueKey             string = "MODULE_READY_REQUEUE_INTERVAL"
+	PolicyDecisionsCacheTTLKey        string = "POLICY_DECISIONS_CACHE_TTL"
+	PolicyReevaluationIntervalKey     string = "POLICY_REEVALUATION_INTERVAL"
+	DefaultDenyKey                    string = "DEFAULT_DENY"
+	PolicyFailClosedKey               string = "POLICY_FAIL_CLOSED"
+	ActionPrecedenceKey               string = "ACTION_PRECEDENCE"
+	ConnectorRateLimitQPSKey          string = "CONNECTOR_RATE_LIMIT_QPS"
+	ConnectorRateLimitBurstKey        string = "CONNECTOR_RATE_LIMIT_BURST"
+	ConnectorMaxConcurrentCallsKey    string = "CONNECTOR_MAX_CONCURRENT_CALLS"
+	ConnectorBreakerThresholdKey      string = "CONNECTOR_BREAKER_FAILURE_THRESHOLD"
+	ConnectorBreakerCooldownKey       string = "CONNECTOR_BREAKER_COOLDOWN"
+	ConnectorProxyURLKey              string = "CONNECTOR_PROXY_URL"
+	ConnectorCABundlePathKey          string = "CONNECTOR_CA_BUNDLE_PATH"
+	ConnectorClientCertPathKey        string = "CONNECTOR_CLIENT_CERT_PATH"
+	ConnectorClientKeyPathKey         string = "CONNECTOR_CLIENT_KEY_PATH"
+	PolicyManagerRequestTimeoutKey    string = "POLICY_MANAGER_REQUEST_TIMEOUT"
+	DataCatalogRequestTimeoutKey      string = "CATALOG_CONNECTOR_REQUEST_TIMEOUT"
+	CatalogDegradedModeKey            string = "CATALOG_DEGRADED_MODE"
+	AssetInfoCacheTTLKey              string = "ASSET_INFO_CACHE_TTL"
+	TracingEndpointKey                string = "TRACING_OTLP_ENDPOINT"
+	TracingInsecureKey                string = "TRACING_OTLP_INSECURE"
+	TaxonomyExtensionsDirKey          string = "TAXONOMY_EXTENSIONS_DIR"
 )
 
 const printValueStr = "%s set to \"%s\""
@@ -160,6 +195,16 @@ func IsVaultEnabled() bool {
 	return v == "true"
 }
 
+// IsDefaultDeny returns true if the access to data is denied unless a policy explicitly allows it
+func IsDefaultDeny() bool {
+	return os.Getenv(DefaultDenyKey) == "true"
+}
+
+// IsPolicyFailClosed returns true if the data paths of the assets are torn down while their policies can not be evaluated
+func IsPolicyFailClosed() bool {
+	return os.Getenv(PolicyFailClosedKey) == "true"
+}
+
 // GetModulesRole returns the modules assigned authentication role for accessing dataset credentials
 func GetModulesRole() string {
 	return os.Getenv(VaultModulesRoleKey)
@@ -248,6 +293,11 @@ func GetCSPPath() string {
 	return os.Getenv(CSPPathKey)
 }
 
+// GetModuleSelectionStrategy returns the strategy used to select the modules of a data path, or "" for the default strategy
+func GetModuleSelectionStrategy() string {
+	return os.Getenv(ModuleSelectionStrategyKey)
+}
+
 // GetCSPArgs returns CSP solver arguments
 func GetCSPArgs() string {
 	return os.Getenv(CSPArgsKey)
@@ -263,6 +313,24 @@ func GetStorageManagerAddress() string {
 	return os.Getenv(StorageManagerAddressKey)
 }
 
+// GetConnectorProxyURL returns the URL of the proxy through which the connectors are called,
+// or "" if the proxy is defined by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func GetConnectorProxyURL() string {
+	return os.Getenv(ConnectorProxyURLKey)
+}
+
+// GetConnectorCABundlePath returns the path of a PEM file holding additional CA certificates
+// which are trusted by the connector clients, or "" if not set
+func GetConnectorCABundlePath() string {
+	return os.Getenv(ConnectorCABundlePathKey)
+}
+
+// GetConnectorClientCertPaths returns the paths of the certificate and private key presented by
+// the connector clients for mutual TLS, or "" if not set
+func GetConnectorClientCertPaths() (string, string) {
+	return os.Getenv(ConnectorClientCertPathKey), os.Getenv(ConnectorClientKeyPathKey)
+}
+
 func logEnvVariable(log *zerolog.Logger, key string) {
 	value, found := os.LookupEnv(key)
 	if found {
@@ -285,9 +353,18 @@ func logEnvVarUpdatedValue(log *zerolog.Logger, envVar, value string, err error)
 
 func LogEnvVariables(log *zerolog.Logger) {
 	envVarArray := [...]string{CatalogConnectorServiceAddressKey, StorageManagerAddressKey, VaultAddressKey, VaultModulesRoleKey,
-		EnableWebhooksKey, MainPolicyManagerConnectorURLKey,
-		MainPolicyManagerNameKey, LoggingVerbosityKey, PrettyLoggingKey,
-		DataDir, ModuleNamespace, ControllerNamespace, ApplicationNamespace, MinTLSVersion}
+		EnableWebhooksKey, MainPolicyManagerConnectorURLKey, NamedPolicyManagersKey,
+		MainPolicyManagerNameKey, AdditionalPolicyManagersKey, LoggingVerbosityKey, PrettyLoggingKey, UnredactedLoggingVerbosityKey,
+		DataDir, ModuleNamespace, ControllerNamespace, ApplicationNamespace, MinTLSVersion,
+		PolicyManagerMaxRetriesKey, PolicyManagerRetryBaseDelayKey, PolicyManagerRetryMaxDelayKey, PolicyManagerRetryJitterKey,
+		PolicyManagerCredentialsPathKey, PolicyManagerPublicKeyPathKey, ModuleSelectionStrategyKey, ModulePreferencesKey,
+		ConnectorReadinessGracePeriodKey, TransientFailureRequeueKey, ModuleReadyRequeueKey, PolicyDecisionsCacheTTLKey,
+		PolicyReevaluationIntervalKey, DefaultDenyKey, ActionPrecedenceKey,
+		PolicyFailClosedKey, ConnectorRateLimitQPSKey, ConnectorRateLimitBurstKey, ConnectorMaxConcurrentCallsKey,
+		ConnectorBreakerThresholdKey, ConnectorBreakerCooldownKey, ConnectorProxyURLKey, TaxonomyExtensionsDirKey,
+		ConnectorCABundlePathKey, ConnectorClientCertPathKey, ConnectorClientKeyPathKey,
+		PolicyManagerRequestTimeoutKey, DataCatalogRequestTimeoutKey, CatalogDegradedModeKey, AssetInfoCacheTTLKey,
+		TracingEndpointKey, TracingInsecureKey}
 
 	log.Info().Msg("Manager configured with the following environment variables:")
 	for _, envVar := range envVarArray {
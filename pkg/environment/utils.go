@@ -33,6 +33,18 @@ func GetEnvAsFloat32(key string, defaultValue float32) float32 {
 	return defaultValue
 }
 
+// Returns the boolean value of an environment variable.
+// If the environment variable is not set or cannot be parsed the default value is returned.
+func GetEnvAsBool(key string, defaultValue bool) bool {
+	if env, isSet := os.LookupEnv(key); isSet {
+		b, err := strconv.ParseBool(env)
+		if err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func MustGetEnv(key string) (string, error) {
 	value, exists := os.LookupEnv(key)
 	if !exists {
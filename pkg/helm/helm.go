@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -46,8 +47,9 @@ type Interface interface {
 	Package(chartPath string, destinationPath string, version string) error
 }
 
-// Fake implementation
+// Fake implementation, which can be used concurrently
 type Fake struct {
+	lock    sync.Mutex
 	release *release.Release
 }
 
@@ -58,6 +60,8 @@ func (r *Fake) GetConfig(kubeNamespace string, log action.DebugLog) (*action.Con
 // Uninstall helm release
 func (r *Fake) Uninstall(cfg *action.Configuration, releaseName string) (*release.UninstallReleaseResponse, error) {
 	res := &release.UninstallReleaseResponse{}
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	r.release = nil
 	return res, nil
 }
@@ -65,6 +69,8 @@ func (r *Fake) Uninstall(cfg *action.Configuration, releaseName string) (*releas
 // Install helm release
 func (r *Fake) Install(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
 	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	r.release = &release.Release{
 		Name: releaseName,
 		Info: &release.Info{Status: release.StatusDeployed},
@@ -75,6 +81,8 @@ func (r *Fake) Install(ctx context.Context, cfg *action.Configuration, chrt *cha
 // Upgrade helm release
 func (r *Fake) Upgrade(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
 	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	r.release = &release.Release{
 		Name: releaseName,
 		Info: &release.Info{Status: release.StatusDeployed},
@@ -84,10 +92,14 @@ func (r *Fake) Upgrade(ctx context.Context, cfg *action.Configuration, chrt *cha
 
 // Status of helm release
 func (r *Fake) Status(cfg *action.Configuration, releaseName string) (*release.Release, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	return r.release, nil
 }
 
 func (r *Fake) IsInstalled(cfg *action.Configuration, releaseName string) (bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
 	return r.release.Info.Status == release.StatusDeployed, nil
 }
 
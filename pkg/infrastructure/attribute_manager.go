@@ -44,7 +44,9 @@ type AttributeManager struct {
 	Attributes []taxonomy.InfrastructureElement
 	// metrics
 	Metrics MetricsDictionary
-	Mux     *sync.RWMutex
+	// metrics contributing to the cost of a data plane
+	CostModel []infraattributes.CostWeight
+	Mux       *sync.RWMutex
 }
 
 func NewAttributeManager() (*AttributeManager, error) {
@@ -57,6 +59,7 @@ func NewAttributeManager() (*AttributeManager, error) {
 		Log:        logging.LogInit(logging.CONTROLLER, "FybrikApplication"),
 		Attributes: attributes,
 		Metrics:    metrics,
+		CostModel:  content.CostModel,
 		Mux:        &sync.RWMutex{},
 	}, nil
 }
@@ -81,6 +84,7 @@ func (m *AttributeManager) OnNotify() {
 	m.Mux.Lock()
 	m.Attributes = attributes
 	m.Metrics = metrics
+	m.CostModel = content.CostModel
 	m.Mux.Unlock()
 }
 
@@ -96,7 +100,8 @@ func parseInfrastructureJSON(content infraattributes.Infrastructure) ([]taxonomy
 // The attribute structure is validated with respect to the generated schema (based on taxonomy)
 func readInfrastructure() (infraattributes.Infrastructure, error) {
 	infrastructureFile := RegoPolicyDirectory + InfrastructureInfo
-	infra := infraattributes.Infrastructure{Attributes: []taxonomy.InfrastructureElement{}, Metrics: []taxonomy.InfrastructureMetrics{}}
+	infra := infraattributes.Infrastructure{Attributes: []taxonomy.InfrastructureElement{}, Metrics: []taxonomy.InfrastructureMetrics{},
+		CostModel: []infraattributes.CostWeight{}}
 	content, err := os.ReadFile(infrastructureFile)
 	if errors.Is(err, fs.ErrNotExist) {
 		// file does not exist - return an empty attribute list for backward compatibility
@@ -126,6 +131,28 @@ func validateStructure(bytes []byte) error {
 	return nil
 }
 
+// GetCostWeights returns the weight of each metric contributing to the cost of a data plane.
+// If no cost model is defined, the cost is measured by the given default metric.
+// Weights that are not numbers are skipped.
+func (m *AttributeManager) GetCostWeights(defaultMetric string) map[string]float64 {
+	if len(m.CostModel) == 0 {
+		return map[string]float64{defaultMetric: 1}
+	}
+	weights := map[string]float64{}
+	for _, item := range m.CostModel {
+		weight := 1.0
+		if item.Weight != "" {
+			var err error
+			if weight, err = strconv.ParseFloat(item.Weight, 64); err != nil {
+				m.Log.Warn().Err(err).Msgf("invalid weight of the metric %s in the cost model", item.MetricName)
+				continue
+			}
+		}
+		weights[item.MetricName] += weight
+	}
+	return weights
+}
+
 // GetAttributeValue returns the value of an infrastructure attribute based on the attribute and instance names
 func (m *AttributeManager) GetAttribute(name, instance string) *taxonomy.InfrastructureElement {
 	for i := range m.Attributes {
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	infraattributes "fybrik.io/fybrik/pkg/model/attributes"
 )
 
 func TestValidInfrastructureAttributeSC(t *testing.T) {
@@ -93,3 +95,23 @@ func TestInvalidInfrastructureAttributeDist(t *testing.T) {
 	validateErr := validateStructure([]byte(data))
 	assert.Error(t, validateErr, "An error is expected")
 }
+
+func TestCostModel(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+		"metrics": [{"name": "cost", "type": "numeric", "units": "US Dollar per TB per month"}],
+		"infrastructure": [],
+		"costModel": [{"metricName": "cost", "weight": "0.5"}]
+	}`
+	assert.Nil(t, validateStructure([]byte(data)), "No error should be found")
+	assert.Error(t, validateStructure([]byte(`{"infrastructure": [], "costModel": [{"weight": "1"}]}`)),
+		"An error is expected for a cost weight without a metric")
+
+	manager := &AttributeManager{}
+	assert.Equal(t, map[string]float64{"cost": 1}, manager.GetCostWeights("cost"))
+	manager.CostModel = []infraattributes.CostWeight{
+		{MetricName: "cost"}, {MetricName: "latency", Weight: "0.5"}, {MetricName: "bandwidth", Weight: "high"},
+	}
+	assert.Equal(t, map[string]float64{"cost": 1, "latency": 0.5}, manager.GetCostWeights("cost"))
+}
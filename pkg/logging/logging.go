@@ -58,16 +58,17 @@ const (
 // Cluster will not be included since not all components know how to determine on which cluster they run.
 // Instead it will be assumed that the logging agents will add this information as they gather the logs.
 const (
-	ACTION       string = "action"       // optional
-	DATASETID    string = "dataSetID"    // optional
-	FORUSER      string = "forUser"      // optional
-	AUDIT        string = "audit"        // optional
-	CLUSTER      string = "cluster"      // optional
-	PLOTTER      string = "plotter"      // optional
-	BLUEPRINT    string = "blueprint"    // optional
-	NAME         string = "name"         // optional
-	NAMESPACE    string = "namespace"    // optional
-	RESPONSETIME string = "responseTime" // optional
+	ACTION        string = "action"        // optional
+	DATASETID     string = "dataSetID"     // optional
+	FORUSER       string = "forUser"       // optional
+	AUDIT         string = "audit"         // optional
+	CLUSTER       string = "cluster"       // optional
+	PLOTTER       string = "plotter"       // optional
+	BLUEPRINT     string = "blueprint"     // optional
+	NAME          string = "name"          // optional
+	NAMESPACE     string = "namespace"     // optional
+	RESPONSETIME  string = "responseTime"  // optional
+	CORRELATIONID string = "correlationID" // optional
 )
 
 // GetLoggingVerbosity returns the level as per https://github.com/rs/zerolog#leveled-logging
@@ -107,20 +108,30 @@ func LogInit(callerType, callerName string) zerolog.Logger {
 }
 
 // LogStructure prints out the provided structure to the log in json format.
+// The values of the sensitive fields, e.g., credentials, are redacted unless the level allows the full structures to be logged.
 func LogStructure(argName string, argStruct interface{}, log *zerolog.Logger, verbosity zerolog.Level, forUser, audit bool) {
 	if log.GetLevel() > verbosity {
 		return
 	}
 	var jsonStruct []byte
 	var err error
-	if PrettyLogging() {
+	redacted := redactionRequired(verbosity)
+	switch {
+	case redacted:
+		jsonStruct, err = MarshalRedacted(argStruct, PrettyLogging())
+	case PrettyLogging():
 		jsonStruct, err = json.MarshalIndent(argStruct, "", "\t")
-	} else {
+	default:
 		jsonStruct, err = json.Marshal(argStruct)
 	}
 
 	if err != nil {
 		msg := "Failed converting " + argName + " to json: "
+		if redacted {
+			// the structure is not printed as is, since its sensitive fields can not be redacted
+			log.WithLevel(verbosity).CallerSkipFrame(1).Bool(FORUSER, forUser).Bool(AUDIT, audit).Msg(msg + err.Error())
+			return
+		}
 		log.WithLevel(verbosity).CallerSkipFrame(1).Bool(FORUSER, forUser).Bool(AUDIT, audit).Msg(msg + fmt.Sprintf("%v", argStruct))
 	} else {
 		// Log the info making sure that the calling function is listed as the caller
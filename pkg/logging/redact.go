@@ -0,0 +1,89 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// RedactedValue replaces the values of the sensitive fields of the logged structures
+const RedactedValue = "[REDACTED]"
+
+// sensitiveFields are the parts of the names of fields that hold credentials, tokens or secret references
+var sensitiveFields = []string{"cred", "password", "token", "secret", "apikey", "api_key", "accesskey", "access_key",
+	"privatekey", "private_key"}
+
+// IsSensitiveField returns true if the value of a field with the given name must not be logged
+func IsSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalRedacted returns the json encoding of a structure in which the values of the sensitive fields are redacted
+func MarshalRedacted(argStruct interface{}, pretty bool) ([]byte, error) {
+	data, err := json.Marshal(argStruct)
+	if err != nil {
+		return nil, err
+	}
+	// numbers are decoded as is, so that large integers are not rounded
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	redact(value)
+	if pretty {
+		return json.MarshalIndent(value, "", "\t")
+	}
+	return json.Marshal(value)
+}
+
+func redact(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if !IsSensitiveField(key) {
+				redact(field)
+				continue
+			}
+			// an empty value is kept, so that it is visible that the field has not been set
+			if field != nil && field != "" {
+				v[key] = RedactedValue
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			redact(item)
+		}
+	}
+}
+
+// redactionRequired returns true if the structures logged at the given level are redacted.
+// UNREDACTED_LOGGING_VERBOSITY is the highest level at which the structures are logged without redaction,
+// e.g., -1 to log the full connector payloads at the trace level during development.
+// The structures are redacted at all levels if it is not set.
+func redactionRequired(verbosity zerolog.Level) bool {
+	levelStr, ok := os.LookupEnv("UNREDACTED_LOGGING_VERBOSITY")
+	levelStr = strings.TrimSpace(levelStr)
+	if !ok || levelStr == "" {
+		return true
+	}
+	level, err := strconv.Atoi(levelStr)
+	if err != nil {
+		return true
+	}
+	return verbosity > zerolog.Level(level)
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package logging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+const (
+	credentialsPath = "/v1/kubernetes-secrets/bucket-creds?namespace=fybrik-system"
+	secretKey       = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+)
+
+// createAssetRequest returns a connector payload that holds credentials
+func createAssetRequest() *datacatalog.CreateAssetRequest {
+	return &datacatalog.CreateAssetRequest{
+		DestinationCatalogID: "fybrik-notebook-sample",
+		ResourceMetadata:     datacatalog.ResourceMetadata{Name: "new-data"},
+		Details: datacatalog.ResourceDetails{
+			Connection: taxonomy.Connection{
+				Name: "s3",
+				AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+					"s3": map[string]interface{}{
+						"endpoint":          "http://localstack.fybrik-notebook-sample.svc.cluster.local:4566",
+						"bucket":            "new-data",
+						"secret_access_key": secretKey,
+					},
+				}},
+			},
+		},
+		Credentials: credentialsPath,
+	}
+}
+
+func logRequest(verbosity zerolog.Level) string {
+	var out bytes.Buffer
+	log := zerolog.New(&out).Level(zerolog.TraceLevel)
+	logging.LogStructure("CreateAssetRequest", createAssetRequest(), &log, verbosity, false, false)
+	return out.String()
+}
+
+func TestLogStructureRedactsCredentials(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	for _, verbosity := range []zerolog.Level{zerolog.TraceLevel, zerolog.DebugLevel, zerolog.InfoLevel} {
+		output := logRequest(verbosity)
+		g.Expect(output).NotTo(gomega.ContainSubstring(credentialsPath))
+		g.Expect(output).NotTo(gomega.ContainSubstring(secretKey))
+		g.Expect(output).To(gomega.ContainSubstring(logging.RedactedValue))
+		// the fields that are not sensitive are logged
+		g.Expect(output).To(gomega.ContainSubstring("localstack.fybrik-notebook-sample"))
+		g.Expect(output).To(gomega.ContainSubstring("new-data"))
+	}
+}
+
+func TestLogStructureUnredactedVerbosity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	t.Setenv(environment.UnredactedLoggingVerbosityKey, "-1")
+	// the full structures are logged at the trace level only
+	g.Expect(logRequest(zerolog.TraceLevel)).To(gomega.ContainSubstring(secretKey))
+	output := logRequest(zerolog.DebugLevel)
+	g.Expect(output).NotTo(gomega.ContainSubstring(credentialsPath))
+	g.Expect(output).NotTo(gomega.ContainSubstring(secretKey))
+}
+
+func TestMarshalRedacted(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	payload := map[string]interface{}{
+		"X-Request-Cred": "token-value",
+		"secretRef":      map[string]interface{}{"name": "creds", "namespace": "default"},
+		"tokens":         []interface{}{"a", "b"},
+		"password":       "",
+		"items":          []interface{}{map[string]interface{}{"apiKey": "key-value", "size": 12345678901234567}},
+	}
+	data, err := logging.MarshalRedacted(payload, false)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(data).To(gomega.MatchJSON(`{"X-Request-Cred":"[REDACTED]","secretRef":"[REDACTED]","tokens":"[REDACTED]",` +
+		`"password":"","items":[{"apiKey":"[REDACTED]","size":12345678901234567}]}`))
+}
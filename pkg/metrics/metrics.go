@@ -0,0 +1,74 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics defines the Prometheus metrics of Fybrik.
+// The metrics are registered with the controller-runtime registry and exposed by the manager metrics endpoint.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Values of the outcome label
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Label names
+const (
+	OutcomeLabel   = "outcome"
+	OperationLabel = "operation"
+)
+
+var (
+	// PolicyRequestDuration measures the requests sent to the policy manager connector
+	PolicyRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fybrik_policy_request_duration_seconds",
+		Help:    "Duration of the requests sent to the policy manager connector",
+		Buckets: prometheus.DefBuckets,
+	}, []string{OutcomeLabel})
+
+	// CatalogRequestDuration measures the requests sent to the data catalog connector, per catalog operation
+	CatalogRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fybrik_catalog_request_duration_seconds",
+		Help:    "Duration of the requests sent to the data catalog connector",
+		Buckets: prometheus.DefBuckets,
+	}, []string{OperationLabel, OutcomeLabel})
+
+	// ReconcileDuration measures the reconcile loop of FybrikApplication resources
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fybrik_reconcile_duration_seconds",
+		Help:    "Duration of the reconciliation of FybrikApplication resources",
+		Buckets: prometheus.DefBuckets,
+	}, []string{OutcomeLabel})
+
+	// ModuleDeploymentDuration measures the installation and upgrade of module charts, per helm operation
+	ModuleDeploymentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "fybrik_module_deployment_duration_seconds",
+		Help:    "Duration of the installation or upgrade of module helm charts",
+		Buckets: prometheus.DefBuckets,
+	}, []string{OperationLabel, OutcomeLabel})
+)
+
+func init() {
+	metrics.Registry.MustRegister(PolicyRequestDuration, CatalogRequestDuration, ReconcileDuration, ModuleDeploymentDuration)
+}
+
+// Outcome returns the value of the outcome label for a request that has returned the given error
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+// ObserveSince records the time elapsed since start in the given histogram.
+// The outcome label is derived from err and follows the given label values.
+func ObserveSince(histogram *prometheus.HistogramVec, start time.Time, err error, labelValues ...string) {
+	labelValues = append(labelValues, Outcome(err))
+	histogram.WithLabelValues(labelValues...).Observe(time.Since(start).Seconds())
+}
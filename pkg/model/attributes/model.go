@@ -13,4 +13,14 @@ type Infrastructure struct {
 	Metrics []taxonomy.InfrastructureMetrics `json:"metrics,omitempty"`
 	// a list of infrastructure arguments
 	Attributes []taxonomy.InfrastructureElement `json:"infrastructure"`
+	// the metrics contributing to the cost of a data plane, used by the cost optimized module selection
+	CostModel []CostWeight `json:"costModel,omitempty"`
+}
+
+// CostWeight defines the contribution of the attributes measured by a metric to the cost of a data plane
+type CostWeight struct {
+	// Name of the metric specified in the metrics section, e.g. cost, bandwidth or latency
+	MetricName string `json:"metricName"`
+	// Weight multiplying the attribute values, 1 if not specified
+	Weight string `json:"weight,omitempty"`
 }
@@ -11,6 +11,21 @@ import (
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostWeight) DeepCopyInto(out *CostWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostWeight.
+func (in *CostWeight) DeepCopy() *CostWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(CostWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Infrastructure) DeepCopyInto(out *Infrastructure) {
 	*out = *in
@@ -28,6 +43,11 @@ func (in *Infrastructure) DeepCopyInto(out *Infrastructure) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CostModel != nil {
+		in, out := &in.CostModel, &out.CostModel
+		*out = make([]CostWeight, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Infrastructure.
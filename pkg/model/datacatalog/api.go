@@ -11,6 +11,10 @@ type GetAssetRequest struct {
 
 	// +kubebuilder:validation:Enum=read;
 	OperationType OperationType `json:"operationType"`
+
+	// +kubebuilder:validation:Optional
+	// Version of the asset to be queried, the latest version if not specified
+	Version string `json:"version,omitempty"`
 }
 
 type GetAssetResponse struct {
@@ -23,6 +27,9 @@ type GetAssetResponse struct {
 	Credentials string `json:"credentials"`
 	// Additional message to be reported to the user
 	Message string `json:"message,omitempty"`
+	// Alternative details of the asset, e.g., a JDBC endpoint of an asset that is also available in S3.
+	// The first details, starting with the details field, whose connection is supported by the modules are used.
+	AlternativeDetails []ResourceDetails `json:"alternativeDetails,omitempty"`
 }
 
 type CreateAssetRequest struct {
@@ -81,3 +88,29 @@ type UpdateAssetResponse struct {
 	// The updation status
 	Status string `json:"status,omitempty"`
 }
+
+type ListAssetsRequest struct {
+	// +kubebuilder:validation:Optional
+	// Maximal number of assets returned in a single page, the catalog default is used if not specified
+	PageSize int32 `json:"pageSize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Cursor returned by the catalog with the previous page, the first page is returned if not specified
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+type AssetSummary struct {
+	// Asset ID of the listed asset
+	AssetID taxonomy.AssetID `json:"assetID"`
+	// Asset metadata like asset name, owner, geography, etc
+	ResourceMetadata ResourceMetadata `json:"resourceMetadata"`
+}
+
+type ListAssetsResponse struct {
+	// Assets of the current page
+	Assets []AssetSummary `json:"assets"`
+
+	// +kubebuilder:validation:Optional
+	// Cursor to be sent to receive the next page, empty if this is the last page
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
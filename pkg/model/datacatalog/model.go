@@ -25,12 +25,16 @@ type ResourceMetadata struct {
 	Tags *taxonomy.Tags `json:"tags,omitempty"`
 	// Columns associated with the asset
 	Columns []ResourceColumn `json:"columns,omitempty"`
+	// Version of the resource
+	Version string `json:"version,omitempty"`
 }
 
 // ResourceColumn represents a column in a tabular resource
 type ResourceColumn struct {
 	// Name of the column
 	Name string `json:"name"`
+	// Data type of the column, e.g., string, integer or timestamp
+	Type string `json:"type,omitempty"`
 	// Tags associated with the column
 	Tags *taxonomy.Tags `json:"tags,omitempty"`
 }
@@ -11,6 +11,22 @@ import (
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssetSummary) DeepCopyInto(out *AssetSummary) {
+	*out = *in
+	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetSummary.
+func (in *AssetSummary) DeepCopy() *AssetSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(AssetSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CreateAssetRequest) DeepCopyInto(out *CreateAssetRequest) {
 	*out = *in
@@ -93,6 +109,13 @@ func (in *GetAssetResponse) DeepCopyInto(out *GetAssetResponse) {
 	*out = *in
 	in.ResourceMetadata.DeepCopyInto(&out.ResourceMetadata)
 	in.Details.DeepCopyInto(&out.Details)
+	if in.AlternativeDetails != nil {
+		in, out := &in.AlternativeDetails, &out.AlternativeDetails
+		*out = make([]ResourceDetails, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetAssetResponse.
@@ -105,6 +128,43 @@ func (in *GetAssetResponse) DeepCopy() *GetAssetResponse {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListAssetsRequest) DeepCopyInto(out *ListAssetsRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListAssetsRequest.
+func (in *ListAssetsRequest) DeepCopy() *ListAssetsRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ListAssetsRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListAssetsResponse) DeepCopyInto(out *ListAssetsResponse) {
+	*out = *in
+	if in.Assets != nil {
+		in, out := &in.Assets, &out.Assets
+		*out = make([]AssetSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListAssetsResponse.
+func (in *ListAssetsResponse) DeepCopy() *ListAssetsResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(ListAssetsResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceColumn) DeepCopyInto(out *ResourceColumn) {
 	*out = *in
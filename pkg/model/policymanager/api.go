@@ -11,6 +11,11 @@ type GetPolicyDecisionsRequest struct {
 	Context  taxonomy.PolicyManagerRequestContext `json:"context,omitempty"`
 	Action   RequestAction                        `json:"action"`
 	Resource Resource                             `json:"resource"`
+	// Identity of the user requesting the data, used by policies that differ per user or role
+	Identity *RequestIdentity `json:"identity,omitempty"`
+	// Time of the request in RFC 3339 format, used by policies that grant access within a time window
+	// +kubebuilder:validation:Format=date-time
+	Time string `json:"time,omitempty"`
 }
 
 type GetPolicyDecisionsResponse struct {
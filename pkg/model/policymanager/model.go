@@ -15,6 +15,14 @@ type RequestAction struct {
 	Destination        string                      `json:"destination,omitempty"`
 }
 
+// RequestIdentity describes the user on behalf of whom the data is accessed
+type RequestIdentity struct {
+	// Name of the user, e.g., the user that has created the FybrikApplication
+	Subject string `json:"subject,omitempty"`
+	// Groups the user belongs to
+	Groups []string `json:"groups,omitempty"`
+}
+
 // Asset metadata
 type Resource struct {
 	ID       taxonomy.AssetID              `json:"id"`
@@ -26,4 +34,16 @@ type ResultItem struct {
 	// The policy on which the decision was based
 	Policy string          `json:"policy"`
 	Action taxonomy.Action `json:"action"`
+	// Validity is the time window in which the action applies, the action always applies if not specified
+	Validity *Validity `json:"validity,omitempty"`
+}
+
+// Validity is a time window in which a policy decision applies
+type Validity struct {
+	// NotBefore is the time in RFC 3339 format from which the action applies, unbounded if not specified
+	// +kubebuilder:validation:Format=date-time
+	NotBefore string `json:"notBefore,omitempty"`
+	// NotAfter is the time in RFC 3339 format from which the action no longer applies, unbounded if not specified
+	// +kubebuilder:validation:Format=date-time
+	NotAfter string `json:"notAfter,omitempty"`
 }
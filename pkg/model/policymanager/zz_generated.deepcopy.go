@@ -17,6 +17,11 @@ func (in *GetPolicyDecisionsRequest) DeepCopyInto(out *GetPolicyDecisionsRequest
 	in.Context.DeepCopyInto(&out.Context)
 	out.Action = in.Action
 	in.Resource.DeepCopyInto(&out.Resource)
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(RequestIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetPolicyDecisionsRequest.
@@ -66,6 +71,26 @@ func (in *RequestAction) DeepCopy() *RequestAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestIdentity) DeepCopyInto(out *RequestIdentity) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestIdentity.
+func (in *RequestIdentity) DeepCopy() *RequestIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
@@ -90,6 +115,11 @@ func (in *Resource) DeepCopy() *Resource {
 func (in *ResultItem) DeepCopyInto(out *ResultItem) {
 	*out = *in
 	in.Action.DeepCopyInto(&out.Action)
+	if in.Validity != nil {
+		in, out := &in.Validity, &out.Validity
+		*out = new(Validity)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResultItem.
@@ -101,3 +131,18 @@ func (in *ResultItem) DeepCopy() *ResultItem {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Validity) DeepCopyInto(out *Validity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Validity.
+func (in *Validity) DeepCopy() *Validity {
+	if in == nil {
+		return nil
+	}
+	out := new(Validity)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -32,6 +32,17 @@ type DeleteStorageRequest struct {
 	Opts Options `json:"options"`
 }
 
+type CommitStorageRequest struct {
+	// Connection object representing the staging storage holding the data to commit
+	Connection taxonomy.Connection `json:"connection"`
+	// Connection object representing the storage the data is moved to
+	Destination taxonomy.Connection `json:"destination"`
+	// Reference to the secret with credentials
+	Secret taxonomy.SecretRef `json:"secret,omitempty"`
+	// Configuration options
+	Opts Options `json:"options"`
+}
+
 type GetSupportedStorageTypesResponse struct {
 	// connection types supported by StorageManager for storage allocation/deletion
 	ConnectionTypes []taxonomy.ConnectionType `json:"connectionTypes"`
@@ -64,6 +64,25 @@ func (in *ApplicationDetails) DeepCopy() *ApplicationDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommitStorageRequest) DeepCopyInto(out *CommitStorageRequest) {
+	*out = *in
+	in.Connection.DeepCopyInto(&out.Connection)
+	in.Destination.DeepCopyInto(&out.Destination)
+	out.Secret = in.Secret
+	out.Opts = in.Opts
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommitStorageRequest.
+func (in *CommitStorageRequest) DeepCopy() *CommitStorageRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(CommitStorageRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigOptions) DeepCopyInto(out *ConfigOptions) {
 	*out = *in
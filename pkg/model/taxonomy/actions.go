@@ -0,0 +1,159 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// allowedDestinationsKey is the property of the actions that restrict the access to a list of destinations
+const allowedDestinationsKey = "allowedDestinations"
+
+// columnsKey is the property of the actions that target columns
+const columnsKey = "columns"
+
+// NewDenyAction returns an action that forbids access to the data.
+// The reason and the policy id are omitted if empty.
+func NewDenyAction(reason, policyID string) Action {
+	return newAction(DenyActionName, DenyAction{Reason: reason, PolicyID: policyID})
+}
+
+// NewAllowAction returns an action that explicitly allows access to the data.
+// The access is restricted to the given destinations, if any. The policy id is omitted if empty.
+func NewAllowAction(policyID string, allowedDestinations ...string) Action {
+	return newAction(AllowActionName, AllowAction{PolicyID: policyID, AllowedDestinations: allowedDestinations})
+}
+
+// IsDestinationAllowed returns false if the action restricts the access to a list of destinations
+// that does not include the given destination
+func IsDestinationAllowed(action *Action, destination string) bool {
+	props, ok := action.AdditionalProperties.Items[string(action.Name)].(map[string]interface{})
+	if !ok {
+		return true
+	}
+	destinations, ok := toStringSlice(props[allowedDestinationsKey])
+	if !ok || len(destinations) == 0 {
+		return true
+	}
+	for _, allowed := range destinations {
+		if allowed == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRedactAction returns an action that masks the values of the given columns as strings
+func NewRedactAction(columns ...string) Action {
+	return newAction(RedactActionName, RedactAction{Columns: columns})
+}
+
+// NewTypedRedactAction returns an action that replaces the values of the given columns according to their types
+func NewTypedRedactAction(strategies RedactionStrategies, columns ...string) Action {
+	return newAction(RedactActionName, RedactAction{Columns: columns, Strategies: &strategies})
+}
+
+// NewMaskedRedactAction returns an action that replaces the values of the given columns by the given mask
+func NewMaskedRedactAction(mask string, columns ...string) Action {
+	return newAction(RedactActionName, RedactAction{Columns: columns, Mask: mask})
+}
+
+// NewHashAction returns an action that hashes the values of the given columns.
+// The default algorithm is used if the algorithm is empty.
+func NewHashAction(algorithm HashAlgorithm, columns ...string) Action {
+	return newAction(HashActionName, HashAction{Columns: columns, Algorithm: algorithm})
+}
+
+// NewProjectionAction returns an action that restricts the data to the given columns
+func NewProjectionAction(columns ...string) Action {
+	return newAction(ProjectionActionName, ProjectionAction{Columns: columns})
+}
+
+// NewFilterAction returns an action that restricts the data to the rows satisfying all predicates.
+// The query is kept for modules that do not support predicates, and is omitted if empty.
+func NewFilterAction(query string, predicates ...FilterPredicate) Action {
+	return newAction(FilterActionName, FilterAction{Query: query, Predicates: predicates})
+}
+
+// newAction returns an action with the given typed properties.
+// The properties are stored in their JSON form, the same as in an action received from a policy manager.
+func newAction(name ActionName, properties interface{}) Action {
+	bytes, err := json.Marshal(properties)
+	if err != nil {
+		// the typed action properties are always serializable
+		panic(err)
+	}
+	var items interface{}
+	if err := json.Unmarshal(bytes, &items); err != nil {
+		panic(err)
+	}
+	return Action{Name: name, AdditionalProperties: serde.Properties{Items: map[string]interface{}{string(name): items}}}
+}
+
+// UniteColumns returns an action with the union of columns of two actions with the same name,
+// provided that the actions differ in their columns only. The columns of the first action come first,
+// and a column targeted by both actions is listed once.
+func UniteColumns(first, second *Action) (Action, bool) {
+	if first.Name != second.Name {
+		return Action{}, false
+	}
+	firstProps, ok1 := first.AdditionalProperties.Items[string(first.Name)].(map[string]interface{})
+	secondProps, ok2 := second.AdditionalProperties.Items[string(second.Name)].(map[string]interface{})
+	if !ok1 || !ok2 || len(first.AdditionalProperties.Items) != 1 || len(second.AdditionalProperties.Items) != 1 {
+		return Action{}, false
+	}
+	firstColumns, ok1 := toStringSlice(firstProps[columnsKey])
+	secondColumns, ok2 := toStringSlice(secondProps[columnsKey])
+	if !ok1 || !ok2 {
+		return Action{}, false
+	}
+	// other properties must be identical
+	props := withoutColumns(firstProps)
+	if !reflect.DeepEqual(props, withoutColumns(secondProps)) {
+		return Action{}, false
+	}
+	columns := []interface{}{}
+	found := map[string]bool{}
+	for _, col := range append(firstColumns, secondColumns...) {
+		if !found[col] {
+			found[col] = true
+			columns = append(columns, col)
+		}
+	}
+	props[columnsKey] = columns
+	merged := Action{Name: first.Name}
+	merged.AdditionalProperties.Items = map[string]interface{}{string(first.Name): props}
+	return merged, true
+}
+
+func withoutColumns(props map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for key, val := range props {
+		if key != columnsKey {
+			res[key] = val
+		}
+	}
+	return res
+}
+
+func toStringSlice(value interface{}) ([]string, bool) {
+	switch values := value.(type) {
+	case []string:
+		return values, true
+	case []interface{}:
+		res := []string{}
+		for _, val := range values {
+			str, ok := val.(string)
+			if !ok {
+				return nil, false
+			}
+			res = append(res, str)
+		}
+		return res, true
+	}
+	return nil, false
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestActionConstructors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		action   Action
+		expected map[string]interface{}
+	}{
+		{
+			name:   "deny",
+			action: NewDenyAction("the asset is tagged as restricted", "restricted-tag"),
+			expected: map[string]interface{}{"name": "Deny",
+				"Deny": map[string]interface{}{"reason": "the asset is tagged as restricted", "policyId": "restricted-tag"}},
+		},
+		{
+			name:     "deny without reason",
+			action:   NewDenyAction("", ""),
+			expected: map[string]interface{}{"name": "Deny", "Deny": map[string]interface{}{}},
+		},
+		{
+			name:     "allow",
+			action:   NewAllowAction("explicit-allow"),
+			expected: map[string]interface{}{"name": "Allow", "Allow": map[string]interface{}{"policyId": "explicit-allow"}},
+		},
+		{
+			name:   "allow to destinations",
+			action: NewAllowAction("allow-theshire", "theshire"),
+			expected: map[string]interface{}{"name": "Allow",
+				"Allow": map[string]interface{}{"policyId": "allow-theshire", "allowedDestinations": []string{"theshire"}}},
+		},
+		{
+			name:     "redact",
+			action:   NewRedactAction("SSN"),
+			expected: map[string]interface{}{"name": "RedactAction", "RedactAction": map[string]interface{}{"columns": []string{"SSN"}}},
+		},
+		{
+			name:   "typed redact",
+			action: NewTypedRedactAction(RedactionStrategies{String: RedactMaskedString, Numeric: RedactZero}, "SSN"),
+			expected: map[string]interface{}{"name": "RedactAction", "RedactAction": map[string]interface{}{
+				"columns":    []string{"SSN"},
+				"strategies": map[string]interface{}{"string": "masked-string", "numeric": "zero"},
+			}},
+		},
+		{
+			name:   "hash",
+			action: NewHashAction(SHA256, "SSN"),
+			expected: map[string]interface{}{"name": "HashAction",
+				"HashAction": map[string]interface{}{"columns": []string{"SSN"}, "algorithm": "sha256"}},
+		},
+		{
+			name:   "projection",
+			action: NewProjectionAction("Name", "Country"),
+			expected: map[string]interface{}{"name": "ProjectionAction",
+				"ProjectionAction": map[string]interface{}{"columns": []string{"Name", "Country"}}},
+		},
+		{
+			name: "filter",
+			action: NewFilterAction("Country == 'UK'",
+				FilterPredicate{Column: "Country", Operator: Equal, Value: NewStringValue("UK")}),
+			expected: map[string]interface{}{"name": "FilterAction", "FilterAction": map[string]interface{}{
+				"query":      "Country == 'UK'",
+				"predicates": []map[string]interface{}{{"column": "Country", "operator": "eq", "value": "UK"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := gomega.NewGomegaWithT(t)
+			expected, err := json.Marshal(tt.expected)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			actual, err := json.Marshal(tt.action)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(actual).To(gomega.MatchJSON(expected))
+
+			// the constructed action equals the action decoded from the hand-built map
+			decoded := Action{}
+			g.Expect(json.Unmarshal(expected, &decoded)).To(gomega.Succeed())
+			g.Expect(tt.action).To(gomega.Equal(decoded))
+		})
+	}
+}
+
+func TestIsDestinationAllowed(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	restricted := NewAllowAction("allow-theshire", "theshire", "neverland")
+	g.Expect(IsDestinationAllowed(&restricted, "theshire")).To(gomega.BeTrue())
+	g.Expect(IsDestinationAllowed(&restricted, "neverland")).To(gomega.BeTrue())
+	g.Expect(IsDestinationAllowed(&restricted, "mordor")).To(gomega.BeFalse())
+	g.Expect(IsDestinationAllowed(&restricted, "")).To(gomega.BeFalse())
+	// the actions that do not restrict the destinations allow any destination
+	unrestricted := NewAllowAction("explicit-allow")
+	g.Expect(IsDestinationAllowed(&unrestricted, "mordor")).To(gomega.BeTrue())
+	redact := NewRedactAction("SSN")
+	g.Expect(IsDestinationAllowed(&redact, "mordor")).To(gomega.BeTrue())
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+)
+
+// FilterValue is a string or a numeric constant
+type FilterValue struct {
+	String   string
+	Number   float64
+	IsNumber bool
+}
+
+// NewStringValue returns a string constant of a filter predicate
+func NewStringValue(value string) FilterValue {
+	return FilterValue{String: value}
+}
+
+// NewNumberValue returns a numeric constant of a filter predicate
+func NewNumberValue(value float64) FilterValue {
+	return FilterValue{Number: value, IsNumber: true}
+}
+
+func (v FilterValue) MarshalJSON() ([]byte, error) {
+	if v.IsNumber {
+		return json.Marshal(v.Number)
+	}
+	return json.Marshal(v.String)
+}
+
+func (v *FilterValue) UnmarshalJSON(bytes []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(bytes, &value); err != nil {
+		return err
+	}
+	switch val := value.(type) {
+	case string:
+		*v = NewStringValue(val)
+	case float64:
+		*v = NewNumberValue(val)
+	default:
+		return errors.Errorf("filter value must be a string or a number, got %s", string(bytes))
+	}
+	return nil
+}
+
+// Validate checks that the predicate is well formed
+func (p *FilterPredicate) Validate() error {
+	if p.Column == "" {
+		return errors.New("filter predicate has no column")
+	}
+	switch p.Operator {
+	case Equal, NotEqual, LessThan, LessOrEqual, GreaterThan, GreaterOrEqual:
+		return nil
+	}
+	return errors.Errorf("unknown filter operator %q for column %s", p.Operator, p.Column)
+}
+
+// Evaluate returns true if the given column value satisfies the predicate.
+// Numeric constants are compared with numeric values, and string constants with string values.
+func (p *FilterPredicate) Evaluate(value interface{}) (bool, error) {
+	if err := p.Validate(); err != nil {
+		return false, err
+	}
+	var cmp int
+	if p.Value.IsNumber {
+		number, ok := toNumber(value)
+		if !ok {
+			return false, errors.Errorf("value %v of column %s is not numeric", value, p.Column)
+		}
+		cmp = compareNumbers(number, p.Value.Number)
+	} else {
+		str, ok := value.(string)
+		if !ok {
+			return false, errors.Errorf("value %v of column %s is not a string", value, p.Column)
+		}
+		cmp = compareStrings(str, p.Value.String)
+	}
+	switch p.Operator {
+	case Equal:
+		return cmp == 0, nil
+	case NotEqual:
+		return cmp != 0, nil
+	case LessThan:
+		return cmp < 0, nil
+	case LessOrEqual:
+		return cmp <= 0, nil
+	case GreaterThan:
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// String returns a human-readable form of the predicate, e.g., region eq "EU"
+func (p *FilterPredicate) String() string {
+	if p.Value.IsNumber {
+		return fmt.Sprintf("%s %s %v", p.Column, p.Operator, p.Value.Number)
+	}
+	return fmt.Sprintf("%s %s %q", p.Column, p.Operator, p.Value.String)
+}
+
+// Validate checks that all predicates of the action are well formed
+func (a *FilterAction) Validate() error {
+	for i := range a.Predicates {
+		if err := a.Predicates[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeActionProperties decodes the properties of an action into a typed action structure, e.g., FilterAction
+func DecodeActionProperties(action *Action, out interface{}) error {
+	props, found := action.AdditionalProperties.Items[string(action.Name)]
+	if !found {
+		return nil
+	}
+	bytes, err := json.Marshal(props)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, out)
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch val := value.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case json.Number:
+		number, err := val.Float64()
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
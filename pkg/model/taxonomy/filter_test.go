@@ -0,0 +1,72 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestFilterPredicateNumeric(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	predicate := FilterPredicate{Column: "Age", Operator: GreaterOrEqual, Value: NewNumberValue(18)}
+	for value, expected := range map[interface{}]bool{17: false, 18: true, int64(40): true, 17.5: false, 18.0: true} {
+		res, err := predicate.Evaluate(value)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(res).To(gomega.Equal(expected), "Age %v", value)
+	}
+	predicate.Operator = LessThan
+	res, err := predicate.Evaluate(json.Number("3"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(res).To(gomega.BeTrue())
+	// a string is not compared with a number
+	_, err = predicate.Evaluate("18")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestFilterPredicateString(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	predicate := FilterPredicate{Column: "Country", Operator: Equal, Value: NewStringValue("UK")}
+	res, err := predicate.Evaluate("UK")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(res).To(gomega.BeTrue())
+	predicate.Operator = NotEqual
+	res, err = predicate.Evaluate("UK")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(res).To(gomega.BeFalse())
+	// strings are compared lexicographically
+	predicate.Operator = LessThan
+	res, err = predicate.Evaluate("France")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(res).To(gomega.BeTrue())
+	_, err = predicate.Evaluate(10)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestFilterActionSerialization(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	action := Action{}
+	g.Expect(json.Unmarshal([]byte(`{"name":"FilterAction","FilterAction":{"predicates":[`+
+		`{"column":"Country","operator":"eq","value":"UK"},{"column":"Age","operator":"gt","value":21}]}}`), &action)).To(gomega.Succeed())
+	filter := FilterAction{}
+	g.Expect(DecodeActionProperties(&action, &filter)).To(gomega.Succeed())
+	g.Expect(filter.Validate()).To(gomega.Succeed())
+	g.Expect(filter.Predicates).To(gomega.Equal([]FilterPredicate{
+		{Column: "Country", Operator: Equal, Value: NewStringValue("UK")},
+		{Column: "Age", Operator: GreaterThan, Value: NewNumberValue(21)},
+	}))
+	bytes, err := json.Marshal(filter.Predicates[1])
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(bytes)).To(gomega.Equal(`{"column":"Age","operator":"gt","value":21}`))
+
+	// unknown operators and non-scalar values are rejected
+	g.Expect(json.Unmarshal([]byte(`{"column":"Age","operator":"gt","value":[21]}`), &FilterPredicate{})).NotTo(gomega.Succeed())
+	filter.Predicates[0].Operator = "like"
+	g.Expect(filter.Validate()).NotTo(gomega.Succeed())
+}
@@ -46,6 +46,158 @@ type Action struct {
 	AdditionalProperties serde.Properties `json:"-"`
 }
 
+// DenyActionName is the name of the action forbidding access to the data
+const DenyActionName ActionName = "Deny"
+
+// AllowActionName is the name of the action explicitly allowing access to the data.
+// It is required if the access is denied by default, and has no effect otherwise.
+const AllowActionName ActionName = "Allow"
+
+// AllowAction identifies the policy that allows access to the data, and the destinations to which the access is allowed
+type AllowAction struct {
+	// Identifier of the policy that allowed the access
+	// +optional
+	PolicyID string `json:"policyId,omitempty"`
+	// Destinations to which the access is allowed, the access to other destinations is denied.
+	// The access is allowed to any destination if not specified.
+	// +optional
+	AllowedDestinations []string `json:"allowedDestinations,omitempty"`
+}
+
+// DenyAction explains why access to the data is forbidden
+type DenyAction struct {
+	// Human readable reason of the denial, reported to the user
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Identifier of the policy that denied the access
+	// +optional
+	PolicyID string `json:"policyId,omitempty"`
+}
+
+// HashActionName is the name of the action that pseudonymizes column values by hashing them
+const HashActionName ActionName = "HashAction"
+
+// HashAlgorithm used for pseudonymization of column values
+// +kubebuilder:validation:Enum=sha256;sha512;hmac-sha256
+type HashAlgorithm string
+
+// List of supported hash algorithms
+const (
+	SHA256     HashAlgorithm = "sha256"
+	SHA512     HashAlgorithm = "sha512"
+	HMACSHA256 HashAlgorithm = "hmac-sha256"
+)
+
+// HashAction replaces the values of the given columns by their hash, keeping the values joinable across datasets
+type HashAction struct {
+	// Columns to be hashed
+	Columns []string `json:"columns"`
+	// Hash algorithm, sha256 is used if not specified
+	// +optional
+	Algorithm HashAlgorithm `json:"algorithm,omitempty"`
+	// Reference to a secret holding the salt (or the key for hmac-sha256)
+	// +optional
+	SaltSecretRef *SecretRef `json:"saltSecretRef,omitempty"`
+}
+
+// ProjectionActionName is the name of the action that restricts the data to an allow-list of columns
+const ProjectionActionName ActionName = "ProjectionAction"
+
+// ProjectionAction enumerates the columns that are allowed to be exposed, all other columns are removed
+type ProjectionAction struct {
+	// Columns that are allowed to be exposed
+	Columns []string `json:"columns"`
+}
+
+// RedactActionName is the name of the action that hides the values of the given columns
+const RedactActionName ActionName = "RedactAction"
+
+// RedactionStrategy defines the values that replace the redacted values of a column
+// +kubebuilder:validation:Enum=null;zero;masked-string;truncate
+type RedactionStrategy string
+
+// List of supported redaction strategies
+const (
+	// RedactNull replaces the values by nulls
+	RedactNull RedactionStrategy = "null"
+	// RedactZero replaces the values by the zero value of the column type, e.g., 0 for numbers and the epoch for dates
+	RedactZero RedactionStrategy = "zero"
+	// RedactMaskedString replaces the values by the mask of the action, "XXXXX" by default,
+	// which is suitable for string columns only
+	RedactMaskedString RedactionStrategy = "masked-string"
+	// RedactTruncate keeps a coarse part of the values only, e.g., the first character of strings,
+	// the integer part of numbers and the date of timestamps
+	RedactTruncate RedactionStrategy = "truncate"
+)
+
+// RedactionStrategies selects the redaction strategy by the type of the redacted column
+type RedactionStrategies struct {
+	// Strategy for string columns
+	// +optional
+	String RedactionStrategy `json:"string,omitempty"`
+	// Strategy for numeric columns, e.g., integers, floating point numbers and decimals
+	// +optional
+	Numeric RedactionStrategy `json:"numeric,omitempty"`
+	// Strategy for date and timestamp columns
+	// +optional
+	Temporal RedactionStrategy `json:"temporal,omitempty"`
+	// Strategy for columns whose type has no strategy, masked-string if not specified
+	// +optional
+	Default RedactionStrategy `json:"default,omitempty"`
+}
+
+// RedactAction hides the values of the given columns
+type RedactAction struct {
+	// Columns to be redacted
+	Columns []string `json:"columns"`
+	// Strategies of replacing the redacted values by column type, the values are masked as strings if not specified
+	// +optional
+	Strategies *RedactionStrategies `json:"strategies,omitempty"`
+	// Mask replacing the values of the masked-string strategy, "XXXXX" if not specified
+	// +optional
+	Mask string `json:"mask,omitempty"`
+	// PreserveLength repeats the mask to the length of each value, e.g., a "*" mask replaces "secret" by "******"
+	// +optional
+	PreserveLength bool `json:"preserveLength,omitempty"`
+}
+
+// FilterActionName is the name of the action that restricts the data to the rows satisfying the given predicates
+const FilterActionName ActionName = "FilterAction"
+
+// FilterOperator compares the value of a column with a constant
+// +kubebuilder:validation:Enum=eq;ne;lt;le;gt;ge
+type FilterOperator string
+
+// List of supported filter operators
+const (
+	Equal          FilterOperator = "eq"
+	NotEqual       FilterOperator = "ne"
+	LessThan       FilterOperator = "lt"
+	LessOrEqual    FilterOperator = "le"
+	GreaterThan    FilterOperator = "gt"
+	GreaterOrEqual FilterOperator = "ge"
+)
+
+// FilterPredicate compares the value of a column with a constant
+type FilterPredicate struct {
+	// Column to be compared
+	Column string `json:"column"`
+	// Comparison operator
+	Operator FilterOperator `json:"operator"`
+	// Constant to compare the column value with, a string or a number
+	Value FilterValue `json:"value"`
+}
+
+// FilterAction restricts the data to the rows satisfying all predicates
+type FilterAction struct {
+	// Predicates to be satisfied by the rows, combined by AND
+	// +optional
+	Predicates []FilterPredicate `json:"predicates,omitempty"`
+	// Query is a free form filter expression, kept for modules that do not support predicates
+	// +optional
+	Query string `json:"query,omitempty"`
+}
+
 func (o Action) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{
 		nameKey: o.Name,
@@ -0,0 +1,46 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import "sort"
+
+// ActionPriorityKey is the optional property of an action that orders it among the actions on the same asset
+const ActionPriorityKey = "priority"
+
+// Default priorities of the actions that do not specify a priority.
+// Row filters precede column transformations, so that a filter may reference a column that is redacted.
+const (
+	FilterPriority         = 100
+	TransformationPriority = 200
+)
+
+// Priority returns the priority of the action, actions with a lower priority are applied first
+func (o *Action) Priority() int {
+	if value, found := o.AdditionalProperties.Items[ActionPriorityKey]; found {
+		if priority, ok := toNumber(value); ok {
+			return int(priority)
+		}
+	}
+	if o.Name == FilterActionName {
+		return FilterPriority
+	}
+	return TransformationPriority
+}
+
+// SortActions returns the actions in the order in which they should be applied: by priority, and by name for the same priority.
+// Actions of the same name keep the order in which they are given, e.g., the order of the policy decisions.
+func SortActions(actions []Action) []Action {
+	if actions == nil {
+		return nil
+	}
+	sorted := make([]Action, len(actions))
+	copy(sorted, actions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if pi, pj := sorted[i].Priority(), sorted[j].Priority(); pi != pj {
+			return pi < pj
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
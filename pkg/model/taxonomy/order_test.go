@@ -0,0 +1,36 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestSortActions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	var actions []Action
+	g.Expect(json.Unmarshal([]byte(`[
+		{"name": "RedactAction", "RedactAction": {"columns": ["a"]}},
+		{"name": "HashAction", "HashAction": {"columns": ["b"]}},
+		{"name": "RedactAction", "RedactAction": {"columns": ["c"]}},
+		{"name": "FilterAction", "FilterAction": {"query": "a > 0"}},
+		{"name": "RemoveAction", "RemoveAction": {"columns": ["d"]}, "priority": 10}
+	]`), &actions)).To(gomega.Succeed())
+
+	sorted := SortActions(actions)
+	names := []ActionName{}
+	for i := range sorted {
+		names = append(names, sorted[i].Name)
+	}
+	g.Expect(names).To(gomega.Equal([]ActionName{"RemoveAction", FilterActionName, HashActionName, "RedactAction", "RedactAction"}))
+	// actions of the same name keep their order
+	g.Expect(sorted[3]).To(gomega.Equal(actions[0]))
+	g.Expect(sorted[4]).To(gomega.Equal(actions[2]))
+	// the given actions are not modified
+	g.Expect(actions[0].Name).To(gomega.Equal(ActionName("RedactAction")))
+}
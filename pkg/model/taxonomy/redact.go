@@ -0,0 +1,81 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultRedactionMask is the mask of the masked-string strategy if the action does not define one
+const DefaultRedactionMask = "XXXXX"
+
+// ColumnType is a category of column types that share a redaction strategy
+type ColumnType string
+
+// List of column type categories
+const (
+	StringColumn   ColumnType = "string"
+	NumericColumn  ColumnType = "numeric"
+	TemporalColumn ColumnType = "temporal"
+	OtherColumn    ColumnType = "other"
+)
+
+// Strategy returns the redaction strategy of a column of the given type.
+// The values are masked as strings if no strategy is specified for the type, or as a default.
+func (o *RedactAction) Strategy(columnType ColumnType) RedactionStrategy {
+	if o.Strategies == nil {
+		return RedactMaskedString
+	}
+	var strategy RedactionStrategy
+	switch columnType {
+	case StringColumn:
+		strategy = o.Strategies.String
+	case NumericColumn:
+		strategy = o.Strategies.Numeric
+	case TemporalColumn:
+		strategy = o.Strategies.Temporal
+	}
+	if strategy == "" {
+		strategy = o.Strategies.Default
+	}
+	if strategy == "" {
+		strategy = RedactMaskedString
+	}
+	return strategy
+}
+
+// UsedStrategies returns the distinct strategies that the action applies to the columns of any type.
+// A module that performs the action has to support all of them.
+func (o *RedactAction) UsedStrategies() []RedactionStrategy {
+	strategies := []RedactionStrategy{}
+	found := map[RedactionStrategy]bool{}
+	for _, columnType := range []ColumnType{StringColumn, NumericColumn, TemporalColumn, OtherColumn} {
+		if strategy := o.Strategy(columnType); !found[strategy] {
+			found[strategy] = true
+			strategies = append(strategies, strategy)
+		}
+	}
+	return strategies
+}
+
+// MaskString returns the mask replacing the values of the masked-string strategy
+func (o *RedactAction) MaskString() string {
+	if o.Mask == "" {
+		return DefaultRedactionMask
+	}
+	return o.Mask
+}
+
+// MaskValue returns the masked-string replacement of the given value.
+// If the length is preserved, the mask is repeated and truncated to the number of characters of the value.
+func (o *RedactAction) MaskValue(value string) string {
+	mask := o.MaskString()
+	if !o.PreserveLength {
+		return mask
+	}
+	length := utf8.RuneCountInString(value)
+	repeated := []rune(strings.Repeat(mask, length/utf8.RuneCountInString(mask)+1))
+	return string(repeated[:length])
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package taxonomy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestRedactionStrategy(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	action := Action{}
+	g.Expect(json.Unmarshal([]byte(`{"name": "RedactAction", "RedactAction": {"columns": ["SSN", "Salary"],
+		"strategies": {"string": "masked-string", "numeric": "zero", "default": "null"}}}`), &action)).To(gomega.Succeed())
+	redact := RedactAction{}
+	g.Expect(DecodeActionProperties(&action, &redact)).To(gomega.Succeed())
+
+	// a numeric column is redacted to zero, while a string column is masked
+	g.Expect(redact.Strategy(NumericColumn)).To(gomega.Equal(RedactZero))
+	g.Expect(redact.Strategy(StringColumn)).To(gomega.Equal(RedactMaskedString))
+	// the default strategy applies to the types without a strategy
+	g.Expect(redact.Strategy(TemporalColumn)).To(gomega.Equal(RedactNull))
+	g.Expect(redact.Strategy(OtherColumn)).To(gomega.Equal(RedactNull))
+	g.Expect(redact.UsedStrategies()).To(gomega.Equal([]RedactionStrategy{RedactMaskedString, RedactZero, RedactNull}))
+}
+
+func TestRedactionWithoutStrategies(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	action := Action{}
+	g.Expect(json.Unmarshal([]byte(`{"name": "RedactAction", "RedactAction": {"columns": ["SSN"]}}`), &action)).To(gomega.Succeed())
+	redact := RedactAction{}
+	g.Expect(DecodeActionProperties(&action, &redact)).To(gomega.Succeed())
+
+	// all values are masked as strings, as done by the modules that do not support strategies
+	g.Expect(redact.Strategy(NumericColumn)).To(gomega.Equal(RedactMaskedString))
+	g.Expect(redact.Strategy(StringColumn)).To(gomega.Equal(RedactMaskedString))
+	g.Expect(redact.UsedStrategies()).To(gomega.Equal([]RedactionStrategy{RedactMaskedString}))
+}
+
+func TestRedactionMask(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	// the values are replaced by the default mask if the action does not define one
+	redact := RedactAction{Columns: []string{"SSN"}}
+	g.Expect(redact.MaskValue("123-45-6789")).To(gomega.Equal(DefaultRedactionMask))
+
+	// a custom mask flows from the policy decision
+	action := Action{}
+	g.Expect(json.Unmarshal([]byte(`{"name": "RedactAction", "RedactAction": {"columns": ["SSN"], "mask": "[REDACTED]"}}`),
+		&action)).To(gomega.Succeed())
+	g.Expect(DecodeActionProperties(&action, &redact)).To(gomega.Succeed())
+	g.Expect(redact.MaskValue("123-45-6789")).To(gomega.Equal("[REDACTED]"))
+
+	// the mask is repeated and truncated to the length of the value
+	redact = RedactAction{Columns: []string{"SSN"}, Mask: "*", PreserveLength: true}
+	g.Expect(redact.MaskValue("secret")).To(gomega.Equal("******"))
+	g.Expect(redact.MaskValue("")).To(gomega.Equal(""))
+	redact.Mask = "ab"
+	g.Expect(redact.MaskValue("Zürich")).To(gomega.Equal("ababab"))
+	g.Expect(redact.MaskValue("Bern")).To(gomega.Equal("abab"))
+	redact.Mask = ""
+	g.Expect(redact.MaskValue("abc")).To(gomega.Equal("XXX"))
+}
@@ -25,6 +25,26 @@ func (in *Action) DeepCopy() *Action {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowAction) DeepCopyInto(out *AllowAction) {
+	*out = *in
+	if in.AllowedDestinations != nil {
+		in, out := &in.AllowedDestinations, &out.AllowedDestinations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowAction.
+func (in *AllowAction) DeepCopy() *AllowAction {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppInfo) DeepCopyInto(out *AppInfo) {
 	*out = *in
@@ -57,6 +77,97 @@ func (in *Connection) DeepCopy() *Connection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DenyAction) DeepCopyInto(out *DenyAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DenyAction.
+func (in *DenyAction) DeepCopy() *DenyAction {
+	if in == nil {
+		return nil
+	}
+	out := new(DenyAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterAction) DeepCopyInto(out *FilterAction) {
+	*out = *in
+	if in.Predicates != nil {
+		in, out := &in.Predicates, &out.Predicates
+		*out = make([]FilterPredicate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterAction.
+func (in *FilterAction) DeepCopy() *FilterAction {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterPredicate) DeepCopyInto(out *FilterPredicate) {
+	*out = *in
+	out.Value = in.Value
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterPredicate.
+func (in *FilterPredicate) DeepCopy() *FilterPredicate {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterPredicate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterValue) DeepCopyInto(out *FilterValue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterValue.
+func (in *FilterValue) DeepCopy() *FilterValue {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashAction) DeepCopyInto(out *HashAction) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SaltSecretRef != nil {
+		in, out := &in.SaltSecretRef, &out.SaltSecretRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashAction.
+func (in *HashAction) DeepCopy() *HashAction {
+	if in == nil {
+		return nil
+	}
+	out := new(HashAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InfrastructureElement) DeepCopyInto(out *InfrastructureElement) {
 	*out = *in
@@ -128,6 +239,26 @@ func (in *PolicyManagerRequestContext) DeepCopy() *PolicyManagerRequestContext {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectionAction) DeepCopyInto(out *ProjectionAction) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectionAction.
+func (in *ProjectionAction) DeepCopy() *ProjectionAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectionAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RangeType) DeepCopyInto(out *RangeType) {
 	*out = *in
@@ -143,6 +274,46 @@ func (in *RangeType) DeepCopy() *RangeType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedactAction) DeepCopyInto(out *RedactAction) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = new(RedactionStrategies)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedactAction.
+func (in *RedactAction) DeepCopy() *RedactAction {
+	if in == nil {
+		return nil
+	}
+	out := new(RedactAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedactionStrategies) DeepCopyInto(out *RedactionStrategies) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedactionStrategies.
+func (in *RedactionStrategies) DeepCopy() *RedactionStrategies {
+	if in == nil {
+		return nil
+	}
+	out := new(RedactionStrategies)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -183,6 +183,7 @@ func (dpc *DataPathCSP) BuildFzModel(pathLength int) (string, error) {
 
 	dpc.addInterfaceConstraints(pathLength)
 	dpc.addGovernanceActionConstraints(pathLength)
+	dpc.addModulePlacementConstraints(pathLength)
 	err := dpc.addAdminConfigRestrictions(pathLength)
 	if err != nil {
 		return "", err
@@ -195,6 +196,17 @@ func (dpc *DataPathCSP) BuildFzModel(pathLength int) (string, error) {
 	return dpc.fzModel.Dump()
 }
 
+// prevents assigning a module capability to a cluster in which the module is not allowed to run
+func (dpc *DataPathCSP) addModulePlacementConstraints(pathLength int) {
+	for modCapIdx, moduleCap := range dpc.modulesCapabilities {
+		for clusterIdx, cluster := range dpc.env.Clusters {
+			if !moduleCap.module.Spec.Placement.Allows(cluster.Name, cluster.Metadata.Region) {
+				preventAssignments(dpc.fzModel, []string{modCapVarname, clusterVarname}, []int{modCapIdx + 1, clusterIdx + 1}, pathLength)
+			}
+		}
+	}
+}
+
 // enforce restrictions from admin configuration decisions.
 // Note: module+capability that does not satisfy capability restrictions is already filtered from dpc.modulesCapabilities
 // a. cluster satisfies restrictions for the selected capability
@@ -362,10 +374,8 @@ func (dpc *DataPathCSP) addActionIndicator(action taxonomy.Action, pathLength in
 	// accumulate module-capabilities that support the current action
 	moduleCapabilitiesStrs := []string{}
 	for modCapIdx, modCap := range dpc.modulesCapabilities {
-		for _, capAction := range modCap.capability.Actions {
-			if capAction.Name == action.Name {
-				moduleCapabilitiesStrs = append(moduleCapabilitiesStrs, strconv.Itoa(modCapIdx+1))
-			}
+		if modCap.capability.SupportsAction(&action) {
+			moduleCapabilitiesStrs = append(moduleCapabilitiesStrs, strconv.Itoa(modCapIdx+1))
 		}
 	}
 
@@ -776,7 +786,8 @@ func (dpc *DataPathCSP) getSolutionActionsAtPos(solverSolution CPSolution, pathP
 			actions = append(actions, action)
 		}
 	}
-	return actions
+	// the actions are applied in the order of their priorities
+	return taxonomy.SortActions(actions)
 }
 
 // Translates a solver's solution into a FybrikApplication Solution for a given data-path
@@ -87,6 +87,35 @@ func (r *Handler) deleteStorage(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "OK"})
 }
 
+// moves the data written to a staging storage to the allocated storage by invoking the specific implementation agent
+// based on the connection type, and deletes the staging storage
+func (r *Handler) commitStorage(c *gin.Context) {
+	// Parse request
+	var request storagemanager.CommitStorageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.Log.Info().Msg(err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error during ShouldBindJSON in commitStorage"})
+		return
+	}
+	if request.Connection.Name != request.Destination.Name {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the staging and the destination storage types differ"})
+		return
+	}
+	impl, err := registrator.GetAgent(request.Connection.Name)
+	if err != nil {
+		r.Log.Info().Msg(err.Error())
+		c.JSON(http.StatusNotImplemented, gin.H{"error": UnsupportedTypeError + string(request.Connection.Name)})
+		return
+	}
+	r.Log.Info().Msgf("commitStorage request for %v", request.Destination)
+	if err = impl.CommitStorage(&request, r.Client); err != nil {
+		r.Log.Info().Msg(err.Error())
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "OK"})
+}
+
 // return a list of supported connection types
 func (r *Handler) getSupportedStorageTypes(c *gin.Context) {
 	resp := &storagemanager.GetSupportedStorageTypesResponse{ConnectionTypes: registrator.GetRegisteredTypes()}
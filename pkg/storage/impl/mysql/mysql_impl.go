@@ -165,3 +165,46 @@ func (impl *MySQLImpl) DeleteStorage(request *storagemanager.DeleteStorageReques
 	}
 	return nil
 }
+
+// storage commit: move the table written to the staging database to the allocated database, and drop the staging database
+func (impl *MySQLImpl) CommitStorage(request *storagemanager.CommitStorageRequest, client kclient.Client) error {
+	details := "commit MySQL storage"
+	staging := request.Connection.AdditionalProperties.Items
+	destination := request.Destination.AdditionalProperties.Items
+	var host, port, stagingDatabase, stagingTable, database, table string
+	var err error
+	if host, err = agent.GetProperty(destination, impl.Name, hostKey); err != nil {
+		return errors.Wrap(err, details)
+	}
+	if port, err = agent.GetProperty(destination, impl.Name, portKey); err != nil {
+		return errors.Wrap(err, details)
+	}
+	if stagingDatabase, err = agent.GetProperty(staging, impl.Name, dbKey); err != nil {
+		return errors.Wrap(err, details)
+	}
+	if stagingTable, err = agent.GetProperty(staging, impl.Name, tableKey); err != nil {
+		return errors.Wrap(err, details)
+	}
+	if database, err = agent.GetProperty(destination, impl.Name, dbKey); err != nil {
+		return errors.Wrap(err, details)
+	}
+	if table, err = agent.GetProperty(destination, impl.Name, tableKey); err != nil {
+		return errors.Wrap(err, details)
+	}
+	// connect to the server
+	db, err := NewClient(host, port, "", request.Secret, client)
+	if err != nil {
+		return errors.Wrap(err, details)
+	}
+	defer db.Close()
+	ctx, cancelfunc := context.WithTimeout(context.Background(), timeout)
+	defer cancelfunc()
+	// renaming the table is atomic, the data becomes visible in the allocated database at once
+	query := fmt.Sprintf("RENAME TABLE `%s`.`%s` TO `%s`.`%s`", stagingDatabase, stagingTable, database, table) + endStatement
+	impl.Log.Info().Msgf("Sending query %s\n", query)
+	if _, err = db.ExecContext(ctx, query); err != nil {
+		return errors.Wrap(err, details)
+	}
+	return impl.DeleteStorage(&storagemanager.DeleteStorageRequest{Connection: request.Connection, Secret: request.Secret,
+		Opts: request.Opts}, client)
+}
@@ -30,6 +30,8 @@ const (
 	endpointKey    = "endpoint"
 	bucketKey      = "bucket"
 	objectKey      = "object_key"
+	// maxCopyObjectSize is the maximal size of an object that is copied by a single request
+	maxCopyObjectSize = 5 * 1024 * 1024 * 1024
 )
 
 // s3 storage manager implementation
@@ -116,6 +118,91 @@ func (impl *S3Impl) DeleteStorage(request *storagemanager.DeleteStorageRequest,
 	return minioClient.RemoveBucket(context.Background(), bucket)
 }
 
+// commit s3 storage: copy the objects written to the staging bucket to the allocated bucket, and delete the staging bucket.
+// If an object fails to be copied, the objects copied to the allocated bucket are removed and the staging bucket is kept.
+func (impl *S3Impl) CommitStorage(request *storagemanager.CommitStorageRequest, client kclient.Client) error {
+	staging := request.Connection.AdditionalProperties.Items
+	destination := request.Destination.AdditionalProperties.Items
+	endpoint, err := agent.GetProperty(destination, impl.Name, endpointKey)
+	if err != nil {
+		return err
+	}
+	if stagingEndpoint, _ := agent.GetProperty(staging, impl.Name, endpointKey); stagingEndpoint != endpoint {
+		return errors.Errorf("the staging endpoint %s differs from the endpoint %s", stagingEndpoint, endpoint)
+	}
+	stagingBucket, err := agent.GetProperty(staging, impl.Name, bucketKey)
+	if err != nil {
+		return err
+	}
+	stagingKey, err := agent.GetProperty(staging, impl.Name, objectKey)
+	if err != nil {
+		return err
+	}
+	bucket, err := agent.GetProperty(destination, impl.Name, bucketKey)
+	if err != nil {
+		return err
+	}
+	key, err := agent.GetProperty(destination, impl.Name, objectKey)
+	if err != nil {
+		return err
+	}
+	// Initialize minio client object.
+	minioClient, err := NewClient(endpoint, &request.Secret, client)
+	if err != nil {
+		return err
+	}
+	if err := commitObjects(context.Background(), minioClient, stagingBucket, stagingKey, bucket, key); err != nil {
+		return err
+	}
+	return impl.DeleteStorage(&storagemanager.DeleteStorageRequest{Connection: request.Connection, Secret: request.Secret,
+		Opts: request.Opts}, client)
+}
+
+// objectStore holds the operations of the minio client that commit the staged objects
+type objectStore interface {
+	ListObjects(ctx context.Context, bucket string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo
+	CopyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions) (minio.UploadInfo, error)
+	ComposeObject(ctx context.Context, dst minio.CopyDestOptions, srcs ...minio.CopySrcOptions) (minio.UploadInfo, error)
+	RemoveObject(ctx context.Context, bucket, object string, opts minio.RemoveObjectOptions) error
+}
+
+// commitObjects copies the objects under the staging key to the destination key. If an object fails to be copied,
+// the objects that have already been copied are removed, so that no partial data is visible at the destination,
+// and the staged objects are kept so that the commit can be retried.
+func commitObjects(ctx context.Context, store objectStore, stagingBucket, stagingKey, bucket, key string) error {
+	copied := []string{}
+	var err error
+	for object := range store.ListObjects(ctx, stagingBucket, minio.ListObjectsOptions{Prefix: stagingKey, Recursive: true}) {
+		if object.Err != nil {
+			err = object.Err
+			break
+		}
+		dst := minio.CopyDestOptions{Bucket: bucket, Object: key + strings.TrimPrefix(object.Key, stagingKey)}
+		src := minio.CopySrcOptions{Bucket: stagingBucket, Object: object.Key}
+		if object.Size > maxCopyObjectSize {
+			// a single copy is limited to 5 GiB, larger objects are copied in parts
+			_, err = store.ComposeObject(ctx, dst, src)
+		} else {
+			_, err = store.CopyObject(ctx, dst, src)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "could not commit the object %s", object.Key)
+			break
+		}
+		copied = append(copied, dst.Object)
+	}
+	if err == nil {
+		return nil
+	}
+	errs := []error{err}
+	for _, object := range copied {
+		if removeErr := store.RemoveObject(ctx, bucket, object, minio.RemoveObjectOptions{}); removeErr != nil {
+			errs = append(errs, errors.Wrapf(removeErr, "could not roll back the committed object %s", object))
+		}
+	}
+	return errors.Combine(errs...)
+}
+
 func generateBucketName(opts *storagemanager.Options) string {
 	suffix, _ := random.Hex(nameHashLength)
 	name := opts.AppDetails.Name + "-" + opts.AppDetails.Namespace + suffix
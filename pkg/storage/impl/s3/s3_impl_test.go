@@ -0,0 +1,91 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/onsi/gomega"
+)
+
+// fakeObjectStore holds the objects of the buckets in memory, and fails to copy the object of the given key
+type fakeObjectStore struct {
+	buckets  map[string]map[string]int64
+	failKey  string
+	composed []string
+}
+
+func (s *fakeObjectStore) ListObjects(ctx context.Context, bucket string, opts minio.ListObjectsOptions) <-chan minio.ObjectInfo {
+	keys := []string{}
+	for key := range s.buckets[bucket] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	objects := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		objects <- minio.ObjectInfo{Key: key, Size: s.buckets[bucket][key]}
+	}
+	close(objects)
+	return objects
+}
+
+func (s *fakeObjectStore) CopyObject(ctx context.Context, dst minio.CopyDestOptions,
+	src minio.CopySrcOptions) (minio.UploadInfo, error) {
+	if src.Object == s.failKey {
+		return minio.UploadInfo{}, errors.New("connection reset by peer")
+	}
+	if s.buckets[dst.Bucket] == nil {
+		s.buckets[dst.Bucket] = map[string]int64{}
+	}
+	s.buckets[dst.Bucket][dst.Object] = s.buckets[src.Bucket][src.Object]
+	return minio.UploadInfo{Bucket: dst.Bucket, Key: dst.Object}, nil
+}
+
+func (s *fakeObjectStore) ComposeObject(ctx context.Context, dst minio.CopyDestOptions,
+	srcs ...minio.CopySrcOptions) (minio.UploadInfo, error) {
+	s.composed = append(s.composed, srcs[0].Object)
+	return s.CopyObject(ctx, dst, srcs[0])
+}
+
+func (s *fakeObjectStore) RemoveObject(ctx context.Context, bucket, object string, opts minio.RemoveObjectOptions) error {
+	delete(s.buckets[bucket], object)
+	return nil
+}
+
+func newFakeObjectStore(failKey string) *fakeObjectStore {
+	return &fakeObjectStore{
+		buckets: map[string]map[string]int64{
+			"staging": {"data-staged/part-0": 10, "data-staged/part-1": maxCopyObjectSize + 1, "data-staged/part-2": 10},
+			"bucket":  {"other": 1},
+		},
+		failKey: failKey,
+	}
+}
+
+func TestCommitObjects(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	store := newFakeObjectStore("")
+	g.Expect(commitObjects(context.Background(), store, "staging", "data-staged", "bucket", "data")).To(gomega.Succeed())
+	g.Expect(store.buckets["bucket"]).To(gomega.Equal(map[string]int64{
+		"other": 1, "data/part-0": 10, "data/part-1": maxCopyObjectSize + 1, "data/part-2": 10}))
+	// the objects larger than 5 GiB are composed
+	g.Expect(store.composed).To(gomega.Equal([]string{"data-staged/part-1"}))
+}
+
+// An object fails to be copied in the middle of the commit
+// Result: the objects that have been copied are removed, and the staged objects are kept
+func TestCommitObjectsFailure(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	store := newFakeObjectStore("data-staged/part-2")
+	err := commitObjects(context.Background(), store, "staging", "data-staged", "bucket", "data")
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("could not commit the object data-staged/part-2")))
+	g.Expect(store.buckets["bucket"]).To(gomega.Equal(map[string]int64{"other": 1}))
+	g.Expect(store.buckets["staging"]).To(gomega.HaveLen(3))
+}
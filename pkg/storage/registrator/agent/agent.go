@@ -19,6 +19,8 @@ type AgentInterface interface {
 	AllocateStorage(request *storagemanager.AllocateStorageRequest, client kclient.Client) (taxonomy.Connection, error)
 	// delete storage
 	DeleteStorage(request *storagemanager.DeleteStorageRequest, client kclient.Client) error
+	// move the data from a staging storage to the allocated storage and delete the staging storage
+	CommitStorage(request *storagemanager.CommitStorageRequest, client kclient.Client) error
 	// return the supported connection type
 	GetConnectionType() taxonomy.ConnectionType
 }
@@ -0,0 +1,85 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"emperror.dev/errors"
+	"github.com/apache/arrow/go/v7/arrow/flight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"fybrik.io/fybrik/pkg/connection"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// Keys of the TLS properties in the secrets referenced by an arrow-flight endpoint
+const (
+	CACertKey     = "ca.crt"
+	ClientCertKey = corev1.TLSCertKey
+	ClientKeyKey  = corev1.TLSPrivateKeyKey
+)
+
+// FlightTLSConfig builds the client TLS configuration of an arrow-flight endpoint,
+// reading the referenced secrets with the given client
+func FlightTLSConfig(ctx context.Context, reader client.Reader, t *connection.ArrowFlightTLS) (*tls.Config, error) {
+	config := &tls.Config{ServerName: t.ServerName, MinVersion: tls.VersionTLS12}
+	if t.CASecretRef != nil {
+		secret, err := readSecret(ctx, reader, t.CASecretRef)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(secret.Data[CACertKey]) {
+			return nil, errors.Errorf("no CA certificate found in key %s of secret %s/%s", CACertKey,
+				t.CASecretRef.Namespace, t.CASecretRef.Name)
+		}
+		config.RootCAs = pool
+	}
+	if t.ClientCertSecretRef != nil {
+		secret, err := readSecret(ctx, reader, t.ClientCertSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(secret.Data[ClientCertKey], secret.Data[ClientKeyKey])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid client certificate in secret %s/%s",
+				t.ClientCertSecretRef.Namespace, t.ClientCertSecretRef.Name)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// NewFlightClient connects to the arrow-flight server at the given address, e.g., a forwarded local port,
+// honoring the TLS settings advertised by the endpoint connection.
+// An insecure connection is used if the endpoint does not require TLS.
+func NewFlightClient(ctx context.Context, reader client.Reader, addr string, endpoint *connection.ArrowFlightConnection,
+	opts ...grpc.DialOption) (flight.Client, error) {
+	transportCredentials := insecure.NewCredentials()
+	if settings := endpoint.TLSSettings(); settings != nil {
+		config, err := FlightTLSConfig(ctx, reader, settings)
+		if err != nil {
+			return nil, err
+		}
+		transportCredentials = credentials.NewTLS(config)
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(transportCredentials)}, opts...)
+	return flight.NewFlightClient(addr, nil, opts...)
+}
+
+func readSecret(ctx context.Context, reader client.Reader, ref *taxonomy.SecretRef) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret %s/%s", ref.Namespace, ref.Name)
+	}
+	return secret, nil
+}
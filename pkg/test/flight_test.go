@@ -0,0 +1,134 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"fybrik.io/fybrik/pkg/connection"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+const flightServerName = "arrow-flight.fybrik-blueprints"
+
+// newCertificate returns a PEM encoded certificate and key signed by the given parent, or self-signed if parent is nil
+func newCertificate(g *gomega.WithT, template *x509.Certificate, parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func certificateTemplate(serial int64, name string, isCA bool) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IsCA:         isCA,
+		DNSNames:     []string{name},
+	}
+	template.BasicConstraintsValid = isCA
+	return template
+}
+
+// startTLSServer starts a gRPC server on a local port that requires client certificates signed by the CA
+func startTLSServer(g *gomega.WithT, serverCert tls.Certificate, caPool *x509.CertPool) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})))
+	go func() { _ = server.Serve(listener) }()
+	return listener.Addr().String(), server.Stop
+}
+
+func TestFlightTLSConfig(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	settings := &connection.ArrowFlightTLS{
+		CASecretRef: &taxonomy.SecretRef{Name: "flight-ca", Namespace: "fybrik-system"},
+		ServerName:  flightServerName,
+	}
+	// the CA secret does not exist
+	_, err := FlightTLSConfig(context.Background(), fake.NewClientBuilder().Build(), settings)
+	g.Expect(err).To(gomega.HaveOccurred())
+	// the system CA certificates are used without a CA secret
+	config, err := FlightTLSConfig(context.Background(), fake.NewClientBuilder().Build(), &connection.ArrowFlightTLS{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(config.RootCAs).To(gomega.BeNil())
+}
+
+func TestNewFlightClientWithCASecret(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	caCert, caKey, caPEM, _ := newCertificate(g, certificateTemplate(1, "fybrik-ca", true), nil, nil)
+	_, _, serverPEM, serverKeyPEM := newCertificate(g, certificateTemplate(2, flightServerName, false), caCert, caKey)
+	_, _, clientPEM, clientKeyPEM := newCertificate(g, certificateTemplate(3, "notebook", false), caCert, caKey)
+	serverCert, err := tls.X509KeyPair(serverPEM, serverKeyPEM)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+	addr, stop := startTLSServer(g, serverCert, caPool)
+	defer stop()
+
+	reader := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "flight-ca", Namespace: "fybrik-system"},
+			Data: map[string][]byte{CACertKey: caPEM}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "notebook-cert", Namespace: "default"},
+			Data: map[string][]byte{ClientCertKey: clientPEM, ClientKeyKey: clientKeyPEM}},
+	).Build()
+	endpoint := &connection.ArrowFlightConnection{
+		Hostname: flightServerName,
+		Port:     443,
+		Scheme:   connection.ArrowFlightTLSScheme,
+		TLS: &connection.ArrowFlightTLS{
+			CASecretRef:         &taxonomy.SecretRef{Name: "flight-ca", Namespace: "fybrik-system"},
+			ClientCertSecretRef: &taxonomy.SecretRef{Name: "notebook-cert", Namespace: "default"},
+			ServerName:          flightServerName,
+		},
+	}
+	ctx := context.Background()
+	flightClient, err := NewFlightClient(ctx, reader, addr, endpoint, grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(flightClient.Close()).To(gomega.Succeed())
+
+	// the server certificate can not be verified without the CA bundle
+	endpoint.TLS.CASecretRef = nil
+	_, err = NewFlightClient(ctx, reader, addr, endpoint, grpc.WithBlock(), grpc.WithTimeout(time.Second))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
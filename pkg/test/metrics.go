@@ -0,0 +1,22 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// HistogramSampleCount returns the number of observations recorded by the histogram with the given label values
+func HistogramSampleCount(histogram *prometheus.HistogramVec, labelValues ...string) uint64 {
+	observer, err := histogram.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		return 0
+	}
+	metric := &dto.Metric{}
+	if err := observer.(prometheus.Histogram).Write(metric); err != nil {
+		return 0
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -4,12 +4,17 @@
 package test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"os/exec"
 	"regexp"
 	"strconv"
+	"sync"
 	"syscall"
+	"testing"
+	"time"
 )
 
 const (
@@ -17,6 +22,32 @@ const (
 	regexpPortMatchLen = 3
 )
 
+// Default retry policy of port forwarding
+const (
+	DefaultPortForwardMaxRetries int           = 25
+	DefaultPortForwardDelay      time.Duration = 5 * time.Second
+)
+
+// portForwardGracePeriod is the time given to a port-forward to terminate after SIGINT before it is killed
+const portForwardGracePeriod = 5 * time.Second
+
+// RetryPolicy defines how many times and how often a failed port-forward is retried.
+// Zero values are replaced by the defaults.
+type RetryPolicy struct {
+	MaxRetries int
+	Delay      time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxRetries <= 0 {
+		p.MaxRetries = DefaultPortForwardMaxRetries
+	}
+	if p.Delay <= 0 {
+		p.Delay = DefaultPortForwardDelay
+	}
+	return p
+}
+
 // TODO support other ports besides 80
 //
 //nolint:gocritic // Bad regex - according to gocritic this regex can be optimized
@@ -72,8 +103,35 @@ func RunPortForward(ns, svcName string, port int) (string, *exec.Cmd, error) {
 	return match[2], cmd, nil
 }
 
+func retryPortForward(ctx context.Context, policy RetryPolicy, forward func() (string, *exec.Cmd, error),
+	stop func(cmd *exec.Cmd) error) (string, error) {
+	policy = policy.withDefaults()
+	for attempt := 0; ; attempt++ {
+		listenPort, cmd, err := forward()
+		if err == nil {
+			return listenPort, nil
+		}
+		if err = stop(cmd); err != nil {
+			return "", errors.New("failed to terminate port-forward " + err.Error())
+		}
+		if attempt >= policy.MaxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(policy.Delay):
+		}
+	}
+	return "", errors.New("Port Forwarding command failed with error")
+}
+
 // This function stops PortForward command
 func StopPortForward(cmd *exec.Cmd) error {
+	if cmd == nil || cmd.Process == nil {
+		// the command has not been started
+		return nil
+	}
 	// SIGINT signals that kubectl port-forward should gracefully terminate
 	if err := cmd.Process.Signal(syscall.SIGINT); err != nil {
 		return errors.New("error sending SIGINT to kubectl port-forward: " + err.Error())
@@ -81,3 +139,100 @@ func StopPortForward(cmd *exec.Cmd) error {
 
 	return nil
 }
+
+// portForwardRunner starts a port-forward to a service, and returns the local port and the started command
+type portForwardRunner func(ns, svcName string, port int) (string, *exec.Cmd, error)
+
+// PortForwarder forwards local ports to services for the duration of a test.
+// The port-forward processes are terminated by Close, which is registered with the test cleanup,
+// so that no process is leaked even if the test fails or panics.
+type PortForwarder struct {
+	policy RetryPolicy
+	run    portForwardRunner
+
+	mutex  sync.Mutex
+	cmds   []*exec.Cmd
+	closed bool
+}
+
+// NewPortForwarder returns a PortForwarder whose port-forwards are retried according to the given policy,
+// and are terminated when the test completes
+func NewPortForwarder(t testing.TB, policy RetryPolicy) *PortForwarder {
+	return newPortForwarder(t, policy, RunPortForward)
+}
+
+func newPortForwarder(t testing.TB, policy RetryPolicy, run portForwardRunner) *PortForwarder {
+	forwarder := &PortForwarder{policy: policy, run: run}
+	t.Cleanup(func() {
+		if err := forwarder.Close(); err != nil {
+			t.Log(err)
+		}
+	})
+	return forwarder
+}
+
+// Forward forwards a local port to the given port of a service, and returns the chosen local port.
+// It may be called concurrently to forward several services.
+// A cancelled context aborts the retries immediately.
+func (p *PortForwarder) Forward(ctx context.Context, ns, svcName string, port int) (string, error) {
+	var started *exec.Cmd
+	listenPort, err := retryPortForward(ctx, p.policy, func() (string, *exec.Cmd, error) {
+		listenPort, cmd, err := p.run(ns, svcName, port)
+		started = cmd
+		return listenPort, cmd, err
+	}, StopPortForward)
+	if err != nil {
+		return "", err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.closed {
+		_ = stopAndWait(started)
+		return "", errors.New("port forwarder is closed")
+	}
+	p.cmds = append(p.cmds, started)
+	return listenPort, nil
+}
+
+// Close terminates all the port-forwards, and returns the first error encountered
+func (p *PortForwarder) Close() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+	var firstErr error
+	for _, cmd := range p.cmds {
+		if err := stopAndWait(cmd); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	p.cmds = nil
+	return firstErr
+}
+
+// stopAndWait stops a port-forward and waits for the process to exit, killing it if it does not exit in time
+func stopAndWait(cmd *exec.Cmd) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	if err := StopPortForward(cmd); err != nil {
+		// the process may have already exited
+		_ = cmd.Process.Kill()
+	}
+	done := make(chan struct{})
+	go func() {
+		// the exit status of an interrupted process is not an error
+		_ = cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(portForwardGracePeriod):
+		if err := cmd.Process.Kill(); err != nil {
+			return fmt.Errorf("failed to kill port-forward process %d: %w", cmd.Process.Pid, err)
+		}
+		<-done
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+// fakePortForward fails the given number of times before returning a port, and records the sequence of calls
+type fakePortForward struct {
+	failures int
+	calls    []string
+}
+
+func (f *fakePortForward) forward() (string, *exec.Cmd, error) {
+	f.calls = append(f.calls, "forward")
+	if f.failures > 0 {
+		f.failures--
+		return "", &exec.Cmd{}, errors.New("connection refused")
+	}
+	return "8080", &exec.Cmd{}, nil
+}
+
+func (f *fakePortForward) stop(cmd *exec.Cmd) error {
+	f.calls = append(f.calls, "stop")
+	return nil
+}
+
+func TestRetryPortForward(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	policy := RetryPolicy{MaxRetries: 3, Delay: time.Millisecond}
+
+	// a failed port-forward is stopped before the next attempt
+	fake := &fakePortForward{failures: 2}
+	port, err := retryPortForward(context.Background(), policy, fake.forward, fake.stop)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(port).To(gomega.Equal("8080"))
+	g.Expect(fake.calls).To(gomega.Equal([]string{"forward", "stop", "forward", "stop", "forward"}))
+
+	// retries are exhausted
+	fake = &fakePortForward{failures: 10}
+	_, err = retryPortForward(context.Background(), policy, fake.forward, fake.stop)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(fake.calls).To(gomega.HaveLen(2 * (policy.MaxRetries + 1)))
+
+	// a cancelled context aborts the retries without waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fake = &fakePortForward{failures: 10}
+	start := time.Now()
+	_, err = retryPortForward(ctx, RetryPolicy{MaxRetries: 3, Delay: time.Hour}, fake.forward, fake.stop)
+	g.Expect(err).To(gomega.MatchError(context.Canceled))
+	g.Expect(fake.calls).To(gomega.Equal([]string{"forward", "stop"}))
+	g.Expect(time.Since(start)).To(gomega.BeNumerically("<", time.Second))
+}
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	policy := RetryPolicy{}.withDefaults()
+	g.Expect(policy.MaxRetries).To(gomega.Equal(DefaultPortForwardMaxRetries))
+	g.Expect(policy.Delay).To(gomega.Equal(DefaultPortForwardDelay))
+}
+
+// fakeRunner starts a long running process instead of kubectl port-forward, and assigns a port per service
+type fakeRunner struct {
+	mutex sync.Mutex
+	cmds  []*exec.Cmd
+}
+
+func (f *fakeRunner) run(ns, svcName string, port int) (string, *exec.Cmd, error) {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		return "", cmd, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.cmds = append(f.cmds, cmd)
+	return strconv.Itoa(8080 + len(f.cmds)), cmd, nil
+}
+
+func TestPortForwarderClose(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	runner := &fakeRunner{}
+	forwarder := newPortForwarder(t, RetryPolicy{}, runner.run)
+
+	// several services are forwarded concurrently
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, svcName := range []string{"svc1", "svc2"} {
+		wg.Add(1)
+		go func(svcName string) {
+			defer wg.Done()
+			_, err := forwarder.Forward(context.Background(), "ns", svcName, 80)
+			errs <- err
+		}(svcName)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+	}
+	g.Expect(runner.cmds).To(gomega.HaveLen(2))
+	for _, cmd := range runner.cmds {
+		g.Expect(cmd.ProcessState).To(gomega.BeNil())
+	}
+
+	// the processes are terminated
+	g.Expect(forwarder.Close()).To(gomega.Succeed())
+	for _, cmd := range runner.cmds {
+		g.Expect(cmd.ProcessState).NotTo(gomega.BeNil())
+		g.Expect(cmd.ProcessState.Exited()).To(gomega.BeFalse(), "the process should be interrupted")
+	}
+
+	// no port is forwarded after Close
+	_, err := forwarder.Forward(context.Background(), "ns", "svc3", 80)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(runner.cmds[2].ProcessState).NotTo(gomega.BeNil())
+}
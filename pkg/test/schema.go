@@ -0,0 +1,30 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"emperror.dev/errors"
+	"github.com/apache/arrow/go/v7/arrow"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+)
+
+// ValidateSchema checks that the schema of the written record batches matches the expected schema of the asset.
+// The columns must match by name and order. Any schema is valid if no schema is expected.
+func ValidateSchema(expected *fappv1.AssetSchema, schema *arrow.Schema) error {
+	if expected == nil {
+		return nil
+	}
+	fields := schema.Fields()
+	if len(fields) != len(expected.Columns) {
+		return errors.Errorf("schema mismatch: expected %d columns %v, got %d columns", len(expected.Columns),
+			expected.Columns, len(fields))
+	}
+	for i, column := range expected.Columns {
+		if fields[i].Name != column {
+			return errors.Errorf("schema mismatch: expected column %q at position %d, got %q", column, i, fields[i].Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"fmt"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/flight"
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+)
+
+// StreamOptions bounds the resources used while reading an arrow-flight stream
+type StreamOptions struct {
+	// MaxMemory is the maximum number of bytes allocated for the records in use, 0 for no limit.
+	// Only the current record is in use unless the callback retains records.
+	// The size of the messages received from the server is bounded separately, by the grpc.MaxCallRecvMsgSize option.
+	MaxMemory int
+	// Schema is the expected schema of the stream, e.g., of the data written to an asset in the strict schema mode.
+	// A stream that does not match it is rejected before reading any record. Any schema is accepted if nil.
+	Schema *fappv1.AssetSchema
+}
+
+// StreamStats summarizes the records read from an arrow-flight stream
+type StreamStats struct {
+	Batches int
+	Rows    int64
+}
+
+// ReadFlightStream reads the stream one record batch at a time and calls the callback for each record.
+// A record is released once the callback returns, so that a large stream is read without retaining its batches.
+// The reading stops at the first error returned by the callback.
+func ReadFlightStream(stream flight.DataStreamReader, options StreamOptions, callback func(arrow.Record) error) (*StreamStats, error) {
+	var allocator memory.Allocator = memory.NewGoAllocator()
+	if options.MaxMemory > 0 {
+		allocator = &limitedAllocator{allocator: allocator, limit: options.MaxMemory}
+	}
+	reader, err := flight.NewRecordReader(stream, ipc.WithAllocator(allocator))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the arrow-flight stream")
+	}
+	defer reader.Release()
+	if err := ValidateSchema(options.Schema, reader.Schema()); err != nil {
+		return nil, err
+	}
+
+	stats := &StreamStats{}
+	for {
+		hasNext, err := nextRecord(reader)
+		if err != nil {
+			return stats, err
+		}
+		if !hasNext {
+			break
+		}
+		record := reader.Record()
+		stats.Batches++
+		stats.Rows += record.NumRows()
+		if err := callback(record); err != nil {
+			return stats, err
+		}
+	}
+	if err := reader.Err(); err != nil {
+		return stats, errors.Wrap(err, "could not read the arrow-flight stream")
+	}
+	return stats, nil
+}
+
+// nextRecord advances the reader, converting a failed allocation of the memory limit into an error
+func nextRecord(reader *flight.Reader) (hasNext bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			limitErr, ok := r.(*memoryLimitError)
+			if !ok {
+				panic(r)
+			}
+			err = limitErr
+		}
+	}()
+	return reader.Next(), nil
+}
+
+type memoryLimitError struct {
+	limit int
+	size  int
+}
+
+func (e *memoryLimitError) Error() string {
+	return fmt.Sprintf("allocation of %d bytes exceeds the memory limit of %d bytes", e.size, e.limit)
+}
+
+// limitedAllocator fails allocations once the bytes in use exceed the limit.
+// Allocators can not return an error, so a failed allocation panics with a memoryLimitError.
+type limitedAllocator struct {
+	allocator memory.Allocator
+	limit     int
+
+	mutex sync.Mutex
+	inUse int
+}
+
+func (a *limitedAllocator) reserve(size int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.inUse+size > a.limit {
+		panic(&memoryLimitError{limit: a.limit, size: size})
+	}
+	a.inUse += size
+}
+
+func (a *limitedAllocator) Allocate(size int) []byte {
+	a.reserve(size)
+	return a.allocator.Allocate(size)
+}
+
+func (a *limitedAllocator) Reallocate(size int, b []byte) []byte {
+	a.reserve(size - len(b))
+	return a.allocator.Reallocate(size, b)
+}
+
+func (a *limitedAllocator) Free(b []byte) {
+	a.mutex.Lock()
+	a.inUse -= len(b)
+	a.mutex.Unlock()
+	a.allocator.Free(b)
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v7/arrow"
+	"github.com/apache/arrow/go/v7/arrow/array"
+	"github.com/apache/arrow/go/v7/arrow/flight"
+	"github.com/apache/arrow/go/v7/arrow/ipc"
+	"github.com/apache/arrow/go/v7/arrow/memory"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+)
+
+const (
+	fakeBatches   = 5
+	fakeBatchRows = 1000
+)
+
+var fakeSchema = arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+// startFakeFlightServer starts an arrow-flight server on a local port that emits several batches of sequential ids
+func startFakeFlightServer(g *gomega.WithT) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	server := grpc.NewServer()
+	flight.RegisterFlightServiceService(server, &flight.FlightServiceService{
+		DoGet: func(ticket *flight.Ticket, stream flight.FlightService_DoGetServer) error {
+			writer := flight.NewRecordWriter(stream, ipc.WithSchema(fakeSchema))
+			defer writer.Close()
+			builder := array.NewInt64Builder(memory.NewGoAllocator())
+			defer builder.Release()
+			for batch := 0; batch < fakeBatches; batch++ {
+				for row := 0; row < fakeBatchRows; row++ {
+					builder.Append(int64(batch*fakeBatchRows + row))
+				}
+				column := builder.NewArray()
+				record := array.NewRecord(fakeSchema, []arrow.Array{column}, fakeBatchRows)
+				err := writer.Write(record)
+				record.Release()
+				column.Release()
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	go func() { _ = server.Serve(listener) }()
+	return listener.Addr().String(), server.Stop
+}
+
+func doGet(g *gomega.WithT, addr string) flight.FlightService_DoGetClient {
+	flightClient, err := flight.NewFlightClient(addr, nil, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	stream, err := flightClient.DoGet(context.Background(), &flight.Ticket{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	return stream
+}
+
+func TestReadFlightStream(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	addr, stop := startFakeFlightServer(g)
+	defer stop()
+
+	// a memory limit of about two batches suffices, since the batches are not retained
+	var next int64
+	stats, err := ReadFlightStream(doGet(g, addr), StreamOptions{MaxMemory: 2 * 8 * fakeBatchRows},
+		func(record arrow.Record) error {
+			ids := record.Column(0).(*array.Int64)
+			for i := 0; i < ids.Len(); i++ {
+				g.Expect(ids.Value(i)).To(gomega.Equal(next))
+				next++
+			}
+			return nil
+		})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(stats).To(gomega.Equal(&StreamStats{Batches: fakeBatches, Rows: fakeBatches * fakeBatchRows}))
+	g.Expect(next).To(gomega.Equal(int64(fakeBatches * fakeBatchRows)))
+
+	// the memory limit is exceeded if the callback retains the batches
+	var retained []arrow.Record
+	stats, err = ReadFlightStream(doGet(g, addr), StreamOptions{MaxMemory: 2 * 8 * fakeBatchRows},
+		func(record arrow.Record) error {
+			record.Retain()
+			retained = append(retained, record)
+			return nil
+		})
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("exceeds the memory limit"))
+	g.Expect(stats.Batches).To(gomega.BeNumerically("<", fakeBatches))
+	for _, record := range retained {
+		record.Release()
+	}
+}
+
+// startFakeWriteServer starts an arrow-flight server on a local port that accepts the writes matching the given schema
+func startFakeWriteServer(g *gomega.WithT, schema *fappv1.AssetSchema) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	server := grpc.NewServer()
+	flight.RegisterFlightServiceService(server, &flight.FlightServiceService{
+		DoPut: func(stream flight.FlightService_DoPutServer) error {
+			_, err := ReadFlightStream(stream, StreamOptions{Schema: schema}, func(arrow.Record) error { return nil })
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			return stream.Send(&flight.PutResult{})
+		},
+	})
+	go func() { _ = server.Serve(listener) }()
+	return listener.Addr().String(), server.Stop
+}
+
+// doPut writes a single batch of ids to the server and returns the error of the write, if any
+func doPut(g *gomega.WithT, addr string, schema *arrow.Schema) error {
+	flightClient, err := flight.NewFlightClient(addr, nil, grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	defer flightClient.Close()
+	stream, err := flightClient.DoPut(context.Background())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	writer := flight.NewRecordWriter(stream, ipc.WithSchema(schema))
+	builder := array.NewInt64Builder(memory.NewGoAllocator())
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3}, nil)
+	column := builder.NewArray()
+	defer column.Release()
+	record := array.NewRecord(schema, []arrow.Array{column}, int64(column.Len()))
+	defer record.Release()
+	g.Expect(writer.Write(record)).To(gomega.Succeed())
+	g.Expect(writer.Close()).To(gomega.Succeed())
+	g.Expect(stream.CloseSend()).To(gomega.Succeed())
+	_, err = stream.Recv()
+	return err
+}
+
+func TestWriteWithStrictSchema(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	addr, stop := startFakeWriteServer(g, &fappv1.AssetSchema{Columns: []string{"id"}})
+	defer stop()
+
+	g.Expect(doPut(g, addr, fakeSchema)).To(gomega.Succeed())
+
+	mismatchedSchema := arrow.NewSchema([]arrow.Field{{Name: "age", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	err := doPut(g, addr, mismatchedSchema)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(status.Code(err)).To(gomega.Equal(codes.InvalidArgument))
+	g.Expect(err.Error()).To(gomega.ContainSubstring(`expected column "id" at position 0, got "age"`))
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"io/fs"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -35,7 +36,16 @@ var certPrivateKeyFile = certsDir + "/" + corev1.TLSPrivateKeyKey
 var CACertFileSuffix = ".crt"
 
 // GetHTTPClient returns an object of type *retryablehttp.Client.
+// The requests are sent through the proxy defined by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, if any.
 func GetHTTPClient(log *zerolog.Logger) *retryablehttp.Client {
+	return GetHTTPClientWithProxy(log, "")
+}
+
+// GetHTTPClientWithProxy returns an object of type *retryablehttp.Client that sends the requests through the given proxy,
+// which overrides the proxy environment variables. The environment variables are used if proxyURL is empty.
+// The client TLS configuration is used for the connection to an https proxy as well, so that mutual TLS is supported.
+// The pooled transport of the retryable client is kept, only its proxy and TLS configuration are set.
+func GetHTTPClientWithProxy(log *zerolog.Logger, proxyURL string) *retryablehttp.Client {
 	retryClient := retryablehttp.NewClient()
 	retryClient.Logger = log
 	config, err := GetClientTLSConfig(log)
@@ -43,12 +53,48 @@ func GetHTTPClient(log *zerolog.Logger) *retryablehttp.Client {
 		log.Error().Err(err)
 		return nil
 	}
-	if config != nil {
-		retryClient.HTTPClient.Transport = &http.Transport{TLSClientConfig: config}
+	proxy := http.ProxyFromEnvironment
+	if proxyURL != "" {
+		parsed, err := ParseProxyURL(proxyURL)
+		if err != nil {
+			log.Error().Err(err).Msg("invalid proxy URL")
+			return nil
+		}
+		proxy = http.ProxyURL(parsed)
+	}
+	transport, ok := retryClient.HTTPClient.Transport.(*http.Transport)
+	if !ok {
+		log.Error().Msg("unexpected transport of the retryable http client")
+		return nil
 	}
+	transport = transport.Clone()
+	transport.TLSClientConfig = config
+	transport.Proxy = proxy
+	retryClient.HTTPClient.Transport = transport
 	return retryClient
 }
 
+// ParseProxyURL parses the URL of a proxy, which must be an absolute http, https or socks5 URL.
+// An empty URL is valid, and means that the proxy is defined by the proxy environment variables.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.New("the proxy URL " + proxyURL + " must have an http, https or socks5 scheme")
+	}
+	if parsed.Host == "" {
+		return nil, errors.New("the proxy URL " + proxyURL + " has no host")
+	}
+	return parsed, nil
+}
+
 // isCertificateProvided returns true if the certificate file and private key were provided as expected.
 // Otherwise it returns false.
 func isCertificateProvided(certFileExists, keyFileExists bool) (bool, error) {
@@ -128,6 +174,48 @@ func getCACertPool() (*x509.CertPool, error) {
 	return nil, nil
 }
 
+// getClientCACertPool returns the CA certificates trusted by the client: the provided private CA certificates
+// together with the CA bundle file set in the environment, if any.
+func getClientCACertPool() (*x509.CertPool, error) {
+	caCertPool, err := getCACertPool()
+	if err != nil {
+		return nil, err
+	}
+	bundleFile := environment.GetConnectorCABundlePath()
+	if bundleFile == "" {
+		return caCertPool, nil
+	}
+	bundle, err := os.ReadFile(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+	if caCertPool == nil {
+		caCertPool = x509.NewCertPool()
+	}
+	if !caCertPool.AppendCertsFromPEM(bundle) {
+		return nil, errors.New("no CA certificates were found in the CA bundle " + bundleFile)
+	}
+	return caCertPool, nil
+}
+
+// getClientCertificate returns the certificate presented by the client for mutual TLS.
+// The certificate files set in the environment take precedence over the mounted certificate.
+func getClientCertificate() (*tls.Certificate, error) {
+	clientCertFile, clientKeyFile := environment.GetConnectorClientCertPaths()
+	if clientCertFile == "" && clientKeyFile == "" {
+		return getCertificate()
+	}
+	if clientCertFile == "" || clientKeyFile == "" {
+		return nil, errors.New("invalid client certificate configuration, " +
+			"please set both the certificate and the private key paths (one is missing)")
+	}
+	cert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
 // GetServerConfig returns the server config for tls connection between the manager and
 // the connectors.
 func GetServerConfig(serverLog *zerolog.Logger) (*tls.Config, error) {
@@ -181,15 +269,16 @@ func GetServerConfig(serverLog *zerolog.Logger) (*tls.Config, error) {
 // GetClientTLSConfig returns the client config for tls connection between the manager and
 // the connectors.
 func GetClientTLSConfig(clientLog *zerolog.Logger) (*tls.Config, error) {
-	caCertPool, err := getCACertPool()
+	caCertPool, err := getClientCACertPool()
 	if err != nil {
+		clientLog.Error().Msg(err.Error())
 		return nil, err
 	} else if caCertPool != nil {
 		clientLog.Log().Msg("private CA certificates were provided in GetClientTLSConfig")
 	} else {
 		clientLog.Log().Msg("private CA certificates were not provided in GetClientTLSConfig")
 	}
-	cert, err := getCertificate()
+	cert, err := getClientCertificate()
 	if err != nil {
 		clientLog.Error().Msg(err.Error())
 		return nil, err
@@ -0,0 +1,51 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package tls
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+func TestGetHTTPClientWithProxyKeepsPooledTransport(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	log := logging.LogInit(logging.SETUP, "tls test")
+	client := GetHTTPClientWithProxy(&log, "http://proxy.example:3128")
+	g.Expect(client).NotTo(gomega.BeNil())
+	transport, ok := client.HTTPClient.Transport.(*http.Transport)
+	g.Expect(ok).To(gomega.BeTrue())
+	pooled, ok := retryablehttp.NewClient().HTTPClient.Transport.(*http.Transport)
+	g.Expect(ok).To(gomega.BeTrue())
+	g.Expect(transport.MaxIdleConnsPerHost).To(gomega.Equal(pooled.MaxIdleConnsPerHost))
+	g.Expect(transport.IdleConnTimeout).To(gomega.Equal(pooled.IdleConnTimeout))
+	g.Expect(transport.TLSHandshakeTimeout).To(gomega.Equal(pooled.TLSHandshakeTimeout))
+
+	req, err := http.NewRequest(http.MethodGet, "http://policy-manager.example", http.NoBody)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	proxy, err := transport.Proxy(req)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(proxy.String()).To(gomega.Equal("http://proxy.example:3128"))
+}
+
+func TestParseProxyURL(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	parsed, err := ParseProxyURL("")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(parsed).To(gomega.BeNil())
+	for _, valid := range []string{"http://proxy.example:3128", "https://proxy.example", "socks5://127.0.0.1:1080"} {
+		_, err = ParseProxyURL(valid)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), valid)
+	}
+	for _, invalid := range []string{"proxy.example:3128", "ftp://proxy.example", "http://", "http://[::1"} {
+		_, err = ParseProxyURL(invalid)
+		g.Expect(err).To(gomega.HaveOccurred(), invalid)
+	}
+}
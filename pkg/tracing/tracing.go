@@ -0,0 +1,87 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing defines the OpenTelemetry spans of Fybrik.
+// The spans are exported to an OTLP collector if an exporter endpoint is configured, and are discarded otherwise.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"emperror.dev/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// TracerName is the instrumentation name of the Fybrik spans
+const TracerName = "fybrik.io/fybrik"
+
+// Span attributes
+const (
+	ApplicationKey    = attribute.Key("fybrik.application")
+	CorrelationIDKey  = attribute.Key("fybrik.correlation_id")
+	DatasetIDKey      = attribute.Key("fybrik.dataset_id")
+	ActionTypeKey     = attribute.Key("fybrik.action_type")
+	DecisionIDKey     = attribute.Key("fybrik.decision_id")
+	DecisionReasonKey = attribute.Key("fybrik.decision_reason")
+	CachedKey         = attribute.Key("fybrik.cached")
+	RequestsKey       = attribute.Key("fybrik.requests")
+	OutcomeKey        = attribute.Key("fybrik.outcome")
+)
+
+// Values of the outcome attribute
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+	OutcomeAllowed = "allowed"
+	OutcomeDenied  = "denied"
+)
+
+// Tracer returns the tracer of the Fybrik spans, which uses the tracer provider registered globally
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// End records the outcome of the operation traced by the span and ends it.
+// An error marks the span as failed, otherwise the given outcome is recorded, e.g., whether an access is allowed.
+func End(span trace.Span, outcome string, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		outcome = OutcomeError
+	}
+	span.SetAttributes(OutcomeKey.String(outcome))
+	span.End()
+}
+
+// SetupFromEnvironment registers a tracer provider that exports the spans to the OTLP collector defined by the environment.
+// If no collector is defined, the spans are discarded. The returned function flushes the spans and stops the export.
+func SetupFromEnvironment(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	endpoint := os.Getenv(environment.TracingEndpointKey)
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if environment.GetEnvAsBool(environment.TracingInsecureKey, false) {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create the OTLP trace exporter")
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
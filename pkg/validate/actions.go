@@ -0,0 +1,182 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/xeipuuv/gojsonschema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+const rootField = "(root)"
+
+// actionSchema is the part of the taxonomy schema that defines the governance actions
+type actionSchema struct {
+	Definitions struct {
+		ActionName struct {
+			Enum []taxonomy.ActionName `json:"enum"`
+		} `json:"ActionName"`
+		Action struct {
+			OneOf []struct {
+				Properties map[string]struct {
+					Enum []taxonomy.ActionName `json:"enum"`
+					Ref  string                `json:"$ref"`
+				} `json:"properties"`
+			} `json:"oneOf"`
+		} `json:"Action"`
+	} `json:"definitions"`
+}
+
+// ActionValidator checks governance actions, e.g., the actions returned by a policy manager,
+// against the actions defined by the taxonomy
+type ActionValidator struct {
+	taxonomyFile string
+	// names of the actions defined by the taxonomy, empty if the taxonomy allows any name
+	names []string
+	// references to the schemas of the action properties, by the action name
+	properties map[taxonomy.ActionName]string
+}
+
+// NewActionValidator reads the action definitions of the given taxonomy file, e.g., taxonomy.json
+func NewActionValidator(taxonomyFile string) (*ActionValidator, error) {
+	taxonomyFile, err := filepath.Abs(taxonomyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get absolute path for the taxonomy")
+	}
+	content, err := os.ReadFile(taxonomyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the taxonomy")
+	}
+	schema := actionSchema{}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, errors.Wrapf(err, "could not parse the taxonomy %s", taxonomyFile)
+	}
+	v := &ActionValidator{taxonomyFile: taxonomyFile, properties: map[taxonomy.ActionName]string{}}
+	for _, name := range schema.Definitions.ActionName.Enum {
+		v.names = append(v.names, string(name))
+	}
+	sort.Strings(v.names)
+	for _, option := range schema.Definitions.Action.OneOf {
+		if len(option.Properties["name"].Enum) != 1 {
+			continue
+		}
+		name := option.Properties["name"].Enum[0]
+		if ref := option.Properties[string(name)].Ref; strings.HasPrefix(ref, "#") {
+			v.properties[name] = ref
+		}
+	}
+	return v, nil
+}
+
+// Validate returns an error for an action with a name unknown to the taxonomy,
+// and for every property of the action that is missing or does not match the schema of the action.
+// Errors that prevent the validation, e.g., an invalid schema of the action, are returned separately.
+func (v *ActionValidator) Validate(action *taxonomy.Action, path *field.Path) (field.ErrorList, error) {
+	if err := v.ValidateName(action.Name, path.Child("name")); err != nil {
+		return field.ErrorList{err}, nil
+	}
+	ref, found := v.properties[action.Name]
+	extension, isExtension := actionExtensionSchema(action.Name)
+	if !found && (!isExtension || v.isKnown(action.Name)) {
+		return nil, nil
+	}
+	propertiesPath := path.Child(string(action.Name))
+	properties, found := action.AdditionalProperties.Items[string(action.Name)]
+	if !found {
+		return field.ErrorList{field.Required(propertiesPath, "the properties of the action must be provided")}, nil
+	}
+	propertiesJSON, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	var result *gojsonschema.Result
+	if ref != "" {
+		schemaLoader := gojsonschema.NewReferenceLoader("file://" + v.taxonomyFile + ref)
+		result, err = gojsonschema.Validate(schemaLoader, gojsonschema.NewBytesLoader(propertiesJSON))
+	} else {
+		// an action extension is validated against the schema it has been registered with
+		result, err = extension.Validate(gojsonschema.NewBytesLoader(propertiesJSON))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not validate the %s action against the taxonomy", action.Name)
+	}
+	var allErrs field.ErrorList
+	for _, desc := range result.Errors() {
+		errPath := propertiesPath
+		if desc.Field() != rootField {
+			for _, child := range strings.Split(desc.Field(), ".") {
+				errPath = errPath.Child(child)
+			}
+		}
+		if desc.Type() == "required" {
+			property, _ := desc.Details()["property"].(string)
+			allErrs = append(allErrs, field.Required(errPath.Child(property), desc.Description()))
+			continue
+		}
+		allErrs = append(allErrs, field.Invalid(errPath, desc.Value(), desc.Description()))
+	}
+	return allErrs, nil
+}
+
+// ValidateName returns an error if the action name is empty or unknown to the taxonomy, e.g.,
+// for the name of an action supported by a module. The names of the registered action extensions are known.
+func (v *ActionValidator) ValidateName(name taxonomy.ActionName, path *field.Path) *field.Error {
+	if name == "" {
+		return field.Required(path, "the action name must be provided")
+	}
+	if len(v.names) > 0 && !v.isKnown(name) && !IsActionExtension(name) {
+		return field.NotSupported(path, name, v.names)
+	}
+	return nil
+}
+
+func (v *ActionValidator) isKnown(name taxonomy.ActionName) bool {
+	i := sort.SearchStrings(v.names, string(name))
+	return i < len(v.names) && v.names[i] == string(name)
+}
+
+// ValidatePolicyDecisionActions checks the actions of a policy manager response against the action definitions of the taxonomy file
+func ValidatePolicyDecisionActions(response *policymanager.GetPolicyDecisionsResponse, taxonomyFile string) (field.ErrorList, error) {
+	validator, err := NewActionValidator(taxonomyFile)
+	if err != nil {
+		return nil, err
+	}
+	var allErrs field.ErrorList
+	resultPath := field.NewPath("result")
+	for i := range response.Result {
+		errs, err := validator.Validate(&response.Result[i].Action, resultPath.Index(i).Child("action"))
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	return allErrs, nil
+}
+
+// WithoutActionExtensions returns the response without the results whose actions are registered extensions,
+// e.g., to validate the response against a taxonomy that does not define these actions.
+// The response is returned as is if it has no such results.
+func WithoutActionExtensions(response *policymanager.GetPolicyDecisionsResponse) *policymanager.GetPolicyDecisionsResponse {
+	result := []policymanager.ResultItem{}
+	for i := range response.Result {
+		if !IsActionExtension(response.Result[i].Action.Name) {
+			result = append(result, response.Result[i])
+		}
+	}
+	if len(result) == len(response.Result) {
+		return response
+	}
+	filtered := *response
+	filtered.Result = result
+	return &filtered
+}
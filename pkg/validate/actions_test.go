@@ -0,0 +1,68 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+var actionTaxonomy = environment.GetDataDir() + "/taxonomy/taxonomy.json"
+
+// newResponse returns a policy manager response with an action of the given name and properties
+func newResponse(name taxonomy.ActionName, properties map[string]interface{}) *policymanager.GetPolicyDecisionsResponse {
+	action := taxonomy.Action{Name: name}
+	if properties != nil {
+		action.AdditionalProperties = serde.Properties{Items: map[string]interface{}{string(name): properties}}
+	}
+	return &policymanager.GetPolicyDecisionsResponse{
+		DecisionID: "decision",
+		Result:     []policymanager.ResultItem{{Action: action, Policy: "policy"}},
+	}
+}
+
+func TestValidateRedactAction(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	response := newResponse("RedactAction", map[string]interface{}{"columns": []string{"SSN"}})
+	allErrs, err := ValidatePolicyDecisionActions(response, actionTaxonomy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.BeEmpty())
+
+	// the columns of the redacted action are required
+	response = newResponse("RedactAction", map[string]interface{}{})
+	allErrs, err = ValidatePolicyDecisionActions(response, actionTaxonomy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.HaveLen(1))
+	g.Expect(allErrs[0].Type).To(gomega.Equal(field.ErrorTypeRequired))
+	g.Expect(allErrs[0].Field).To(gomega.Equal("result[0].action.RedactAction.columns"))
+}
+
+func TestValidateDenyAction(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	response := newResponse("Deny", map[string]interface{}{"reason": "access is not allowed"})
+	allErrs, err := ValidatePolicyDecisionActions(response, actionTaxonomy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.BeEmpty())
+}
+
+func TestValidateUnknownAction(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	response := newResponse("RedcatAction", map[string]interface{}{"columns": []string{"SSN"}})
+	allErrs, err := ValidatePolicyDecisionActions(response, actionTaxonomy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.HaveLen(1))
+	g.Expect(allErrs[0].Type).To(gomega.Equal(field.ErrorTypeNotSupported))
+	g.Expect(allErrs[0].Field).To(gomega.Equal("result[0].action.name"))
+	g.Expect(allErrs[0].BadValue).To(gomega.Equal(taxonomy.ActionName("RedcatAction")))
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/xeipuuv/gojsonschema"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// actionExtensionSuffix is the suffix of the files holding the schemas of the action extensions
+const actionExtensionSuffix = ".json"
+
+// actionExtensions holds the schemas of the properties of the governance actions that extend the taxonomy, by the action name
+var actionExtensions = struct {
+	sync.RWMutex
+	schemas map[taxonomy.ActionName]*gojsonschema.Schema
+}{schemas: map[taxonomy.ActionName]*gojsonschema.Schema{}}
+
+// RegisterActionExtension registers a governance action that is not defined by the taxonomy, e.g., an action of the organization,
+// so that it is validated against the given JSON schema of its properties rather than rejected as unknown.
+// An action defined by the taxonomy is validated against the taxonomy even if it is registered.
+func RegisterActionExtension(name taxonomy.ActionName, schemaJSON []byte) error {
+	if name == "" {
+		return errors.New("the name of the action extension must be provided")
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schemaJSON))
+	if err != nil {
+		return errors.Wrapf(err, "invalid schema of the %s action extension", name)
+	}
+	actionExtensions.Lock()
+	defer actionExtensions.Unlock()
+	actionExtensions.schemas[name] = schema
+	return nil
+}
+
+// IsActionExtension returns true if the action has been registered as an extension of the taxonomy
+func IsActionExtension(name taxonomy.ActionName) bool {
+	_, found := actionExtensionSchema(name)
+	return found
+}
+
+func actionExtensionSchema(name taxonomy.ActionName) (*gojsonschema.Schema, bool) {
+	actionExtensions.RLock()
+	defer actionExtensions.RUnlock()
+	schema, found := actionExtensions.schemas[name]
+	return schema, found
+}
+
+// LoadActionExtensions registers the action extensions defined in the given directory, e.g., a mounted ConfigMap.
+// Every file <name>.json holds the JSON schema of the properties of the action <name>.
+// Other files and hidden files, e.g., the links maintained by kubernetes in the ConfigMap mount, are ignored.
+func LoadActionExtensions(dir string) ([]taxonomy.ActionName, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the action extensions")
+	}
+	names := []taxonomy.ActionName{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if strings.HasPrefix(fileName, ".") || !strings.HasSuffix(fileName, actionExtensionSuffix) {
+			continue
+		}
+		schemaJSON, err := os.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read the action extension %s", fileName)
+		}
+		name := taxonomy.ActionName(strings.TrimSuffix(fileName, actionExtensionSuffix))
+		if err := RegisterActionExtension(name, schemaJSON); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names, nil
+}
+
+// LoadActionExtensionsFromEnvironment registers the action extensions in the directory defined by the environment,
+// if any, and returns their names
+func LoadActionExtensionsFromEnvironment() ([]taxonomy.ActionName, error) {
+	dir := os.Getenv(environment.TaxonomyExtensionsDirKey)
+	if dir == "" {
+		return nil, nil
+	}
+	return LoadActionExtensions(dir)
+}
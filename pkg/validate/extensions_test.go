@@ -0,0 +1,58 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+const watermarkSchema = `{
+	"type": "object",
+	"properties": {"text": {"type": "string"}},
+	"required": ["text"]
+}`
+
+func TestLoadActionExtensions(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "WatermarkAction.json"), []byte(watermarkSchema), 0o600)).To(gomega.Succeed())
+	// files that are not schemas of actions are ignored
+	g.Expect(os.WriteFile(filepath.Join(dir, ".data"), []byte("link"), 0o600)).To(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("watermark"), 0o600)).To(gomega.Succeed())
+
+	names, err := LoadActionExtensions(dir)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(names).To(gomega.Equal([]taxonomy.ActionName{"WatermarkAction"}))
+	g.Expect(IsActionExtension("WatermarkAction")).To(gomega.BeTrue())
+
+	response := newResponse("WatermarkAction", map[string]interface{}{"text": "confidential"})
+	allErrs, err := ValidatePolicyDecisionActions(response, actionTaxonomy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.BeEmpty())
+
+	// the instance is validated against the schema of the extension
+	response = newResponse("WatermarkAction", map[string]interface{}{})
+	allErrs, err = ValidatePolicyDecisionActions(response, actionTaxonomy)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(allErrs).To(gomega.HaveLen(1))
+	g.Expect(allErrs[0].Type).To(gomega.Equal(field.ErrorTypeRequired))
+}
+
+func TestLoadInvalidActionExtension(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "BrokenAction.json"), []byte(`{"type": 5}`), 0o600)).To(gomega.Succeed())
+	_, err := LoadActionExtensions(dir)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(IsActionExtension("BrokenAction")).To(gomega.BeFalse())
+}
@@ -33,6 +33,13 @@ func PathForReadingKubeSecret(secretNamespace, secretName string) string {
 	return secretPath
 }
 
+// IsVaultPath returns true if the given string has the form of a path to a Vault secret, e.g.,
+// "/v1/kubernetes-secrets/my-secret?namespace=default".
+// A catalog that returns the credentials themselves rather than their path would fail this check.
+func IsVaultPath(credentialsPath string) bool {
+	return strings.HasPrefix(credentialsPath, "/v1/") && !strings.ContainsAny(credentialsPath, " \t\n{}")
+}
+
 // Given a path to Vault secret that holds dataset credentials
 // return the name of the secret and its namespace
 // for example, for vault secret path:
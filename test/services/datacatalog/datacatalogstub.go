@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/pkg/connectors"
 	dc "fybrik.io/fybrik/pkg/connectors/datacatalog/clients"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 )
@@ -26,6 +27,15 @@ var router *gin.Engine
 func main() {
 	router = gin.Default()
 
+	// echo the correlation id of the requests, so that they can be traced to the reconcile that issued them
+	router.Use(func(c *gin.Context) {
+		if correlationID := c.GetHeader(connectors.CorrelationIDHeader); correlationID != "" {
+			log.Println("correlation id of the request:", correlationID)
+			c.Header(connectors.CorrelationIDHeader, correlationID)
+		}
+		c.Next()
+	})
+
 	router.POST("/getAssetInfo", func(c *gin.Context) {
 		creds := ""
 		if values := c.Request.Header["X-Request-Datacatalog-Cred"]; len(values) > 0 {
@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	mockup "fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/pkg/connectors"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 )
 
@@ -38,6 +39,15 @@ func constructPolicyManagerRequest(inputString string) *policymanager.GetPolicyD
 func main() {
 	router = gin.Default()
 
+	// echo the correlation id of the requests, so that they can be traced to the reconcile that issued them
+	router.Use(func(c *gin.Context) {
+		if correlationID := c.GetHeader(connectors.CorrelationIDHeader); correlationID != "" {
+			log.Println("correlation id of the request:", correlationID)
+			c.Header(connectors.CorrelationIDHeader, correlationID)
+		}
+		c.Next()
+	})
+
 	router.POST("/getPoliciesDecisions", func(c *gin.Context) {
 		creds := ""
 		if values := c.Request.Header["X-Request-Cred"]; len(values) > 0 {
@@ -49,7 +59,7 @@ func main() {
 
 		policyManagerReq := constructPolicyManagerRequest(string(input))
 		policyManager := &mockup.MockPolicyManager{}
-		policyManagerResp, err := policyManager.GetPoliciesDecisions(policyManagerReq, creds)
+		policyManagerResp, err := policyManager.GetPoliciesDecisions(c.Request.Context(), policyManagerReq, creds)
 		if err != nil {
 			c.String(http.StatusInternalServerError, "Error in GetPoliciesDecisions!")
 			return
@@ -57,6 +67,31 @@ func main() {
 		c.JSON(http.StatusOK, policyManagerResp)
 	})
 
+	router.POST("/getPoliciesDecisionsBatch", func(c *gin.Context) {
+		creds := ""
+		if values := c.Request.Header["X-Request-Cred"]; len(values) > 0 {
+			creds = values[0]
+		}
+		var requests []*policymanager.GetPolicyDecisionsRequest
+		if err := c.ShouldBindJSON(&requests); err != nil {
+			c.String(http.StatusBadRequest, "Error in GetPoliciesDecisionsBatch!")
+			return
+		}
+		log.Println("batch of", len(requests), "requests received by mockup policy manager")
+		policyManager := &mockup.MockBatchPolicyManager{}
+		decisions, err := policyManager.GetPoliciesDecisionsBatch(c.Request.Context(), requests, creds)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "Error in GetPoliciesDecisionsBatch!")
+			return
+		}
+		// the responses are in the order of the requests
+		responses := []*policymanager.GetPolicyDecisionsResponse{}
+		for _, req := range requests {
+			responses = append(responses, decisions[req.Resource.ID])
+		}
+		c.JSON(http.StatusOK, responses)
+	})
+
 	router.GET("/", func(c *gin.Context) {
 		c.String(http.StatusOK, "Serving REST APIs as part of policy manager stub")
 	})